@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AcceleratorExporter is implemented by the exporters of accelerated device telemetry, like the DcgmExporter and the
+// NeuronMonitor, whose status is reported the same way whatever the device.
+type AcceleratorExporter interface {
+	metav1.Object
+	runtime.Object
+	// GetAcceleratorExporterStatus returns the status fields reported by the reconcile of the exporter.
+	GetAcceleratorExporterStatus() AcceleratorExporterStatus
+	// SetAcceleratorExporterStatus sets the status fields reported by the reconcile of the exporter.
+	SetAcceleratorExporterStatus(status AcceleratorExporterStatus)
+}
+
+// AcceleratorExporterStatus holds the status fields reported by the reconcile of an accelerator exporter.
+// +kubebuilder:object:generate=false
+type AcceleratorExporterStatus struct {
+	Version            string
+	Image              string
+	ObservedGeneration int64
+	Conditions         []metav1.Condition
+	Rollout            *RolloutStatus
+	LastReconcileError string
+}
+
+var (
+	_ AcceleratorExporter = &DcgmExporter{}
+	_ AcceleratorExporter = &NeuronMonitor{}
+)
+
+// GetAcceleratorExporterStatus returns the status fields reported by the reconcile of the DcgmExporter.
+func (in *DcgmExporter) GetAcceleratorExporterStatus() AcceleratorExporterStatus {
+	return AcceleratorExporterStatus{
+		Version:            in.Status.Version,
+		Image:              in.Status.Image,
+		ObservedGeneration: in.Status.ObservedGeneration,
+		Conditions:         in.Status.Conditions,
+		Rollout:            in.Status.Rollout,
+		LastReconcileError: in.Status.LastReconcileError,
+	}
+}
+
+// SetAcceleratorExporterStatus sets the status fields reported by the reconcile of the DcgmExporter.
+func (in *DcgmExporter) SetAcceleratorExporterStatus(status AcceleratorExporterStatus) {
+	in.Status.Version = status.Version
+	in.Status.Image = status.Image
+	in.Status.ObservedGeneration = status.ObservedGeneration
+	in.Status.Conditions = status.Conditions
+	in.Status.Rollout = status.Rollout
+	in.Status.LastReconcileError = status.LastReconcileError
+}
+
+// GetAcceleratorExporterStatus returns the status fields reported by the reconcile of the NeuronMonitor.
+func (in *NeuronMonitor) GetAcceleratorExporterStatus() AcceleratorExporterStatus {
+	return AcceleratorExporterStatus{
+		Version:            in.Status.Version,
+		Image:              in.Status.Image,
+		ObservedGeneration: in.Status.ObservedGeneration,
+		Conditions:         in.Status.Conditions,
+		Rollout:            in.Status.Rollout,
+		LastReconcileError: in.Status.LastReconcileError,
+	}
+}
+
+// SetAcceleratorExporterStatus sets the status fields reported by the reconcile of the NeuronMonitor.
+func (in *NeuronMonitor) SetAcceleratorExporterStatus(status AcceleratorExporterStatus) {
+	in.Status.Version = status.Version
+	in.Status.Image = status.Image
+	in.Status.ObservedGeneration = status.ObservedGeneration
+	in.Status.Conditions = status.Conditions
+	in.Status.Rollout = status.Rollout
+	in.Status.LastReconcileError = status.LastReconcileError
+}
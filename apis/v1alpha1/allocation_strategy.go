@@ -5,11 +5,18 @@ package v1alpha1
 
 type (
 	// AmazonCloudWatchAgentTargetAllocatorAllocationStrategy represent which strategy to distribute target to each collector
-	// +kubebuilder:validation:Enum=consistent-hashing
+	// +kubebuilder:validation:Enum=consistent-hashing;least-weighted;per-node
 	AmazonCloudWatchAgentTargetAllocatorAllocationStrategy string
 )
 
 const (
 	// AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing targets will be consistently added to collectors, which allows a high-availability setup.
 	AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing AmazonCloudWatchAgentTargetAllocatorAllocationStrategy = "consistent-hashing"
+
+	// AmazonCloudWatchAgentTargetAllocatorAllocationStrategyLeastWeighted targets will be distributed to the collector with the lowest total weight, the scrape cost of its targets.
+	// Targets weigh the series count reported by their collector, else the value of their cloudwatch.aws.amazon.com/scrape-weight annotation or label, else 1.
+	AmazonCloudWatchAgentTargetAllocatorAllocationStrategyLeastWeighted AmazonCloudWatchAgentTargetAllocatorAllocationStrategy = "least-weighted"
+
+	// AmazonCloudWatchAgentTargetAllocatorAllocationStrategyPerNode targets will be assigned to the collector on the node they reside on, only for daemonset mode.
+	AmazonCloudWatchAgentTargetAllocatorAllocationStrategyPerNode AmazonCloudWatchAgentTargetAllocatorAllocationStrategy = "per-node"
 )
@@ -289,9 +289,9 @@ type AmazonCloudWatchAgentSpec struct {
 
 // AmazonCloudWatchAgentTargetAllocator defines the configurations for the Prometheus target allocator.
 type AmazonCloudWatchAgentTargetAllocator struct {
-	// Replicas is the number of pod instances for the underlying TargetAllocator. This should only be set to a value
-	// other than 1 if a strategy that allows for high availability is chosen. Currently, the only allocation strategy
-	// that can be run in a high availability mode is consistent-hashing.
+	// Replicas is the number of pod instances for the underlying TargetAllocator. With more than 1 replica, the replicas
+	// elect a leader allocating the targets, the others serve the assignments it replicates in a ConfigMap. This requires
+	// the TargetAllocator's service account to manage Leases and ConfigMaps in its namespace.
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 	// NodeSelector to schedule OpenTelemetry TargetAllocator pods.
@@ -301,14 +301,21 @@ type AmazonCloudWatchAgentTargetAllocator struct {
 	// +optional
 	Resources v1.ResourceRequirements `json:"resources,omitempty"`
 	// AllocationStrategy determines which strategy the target allocator should use for allocation.
-	// The current option is consistent-hashing.
+	// The current options are consistent-hashing, least-weighted and per-node. The default is consistent-hashing.
+	// per-node is only supported in daemonset mode.
 	// +optional
 	AllocationStrategy AmazonCloudWatchAgentTargetAllocatorAllocationStrategy `json:"allocationStrategy,omitempty"`
 	// FilterStrategy determines how to filter targets before allocating them among the collectors.
-	// The only current option is relabel-config (drops targets based on prom relabel_config).
+	// The options are relabel-config (drops targets based on prom relabel_config), deduplication (drops the targets of
+	// different jobs scraping the same URL), namespace (drops targets based on the namespaces allowed and denied in
+	// Filter) and cardinality-guard (caps the number of targets per job set in Filter). Several options separated by
+	// commas are chained in this order, e.g. relabel-config,deduplication.
 	// Filtering is disabled by default.
 	// +optional
 	FilterStrategy string `json:"filterStrategy,omitempty"`
+	// Filter configures the namespace and cardinality-guard filter strategies.
+	// +optional
+	Filter AmazonCloudWatchAgentTargetAllocatorFilter `json:"filter,omitempty"`
 	// ServiceAccount indicates the name of an existing service account to use with this instance. When set,
 	// the operator will not automatically create a ServiceAccount for the TargetAllocator.
 	// +optional
@@ -346,6 +353,23 @@ type AmazonCloudWatchAgentTargetAllocator struct {
 	Env []v1.EnvVar `json:"env,omitempty"`
 }
 
+type AmazonCloudWatchAgentTargetAllocatorFilter struct {
+	// AllowedNamespaces are the only namespaces the namespace filter strategy keeps the targets of. The targets of all
+	// the namespaces are kept if empty.
+	// +optional
+	// +listType=set
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	// DeniedNamespaces are the namespaces the namespace filter strategy drops the targets of.
+	// +optional
+	// +listType=set
+	DeniedNamespaces []string `json:"deniedNamespaces,omitempty"`
+	// MaxTargetsPerJob is the number of targets of a job the cardinality-guard filter strategy keeps. The number of
+	// targets is unlimited if 0.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxTargetsPerJob int32 `json:"maxTargetsPerJob,omitempty"`
+}
+
 type AmazonCloudWatchAgentTargetAllocatorPrometheusCR struct {
 	// Enabled indicates whether to use a PrometheusOperator custom resources as targets or not.
 	// +optional
@@ -366,6 +390,14 @@ type AmazonCloudWatchAgentTargetAllocatorPrometheusCR struct {
 	// ServiceMonitor's meta labels. The requirements are ANDed.
 	// +optional
 	ServiceMonitorSelector map[string]string `json:"serviceMonitorSelector,omitempty"`
+	// PodMonitorNamespaceSelector selects the namespaces PodMonitors are discovered in, with label selector
+	// expressions on the namespace labels. PodMonitors are discovered in all namespaces if unset.
+	// +optional
+	PodMonitorNamespaceSelector *metav1.LabelSelector `json:"podMonitorNamespaceSelector,omitempty"`
+	// ServiceMonitorNamespaceSelector selects the namespaces ServiceMonitors are discovered in, with label selector
+	// expressions on the namespace labels. ServiceMonitors are discovered in all namespaces if unset.
+	// +optional
+	ServiceMonitorNamespaceSelector *metav1.LabelSelector `json:"serviceMonitorNamespaceSelector,omitempty"`
 }
 
 // ScaleSubresourceStatus defines the observed state of the AmazonCloudWatchAgent's
@@ -388,6 +420,33 @@ type ScaleSubresourceStatus struct {
 	StatusReplicas string `json:"statusReplicas,omitempty"`
 }
 
+// Condition types reported on the status of the AmazonCloudWatchAgent, DcgmExporter and NeuronMonitor.
+const (
+	// ConditionTypeReady is true once the last reconcile succeeded and all the pods of the operand are updated and ready.
+	ConditionTypeReady = "Ready"
+	// ConditionTypeReconciled is true if the last reconcile applied all the manifests of the operand.
+	ConditionTypeReconciled = "Reconciled"
+	// ConditionTypeConfigValid is false if the manifests of the operand cannot be built from the spec.
+	ConditionTypeConfigValid = "ConfigValid"
+	// ConditionTypeDegraded is true if the last reconcile failed or pods of the operand are not ready once rolled out.
+	ConditionTypeDegraded = "Degraded"
+)
+
+// RolloutStatus is the rollout progress of the Deployment, DaemonSet or StatefulSet running the operand.
+type RolloutStatus struct {
+	// Desired is the number of pods the workload should run.
+	// +optional
+	Desired int32 `json:"desired,omitempty"`
+
+	// Updated is the number of pods running the latest spec of the workload.
+	// +optional
+	Updated int32 `json:"updated,omitempty"`
+
+	// Ready is the number of ready pods of the workload.
+	// +optional
+	Ready int32 `json:"ready,omitempty"`
+}
+
 // AmazonCloudWatchAgentStatus defines the observed state of AmazonCloudWatchAgent.
 type AmazonCloudWatchAgentStatus struct {
 	// Scale is the AmazonCloudWatchAgent's scale subresource status.
@@ -412,6 +471,24 @@ type AmazonCloudWatchAgentStatus struct {
 	// +optional
 	// Deprecated: use "AmazonCloudWatchAgent.Status.Scale.Replicas" instead.
 	Replicas int32 `json:"replicas,omitempty"`
+
+	// ObservedGeneration is the generation of the AmazonCloudWatchAgent last reconciled by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions report whether the AmazonCloudWatchAgent is reconciled, its config valid and its pods ready.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Rollout is the rollout progress of the AmazonCloudWatchAgent's deployment, daemonSet or statefulSet.
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// LastReconcileError is the error of the last reconcile, empty if it succeeded.
+	// +optional
+	LastReconcileError string `json:"lastReconcileError,omitempty"`
 }
 
 // +kubebuilder:object:root=true
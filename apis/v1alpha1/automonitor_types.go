@@ -0,0 +1,204 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AutoMonitorName is the name of the single AutoMonitor the operator applies.
+const AutoMonitorName = "default"
+
+// AutoMonitorConditionApplied is the condition type reporting whether the AutoMonitor is in use by the operator.
+const AutoMonitorConditionApplied = "Applied"
+
+// AutoMonitorSpec defines the auto-monitor configuration. It has the same shape as the --auto-monitor-config flag,
+// which it replaces while the AutoMonitor exists. The deprecated --auto-annotation-config flag is not
+// covered: while it is set, the auto-monitor is disabled and the AutoMonitor is not applied.
+type AutoMonitorSpec struct {
+	// MonitorAllServices enables the auto-instrumentation of all the workloads selected by a service.
+	// +optional
+	MonitorAllServices bool `json:"monitorAllServices,omitempty"`
+
+	// Languages are the languages instrumented for the workloads selected by a service. Defaults to all the supported
+	// languages but Go, whose instrumentation runs a privileged eBPF sidecar and must be listed explicitly.
+	// +optional
+	// +listType=set
+	Languages []AutoMonitorLanguage `json:"languages,omitempty"`
+
+	// DetectLanguages restricts the languages instrumented for the workloads selected by a service to the ones detected
+	// in their pod template, e.g. from the container images, commands or environment variables. Workloads with no
+	// detected language are not instrumented unless InstrumentUndetected is set.
+	// +optional
+	DetectLanguages bool `json:"detectLanguages,omitempty"`
+
+	// InstrumentUndetected instruments the workloads with no detected language for all the Languages when
+	// DetectLanguages is enabled.
+	// +optional
+	InstrumentUndetected bool `json:"instrumentUndetected,omitempty"`
+
+	// RestartPods allows the operator to restart the workloads to apply the configuration. Otherwise, the
+	// configuration is applied on the next rollout of the workloads.
+	// +optional
+	RestartPods bool `json:"restartPods,omitempty"`
+
+	// RestartStrategy controls how the workloads are restarted when RestartPods is enabled. Without a strategy, all the
+	// workloads are restarted at once.
+	// +optional
+	RestartStrategy *AutoMonitorRestartStrategy `json:"restartStrategy,omitempty"`
+
+	// Exclude lists the resources that are never instrumented for the language.
+	// +optional
+	Exclude AutoMonitorSelector `json:"exclude,omitempty"`
+
+	// CustomSelector lists the resources that are always instrumented for the language.
+	// +optional
+	CustomSelector AutoMonitorSelector `json:"customSelector,omitempty"`
+}
+
+// AutoMonitorRestartStrategy restarts the workloads in batches.
+type AutoMonitorRestartStrategy struct {
+	// MaxConcurrent is the maximum number of workloads restarted in a batch. Zero means no limit.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxConcurrent int `json:"maxConcurrent,omitempty"`
+
+	// BatchByNamespace restricts the batches to the workloads of a single namespace.
+	// +optional
+	BatchByNamespace bool `json:"batchByNamespace,omitempty"`
+
+	// PauseBetweenBatches is how long to wait after a batch before restarting the next one.
+	// +optional
+	PauseBetweenBatches metav1.Duration `json:"pauseBetweenBatches,omitempty"`
+
+	// WaitForRollout waits for the workloads of a batch to be rolled out before restarting the next one. The restarts
+	// stop if they aren't rolled out within RolloutTimeout.
+	// +optional
+	WaitForRollout bool `json:"waitForRollout,omitempty"`
+
+	// RolloutTimeout defaults to 10 minutes.
+	// +optional
+	RolloutTimeout metav1.Duration `json:"rolloutTimeout,omitempty"`
+
+	// MaintenanceWindow restricts the restarts to a daily time window.
+	// +optional
+	MaintenanceWindow AutoMonitorMaintenanceWindow `json:"maintenanceWindow,omitempty"`
+}
+
+// AutoMonitorMaintenanceWindow is a daily time window in UTC, e.g. from 22:00 to 06:00. An empty window is always
+// open.
+type AutoMonitorMaintenanceWindow struct {
+	// Start is the time the window opens, formatted as HH:MM.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start,omitempty"`
+
+	// End is the time the window closes, formatted as HH:MM.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end,omitempty"`
+}
+
+// AutoMonitorLanguage is a language supported by the auto-monitor.
+// +kubebuilder:validation:Enum=java;python;dotnet;nodejs;go
+type AutoMonitorLanguage string
+
+// AutoMonitorSelector details the resources selected for each language.
+type AutoMonitorSelector struct {
+	// +optional
+	Java AutoMonitorResources `json:"java,omitempty"`
+	// +optional
+	Python AutoMonitorResources `json:"python,omitempty"`
+	// +optional
+	DotNet AutoMonitorResources `json:"dotnet,omitempty"`
+	// +optional
+	NodeJS AutoMonitorResources `json:"nodejs,omitempty"`
+	// +optional
+	Go AutoMonitorResources `json:"go,omitempty"`
+}
+
+// AutoMonitorResources selects resources by name, by label or both.
+type AutoMonitorResources struct {
+	// Namespaces are the names of the selected namespaces. Glob patterns are supported, e.g. team-*.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Deployments are the selected deployments as namespace/name. Glob patterns are supported, e.g. shop/*-api.
+	// +optional
+	Deployments []string `json:"deployments,omitempty"`
+	// DaemonSets are the selected daemonsets as namespace/name. Glob patterns are supported.
+	// +optional
+	DaemonSets []string `json:"daemonsets,omitempty"`
+	// StatefulSets are the selected statefulsets as namespace/name. Glob patterns are supported.
+	// +optional
+	StatefulSets []string `json:"statefulsets,omitempty"`
+	// NamespaceSelector selects namespaces by label.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// WorkloadSelector selects deployments, daemonsets and statefulsets by label.
+	// +optional
+	WorkloadSelector *metav1.LabelSelector `json:"workloadSelector,omitempty"`
+}
+
+// AutoMonitorStatus defines the observed state of the AutoMonitor.
+type AutoMonitorStatus struct {
+	// ObservedGeneration is the generation of the AutoMonitor last applied by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Languages lists the number of workloads currently selected for each language.
+	// +optional
+	// +listType=map
+	// +listMapKey=language
+	Languages []AutoMonitorLanguageStatus `json:"languages,omitempty"`
+
+	// Conditions report whether the AutoMonitor is applied by the operator.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastUpdateTime is the last time the workload counts were refreshed.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// AutoMonitorLanguageStatus holds the number of workloads selected for a language.
+type AutoMonitorLanguageStatus struct {
+	// Language is the instrumentation language, e.g. java or python.
+	Language AutoMonitorLanguage `json:"language"`
+
+	// Workloads is the number of deployments, daemonsets and statefulsets selected for the language.
+	// +optional
+	Workloads int32 `json:"workloads,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Monitor All Services",type="boolean",JSONPath=".spec.monitorAllServices"
+// +kubebuilder:printcolumn:name="Restart Pods",type="boolean",JSONPath=".spec.restartPods"
+// +kubebuilder:printcolumn:name="Applied",type="string",JSONPath=".status.conditions[?(@.type==\"Applied\")].status"
+// +operator-sdk:csv:customresourcedefinitions:displayName="Auto Monitor"
+
+// AutoMonitor is the cluster-wide configuration of the auto-monitor. Only the AutoMonitor named default is applied.
+type AutoMonitor struct {
+	Status            AutoMonitorStatus `json:"status,omitempty"`
+	metav1.TypeMeta   `json:",inline"`
+	Spec              AutoMonitorSpec `json:"spec,omitempty"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AutoMonitorList contains a list of AutoMonitor.
+type AutoMonitorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AutoMonitor `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AutoMonitor{}, &AutoMonitorList{})
+}
@@ -0,0 +1,146 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var (
+	_ admission.Validator[*AutoMonitor] = &AutoMonitorWebhook{}
+)
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-cloudwatch-aws-amazon-com-v1alpha1-automonitor,mutating=false,failurePolicy=fail,groups=cloudwatch.aws.amazon.com,resources=automonitors,versions=v1alpha1,name=vautomonitorcreateupdate.kb.io,sideEffects=none,admissionReviewVersions=v1
+// +kubebuilder:object:generate=false
+
+type AutoMonitorWebhook struct {
+	logger logr.Logger
+}
+
+func (w AutoMonitorWebhook) ValidateCreate(ctx context.Context, obj *AutoMonitor) (admission.Warnings, error) {
+	return w.validate(obj)
+}
+
+func (w AutoMonitorWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj *AutoMonitor) (admission.Warnings, error) {
+	return w.validate(newObj)
+}
+
+func (w AutoMonitorWebhook) ValidateDelete(ctx context.Context, obj *AutoMonitor) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (w AutoMonitorWebhook) validate(r *AutoMonitor) (admission.Warnings, error) {
+	if r.Name != AutoMonitorName {
+		return nil, fmt.Errorf("the AutoMonitor must be named %s, other AutoMonitors are ignored by the operator", AutoMonitorName)
+	}
+	var warnings admission.Warnings
+	var errs []error
+	for _, field := range []struct {
+		name     string
+		selector AutoMonitorSelector
+	}{{"exclude", r.Spec.Exclude}, {"customSelector", r.Spec.CustomSelector}} {
+		for _, language := range autoMonitorLanguages {
+			fieldWarnings, err := field.selector.resources(language).validate()
+			prefix := fmt.Sprintf("spec.%s.%s", field.name, language)
+			for _, warning := range fieldWarnings {
+				warnings = append(warnings, fmt.Sprintf("%s: %s", prefix, warning))
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", prefix, err))
+			}
+		}
+	}
+	if err := r.Spec.RestartStrategy.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("spec.restartStrategy: %w", err))
+	}
+	return warnings, errors.Join(errs...)
+}
+
+// validate checks the maintenance window, the other fields are validated by the CRD schema.
+func (s *AutoMonitorRestartStrategy) validate() error {
+	if s == nil {
+		return nil
+	}
+	window := s.MaintenanceWindow
+	if (window.Start == "") != (window.End == "") {
+		return fmt.Errorf("maintenanceWindow: both start and end must be set")
+	}
+	if window.Start != "" && window.Start == window.End {
+		return fmt.Errorf("maintenanceWindow: start and end must differ")
+	}
+	return nil
+}
+
+var autoMonitorLanguages = []AutoMonitorLanguage{"java", "python", "dotnet", "nodejs", "go"}
+
+func (s AutoMonitorSelector) resources(language AutoMonitorLanguage) AutoMonitorResources {
+	switch language {
+	case "java":
+		return s.Java
+	case "python":
+		return s.Python
+	case "dotnet":
+		return s.DotNet
+	case "nodejs":
+		return s.NodeJS
+	case "go":
+		return s.Go
+	default:
+		return AutoMonitorResources{}
+	}
+}
+
+// validate checks the name patterns and label selectors, and warns about workload names that aren't namespaced and
+// therefore never match.
+func (r AutoMonitorResources) validate() (admission.Warnings, error) {
+	var warnings admission.Warnings
+	var errs []error
+	for _, pattern := range r.Namespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("invalid namespace pattern %q: %w", pattern, err))
+		}
+	}
+	for _, workloads := range []struct {
+		kind  string
+		names []string
+	}{{"deployment", r.Deployments}, {"daemonset", r.DaemonSets}, {"statefulset", r.StatefulSets}} {
+		for _, pattern := range workloads.names {
+			if _, err := path.Match(pattern, ""); err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s pattern %q: %w", workloads.kind, pattern, err))
+			}
+			if !strings.Contains(pattern, "/") {
+				warnings = append(warnings, fmt.Sprintf("%s %q is not namespaced and will never match, use namespace/name", workloads.kind, pattern))
+			}
+		}
+	}
+	if _, err := metav1.LabelSelectorAsSelector(r.NamespaceSelector); err != nil {
+		errs = append(errs, fmt.Errorf("invalid namespaceSelector: %w", err))
+	}
+	if _, err := metav1.LabelSelectorAsSelector(r.WorkloadSelector); err != nil {
+		errs = append(errs, fmt.Errorf("invalid workloadSelector: %w", err))
+	}
+	return warnings, errors.Join(errs...)
+}
+
+func NewAutoMonitorWebhook(logger logr.Logger) *AutoMonitorWebhook {
+	return &AutoMonitorWebhook{
+		logger: logger,
+	}
+}
+
+func SetupAutoMonitorWebhook(mgr ctrl.Manager) error {
+	amw := NewAutoMonitorWebhook(mgr.GetLogger().WithValues("handler", "AutoMonitorWebhook"))
+	return ctrl.NewWebhookManagedBy(mgr, &AutoMonitor{}).
+		WithValidator(amw).
+		Complete()
+}
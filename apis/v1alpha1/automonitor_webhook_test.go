@@ -0,0 +1,122 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestAutoMonitorValidatingWebhook(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      string
+		warnings admission.Warnings
+		monitor  AutoMonitor
+	}{
+		{
+			name:    "empty",
+			monitor: AutoMonitor{ObjectMeta: metav1.ObjectMeta{Name: AutoMonitorName}},
+		},
+		{
+			name: "valid",
+			monitor: AutoMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: AutoMonitorName},
+				Spec: AutoMonitorSpec{
+					MonitorAllServices: true,
+					Languages:          []AutoMonitorLanguage{"java"},
+					Exclude: AutoMonitorSelector{
+						Java: AutoMonitorResources{
+							Namespaces:        []string{"team-*"},
+							Deployments:       []string{"shop/*-api"},
+							NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "not the default AutoMonitor",
+			monitor: AutoMonitor{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+			err:     "the AutoMonitor must be named default",
+		},
+		{
+			name: "invalid pattern",
+			monitor: AutoMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: AutoMonitorName},
+				Spec: AutoMonitorSpec{
+					CustomSelector: AutoMonitorSelector{
+						Python: AutoMonitorResources{Namespaces: []string{"team-[a"}},
+					},
+				},
+			},
+			err: `spec.customSelector.python: invalid namespace pattern "team-[a"`,
+		},
+		{
+			name: "invalid selector",
+			monitor: AutoMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: AutoMonitorName},
+				Spec: AutoMonitorSpec{
+					Exclude: AutoMonitorSelector{
+						NodeJS: AutoMonitorResources{WorkloadSelector: &metav1.LabelSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Unknown"}},
+						}},
+					},
+				},
+			},
+			err: "spec.exclude.nodejs: invalid workloadSelector",
+		},
+		{
+			name: "incomplete maintenance window",
+			monitor: AutoMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: AutoMonitorName},
+				Spec: AutoMonitorSpec{
+					RestartStrategy: &AutoMonitorRestartStrategy{
+						MaintenanceWindow: AutoMonitorMaintenanceWindow{Start: "22:00"},
+					},
+				},
+			},
+			err: "spec.restartStrategy: maintenanceWindow: both start and end must be set",
+		},
+		{
+			name: "workload not namespaced",
+			monitor: AutoMonitor{
+				ObjectMeta: metav1.ObjectMeta{Name: AutoMonitorName},
+				Spec: AutoMonitorSpec{
+					CustomSelector: AutoMonitorSelector{
+						DotNet: AutoMonitorResources{StatefulSets: []string{"db"}},
+					},
+				},
+			},
+			warnings: []string{`spec.customSelector.dotnet: statefulset "db" is not namespaced and will never match, use namespace/name`},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			webhook := NewAutoMonitorWebhook(logr.Discard())
+			ctx := context.Background()
+			warnings, err := webhook.ValidateCreate(ctx, &test.monitor)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.err)
+			}
+			assert.Equal(t, test.warnings, warnings)
+
+			warnings, err = webhook.ValidateUpdate(ctx, &test.monitor, &test.monitor)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, test.err)
+			}
+			assert.Equal(t, test.warnings, warnings)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-logr/logr"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -42,15 +43,17 @@ func (c CollectorWebhook) Default(ctx context.Context, obj *AmazonCloudWatchAgen
 }
 
 func (c CollectorWebhook) ValidateCreate(ctx context.Context, obj *AmazonCloudWatchAgent) (admission.Warnings, error) {
-	return c.validate(obj)
+	return c.validate(obj, true)
 }
 
 func (c CollectorWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj *AmazonCloudWatchAgent) (admission.Warnings, error) {
-	return c.validate(newObj)
+	// the agent configuration of existing resources is only validated when it changes,
+	// so that they can still be updated, e.g. by the finalizer patch of the operator
+	return c.validate(newObj, oldObj == nil || oldObj.Spec.Config != newObj.Spec.Config)
 }
 
 func (c CollectorWebhook) ValidateDelete(ctx context.Context, obj *AmazonCloudWatchAgent) (admission.Warnings, error) {
-	return c.validate(obj)
+	return c.validate(obj, false)
 }
 
 func (c CollectorWebhook) defaulter(r *AmazonCloudWatchAgent) error {
@@ -127,7 +130,7 @@ func (c CollectorWebhook) defaulter(r *AmazonCloudWatchAgent) error {
 	return nil
 }
 
-func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings, error) {
+func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent, validateConfig bool) (admission.Warnings, error) {
 	warnings := admission.Warnings{}
 	// validate volumeClaimTemplates
 	if r.Spec.Mode != ModeStatefulSet && len(r.Spec.VolumeClaimTemplates) > 0 {
@@ -154,10 +157,30 @@ func (c CollectorWebhook) validate(r *AmazonCloudWatchAgent) (admission.Warnings
 	}
 
 	// validate target allocation
-	if r.Spec.TargetAllocator.Enabled && r.Spec.Mode != ModeStatefulSet {
+	if r.Spec.TargetAllocator.AllocationStrategy == AmazonCloudWatchAgentTargetAllocatorAllocationStrategyPerNode && r.Spec.Mode != ModeDaemonSet {
+		return warnings, fmt.Errorf("the Amazon CloudWatch Agent mode is set to %s, which does not support the target allocation strategy %s", r.Spec.Mode, r.Spec.TargetAllocator.AllocationStrategy)
+	}
+	if r.Spec.TargetAllocator.Enabled && r.Spec.Mode != ModeStatefulSet && r.Spec.TargetAllocator.AllocationStrategy != AmazonCloudWatchAgentTargetAllocatorAllocationStrategyPerNode {
 		warnings = append(warnings, fmt.Sprintf("The Amazon CloudWatch Agent mode is set to %s, we do not recommend enabling Target Allocator when not running as a StatefulSet", r.Spec.Mode))
 	}
 
+	// validate the CloudWatch agent configuration
+	if validateConfig && r.Spec.Config != "" {
+		configWarnings, err := adapters.ValidateAgentConfig(r.Spec.Config)
+		warnings = append(warnings, configWarnings...)
+		if err != nil {
+			return warnings, fmt.Errorf("the Amazon CloudWatch Agent Spec Config is incorrect, %w", err)
+		}
+	}
+
+	// validate the namespace selectors of the Prometheus CR discovery
+	if _, err := metav1.LabelSelectorAsSelector(r.Spec.TargetAllocator.PrometheusCR.PodMonitorNamespaceSelector); err != nil {
+		return warnings, fmt.Errorf("the Amazon CloudWatch Agent Spec TargetAllocator podMonitorNamespaceSelector is incorrect, %w", err)
+	}
+	if _, err := metav1.LabelSelectorAsSelector(r.Spec.TargetAllocator.PrometheusCR.ServiceMonitorNamespaceSelector); err != nil {
+		return warnings, fmt.Errorf("the Amazon CloudWatch Agent Spec TargetAllocator serviceMonitorNamespaceSelector is incorrect, %w", err)
+	}
+
 	// validate Prometheus config for target allocation
 	if r.Spec.TargetAllocator.Enabled {
 		promConfigYaml, err := r.Spec.Prometheus.Yaml()
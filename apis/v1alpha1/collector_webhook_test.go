@@ -387,6 +387,45 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 			},
 			expectedErr: "the OpenTelemetry Spec Prometheus configuration is incorrect",
 		},
+		{
+			name: "invalid mode with per-node allocation strategy",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Mode: ModeStatefulSet,
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						Enabled:            true,
+						AllocationStrategy: AmazonCloudWatchAgentTargetAllocatorAllocationStrategyPerNode,
+					},
+				},
+			},
+			expectedErr: "which does not support the target allocation strategy per-node",
+		},
+		{
+			name: "invalid agent config",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					Config: `{"metric": {}}`,
+				},
+			},
+			expectedErr: "metric is a forbidden property",
+		},
+		{
+			name: "invalid service monitor namespace selector",
+			otelcol: AmazonCloudWatchAgent{
+				Spec: AmazonCloudWatchAgentSpec{
+					TargetAllocator: AmazonCloudWatchAgentTargetAllocator{
+						PrometheusCR: AmazonCloudWatchAgentTargetAllocatorPrometheusCR{
+							ServiceMonitorNamespaceSelector: &metav1.LabelSelector{
+								MatchExpressions: []metav1.LabelSelectorRequirement{
+									{Key: "team", Operator: "Matches", Values: []string{"a"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErr: "serviceMonitorNamespaceSelector is incorrect",
+		},
 		{
 			name: "invalid port name",
 			otelcol: AmazonCloudWatchAgent{
@@ -803,3 +842,37 @@ func TestOTELColValidatingWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestOTELColValidatingWebhookAgentConfigUpdate(t *testing.T) {
+	invalid := AmazonCloudWatchAgent{
+		Spec: AmazonCloudWatchAgentSpec{
+			Config: `{"metric": {}}`,
+		},
+	}
+	changed := AmazonCloudWatchAgent{
+		Spec: AmazonCloudWatchAgentSpec{
+			Config: `{"metric": {}, "logs": {}}`,
+		},
+	}
+	finalized := *invalid.DeepCopy()
+	finalized.Finalizers = []string{"amazon-cloudwatch-agent-operator/finalizer"}
+
+	cvw := &CollectorWebhook{
+		logger: logr.Discard(),
+		scheme: testScheme,
+		cfg: config.New(
+			config.WithCollectorImage("collector:v0.0.0"),
+			config.WithTargetAllocatorImage("ta:v0.0.0"),
+		),
+	}
+	ctx := context.Background()
+
+	// an unchanged configuration is not validated again
+	_, err := cvw.ValidateUpdate(ctx, &invalid, &finalized)
+	assert.NoError(t, err)
+	_, err = cvw.ValidateDelete(ctx, &invalid)
+	assert.NoError(t, err)
+
+	_, err = cvw.ValidateUpdate(ctx, &invalid, &changed)
+	assert.ErrorContains(t, err, "metric is a forbidden property")
+}
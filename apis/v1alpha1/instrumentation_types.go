@@ -65,6 +65,14 @@ type InstrumentationSpec struct {
 	// Nginx defines configuration for Nginx auto-instrumentation.
 	// +optional
 	Nginx Nginx `json:"nginx,omitempty"`
+
+	// Ruby defines configuration for Ruby auto-instrumentation.
+	// +optional
+	Ruby Ruby `json:"ruby,omitempty"`
+
+	// PHP defines configuration for PHP auto-instrumentation.
+	// +optional
+	PHP PHP `json:"php,omitempty"`
 }
 
 // Resource defines the configuration for the resource attributes, as defined by the OpenTelemetry specification.
@@ -278,8 +286,131 @@ type Nginx struct {
 	Resources corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
 }
 
+// Ruby defines Ruby SDK and instrumentation configuration.
+type Ruby struct {
+	// Image is a container image with Ruby SDK and auto-instrumentation.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// VolumeSizeLimit defines size limit for volume used for auto-instrumentation.
+	// The default size is 200Mi.
+	VolumeSizeLimit *resource.Quantity `json:"volumeLimitSize,omitempty"`
+
+	// Env defines Ruby specific env vars. There are four layers for env vars' definitions and
+	// the precedence order is: `original container env vars` > `language specific env vars` > `common env vars` > `instrument spec configs' vars`.
+	// If the former var had been defined, then the other vars would be ignored.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Resources describes the compute resource requirements.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+}
+
+// PHP defines PHP SDK and instrumentation configuration.
+type PHP struct {
+	// Image is a container image with the PHP extension, SDK and auto-instrumentation.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// VolumeSizeLimit defines size limit for volume used for auto-instrumentation.
+	// The default size is 200Mi.
+	VolumeSizeLimit *resource.Quantity `json:"volumeLimitSize,omitempty"`
+
+	// Env defines PHP specific env vars. There are four layers for env vars' definitions and
+	// the precedence order is: `original container env vars` > `language specific env vars` > `common env vars` > `instrument spec configs' vars`.
+	// If the former var had been defined, then the other vars would be ignored.
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Resources describes the compute resource requirements.
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resourceRequirements,omitempty"`
+}
+
+// InjectionState describes the outcome of the pod webhook for a workload.
+type InjectionState string
+
+const (
+	// InjectionStateInjected means the auto-instrumentation was added to the workload's pods.
+	InjectionStateInjected InjectionState = "Injected"
+	// InjectionStateSkipped means the pod webhook decided not to instrument the workload's pods.
+	InjectionStateSkipped InjectionState = "Skipped"
+	// InjectionStateFailed means the pod webhook could not process the workload's pods.
+	InjectionStateFailed InjectionState = "Failed"
+)
+
 // InstrumentationStatus defines status of the instrumentation.
 type InstrumentationStatus struct {
+	// Injected is the number of workloads whose pods were instrumented using this Instrumentation.
+	// +optional
+	Injected int32 `json:"injected,omitempty"`
+
+	// Skipped is the number of workloads requesting this Instrumentation that the pod webhook decided not to instrument.
+	// +optional
+	Skipped int32 `json:"skipped,omitempty"`
+
+	// Failed is the number of workloads requesting this Instrumentation that the pod webhook could not process.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+
+	// Languages summarizes the workload counts per instrumentation language.
+	// +optional
+	// +listType=map
+	// +listMapKey=language
+	Languages []LanguageCoverage `json:"languages,omitempty"`
+
+	// Workloads lists the workloads requesting this Instrumentation and the outcome of the injection.
+	// +optional
+	Workloads []WorkloadInstrumentationStatus `json:"workloads,omitempty"`
+
+	// LastUpdateTime is the last time the coverage was refreshed.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// LanguageCoverage holds the workload counts for a single instrumentation language.
+type LanguageCoverage struct {
+	// Language is the instrumentation language, e.g. java or python.
+	Language string `json:"language"`
+
+	// Injected is the number of instrumented workloads for the language.
+	// +optional
+	Injected int32 `json:"injected,omitempty"`
+
+	// Skipped is the number of skipped workloads for the language.
+	// +optional
+	Skipped int32 `json:"skipped,omitempty"`
+
+	// Failed is the number of failed workloads for the language.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+}
+
+// WorkloadInstrumentationStatus describes the injection outcome for a single workload and language.
+type WorkloadInstrumentationStatus struct {
+	// Kind is the kind of the workload owning the pods, e.g. Deployment. Pods without an owner are reported as Pod.
+	Kind string `json:"kind"`
+
+	// Namespace of the workload.
+	Namespace string `json:"namespace"`
+
+	// Name of the workload.
+	Name string `json:"name"`
+
+	// Language is the instrumentation language requested by the workload.
+	Language string `json:"language"`
+
+	// State is the outcome of the injection.
+	State InjectionState `json:"state"`
+
+	// Reason explains why the workload was skipped or failed.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Pods is the number of pods of the workload with this outcome.
+	// +optional
+	Pods int32 `json:"pods,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -289,6 +420,8 @@ type InstrumentationStatus struct {
 // +kubebuilder:printcolumn:name="Endpoint",type="string",JSONPath=".spec.exporter.endpoint"
 // +kubebuilder:printcolumn:name="Sampler",type="string",JSONPath=".spec.sampler.type"
 // +kubebuilder:printcolumn:name="Sampler Arg",type="string",JSONPath=".spec.sampler.argument"
+// +kubebuilder:printcolumn:name="Injected",type="integer",JSONPath=".status.injected"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failed"
 // +operator-sdk:csv:customresourcedefinitions:displayName="OpenTelemetry Instrumentation"
 // +operator-sdk:csv:customresourcedefinitions:resources={{Pod,v1}}
 
@@ -175,6 +175,36 @@ func (w InstrumentationWebhook) defaulter(r *Instrumentation) error {
 	if r.Spec.Nginx.ConfigFile == "" {
 		r.Spec.Nginx.ConfigFile = "/etc/nginx/nginx.conf"
 	}
+	if r.Spec.Ruby.Image == "" {
+		r.Spec.Ruby.Image = w.cfg.AutoInstrumentationRubyImage()
+	}
+	if r.Spec.Ruby.Resources.Limits == nil {
+		r.Spec.Ruby.Resources.Limits = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		}
+	}
+	if r.Spec.Ruby.Resources.Requests == nil {
+		r.Spec.Ruby.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("50m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		}
+	}
+	if r.Spec.PHP.Image == "" {
+		r.Spec.PHP.Image = w.cfg.AutoInstrumentationPHPImage()
+	}
+	if r.Spec.PHP.Resources.Limits == nil {
+		r.Spec.PHP.Resources.Limits = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		}
+	}
+	if r.Spec.PHP.Resources.Requests == nil {
+		r.Spec.PHP.Resources.Requests = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("50m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		}
+	}
 	// Set the defaulting annotations
 	if r.Annotations == nil {
 		r.Annotations = map[string]string{}
@@ -186,6 +216,8 @@ func (w InstrumentationWebhook) defaulter(r *Instrumentation) error {
 	r.Annotations[constants.AnnotationDefaultAutoInstrumentationGo] = w.cfg.AutoInstrumentationGoImage()
 	r.Annotations[constants.AnnotationDefaultAutoInstrumentationApacheHttpd] = w.cfg.AutoInstrumentationApacheHttpdImage()
 	r.Annotations[constants.AnnotationDefaultAutoInstrumentationNginx] = w.cfg.AutoInstrumentationNginxImage()
+	r.Annotations[constants.AnnotationDefaultAutoInstrumentationRuby] = w.cfg.AutoInstrumentationRubyImage()
+	r.Annotations[constants.AnnotationDefaultAutoInstrumentationPHP] = w.cfg.AutoInstrumentationPHPImage()
 	return nil
 }
 
@@ -244,6 +276,12 @@ func (w InstrumentationWebhook) validate(r *Instrumentation) (admission.Warnings
 	if err := w.validateEnv(r.Spec.Nginx.Env); err != nil {
 		return warnings, err
 	}
+	if err := w.validateEnv(r.Spec.Ruby.Env); err != nil {
+		return warnings, err
+	}
+	if err := w.validateEnv(r.Spec.PHP.Env); err != nil {
+		return warnings, err
+	}
 	return warnings, nil
 }
 
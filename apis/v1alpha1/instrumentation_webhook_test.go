@@ -23,6 +23,8 @@ func TestInstrumentationDefaultingWebhook(t *testing.T) {
 			config.WithAutoInstrumentationDotNetImage("dotnet-img:1"),
 			config.WithAutoInstrumentationApacheHttpdImage("apache-httpd-img:1"),
 			config.WithAutoInstrumentationNginxImage("nginx-img:1"),
+			config.WithAutoInstrumentationRubyImage("ruby-img:1"),
+			config.WithAutoInstrumentationPHPImage("php-img:1"),
 		),
 	}.Default(context.Background(), inst)
 	assert.NoError(t, err)
@@ -32,6 +34,8 @@ func TestInstrumentationDefaultingWebhook(t *testing.T) {
 	assert.Equal(t, "dotnet-img:1", inst.Spec.DotNet.Image)
 	assert.Equal(t, "apache-httpd-img:1", inst.Spec.ApacheHttpd.Image)
 	assert.Equal(t, "nginx-img:1", inst.Spec.Nginx.Image)
+	assert.Equal(t, "ruby-img:1", inst.Spec.Ruby.Image)
+	assert.Equal(t, "php-img:1", inst.Spec.PHP.Image)
 }
 
 func TestInstrumentationValidatingWebhook(t *testing.T) {
@@ -74,7 +74,8 @@ type NeuronMonitorSpec struct {
 	// +optional
 	// +listType=atomic
 	VolumeMounts []v1.VolumeMount `json:"volumeMounts,omitempty"`
-	// If specified, indicates the pod's scheduling constraints
+	// If specified, indicates the pod's scheduling constraints.
+	// Otherwise the pods are scheduled on the node groups with Neuron resources allocatable.
 	// +optional
 	Affinity *v1.Affinity `json:"affinity,omitempty"`
 }
@@ -103,6 +104,24 @@ type NeuronMonitorStatus struct {
 	// +optional
 	// Deprecated: use "NeuronMonitor.Status.Scale.Replicas" instead.
 	Replicas int32 `json:"replicas,omitempty"`
+
+	// ObservedGeneration is the generation of the NeuronMonitor last reconciled by the operator.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions report whether the NeuronMonitor is reconciled, its config valid and its pods ready.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Rollout is the rollout progress of the NeuronMonitor's daemonSet.
+	// +optional
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// LastReconcileError is the error of the last reconcile, empty if it succeeded.
+	// +optional
+	LastReconcileError string `json:"lastReconcileError,omitempty"`
 }
 
 // +kubebuilder:object:root=true
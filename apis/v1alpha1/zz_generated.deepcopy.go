@@ -256,6 +256,18 @@ func (in *AmazonCloudWatchAgentStatus) DeepCopyInto(out *AmazonCloudWatchAgentSt
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentStatus.
@@ -284,6 +296,7 @@ func (in *AmazonCloudWatchAgentTargetAllocator) DeepCopyInto(out *AmazonCloudWat
 		}
 	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	in.Filter.DeepCopyInto(&out.Filter)
 	if in.Affinity != nil {
 		in, out := &in.Affinity, &out.Affinity
 		*out = new(corev1.Affinity)
@@ -328,6 +341,31 @@ func (in *AmazonCloudWatchAgentTargetAllocator) DeepCopy() *AmazonCloudWatchAgen
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AmazonCloudWatchAgentTargetAllocatorFilter) DeepCopyInto(out *AmazonCloudWatchAgentTargetAllocatorFilter) {
+	*out = *in
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedNamespaces != nil {
+		in, out := &in.DeniedNamespaces, &out.DeniedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentTargetAllocatorFilter.
+func (in *AmazonCloudWatchAgentTargetAllocatorFilter) DeepCopy() *AmazonCloudWatchAgentTargetAllocatorFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(AmazonCloudWatchAgentTargetAllocatorFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AmazonCloudWatchAgentTargetAllocatorPrometheusCR) DeepCopyInto(out *AmazonCloudWatchAgentTargetAllocatorPrometheusCR) {
 	*out = *in
@@ -350,6 +388,16 @@ func (in *AmazonCloudWatchAgentTargetAllocatorPrometheusCR) DeepCopyInto(out *Am
 			(*out)[key] = val
 		}
 	}
+	if in.PodMonitorNamespaceSelector != nil {
+		in, out := &in.PodMonitorNamespaceSelector, &out.PodMonitorNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMonitorNamespaceSelector != nil {
+		in, out := &in.ServiceMonitorNamespaceSelector, &out.ServiceMonitorNamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AmazonCloudWatchAgentTargetAllocatorPrometheusCR.
@@ -397,6 +445,236 @@ func (in *ApacheHttpd) DeepCopy() *ApacheHttpd {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoMonitor) DeepCopyInto(out *AutoMonitor) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	out.TypeMeta = in.TypeMeta
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoMonitor.
+func (in *AutoMonitor) DeepCopy() *AutoMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AutoMonitor) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoMonitorLanguageStatus) DeepCopyInto(out *AutoMonitorLanguageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoMonitorLanguageStatus.
+func (in *AutoMonitorLanguageStatus) DeepCopy() *AutoMonitorLanguageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoMonitorLanguageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoMonitorList) DeepCopyInto(out *AutoMonitorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AutoMonitor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoMonitorList.
+func (in *AutoMonitorList) DeepCopy() *AutoMonitorList {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoMonitorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AutoMonitorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoMonitorMaintenanceWindow) DeepCopyInto(out *AutoMonitorMaintenanceWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoMonitorMaintenanceWindow.
+func (in *AutoMonitorMaintenanceWindow) DeepCopy() *AutoMonitorMaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoMonitorMaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoMonitorResources) DeepCopyInto(out *AutoMonitorResources) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Deployments != nil {
+		in, out := &in.Deployments, &out.Deployments
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DaemonSets != nil {
+		in, out := &in.DaemonSets, &out.DaemonSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StatefulSets != nil {
+		in, out := &in.StatefulSets, &out.StatefulSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WorkloadSelector != nil {
+		in, out := &in.WorkloadSelector, &out.WorkloadSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoMonitorResources.
+func (in *AutoMonitorResources) DeepCopy() *AutoMonitorResources {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoMonitorResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoMonitorRestartStrategy) DeepCopyInto(out *AutoMonitorRestartStrategy) {
+	*out = *in
+	out.PauseBetweenBatches = in.PauseBetweenBatches
+	out.RolloutTimeout = in.RolloutTimeout
+	out.MaintenanceWindow = in.MaintenanceWindow
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoMonitorRestartStrategy.
+func (in *AutoMonitorRestartStrategy) DeepCopy() *AutoMonitorRestartStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoMonitorRestartStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoMonitorSelector) DeepCopyInto(out *AutoMonitorSelector) {
+	*out = *in
+	in.Java.DeepCopyInto(&out.Java)
+	in.Python.DeepCopyInto(&out.Python)
+	in.DotNet.DeepCopyInto(&out.DotNet)
+	in.NodeJS.DeepCopyInto(&out.NodeJS)
+	in.Go.DeepCopyInto(&out.Go)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoMonitorSelector.
+func (in *AutoMonitorSelector) DeepCopy() *AutoMonitorSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoMonitorSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoMonitorSpec) DeepCopyInto(out *AutoMonitorSpec) {
+	*out = *in
+	if in.Languages != nil {
+		in, out := &in.Languages, &out.Languages
+		*out = make([]AutoMonitorLanguage, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestartStrategy != nil {
+		in, out := &in.RestartStrategy, &out.RestartStrategy
+		*out = new(AutoMonitorRestartStrategy)
+		**out = **in
+	}
+	in.Exclude.DeepCopyInto(&out.Exclude)
+	in.CustomSelector.DeepCopyInto(&out.CustomSelector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoMonitorSpec.
+func (in *AutoMonitorSpec) DeepCopy() *AutoMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoMonitorStatus) DeepCopyInto(out *AutoMonitorStatus) {
+	*out = *in
+	if in.Languages != nil {
+		in, out := &in.Languages, &out.Languages
+		*out = make([]AutoMonitorLanguageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoMonitorStatus.
+func (in *AutoMonitorStatus) DeepCopy() *AutoMonitorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoMonitorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoscalerSpec) DeepCopyInto(out *AutoscalerSpec) {
 	*out = *in
@@ -602,6 +880,18 @@ func (in *DcgmExporterStatus) DeepCopyInto(out *DcgmExporterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DcgmExporterStatus.
@@ -723,7 +1013,7 @@ func (in *Ingress) DeepCopy() *Ingress {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Instrumentation) DeepCopyInto(out *Instrumentation) {
 	*out = *in
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	out.TypeMeta = in.TypeMeta
 	in.Spec.DeepCopyInto(&out.Spec)
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -804,6 +1094,8 @@ func (in *InstrumentationSpec) DeepCopyInto(out *InstrumentationSpec) {
 	in.Go.DeepCopyInto(&out.Go)
 	in.ApacheHttpd.DeepCopyInto(&out.ApacheHttpd)
 	in.Nginx.DeepCopyInto(&out.Nginx)
+	in.Ruby.DeepCopyInto(&out.Ruby)
+	in.PHP.DeepCopyInto(&out.PHP)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstrumentationSpec.
@@ -819,6 +1111,20 @@ func (in *InstrumentationSpec) DeepCopy() *InstrumentationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstrumentationStatus) DeepCopyInto(out *InstrumentationStatus) {
 	*out = *in
+	if in.Languages != nil {
+		in, out := &in.Languages, &out.Languages
+		*out = make([]LanguageCoverage, len(*in))
+		copy(*out, *in)
+	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]WorkloadInstrumentationStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstrumentationStatus.
@@ -859,6 +1165,21 @@ func (in *Java) DeepCopy() *Java {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LanguageCoverage) DeepCopyInto(out *LanguageCoverage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LanguageCoverage.
+func (in *LanguageCoverage) DeepCopy() *LanguageCoverage {
+	if in == nil {
+		return nil
+	}
+	out := new(LanguageCoverage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricSpec) DeepCopyInto(out *MetricSpec) {
 	*out = *in
@@ -1042,6 +1363,18 @@ func (in *NeuronMonitorStatus) DeepCopyInto(out *NeuronMonitorStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NeuronMonitorStatus.
@@ -1148,6 +1481,34 @@ func (in *OpenShiftRoute) DeepCopy() *OpenShiftRoute {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PHP) DeepCopyInto(out *PHP) {
+	*out = *in
+	if in.VolumeSizeLimit != nil {
+		in, out := &in.VolumeSizeLimit, &out.VolumeSizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PHP.
+func (in *PHP) DeepCopy() *PHP {
+	if in == nil {
+		return nil
+	}
+	out := new(PHP)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodDisruptionBudgetSpec) DeepCopyInto(out *PodDisruptionBudgetSpec) {
 	*out = *in
@@ -1291,6 +1652,49 @@ func (in *Resource) DeepCopy() *Resource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ruby) DeepCopyInto(out *Ruby) {
+	*out = *in
+	if in.VolumeSizeLimit != nil {
+		in, out := &in.VolumeSizeLimit, &out.VolumeSizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ruby.
+func (in *Ruby) DeepCopy() *Ruby {
+	if in == nil {
+		return nil
+	}
+	out := new(Ruby)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sampler) DeepCopyInto(out *Sampler) {
 	*out = *in
@@ -1320,3 +1724,18 @@ func (in *ScaleSubresourceStatus) DeepCopy() *ScaleSubresourceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadInstrumentationStatus) DeepCopyInto(out *WorkloadInstrumentationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadInstrumentationStatus.
+func (in *WorkloadInstrumentationStatus) DeepCopy() *WorkloadInstrumentationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadInstrumentationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
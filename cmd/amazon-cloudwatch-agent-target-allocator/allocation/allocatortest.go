@@ -24,9 +24,10 @@ func MakeNNewTargets(n int, numCollectors int, startingIndex int) map[string]*ta
 	for i := startingIndex; i < n+startingIndex; i++ {
 		collector := fmt.Sprintf("collector-%d", colIndex(i, numCollectors))
 		label := model.LabelSet{
-			"collector": model.LabelValue(collector),
-			"i":         model.LabelValue(strconv.Itoa(i)),
-			"total":     model.LabelValue(strconv.Itoa(n + startingIndex)),
+			"collector":                       model.LabelValue(collector),
+			"i":                               model.LabelValue(strconv.Itoa(i)),
+			"total":                           model.LabelValue(strconv.Itoa(n + startingIndex)),
+			"__meta_kubernetes_pod_node_name": model.LabelValue(fmt.Sprintf("node-%d", colIndex(i, numCollectors))),
 		}
 		newTarget := target.NewItem(fmt.Sprintf("test-job-%d", i), fmt.Sprintf("test-url-%d", i), label, collector)
 		toReturn[newTarget.Hash()] = newTarget
@@ -40,6 +41,7 @@ func MakeNCollectors(n int, startingIndex int) map[string]*Collector {
 		collector := fmt.Sprintf("collector-%d", i)
 		toReturn[collector] = &Collector{
 			Name:       collector,
+			NodeName:   fmt.Sprintf("node-%d", i),
 			NumTargets: 0,
 		}
 	}
@@ -46,6 +46,8 @@ type consistentHashingAllocator struct {
 	log logr.Logger
 
 	filter Filter
+
+	weights *Weights
 }
 
 func newConsistentHashingAllocator(log logr.Logger, opts ...AllocationOption) Allocator {
@@ -75,6 +77,11 @@ func (c *consistentHashingAllocator) SetFilter(filter Filter) {
 	c.filter = filter
 }
 
+// SetWeights sets the scrape cost of the targets, recorded in the collectors' weight.
+func (c *consistentHashingAllocator) SetWeights(weights *Weights) {
+	c.weights = weights
+}
+
 // addCollectorTargetItemMapping keeps track of which collector has which jobs and targets
 // this allows the allocator to respond without any extra allocations to http calls. The caller of this method
 // has to acquire a lock.
@@ -96,7 +103,7 @@ func (c *consistentHashingAllocator) addCollectorTargetItemMapping(tg *target.It
 // item while it's being encoded by the server JSON handler.
 func (c *consistentHashingAllocator) addTargetToTargetItems(tg *target.Item) {
 	// Check if this is a reassignment, if so, decrement the previous collector's NumTargets
-	if previousColName, ok := c.collectors[tg.CollectorName]; ok {
+	if previousColName, ok := c.collectors[tg.CollectorName]; ok && c.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName][tg.Hash()] {
 		previousColName.NumTargets--
 		delete(c.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName], tg.Hash())
 		TargetsPerCollector.WithLabelValues(previousColName.String(), consistentHashingStrategyName).Set(float64(c.collectors[previousColName.String()].NumTargets))
@@ -147,10 +154,11 @@ func (c *consistentHashingAllocator) handleCollectors(diff diff.Changes[*Collect
 		delete(c.targetItemsPerJobPerCollector, k.Name)
 		c.consistentHasher.Remove(k.Name)
 		TargetsPerCollector.WithLabelValues(k.Name, consistentHashingStrategyName).Set(0)
+		WeightPerCollector.WithLabelValues(k.Name, consistentHashingStrategyName).Set(0)
 	}
 	// Insert the new collectors
 	for _, i := range diff.Additions() {
-		c.collectors[i.Name] = NewCollector(i.Name)
+		c.collectors[i.Name] = NewCollector(i.Name, i.NodeName)
 		c.consistentHasher.Add(c.collectors[i.Name])
 	}
 
@@ -218,6 +226,8 @@ func (c *consistentHashingAllocator) SetTargets(targets map[string]*target.Item)
 	if len(targetsDiff.Additions()) != 0 || len(targetsDiff.Removals()) != 0 {
 		c.handleTargets(targetsDiff)
 	}
+	// The weights reported by the collectors change even if the targets don't
+	setCollectorWeights(consistentHashingStrategyName, c.weights, c.collectors, c.targetItems)
 }
 
 // SetCollectors sets the set of collectors with key=collectorName, value=Collector object.
@@ -239,6 +249,7 @@ func (c *consistentHashingAllocator) SetCollectors(collectors map[string]*Collec
 	collectorsDiff := diff.Maps(c.collectors, collectors)
 	if len(collectorsDiff.Additions()) != 0 || len(collectorsDiff.Removals()) != 0 {
 		c.handleCollectors(collectorsDiff)
+		setCollectorWeights(consistentHashingStrategyName, c.weights, c.collectors, c.targetItems)
 	}
 	c.log.Info("Setting collector completed")
 }
@@ -0,0 +1,284 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package allocation
+
+import (
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+var _ Allocator = &leastWeightedAllocator{}
+
+const leastWeightedStrategyName = "least-weighted"
+
+// leastWeightedAllocator assigns each new target to the collector with the lowest total weight, the scrape cost of
+// its targets. Without weights, every target weighs DefaultWeight and it is the collector with the fewest targets.
+// Targets keep their collector until it goes away, so adding collectors only balances the targets discovered afterwards.
+type leastWeightedAllocator struct {
+	// m protects collectors and targetItems for concurrent use.
+	m sync.RWMutex
+
+	// collectors is a map from a Collector's name to a Collector instance
+	// collectorKey -> collector pointer
+	collectors map[string]*Collector
+
+	// targetItems is a map from a target item's hash to the target items allocated state
+	// targetItem hash -> target item pointer
+	targetItems map[string]*target.Item
+
+	// collectorKey -> job -> target item hash -> true
+	targetItemsPerJobPerCollector map[string]map[string]map[string]bool
+
+	log logr.Logger
+
+	filter Filter
+
+	weights *Weights
+}
+
+func newLeastWeightedAllocator(log logr.Logger, opts ...AllocationOption) Allocator {
+	lwAllocator := &leastWeightedAllocator{
+		collectors:                    make(map[string]*Collector),
+		targetItems:                   make(map[string]*target.Item),
+		targetItemsPerJobPerCollector: make(map[string]map[string]map[string]bool),
+		log:                           log,
+	}
+	for _, opt := range opts {
+		opt(lwAllocator)
+	}
+
+	return lwAllocator
+}
+
+// SetFilter sets the filtering hook to use.
+func (allocator *leastWeightedAllocator) SetFilter(filter Filter) {
+	allocator.filter = filter
+}
+
+// SetWeights sets the scrape cost of the targets the collectors are balanced with.
+func (allocator *leastWeightedAllocator) SetWeights(weights *Weights) {
+	allocator.weights = weights
+}
+
+// isAssigned returns whether the target is assigned to one of the current collectors. The caller of this method has to
+// acquire a lock.
+func (allocator *leastWeightedAllocator) isAssigned(tg *target.Item) bool {
+	return allocator.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName][tg.Hash()]
+}
+
+// addCollectorTargetItemMapping keeps track of which collector has which jobs and targets
+// this allows the allocator to respond without any extra allocations to http calls. The caller of this method
+// has to acquire a lock.
+func (allocator *leastWeightedAllocator) addCollectorTargetItemMapping(tg *target.Item) {
+	if allocator.targetItemsPerJobPerCollector[tg.CollectorName] == nil {
+		allocator.targetItemsPerJobPerCollector[tg.CollectorName] = make(map[string]map[string]bool)
+	}
+	if allocator.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName] == nil {
+		allocator.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName] = make(map[string]bool)
+	}
+	allocator.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName][tg.Hash()] = true
+}
+
+// findNextCollector returns the collector with the lowest weight, ties are broken by number of targets then by name
+// so the allocation is deterministic. The caller of this method has to acquire a lock.
+// INVARIANT: allocator.collectors must have at least 1 collector set.
+func (allocator *leastWeightedAllocator) findNextCollector() *Collector {
+	var next *Collector
+	for _, col := range allocator.collectors {
+		switch {
+		case next == nil, col.Weight < next.Weight:
+			next = col
+		case col.Weight == next.Weight && (col.NumTargets < next.NumTargets || (col.NumTargets == next.NumTargets && col.Name < next.Name)):
+			next = col
+		}
+	}
+	return next
+}
+
+// addTargetToTargetItems assigns a target to the least weighted collector and adds it to the allocator's targetItems
+// A new target already naming one of the collectors, e.g. assigned by a previous leader, keeps its collector.
+// This method is called from within SetTargets and SetCollectors, which acquire the needed lock.
+// INVARIANT: allocator.collectors must have at least 1 collector set.
+func (allocator *leastWeightedAllocator) addTargetToTargetItems(tg *target.Item) {
+	colOwner, ok := allocator.collectors[tg.CollectorName]
+	// Check if this is a reassignment, if so, decrement the previous collector's NumTargets
+	if ok && allocator.isAssigned(tg) {
+		colOwner.NumTargets--
+		colOwner.Weight -= allocator.weights.Weight(tg)
+		delete(allocator.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName], tg.Hash())
+		TargetsPerCollector.WithLabelValues(colOwner.String(), leastWeightedStrategyName).Set(float64(colOwner.NumTargets))
+		colOwner = allocator.findNextCollector()
+	} else if !ok {
+		colOwner = allocator.findNextCollector()
+	}
+	tg.CollectorName = colOwner.Name
+	allocator.targetItems[tg.Hash()] = tg
+	allocator.addCollectorTargetItemMapping(tg)
+	colOwner.NumTargets++
+	colOwner.Weight += allocator.weights.Weight(tg)
+	TargetsPerCollector.WithLabelValues(colOwner.String(), leastWeightedStrategyName).Set(float64(colOwner.NumTargets))
+}
+
+// handleTargets receives the new and removed targets and reconciles the current state.
+// Any removals are removed from the allocator's targetItems and unassigned from the corresponding collector.
+// Any net-new additions are assigned to the least weighted collector.
+func (allocator *leastWeightedAllocator) handleTargets(diff diff.Changes[*target.Item]) {
+	// Start from the current weights, the ones reported by the collectors change over time
+	setCollectorWeights(leastWeightedStrategyName, allocator.weights, allocator.collectors, allocator.targetItems)
+
+	// Check for removals
+	for k, item := range allocator.targetItems {
+		// if the current item is in the removals list
+		if _, ok := diff.Removals()[k]; ok {
+			if col, found := allocator.collectors[item.CollectorName]; found && allocator.isAssigned(item) {
+				col.NumTargets--
+				col.Weight -= allocator.weights.Weight(item)
+				delete(allocator.targetItemsPerJobPerCollector[item.CollectorName][item.JobName], item.Hash())
+				TargetsPerCollector.WithLabelValues(item.CollectorName, leastWeightedStrategyName).Set(float64(col.NumTargets))
+			}
+			delete(allocator.targetItems, k)
+		}
+	}
+
+	// Check for additions
+	for k, item := range diff.Additions() {
+		// Do nothing if the item is already there
+		if _, ok := allocator.targetItems[k]; ok {
+			continue
+		}
+		// Add item to item pool and assign a collector
+		allocator.addTargetToTargetItems(item)
+	}
+}
+
+// handleCollectors receives the new and removed collectors and reconciles the current state.
+// Any removals are removed from the allocator's collectors. New collectors are added to the allocator's collector map.
+// Finally, the targets that aren't assigned to any collector anymore are assigned to the least weighted collectors.
+func (allocator *leastWeightedAllocator) handleCollectors(diff diff.Changes[*Collector]) {
+	// Clear removed collectors
+	for _, k := range diff.Removals() {
+		delete(allocator.collectors, k.Name)
+		delete(allocator.targetItemsPerJobPerCollector, k.Name)
+		TargetsPerCollector.WithLabelValues(k.Name, leastWeightedStrategyName).Set(0)
+		WeightPerCollector.WithLabelValues(k.Name, leastWeightedStrategyName).Set(0)
+	}
+	// Insert the new collectors
+	for _, i := range diff.Additions() {
+		allocator.collectors[i.Name] = NewCollector(i.Name, i.NodeName)
+	}
+	setCollectorWeights(leastWeightedStrategyName, allocator.weights, allocator.collectors, allocator.targetItems)
+
+	// Re-Allocate the targets of the removed collectors and the ones discovered before any collector
+	for _, item := range allocator.targetItems {
+		if !allocator.isAssigned(item) {
+			allocator.addTargetToTargetItems(item)
+		}
+	}
+}
+
+// SetTargets accepts a list of targets that will be used to make
+// load balancing decisions. This method should be called when there are
+// new targets discovered or existing targets are shutdown.
+func (allocator *leastWeightedAllocator) SetTargets(targets map[string]*target.Item) {
+	timer := prometheus.NewTimer(TimeToAssign.WithLabelValues("SetTargets", leastWeightedStrategyName))
+	defer timer.ObserveDuration()
+
+	if allocator.filter != nil {
+		targets = allocator.filter.Apply(targets)
+	}
+	RecordTargetsKept(targets)
+
+	allocator.m.Lock()
+	defer allocator.m.Unlock()
+
+	if len(allocator.collectors) == 0 {
+		allocator.log.Info("No collector instances present, saving targets to allocate to collector(s)")
+		// Keep the targets as they are, they are assigned once collectors are set
+		targetsDiffEmptyCollectorSet := diff.Maps(allocator.targetItems, targets)
+		for k := range targetsDiffEmptyCollectorSet.Removals() {
+			delete(allocator.targetItems, k)
+		}
+		for k, item := range targetsDiffEmptyCollectorSet.Additions() {
+			allocator.targetItems[k] = item
+		}
+		return
+	}
+	// Check for target changes
+	targetsDiff := diff.Maps(allocator.targetItems, targets)
+	// If there are any additions or removals
+	if len(targetsDiff.Additions()) != 0 || len(targetsDiff.Removals()) != 0 {
+		allocator.handleTargets(targetsDiff)
+	}
+	// The weights reported by the collectors change even if the targets don't
+	setCollectorWeights(leastWeightedStrategyName, allocator.weights, allocator.collectors, allocator.targetItems)
+}
+
+// SetCollectors sets the set of collectors with key=collectorName, value=Collector object.
+// This method is called when Collectors are added or removed.
+func (allocator *leastWeightedAllocator) SetCollectors(collectors map[string]*Collector) {
+	timer := prometheus.NewTimer(TimeToAssign.WithLabelValues("SetCollectors", leastWeightedStrategyName))
+	defer timer.ObserveDuration()
+
+	CollectorsAllocatable.WithLabelValues(leastWeightedStrategyName).Set(float64(len(collectors)))
+	if len(collectors) == 0 {
+		allocator.log.Info("No collector instances present")
+		return
+	}
+
+	allocator.m.Lock()
+	defer allocator.m.Unlock()
+
+	// Check for collector changes
+	collectorsDiff := diff.Maps(allocator.collectors, collectors)
+	if len(collectorsDiff.Additions()) != 0 || len(collectorsDiff.Removals()) != 0 {
+		allocator.handleCollectors(collectorsDiff)
+		setCollectorWeights(leastWeightedStrategyName, allocator.weights, allocator.collectors, allocator.targetItems)
+	}
+	allocator.log.Info("Setting collector completed")
+}
+
+func (allocator *leastWeightedAllocator) GetTargetsForCollectorAndJob(collector string, job string) []*target.Item {
+	allocator.m.RLock()
+	defer allocator.m.RUnlock()
+	if _, ok := allocator.targetItemsPerJobPerCollector[collector]; !ok {
+		return []*target.Item{}
+	}
+	if _, ok := allocator.targetItemsPerJobPerCollector[collector][job]; !ok {
+		return []*target.Item{}
+	}
+	targetItemsCopy := make([]*target.Item, len(allocator.targetItemsPerJobPerCollector[collector][job]))
+	index := 0
+	for targetHash := range allocator.targetItemsPerJobPerCollector[collector][job] {
+		targetItemsCopy[index] = allocator.targetItems[targetHash]
+		index++
+	}
+	return targetItemsCopy
+}
+
+// TargetItems returns a shallow copy of the targetItems map.
+func (allocator *leastWeightedAllocator) TargetItems() map[string]*target.Item {
+	allocator.m.RLock()
+	defer allocator.m.RUnlock()
+	targetItemsCopy := make(map[string]*target.Item)
+	for k, v := range allocator.targetItems {
+		targetItemsCopy[k] = v
+	}
+	return targetItemsCopy
+}
+
+// Collectors returns a shallow copy of the collectors map.
+func (allocator *leastWeightedAllocator) Collectors() map[string]*Collector {
+	allocator.m.RLock()
+	defer allocator.m.RUnlock()
+	collectorsCopy := make(map[string]*Collector)
+	for k, v := range allocator.collectors {
+		collectorsCopy[k] = v
+	}
+	return collectorsCopy
+}
@@ -0,0 +1,160 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package allocation
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+func TestLeastWeightedCanSetSingleTarget(t *testing.T) {
+	cols := MakeNCollectors(3, 0)
+	lw := newLeastWeightedAllocator(logger)
+	lw.SetCollectors(cols)
+	lw.SetTargets(MakeNNewTargets(1, 3, 0))
+	actualTargetItems := lw.TargetItems()
+	assert.Len(t, actualTargetItems, 1)
+	for _, item := range actualTargetItems {
+		assert.Equal(t, "collector-0", item.CollectorName)
+	}
+}
+
+func TestLeastWeightedEvenDistribution(t *testing.T) {
+	numCols := 15
+	numItems := 10000
+	lw := newLeastWeightedAllocator(logger)
+	lw.SetCollectors(MakeNCollectors(numCols, 0))
+	lw.SetTargets(MakeNNewTargets(numItems, 0, 0))
+	assert.Len(t, lw.TargetItems(), numItems)
+	actualCollectors := lw.Collectors()
+	assert.Len(t, actualCollectors, numCols)
+	for _, col := range actualCollectors {
+		assert.InDelta(t, numItems/numCols, col.NumTargets, 1)
+	}
+}
+
+func TestLeastWeightedNewTargetsGoToTheLightestCollector(t *testing.T) {
+	lw := newLeastWeightedAllocator(logger)
+	targets := MakeNNewTargets(4, 0, 0)
+	lw.SetCollectors(MakeNCollectors(2, 0))
+	lw.SetTargets(targets)
+
+	// a new collector gets the new targets until it catches up with the others
+	lw.SetCollectors(MakeNCollectors(3, 0))
+	previous := lw.TargetItems()
+	for hash, item := range MakeNNewTargets(2, 0, 4) {
+		targets[hash] = item
+	}
+	lw.SetTargets(targets)
+	for hash, item := range lw.TargetItems() {
+		if previousItem, ok := previous[hash]; ok {
+			assert.Equal(t, previousItem.CollectorName, item.CollectorName, "assigned targets must not move")
+		} else {
+			assert.Equal(t, "collector-2", item.CollectorName)
+		}
+	}
+	for _, col := range lw.Collectors() {
+		assert.Equal(t, 2, col.NumTargets)
+	}
+}
+
+func TestLeastWeightedKeepsAssignedCollectors(t *testing.T) {
+	lw := newLeastWeightedAllocator(logger)
+	lw.SetCollectors(MakeNCollectors(3, 0))
+	// the targets are assigned to collector-0 to collector-5, as if by a previous leader
+	targets := MakeNNewTargets(6, 6, 0)
+	lw.SetTargets(targets)
+
+	for hash, item := range lw.TargetItems() {
+		previous := string(targets[hash].Labels["collector"])
+		if _, ok := lw.Collectors()[previous]; ok {
+			assert.Equal(t, previous, item.CollectorName, "targets of current collectors must keep them")
+		}
+	}
+	total := 0
+	for _, col := range lw.Collectors() {
+		assert.Equal(t, col.NumTargets, col.Weight)
+		total += col.NumTargets
+	}
+	assert.Equal(t, 6, total)
+}
+
+func TestLeastWeightedCollectorRemoval(t *testing.T) {
+	lw := newLeastWeightedAllocator(logger)
+	lw.SetCollectors(MakeNCollectors(3, 0))
+	lw.SetTargets(MakeNNewTargets(9, 3, 0))
+
+	lw.SetCollectors(MakeNCollectors(2, 0))
+	actualCollectors := lw.Collectors()
+	assert.Len(t, actualCollectors, 2)
+	for _, item := range lw.TargetItems() {
+		_, ok := actualCollectors[item.CollectorName]
+		assert.True(t, ok, "Some items weren't reallocated correctly")
+	}
+	total := 0
+	for name, col := range actualCollectors {
+		assert.InDelta(t, 4.5, col.NumTargets, 0.5)
+		total += col.NumTargets
+		var jobTargets int
+		for _, item := range lw.TargetItems() {
+			if item.CollectorName == name {
+				jobTargets += len(lw.GetTargetsForCollectorAndJob(name, item.JobName))
+			}
+		}
+		assert.Equal(t, col.NumTargets, jobTargets)
+	}
+	assert.Equal(t, 9, total)
+}
+
+func TestTargetsWithNoCollectorsLeastWeighted(t *testing.T) {
+	lw := newLeastWeightedAllocator(logger)
+
+	// Adding 10 new targets
+	lw.SetTargets(MakeNNewTargetsWithEmptyCollectors(10, 0))
+	assert.Len(t, lw.TargetItems(), 10)
+
+	// Adding 5 new targets, and removing the old 10 targets
+	lw.SetTargets(MakeNNewTargetsWithEmptyCollectors(5, 10))
+	assert.Len(t, lw.TargetItems(), 5)
+
+	// Adding collectors to test allocation
+	lw.SetCollectors(MakeNCollectors(2, 0))
+	assert.Len(t, lw.TargetItems(), 5)
+	actualCollectors := lw.Collectors()
+	assert.Len(t, actualCollectors, 2)
+	for _, col := range actualCollectors {
+		assert.InDelta(t, 2.5, col.NumTargets, 0.5)
+	}
+}
+
+func TestLeastWeightedBalancesWeights(t *testing.T) {
+	heavy := target.NewItem("kube-state-metrics", "ksm:8080", model.LabelSet{target.WeightLabel: "100"}, "")
+	targets := map[string]*target.Item{heavy.Hash(): heavy}
+	lw := newLeastWeightedAllocator(logger)
+	lw.SetCollectors(MakeNCollectors(2, 0))
+	lw.SetTargets(targets)
+
+	for hash, item := range MakeNNewTargets(10, 0, 0) {
+		targets[hash] = item
+	}
+	lw.SetTargets(targets)
+	for _, item := range lw.TargetItems() {
+		if item != heavy {
+			assert.NotEqual(t, heavy.CollectorName, item.CollectorName, "the light targets must go to the other collector")
+		}
+	}
+	collectors := lw.Collectors()
+	assert.Equal(t, 100, collectors[heavy.CollectorName].Weight)
+	assert.Equal(t, 1, collectors[heavy.CollectorName].NumTargets)
+	for name, col := range collectors {
+		if name != heavy.CollectorName {
+			assert.Equal(t, 10, col.Weight)
+			assert.Equal(t, 10, col.NumTargets)
+		}
+	}
+}
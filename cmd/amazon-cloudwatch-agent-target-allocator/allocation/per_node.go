@@ -0,0 +1,289 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package allocation
+
+import (
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/diff"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+var _ Allocator = &perNodeAllocator{}
+
+const perNodeStrategyName = "per-node"
+
+// perNodeAllocator assigns each target to the collector running on the node of the target, as told by its
+// __meta_kubernetes_pod_node_name label. It is meant for collectors deployed as a DaemonSet. The targets without a
+// node, or whose node runs no collector, are kept unassigned until a collector shows up on their node.
+type perNodeAllocator struct {
+	// m protects collectors and targetItems for concurrent use.
+	m sync.RWMutex
+
+	// collectors is a map from a Collector's name to a Collector instance
+	// collectorKey -> collector pointer
+	collectors map[string]*Collector
+
+	// collectorsByNode is a map from a node name to the Collector running on it
+	// nodeName -> collector pointer
+	collectorsByNode map[string]*Collector
+
+	// targetItems is a map from a target item's hash to the target items allocated state
+	// targetItem hash -> target item pointer
+	targetItems map[string]*target.Item
+
+	// collectorKey -> job -> target item hash -> true
+	targetItemsPerJobPerCollector map[string]map[string]map[string]bool
+
+	// unassigned is the number of targetItems not assigned to any collector
+	unassigned int
+
+	log logr.Logger
+
+	filter Filter
+
+	weights *Weights
+}
+
+func newPerNodeAllocator(log logr.Logger, opts ...AllocationOption) Allocator {
+	pnAllocator := &perNodeAllocator{
+		collectors:                    make(map[string]*Collector),
+		collectorsByNode:              make(map[string]*Collector),
+		targetItems:                   make(map[string]*target.Item),
+		targetItemsPerJobPerCollector: make(map[string]map[string]map[string]bool),
+		log:                           log,
+	}
+	for _, opt := range opts {
+		opt(pnAllocator)
+	}
+
+	return pnAllocator
+}
+
+// SetFilter sets the filtering hook to use.
+func (allocator *perNodeAllocator) SetFilter(filter Filter) {
+	allocator.filter = filter
+}
+
+// SetWeights sets the scrape cost of the targets, recorded in the collectors' weight.
+func (allocator *perNodeAllocator) SetWeights(weights *Weights) {
+	allocator.weights = weights
+}
+
+// addCollectorTargetItemMapping keeps track of which collector has which jobs and targets
+// this allows the allocator to respond without any extra allocations to http calls. The caller of this method
+// has to acquire a lock.
+func (allocator *perNodeAllocator) addCollectorTargetItemMapping(tg *target.Item) {
+	if allocator.targetItemsPerJobPerCollector[tg.CollectorName] == nil {
+		allocator.targetItemsPerJobPerCollector[tg.CollectorName] = make(map[string]map[string]bool)
+	}
+	if allocator.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName] == nil {
+		allocator.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName] = make(map[string]bool)
+	}
+	allocator.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName][tg.Hash()] = true
+}
+
+// addTargetToTargetItems assigns a target to the collector of its node and adds it to the allocator's targetItems.
+// This method is called from within SetTargets and SetCollectors, which acquire the needed lock.
+// This is only called after the collector assignments are cleared or when a new target has been found.
+func (allocator *perNodeAllocator) addTargetToTargetItems(tg *target.Item) {
+	allocator.targetItems[tg.Hash()] = tg
+	colOwner, ok := allocator.collectorsByNode[tg.GetNodeName()]
+	if !ok {
+		tg.CollectorName = ""
+		allocator.unassigned++
+		allocator.log.V(2).Info("Unable to find a collector on the node of the target", "job", tg.JobName, "target", tg.TargetURL, "node", tg.GetNodeName())
+		return
+	}
+	tg.CollectorName = colOwner.Name
+	allocator.addCollectorTargetItemMapping(tg)
+	colOwner.NumTargets++
+}
+
+// removeTargetFromTargetItems removes a target from the allocator's targetItems and unassigns it from its collector.
+// The caller of this method has to acquire a lock.
+func (allocator *perNodeAllocator) removeTargetFromTargetItems(tg *target.Item) {
+	delete(allocator.targetItems, tg.Hash())
+	col, ok := allocator.collectors[tg.CollectorName]
+	if !ok {
+		allocator.unassigned--
+		return
+	}
+	col.NumTargets--
+	delete(allocator.targetItemsPerJobPerCollector[tg.CollectorName][tg.JobName], tg.Hash())
+}
+
+// recordTargets records the number of targets of each collector and of unassigned targets.
+// The caller of this method has to acquire a lock.
+func (allocator *perNodeAllocator) recordTargets() {
+	for _, col := range allocator.collectors {
+		TargetsPerCollector.WithLabelValues(col.String(), perNodeStrategyName).Set(float64(col.NumTargets))
+	}
+	TargetsUnassigned.WithLabelValues(perNodeStrategyName).Set(float64(allocator.unassigned))
+	setCollectorWeights(perNodeStrategyName, allocator.weights, allocator.collectors, allocator.targetItems)
+}
+
+// handleTargets receives the new and removed targets and reconciles the current state.
+// Any removals are removed from the allocator's targetItems and unassigned from the corresponding collector.
+// Any net-new additions are assigned to the collector of their node.
+func (allocator *perNodeAllocator) handleTargets(diff diff.Changes[*target.Item]) {
+	// Check for removals
+	for k, item := range allocator.targetItems {
+		// if the current item is in the removals list
+		if _, ok := diff.Removals()[k]; ok {
+			allocator.removeTargetFromTargetItems(item)
+		}
+	}
+
+	// Check for additions
+	for k, item := range diff.Additions() {
+		// Do nothing if the item is already there
+		if _, ok := allocator.targetItems[k]; ok {
+			continue
+		}
+		// Add item to item pool and assign a collector
+		allocator.addTargetToTargetItems(item)
+	}
+	allocator.recordTargets()
+}
+
+// handleCollectors replaces the allocator's collectors with the given ones and reassigns all targets to the collector
+// of their node.
+func (allocator *perNodeAllocator) handleCollectors(collectors map[string]*Collector) {
+	for name := range allocator.collectors {
+		if _, ok := collectors[name]; !ok {
+			TargetsPerCollector.WithLabelValues(name, perNodeStrategyName).Set(0)
+			WeightPerCollector.WithLabelValues(name, perNodeStrategyName).Set(0)
+		}
+	}
+	allocator.collectors = make(map[string]*Collector, len(collectors))
+	allocator.collectorsByNode = make(map[string]*Collector, len(collectors))
+	allocator.targetItemsPerJobPerCollector = make(map[string]map[string]map[string]bool, len(collectors))
+	for _, i := range collectors {
+		col := NewCollector(i.Name, i.NodeName)
+		allocator.collectors[col.Name] = col
+		if col.NodeName == "" {
+			continue
+		}
+		if other, ok := allocator.collectorsByNode[col.NodeName]; ok {
+			allocator.log.Info("More than one collector on the node, targets are assigned to one of them", "node", col.NodeName, "collectors", []string{other.Name, col.Name})
+			if other.Name < col.Name {
+				continue
+			}
+		}
+		allocator.collectorsByNode[col.NodeName] = col
+	}
+
+	// Re-Allocate all targets
+	allocator.unassigned = 0
+	for _, item := range allocator.targetItems {
+		allocator.addTargetToTargetItems(item)
+	}
+	allocator.recordTargets()
+}
+
+// collectorsChanged returns whether collectors were added, removed or moved to another node.
+// The caller of this method has to acquire a lock.
+func (allocator *perNodeAllocator) collectorsChanged(collectors map[string]*Collector) bool {
+	collectorsDiff := diff.Maps(allocator.collectors, collectors)
+	if len(collectorsDiff.Additions()) != 0 || len(collectorsDiff.Removals()) != 0 {
+		return true
+	}
+	for name, col := range collectors {
+		if allocator.collectors[name].NodeName != col.NodeName {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTargets accepts a list of targets that will be used to make
+// load balancing decisions. This method should be called when there are
+// new targets discovered or existing targets are shutdown.
+func (allocator *perNodeAllocator) SetTargets(targets map[string]*target.Item) {
+	timer := prometheus.NewTimer(TimeToAssign.WithLabelValues("SetTargets", perNodeStrategyName))
+	defer timer.ObserveDuration()
+
+	if allocator.filter != nil {
+		targets = allocator.filter.Apply(targets)
+	}
+	RecordTargetsKept(targets)
+
+	allocator.m.Lock()
+	defer allocator.m.Unlock()
+
+	// Check for target changes, the targets without a collector on their node are kept unassigned
+	targetsDiff := diff.Maps(allocator.targetItems, targets)
+	// If there are any additions or removals
+	if len(targetsDiff.Additions()) != 0 || len(targetsDiff.Removals()) != 0 {
+		allocator.handleTargets(targetsDiff)
+	} else {
+		// The weights reported by the collectors change even if the targets don't
+		setCollectorWeights(perNodeStrategyName, allocator.weights, allocator.collectors, allocator.targetItems)
+	}
+}
+
+// SetCollectors sets the set of collectors with key=collectorName, value=Collector object.
+// This method is called when Collectors are added, removed or scheduled on a node.
+func (allocator *perNodeAllocator) SetCollectors(collectors map[string]*Collector) {
+	timer := prometheus.NewTimer(TimeToAssign.WithLabelValues("SetCollectors", perNodeStrategyName))
+	defer timer.ObserveDuration()
+
+	CollectorsAllocatable.WithLabelValues(perNodeStrategyName).Set(float64(len(collectors)))
+	if len(collectors) == 0 {
+		allocator.log.Info("No collector instances present")
+		return
+	}
+
+	allocator.m.Lock()
+	defer allocator.m.Unlock()
+
+	if allocator.collectorsChanged(collectors) {
+		allocator.handleCollectors(collectors)
+	}
+	allocator.log.Info("Setting collector completed")
+}
+
+func (allocator *perNodeAllocator) GetTargetsForCollectorAndJob(collector string, job string) []*target.Item {
+	allocator.m.RLock()
+	defer allocator.m.RUnlock()
+	if _, ok := allocator.targetItemsPerJobPerCollector[collector]; !ok {
+		return []*target.Item{}
+	}
+	if _, ok := allocator.targetItemsPerJobPerCollector[collector][job]; !ok {
+		return []*target.Item{}
+	}
+	targetItemsCopy := make([]*target.Item, len(allocator.targetItemsPerJobPerCollector[collector][job]))
+	index := 0
+	for targetHash := range allocator.targetItemsPerJobPerCollector[collector][job] {
+		targetItemsCopy[index] = allocator.targetItems[targetHash]
+		index++
+	}
+	return targetItemsCopy
+}
+
+// TargetItems returns a shallow copy of the targetItems map.
+func (allocator *perNodeAllocator) TargetItems() map[string]*target.Item {
+	allocator.m.RLock()
+	defer allocator.m.RUnlock()
+	targetItemsCopy := make(map[string]*target.Item)
+	for k, v := range allocator.targetItems {
+		targetItemsCopy[k] = v
+	}
+	return targetItemsCopy
+}
+
+// Collectors returns a shallow copy of the collectors map.
+func (allocator *perNodeAllocator) Collectors() map[string]*Collector {
+	allocator.m.RLock()
+	defer allocator.m.RUnlock()
+	collectorsCopy := make(map[string]*Collector)
+	for k, v := range allocator.collectors {
+		collectorsCopy[k] = v
+	}
+	return collectorsCopy
+}
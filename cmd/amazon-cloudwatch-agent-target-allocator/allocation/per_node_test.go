@@ -0,0 +1,74 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package allocation
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+func TestPerNodeAllocation(t *testing.T) {
+	pn := newPerNodeAllocator(logger)
+	pn.SetCollectors(MakeNCollectors(3, 0))
+	pn.SetTargets(MakeNNewTargets(30, 3, 0))
+
+	actualTargetItems := pn.TargetItems()
+	assert.Len(t, actualTargetItems, 30)
+	for _, item := range actualTargetItems {
+		assert.Equal(t, item.Labels["collector"], model.LabelValue(item.CollectorName))
+	}
+	for _, col := range pn.Collectors() {
+		assert.Equal(t, 10, col.NumTargets)
+	}
+}
+
+func TestPerNodeUnassignedTargets(t *testing.T) {
+	noNode := target.NewItem("test-job", "test-url-no-node", model.LabelSet{}, "")
+	otherNode := target.NewItem("test-job", "test-url-other-node", model.LabelSet{"__meta_kubernetes_pod_node_name": "node-9"}, "")
+	onNode := target.NewItem("test-job", "test-url-node", model.LabelSet{"__meta_kubernetes_pod_node_name": "node-0"}, "")
+	targets := map[string]*target.Item{noNode.Hash(): noNode, otherNode.Hash(): otherNode, onNode.Hash(): onNode}
+
+	pn := newPerNodeAllocator(logger)
+	pn.SetCollectors(MakeNCollectors(1, 0))
+	pn.SetTargets(targets)
+
+	assert.Len(t, pn.TargetItems(), 3)
+	assert.Equal(t, []*target.Item{onNode}, pn.GetTargetsForCollectorAndJob("collector-0", "test-job"))
+	assert.Equal(t, 1, pn.Collectors()["collector-0"].NumTargets)
+	assert.Empty(t, noNode.CollectorName)
+	assert.Empty(t, otherNode.CollectorName)
+
+	// the target is assigned once a collector runs on its node
+	pn.SetCollectors(map[string]*Collector{
+		"collector-0": NewCollector("collector-0", "node-0"),
+		"collector-9": NewCollector("collector-9", "node-9"),
+	})
+	assert.Equal(t, "collector-9", otherNode.CollectorName)
+	assert.Equal(t, []*target.Item{otherNode}, pn.GetTargetsForCollectorAndJob("collector-9", "test-job"))
+	assert.Empty(t, noNode.CollectorName)
+
+	// removing a target unassigns it from its collector
+	delete(targets, onNode.Hash())
+	pn.SetTargets(targets)
+	assert.Empty(t, pn.GetTargetsForCollectorAndJob("collector-0", "test-job"))
+	assert.Equal(t, 0, pn.Collectors()["collector-0"].NumTargets)
+}
+
+func TestPerNodeCollectorScheduled(t *testing.T) {
+	pn := newPerNodeAllocator(logger)
+	// collectors are discovered before being scheduled on a node
+	pn.SetCollectors(map[string]*Collector{"collector-0": NewCollector("collector-0", "")})
+	pn.SetTargets(MakeNNewTargets(3, 1, 0))
+	assert.Equal(t, 0, pn.Collectors()["collector-0"].NumTargets)
+
+	pn.SetCollectors(MakeNCollectors(1, 0))
+	assert.Equal(t, 3, pn.Collectors()["collector-0"].NumTargets)
+	for _, item := range pn.TargetItems() {
+		assert.Equal(t, "collector-0", item.CollectorName)
+	}
+}
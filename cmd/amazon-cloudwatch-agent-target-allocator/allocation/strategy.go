@@ -26,6 +26,11 @@ var (
 		Name: "cloudwatch_agent_allocator_targets_per_collector",
 		Help: "The number of targets for each collector.",
 	}, []string{"collector_name", "strategy"})
+	// WeightPerCollector records the total weight, in number of series, of the targets assigned to each collector.
+	WeightPerCollector = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cloudwatch_agent_allocator_weight_per_collector",
+		Help: "The total weight of the targets for each collector.",
+	}, []string{"collector_name", "strategy"})
 	CollectorsAllocatable = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "cloudwatch_agent_allocator_collectors_allocatable",
 		Help: "Number of collectors the allocator is able to allocate to.",
@@ -34,6 +39,10 @@ var (
 		Name: "cloudwatch_agent_allocator_time_to_allocate",
 		Help: "The time it takes to allocate",
 	}, []string{"method", "strategy"})
+	TargetsUnassigned = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cloudwatch_agent_allocator_targets_unassigned",
+		Help: "Number of targets the allocator could not assign to any collector.",
+	}, []string{"strategy"})
 	targetsRemaining = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "cloudwatch_agent_allocator_targets_remaining",
 		Help: "Number of targets kept after filtering.",
@@ -52,6 +61,13 @@ func WithFilter(filter Filter) AllocationOption {
 	}
 }
 
+// WithWeights sets the scrape cost of the targets the allocator balances the collectors with.
+func WithWeights(weights *Weights) AllocationOption {
+	return func(allocator Allocator) {
+		allocator.SetWeights(weights)
+	}
+}
+
 func RecordTargetsKept(targets map[string]*target.Item) {
 	targetsRemaining.Add(float64(len(targets)))
 }
@@ -86,6 +102,7 @@ type Allocator interface {
 	Collectors() map[string]*Collector
 	GetTargetsForCollectorAndJob(collector string, job string) []*target.Item
 	SetFilter(filter Filter)
+	SetWeights(weights *Weights)
 }
 
 var _ consistent.Member = Collector{}
@@ -95,7 +112,10 @@ var _ consistent.Member = Collector{}
 // This struct can be extended with information like annotations and labels in the future.
 type Collector struct {
 	Name       string
+	NodeName   string
 	NumTargets int
+	// Weight is the total weight of the targets assigned to the collector.
+	Weight int
 }
 
 func (c Collector) Hash() string {
@@ -106,8 +126,8 @@ func (c Collector) String() string {
 	return c.Name
 }
 
-func NewCollector(name string) *Collector {
-	return &Collector{Name: name}
+func NewCollector(name, node string) *Collector {
+	return &Collector{Name: name, NodeName: node}
 }
 
 func init() {
@@ -115,4 +135,12 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+	err = Register(leastWeightedStrategyName, newLeastWeightedAllocator)
+	if err != nil {
+		panic(err)
+	}
+	err = Register(perNodeStrategyName, newPerNodeAllocator)
+	if err != nil {
+		panic(err)
+	}
 }
@@ -0,0 +1,126 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package allocation
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+const (
+	// DefaultWeight is the weight of the targets whose scrape cost is neither set nor reported.
+	DefaultWeight = 1
+
+	// learnedWeightTTL is how long the series count reported for a target is used without being reported again.
+	learnedWeightTTL = 10 * time.Minute
+
+	// maxLearnedWeights is the most targets whose series count is recorded, the counts reported for more targets
+	// are ignored until recorded ones expire.
+	maxLearnedWeights = 100000
+)
+
+// SeriesCount is the number of series a collector scraped from a target during its last scrape.
+type SeriesCount struct {
+	Job    string `json:"job"`
+	Target string `json:"target"`
+	Series int    `json:"series"`
+}
+
+type learnedWeight struct {
+	weight    int
+	updatedAt time.Time
+}
+
+// Weights tracks the scrape cost of the targets, in number of series. The weight of a target is, in order of
+// precedence, the series count its collector last reported, the weight set by its labels or DefaultWeight.
+// A nil *Weights only uses the weights set by the target labels.
+type Weights struct {
+	// m protects learned for concurrent use.
+	m sync.RWMutex
+
+	// learned is a map from a target's job and URL to the series count reported for it
+	learned map[string]learnedWeight
+	// maxLearned is the most targets whose series count is recorded
+	maxLearned int
+
+	now func() time.Time
+}
+
+func NewWeights() *Weights {
+	return &Weights{
+		learned:    make(map[string]learnedWeight),
+		maxLearned: maxLearnedWeights,
+		now:        time.Now,
+	}
+}
+
+func weightKey(job, targetURL string) string {
+	return job + "\x00" + targetURL
+}
+
+// Weight returns the scrape cost of the target.
+func (w *Weights) Weight(item *target.Item) int {
+	if w != nil {
+		w.m.RLock()
+		learned, ok := w.learned[weightKey(item.JobName, strings.Join(item.TargetURL, ""))]
+		w.m.RUnlock()
+		if ok && w.now().Sub(learned.updatedAt) < learnedWeightTTL {
+			return learned.weight
+		}
+	}
+	if weight, ok := item.ConfiguredWeight(); ok {
+		return weight
+	}
+	return DefaultWeight
+}
+
+// Record records the series counts reported by a collector for the target items and forgets the ones that expired.
+// The counts reported for other targets are ignored.
+func (w *Weights) Record(counts []SeriesCount, targetItems map[string]*target.Item) {
+	known := make(map[string]struct{}, len(targetItems))
+	for _, item := range targetItems {
+		known[weightKey(item.JobName, strings.Join(item.TargetURL, ""))] = struct{}{}
+	}
+
+	w.m.Lock()
+	defer w.m.Unlock()
+	now := w.now()
+	for key, learned := range w.learned {
+		if now.Sub(learned.updatedAt) >= learnedWeightTTL {
+			delete(w.learned, key)
+		}
+	}
+	for _, count := range counts {
+		if count.Series <= 0 {
+			continue
+		}
+		key := weightKey(count.Job, count.Target)
+		if _, ok := known[key]; !ok {
+			continue
+		}
+		if _, ok := w.learned[key]; !ok && len(w.learned) >= w.maxLearned {
+			continue
+		}
+		w.learned[key] = learnedWeight{weight: count.Series, updatedAt: now}
+	}
+}
+
+// setCollectorWeights sets the weight of the collectors to the total weight of the targets assigned to them and
+// records it. The caller of this method has to acquire a lock.
+func setCollectorWeights(strategy string, weights *Weights, collectors map[string]*Collector, targetItems map[string]*target.Item) {
+	for _, col := range collectors {
+		col.Weight = 0
+	}
+	for _, item := range targetItems {
+		if col, ok := collectors[item.CollectorName]; ok {
+			col.Weight += weights.Weight(item)
+		}
+	}
+	for _, col := range collectors {
+		WeightPerCollector.WithLabelValues(col.String(), strategy).Set(float64(col.Weight))
+	}
+}
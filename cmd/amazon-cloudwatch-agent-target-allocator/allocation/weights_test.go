@@ -0,0 +1,139 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package allocation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+func TestWeight(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels model.LabelSet
+		want   int
+	}{
+		{
+			name: "default",
+			want: DefaultWeight,
+		},
+		{
+			name:   "weight label",
+			labels: model.LabelSet{target.WeightLabel: "50"},
+			want:   50,
+		},
+		{
+			name:   "pod annotation",
+			labels: model.LabelSet{"__meta_kubernetes_pod_annotation_cloudwatch_aws_amazon_com_scrape_weight": "100"},
+			want:   100,
+		},
+		{
+			name: "weight label over pod annotation",
+			labels: model.LabelSet{
+				target.WeightLabel: "50",
+				"__meta_kubernetes_pod_annotation_cloudwatch_aws_amazon_com_scrape_weight": "100",
+			},
+			want: 50,
+		},
+		{
+			name:   "invalid weight",
+			labels: model.LabelSet{target.WeightLabel: "heavy"},
+			want:   DefaultWeight,
+		},
+		{
+			name:   "negative weight",
+			labels: model.LabelSet{target.WeightLabel: "-5"},
+			want:   DefaultWeight,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := target.NewItem("test-job", "test-url", tt.labels, "")
+			assert.Equal(t, tt.want, NewWeights().Weight(item))
+			var nilWeights *Weights
+			assert.Equal(t, tt.want, nilWeights.Weight(item))
+		})
+	}
+}
+
+func TestLearnedWeight(t *testing.T) {
+	now := time.Now()
+	weights := NewWeights()
+	weights.now = func() time.Time { return now }
+	item := target.NewItem("test-job", "test-url", model.LabelSet{target.WeightLabel: "50"}, "")
+	items := map[string]*target.Item{item.Hash(): item}
+
+	weights.Record([]SeriesCount{
+		{Job: "test-job", Target: "test-url", Series: 1200},
+		{Job: "other-job", Target: "test-url", Series: 10},
+	}, items)
+	assert.Equal(t, 1200, weights.Weight(item), "the reported series count takes precedence")
+	assert.Len(t, weights.learned, 1, "the series counts of unknown targets are ignored")
+
+	weights.Record([]SeriesCount{{Job: "test-job", Target: "test-url", Series: 0}}, items)
+	assert.Equal(t, 1200, weights.Weight(item), "empty scrapes are ignored")
+
+	now = now.Add(learnedWeightTTL)
+	assert.Equal(t, 50, weights.Weight(item), "the reported series count expires")
+	weights.Record(nil, items)
+	assert.Empty(t, weights.learned)
+}
+
+func TestLearnedWeightsLimit(t *testing.T) {
+	weights := NewWeights()
+	weights.maxLearned = 2
+	items := map[string]*target.Item{}
+	var reported []SeriesCount
+	for i := 0; i < 3; i++ {
+		item := target.NewItem("test-job", fmt.Sprintf("test-url-%d", i), model.LabelSet{}, "")
+		items[item.Hash()] = item
+		reported = append(reported, SeriesCount{Job: item.JobName, Target: item.TargetURL[0], Series: 100})
+	}
+
+	weights.Record(reported, items)
+	assert.Len(t, weights.learned, 2)
+
+	// the recorded targets are still updated at the limit
+	for i := range reported {
+		reported[i].Series = 200
+	}
+	weights.Record(reported, items)
+	assert.Len(t, weights.learned, 2)
+	for _, learned := range weights.learned {
+		assert.Equal(t, 200, learned.weight)
+	}
+}
+
+func TestCollectorWeights(t *testing.T) {
+	for _, strategy := range GetRegisteredAllocatorNames() {
+		t.Run(strategy, func(t *testing.T) {
+			weights := NewWeights()
+			a, err := New(strategy, logger, WithWeights(weights))
+			assert.NoError(t, err)
+			targets := MakeNNewTargets(10, 2, 0)
+			a.SetCollectors(MakeNCollectors(2, 0))
+			a.SetTargets(targets)
+
+			var reported []SeriesCount
+			for _, item := range targets {
+				reported = append(reported, SeriesCount{Job: item.JobName, Target: item.TargetURL[0], Series: 100})
+			}
+			weights.Record(reported, a.TargetItems())
+			a.SetTargets(targets)
+			expected := map[string]int{}
+			for _, item := range a.TargetItems() {
+				expected[item.CollectorName] += 100
+			}
+			for name, col := range a.Collectors() {
+				assert.Equal(t, expected[name], col.Weight)
+			}
+		})
+	}
+}
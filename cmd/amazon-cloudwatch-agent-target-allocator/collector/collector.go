@@ -5,6 +5,7 @@ package collector
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"time"
 
@@ -13,16 +14,13 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/allocation"
-)
-
-const (
-	watcherTimeout = 15 * time.Minute
+	allocatorconfig "github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/config"
 )
 
 var (
@@ -37,91 +35,218 @@ type Client struct {
 	log       logr.Logger
 	k8sClient kubernetes.Interface
 	close     chan struct{}
+
+	// notReadyGracePeriod is how long a collector that is not ready anymore keeps its targets, so a flapping
+	// collector's targets aren't reassigned back and forth.
+	notReadyGracePeriod time.Duration
+	// notReadySince is a map from the name of a collector kept during the grace period to the time it became not ready
+	notReadySince map[string]time.Time
 }
 
-func NewClient(logger logr.Logger, kubeConfig *rest.Config) (*Client, error) {
+func NewClient(logger logr.Logger, kubeConfig *rest.Config, notReadyGracePeriod time.Duration) (*Client, error) {
 	clientset, err := kubernetes.NewForConfig(kubeConfig)
 	if err != nil {
 		return &Client{}, err
 	}
 
 	return &Client{
-		log:       logger.WithValues("component", "amazon-cloudwatch-agent-target-allocator"),
-		k8sClient: clientset,
-		close:     make(chan struct{}),
+		log:                 logger.WithValues("component", "amazon-cloudwatch-agent-target-allocator"),
+		k8sClient:           clientset,
+		close:               make(chan struct{}),
+		notReadyGracePeriod: notReadyGracePeriod,
+		notReadySince:       map[string]time.Time{},
 	}, nil
 }
 
-func (k *Client) Watch(ctx context.Context, labelMap map[string]string, fn func(collectors map[string]*allocation.Collector)) error {
-	collectorMap := map[string]*allocation.Collector{}
-
-	opts := metav1.ListOptions{
-		LabelSelector: labels.SelectorFromSet(labelMap).String(),
+// isReady returns whether the collector pod is running, ready and not terminating.
+func isReady(pod *v1.Pod) bool {
+	if pod.GetDeletionTimestamp() != nil || pod.Status.Phase != v1.PodRunning {
+		return false
 	}
-	pods, err := k.k8sClient.CoreV1().Pods(ns).List(ctx, opts)
-	if err != nil {
-		k.log.Error(err, "Pod failure")
-		os.Exit(1)
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
 	}
-	for i := range pods.Items {
-		pod := pods.Items[i]
-		if pod.GetObjectMeta().GetDeletionTimestamp() == nil {
-			collectorMap[pod.Name] = allocation.NewCollector(pod.Name)
+	return false
+}
+
+// updateCollector updates the collectors with the pod and returns whether they changed. Only ready pods are
+// collectors. A collector that is not ready anymore is kept during the grace period, unless it is terminating.
+func (k *Client) updateCollector(pod *v1.Pod, collectorMap map[string]*allocation.Collector) bool {
+	current, present := collectorMap[pod.Name]
+	switch {
+	case isReady(pod):
+		delete(k.notReadySince, pod.Name)
+		if present && current.NodeName == pod.Spec.NodeName {
+			return false
+		}
+		collectorMap[pod.Name] = allocation.NewCollector(pod.Name, pod.Spec.NodeName)
+		return true
+	case !present:
+		return false
+	case pod.GetDeletionTimestamp() == nil && k.notReadyGracePeriod > 0:
+		if _, ok := k.notReadySince[pod.Name]; !ok {
+			k.log.Info("Collector is not ready, keeping its targets during the grace period", "collector", pod.Name, "gracePeriod", k.notReadyGracePeriod)
+			k.notReadySince[pod.Name] = time.Now()
 		}
+		return false
+	default:
+		k.removeCollector(pod.Name, collectorMap)
+		return true
 	}
-	fn(collectorMap)
+}
 
-	for {
-		if !k.restartWatch(ctx, opts, collectorMap, fn) {
-			return nil
+func (k *Client) removeCollector(name string, collectorMap map[string]*allocation.Collector) {
+	delete(collectorMap, name)
+	delete(k.notReadySince, name)
+}
+
+// expireNotReady removes the collectors not ready for longer than the grace period. It returns whether the
+// collectors changed and when the next collector expires, zero if none is kept during the grace period.
+func (k *Client) expireNotReady(collectorMap map[string]*allocation.Collector) (bool, time.Duration) {
+	changed := false
+	var next time.Duration
+	for name, since := range k.notReadySince {
+		remaining := k.notReadyGracePeriod - time.Since(since)
+		if remaining <= 0 {
+			k.log.Info("Collector not ready for longer than the grace period, reassigning its targets", "collector", name)
+			k.removeCollector(name, collectorMap)
+			changed = true
+			continue
+		}
+		if next == 0 || remaining < next {
+			next = remaining
 		}
 	}
+	return changed, next
 }
 
-func (k *Client) restartWatch(ctx context.Context, opts metav1.ListOptions, collectorMap map[string]*allocation.Collector, fn func(collectors map[string]*allocation.Collector)) bool {
-	// add timeout to the context before calling Watch
-	ctx, cancel := context.WithTimeout(ctx, watcherTimeout)
-	defer cancel()
-	watcher, err := k.k8sClient.CoreV1().Pods(ns).Watch(ctx, opts)
-	if err != nil {
-		k.log.Error(err, "unable to create collector pod watcher")
-		return false
+// syncCollectors updates the collectors with the pods of the informer's store and returns whether they changed.
+func (k *Client) syncCollectors(store cache.Store, collectorMap map[string]*allocation.Collector) bool {
+	changed := false
+	pods := map[string]bool{}
+	for _, obj := range store.List() {
+		pod, ok := obj.(*v1.Pod)
+		if !ok {
+			continue
+		}
+		pods[pod.Name] = true
+		if k.updateCollector(pod, collectorMap) {
+			changed = true
+		}
 	}
-	k.log.Info("Successfully started a collector pod watcher")
-	if msg := runWatch(ctx, k, watcher.ResultChan(), collectorMap, fn); msg != "" {
-		k.log.Info("Collector pod watch event stopped " + msg)
-		return false
+	for name := range collectorMap {
+		if !pods[name] {
+			k.removeCollector(name, collectorMap)
+			changed = true
+		}
 	}
-
-	return true
+	return changed
 }
 
-func runWatch(ctx context.Context, k *Client, c <-chan watch.Event, collectorMap map[string]*allocation.Collector, fn func(collectors map[string]*allocation.Collector)) string {
-	for {
-		collectorsDiscovered.Set(float64(len(collectorMap)))
+// Watch discovers the collector pods selected by the label selector with a shared informer and calls fn with the
+// ready collectors each time they change. It returns an error if the collector pods can't be listed at start up, and
+// nil once the client is closed or the context is done.
+func (k *Client) Watch(ctx context.Context, labelSelector *metav1.LabelSelector, fn func(collectors map[string]*allocation.Collector)) error {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return fmt.Errorf("invalid collector selector: %w", err)
+	}
+	if k.notReadySince == nil {
+		k.notReadySince = map[string]time.Time{}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
 		select {
 		case <-k.close:
-			return "kubernetes client closed"
+			cancel()
 		case <-ctx.Done():
-			return "" // this means that the watcher most likely timed out
-		case event, ok := <-c:
-			if !ok {
-				k.log.Info("No event found. Restarting watch routine")
-				return ""
-			}
+		}
+	}()
 
-			pod, ok := event.Object.(*v1.Pod)
-			if !ok {
-				k.log.Info("No pod found in event Object. Restarting watch routine")
-				return ""
+	factory := informers.NewSharedInformerFactoryWithOptions(k.k8sClient, allocatorconfig.DefaultResyncTime,
+		informers.WithNamespace(ns),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = selector.String()
+		}))
+	informer := factory.Core().V1().Pods().Informer()
+
+	// events only notify the loop below, which reads the collector pods from the informer's store
+	events := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case events <- struct{}{}:
+		default:
+		}
+	}
+	_, err = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	})
+	if err != nil {
+		return fmt.Errorf("unable to watch the collector pods: %w", err)
+	}
+	listErrs := make(chan error, 1)
+	err = informer.SetWatchErrorHandlerWithContext(func(ctx context.Context, r *cache.Reflector, err error) {
+		cache.DefaultWatchErrorHandler(ctx, r, err)
+		if !informer.HasSynced() {
+			select {
+			case listErrs <- err:
+			default:
 			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("unable to watch the collector pods: %w", err)
+	}
+
+	factory.Start(ctx.Done())
+	defer func() {
+		// the informers stop once the context is done
+		cancel()
+		factory.Shutdown()
+	}()
+	synced := make(chan bool, 1)
+	go func() {
+		synced <- cache.WaitForCacheSync(ctx.Done(), informer.HasSynced)
+	}()
+	select {
+	case err = <-listErrs:
+		return fmt.Errorf("unable to list the collector pods: %w", err)
+	case ok := <-synced:
+		if !ok {
+			return nil
+		}
+	}
+	k.log.Info("Successfully started a collector pod informer", "selector", selector.String())
 
-			switch event.Type { //nolint:exhaustive
-			case watch.Added:
-				collectorMap[pod.Name] = allocation.NewCollector(pod.Name)
-			case watch.Deleted:
-				delete(collectorMap, pod.Name)
+	collectorMap := map[string]*allocation.Collector{}
+	k.syncCollectors(informer.GetStore(), collectorMap)
+	collectorsDiscovered.Set(float64(len(collectorMap)))
+	fn(collectorMap)
+	for {
+		// the grace period of the collectors not ready ends with the earliest expiry
+		var gracePeriodEnd <-chan time.Time
+		changed, next := k.expireNotReady(collectorMap)
+		if next > 0 {
+			gracePeriodEnd = time.After(next)
+		}
+		if !changed {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-gracePeriodEnd:
+				continue
+			case <-events:
+				changed = k.syncCollectors(informer.GetStore(), collectorMap)
 			}
+		}
+		collectorsDiscovered.Set(float64(len(collectorMap)))
+		if changed {
 			fn(collectorMap)
 		}
 	}
@@ -5,46 +5,40 @@ package collector
 
 import (
 	"context"
-	"fmt"
-	"os"
+	"errors"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/allocation"
 )
 
-var logger = logf.Log.WithName("collector-unit-tests")
-
-func getTestClient() (Client, watch.Interface) {
-	kubeClient := Client{
-		k8sClient: fake.NewSimpleClientset(),
-		close:     make(chan struct{}),
-		log:       logger,
-	}
-
-	labelMap := map[string]string{
-		"app.kubernetes.io/instance":   "default.test",
-		"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+var (
+	logger        = logf.Log.WithName("collector-unit-tests")
+	labelSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"app.kubernetes.io/instance":   "default.test",
+			"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+		},
 	}
+)
 
-	opts := metav1.ListOptions{
-		LabelSelector: labels.SelectorFromSet(labelMap).String(),
-	}
-	watcher, err := kubeClient.k8sClient.CoreV1().Pods("test-ns").Watch(context.Background(), opts)
-	if err != nil {
-		fmt.Printf("failed to setup a Collector Pod watcher: %v", err)
-		os.Exit(1)
+func getTestClient(gracePeriod time.Duration) *Client {
+	return &Client{
+		k8sClient:           fake.NewSimpleClientset(),
+		close:               make(chan struct{}),
+		log:                 logger,
+		notReadyGracePeriod: gracePeriod,
 	}
-	return kubeClient, watcher
 }
 
 func pod(name string) *v1.Pod {
@@ -58,31 +52,74 @@ func pod(name string) *v1.Pod {
 			Namespace: "test-ns",
 			Labels:    labelSet,
 		},
+		Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: v1.ConditionTrue},
+			},
+		},
 	}
 }
 
-func Test_runWatch(t *testing.T) {
-	type args struct {
-		kubeFn       func(t *testing.T, client Client, group *sync.WaitGroup)
-		collectorMap map[string]*allocation.Collector
-	}
+func notReadyPod(name string) *v1.Pod {
+	p := pod(name)
+	p.Status.Conditions[0].Status = v1.ConditionFalse
+	return p
+}
+
+// collectorWatch records the collectors the watched client calls back with.
+type collectorWatch struct {
+	mtx        sync.Mutex
+	collectors map[string]*allocation.Collector
+	updates    int
+	done       chan error
+}
+
+func watchCollectors(client *Client, selector *metav1.LabelSelector) *collectorWatch {
+	w := &collectorWatch{done: make(chan error, 1)}
+	go func() {
+		w.done <- client.Watch(context.Background(), selector, func(colMap map[string]*allocation.Collector) {
+			collectors := map[string]*allocation.Collector{}
+			for k, v := range colMap {
+				collectors[k] = v
+			}
+			w.mtx.Lock()
+			defer w.mtx.Unlock()
+			w.collectors = collectors
+			w.updates++
+		})
+	}()
+	return w
+}
+
+func (w *collectorWatch) assertCollectors(t *testing.T, want map[string]*allocation.Collector) {
+	assert.Eventually(t, func() bool {
+		w.mtx.Lock()
+		defer w.mtx.Unlock()
+		return assert.ObjectsAreEqual(want, w.collectors)
+	}, 5*time.Second, 10*time.Millisecond, "expected collectors %v", want)
+}
+
+func (w *collectorWatch) updateCount() int {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.updates
+}
+
+func Test_Watch(t *testing.T) {
 	tests := []struct {
-		name string
-		args args
-		want map[string]*allocation.Collector
+		name     string
+		existing []string
+		kubeFn   func(t *testing.T, client *Client)
+		want     map[string]*allocation.Collector
 	}{
 		{
 			name: "pod add",
-			args: args{
-				kubeFn: func(t *testing.T, client Client, group *sync.WaitGroup) {
-					for _, k := range []string{"test-pod1", "test-pod2", "test-pod3"} {
-						p := pod(k)
-						group.Add(1)
-						_, err := client.k8sClient.CoreV1().Pods("test-ns").Create(context.Background(), p, metav1.CreateOptions{})
-						assert.NoError(t, err)
-					}
-				},
-				collectorMap: map[string]*allocation.Collector{},
+			kubeFn: func(t *testing.T, client *Client) {
+				for _, k := range []string{"test-pod1", "test-pod2", "test-pod3"} {
+					_, err := client.k8sClient.CoreV1().Pods("test-ns").Create(context.Background(), pod(k), metav1.CreateOptions{})
+					assert.NoError(t, err)
+				}
 			},
 			want: map[string]*allocation.Collector{
 				"test-pod1": {
@@ -97,26 +134,13 @@ func Test_runWatch(t *testing.T) {
 			},
 		},
 		{
-			name: "pod delete",
-			args: args{
-				kubeFn: func(t *testing.T, client Client, group *sync.WaitGroup) {
-					for _, k := range []string{"test-pod2", "test-pod3"} {
-						group.Add(1)
-						err := client.k8sClient.CoreV1().Pods("test-ns").Delete(context.Background(), k, metav1.DeleteOptions{})
-						assert.NoError(t, err)
-					}
-				},
-				collectorMap: map[string]*allocation.Collector{
-					"test-pod1": {
-						Name: "test-pod1",
-					},
-					"test-pod2": {
-						Name: "test-pod2",
-					},
-					"test-pod3": {
-						Name: "test-pod3",
-					},
-				},
+			name:     "pod delete",
+			existing: []string{"test-pod1", "test-pod2", "test-pod3"},
+			kubeFn: func(t *testing.T, client *Client) {
+				for _, k := range []string{"test-pod2", "test-pod3"} {
+					err := client.k8sClient.CoreV1().Pods("test-ns").Delete(context.Background(), k, metav1.DeleteOptions{})
+					assert.NoError(t, err)
+				}
 			},
 			want: map[string]*allocation.Collector{
 				"test-pod1": {
@@ -124,86 +148,204 @@ func Test_runWatch(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "pod not selected",
+			kubeFn: func(t *testing.T, client *Client) {
+				p := pod("test-pod1")
+				p.Labels["app.kubernetes.io/instance"] = "default.other"
+				_, err := client.k8sClient.CoreV1().Pods("test-ns").Create(context.Background(), p, metav1.CreateOptions{})
+				assert.NoError(t, err)
+				_, err = client.k8sClient.CoreV1().Pods("test-ns").Create(context.Background(), pod("test-pod2"), metav1.CreateOptions{})
+				assert.NoError(t, err)
+			},
+			want: map[string]*allocation.Collector{
+				"test-pod2": {
+					Name: "test-pod2",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			kubeClient, watcher := getTestClient()
-			defer func() {
-				close(kubeClient.close)
-				watcher.Stop()
-			}()
-			var wg sync.WaitGroup
-			actual := make(map[string]*allocation.Collector)
-			for _, k := range tt.args.collectorMap {
-				p := pod(k.Name)
-				_, err := kubeClient.k8sClient.CoreV1().Pods("test-ns").Create(context.Background(), p, metav1.CreateOptions{})
-				wg.Add(1)
-				assert.NoError(t, err)
+			kubeClient := getTestClient(0)
+			defer kubeClient.Close()
+			for _, k := range tt.existing {
+				_, err := kubeClient.k8sClient.CoreV1().Pods("test-ns").Create(context.Background(), pod(k), metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			w := watchCollectors(kubeClient, labelSelector)
+			if len(tt.existing) > 0 {
+				assert.Eventually(t, func() bool { return w.updateCount() > 0 }, 5*time.Second, 10*time.Millisecond)
 			}
-			go runWatch(context.Background(), &kubeClient, watcher.ResultChan(), map[string]*allocation.Collector{}, func(colMap map[string]*allocation.Collector) {
-				actual = colMap
-				wg.Done()
-			})
-
-			tt.args.kubeFn(t, kubeClient, &wg)
-			wg.Wait()
 
-			assert.Len(t, actual, len(tt.want))
-			assert.Equal(t, actual, tt.want)
+			tt.kubeFn(t, kubeClient)
+			w.assertCollectors(t, tt.want)
 		})
 	}
 }
 
-// this tests runWatch in the case of watcher channel closing and watcher timing out.
-func Test_closeChannel(t *testing.T) {
+func Test_WatchReadiness(t *testing.T) {
 	tests := []struct {
-		description    string
-		isCloseChannel bool
-		timeout        time.Duration
+		name        string
+		gracePeriod time.Duration
+		kubeFn      func(t *testing.T, client *Client, w *collectorWatch)
+		want        map[string]*allocation.Collector
 	}{
 		{
-			// event is triggered by channel closing.
-			description:    "close_channel",
-			isCloseChannel: true,
-			// channel should be closed before this timeout occurs
-			timeout: 10 * time.Second,
+			name: "not ready pod added",
+			kubeFn: func(t *testing.T, client *Client, w *collectorWatch) {
+				createPod(t, client, notReadyPod("test-pod1"))
+				createPod(t, client, pod("test-pod2"))
+			},
+			want: map[string]*allocation.Collector{"test-pod2": {Name: "test-pod2"}},
 		},
 		{
-			// event triggered by timeout.
-			description:    "watcher_timeout",
-			isCloseChannel: false,
-			timeout:        0 * time.Second,
+			name: "pod becomes ready",
+			kubeFn: func(t *testing.T, client *Client, w *collectorWatch) {
+				createPod(t, client, notReadyPod("test-pod1"))
+				updatePod(t, client, pod("test-pod1"))
+			},
+			want: map[string]*allocation.Collector{"test-pod1": {Name: "test-pod1"}},
+		},
+		{
+			name: "pod scheduled",
+			kubeFn: func(t *testing.T, client *Client, w *collectorWatch) {
+				createPod(t, client, pod("test-pod1"))
+				w.assertCollectors(t, map[string]*allocation.Collector{"test-pod1": {Name: "test-pod1"}})
+				scheduled := pod("test-pod1")
+				scheduled.Spec.NodeName = "node-1"
+				updatePod(t, client, scheduled)
+			},
+			want: map[string]*allocation.Collector{"test-pod1": {Name: "test-pod1", NodeName: "node-1"}},
+		},
+		{
+			name: "pod becomes not ready",
+			kubeFn: func(t *testing.T, client *Client, w *collectorWatch) {
+				createPod(t, client, pod("test-pod1"))
+				w.assertCollectors(t, map[string]*allocation.Collector{"test-pod1": {Name: "test-pod1"}})
+				updatePod(t, client, notReadyPod("test-pod1"))
+			},
+			want: map[string]*allocation.Collector{},
+		},
+		{
+			name:        "pod terminating during the grace period",
+			gracePeriod: time.Hour,
+			kubeFn: func(t *testing.T, client *Client, w *collectorWatch) {
+				createPod(t, client, pod("test-pod1"))
+				w.assertCollectors(t, map[string]*allocation.Collector{"test-pod1": {Name: "test-pod1"}})
+				terminating := pod("test-pod1")
+				terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+				updatePod(t, client, terminating)
+			},
+			want: map[string]*allocation.Collector{},
+		},
+		{
+			name:        "flapping pod kept during the grace period",
+			gracePeriod: time.Hour,
+			kubeFn: func(t *testing.T, client *Client, w *collectorWatch) {
+				createPod(t, client, pod("test-pod1"))
+				w.assertCollectors(t, map[string]*allocation.Collector{"test-pod1": {Name: "test-pod1"}})
+				updatePod(t, client, notReadyPod("test-pod1"))
+				createPod(t, client, pod("test-pod2"))
+				w.assertCollectors(t, map[string]*allocation.Collector{"test-pod1": {Name: "test-pod1"}, "test-pod2": {Name: "test-pod2"}})
+				updatePod(t, client, pod("test-pod1"))
+				time.Sleep(100 * time.Millisecond)
+			},
+			want: map[string]*allocation.Collector{"test-pod1": {Name: "test-pod1"}, "test-pod2": {Name: "test-pod2"}},
+		},
+		{
+			name:        "not ready pod removed after the grace period",
+			gracePeriod: 100 * time.Millisecond,
+			kubeFn: func(t *testing.T, client *Client, w *collectorWatch) {
+				createPod(t, client, pod("test-pod1"))
+				w.assertCollectors(t, map[string]*allocation.Collector{"test-pod1": {Name: "test-pod1"}})
+				updatePod(t, client, notReadyPod("test-pod1"))
+			},
+			want: map[string]*allocation.Collector{},
 		},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := getTestClient(tt.gracePeriod)
+			defer kubeClient.Close()
+			w := watchCollectors(kubeClient, labelSelector)
+			w.assertCollectors(t, map[string]*allocation.Collector{})
 
-	for _, tc := range tests {
-		t.Run(tc.description, func(t *testing.T) {
-			kubeClient, watcher := getTestClient()
-
-			defer func() {
-				close(kubeClient.close)
-				watcher.Stop()
-			}()
-			var wg sync.WaitGroup
-			wg.Add(1)
-			terminated := false
-
-			go func(watcher watch.Interface) {
-				defer wg.Done()
-				ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
-				defer cancel()
-				if msg := runWatch(ctx, &kubeClient, watcher.ResultChan(), map[string]*allocation.Collector{}, func(colMap map[string]*allocation.Collector) {}); msg != "" {
-					terminated = true
-					return
-				}
-			}(watcher)
-
-			if tc.isCloseChannel {
-				// stop pod watcher to trigger event.
-				watcher.Stop()
-			}
-			wg.Wait()
-			assert.False(t, terminated)
+			tt.kubeFn(t, kubeClient, w)
+			w.assertCollectors(t, tt.want)
 		})
 	}
 }
+
+func createPod(t *testing.T, client *Client, p *v1.Pod) {
+	_, err := client.k8sClient.CoreV1().Pods(p.Namespace).Create(context.Background(), p, metav1.CreateOptions{})
+	require.NoError(t, err)
+}
+
+func updatePod(t *testing.T, client *Client, p *v1.Pod) {
+	_, err := client.k8sClient.CoreV1().Pods(p.Namespace).Update(context.Background(), p, metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+func Test_WatchCollectorSelector(t *testing.T) {
+	kubeClient := getTestClient(0)
+	defer kubeClient.Close()
+	selector := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "app.kubernetes.io/instance", Operator: metav1.LabelSelectorOpIn, Values: []string{"default.test", "default.other"}},
+		},
+	}
+	other := pod("test-pod2")
+	other.Labels["app.kubernetes.io/instance"] = "default.other"
+	ignored := pod("test-pod3")
+	ignored.Labels["app.kubernetes.io/instance"] = "default.ignored"
+	for _, p := range []*v1.Pod{pod("test-pod1"), other, ignored} {
+		createPod(t, kubeClient, p)
+	}
+
+	w := watchCollectors(kubeClient, selector)
+	w.assertCollectors(t, map[string]*allocation.Collector{"test-pod1": {Name: "test-pod1"}, "test-pod2": {Name: "test-pod2"}})
+}
+
+func Test_WatchInvalidSelector(t *testing.T) {
+	kubeClient := getTestClient(0)
+	defer kubeClient.Close()
+	selector := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "app.kubernetes.io/instance", Operator: "Like"},
+		},
+	}
+	err := kubeClient.Watch(context.Background(), selector, func(map[string]*allocation.Collector) {})
+	assert.ErrorContains(t, err, "invalid collector selector")
+}
+
+func Test_WatchListError(t *testing.T) {
+	kubeClient := getTestClient(0)
+	defer kubeClient.Close()
+	kubeClient.k8sClient.(*fake.Clientset).PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("forbidden")
+	})
+
+	w := watchCollectors(kubeClient, labelSelector)
+	select {
+	case err := <-w.done:
+		assert.ErrorContains(t, err, "unable to list the collector pods")
+	case <-time.After(10 * time.Second):
+		t.Fatal("the watch didn't return the list error")
+	}
+	assert.Equal(t, 0, w.updateCount())
+}
+
+func Test_WatchClose(t *testing.T) {
+	kubeClient := getTestClient(0)
+	w := watchCollectors(kubeClient, labelSelector)
+	w.assertCollectors(t, map[string]*allocation.Collector{})
+
+	kubeClient.Close()
+	select {
+	case err := <-w.done:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the watch didn't stop once the client closed")
+	}
+}
@@ -39,6 +39,9 @@ const (
 	DefaultTLSKeyPath                         = DefaultCertMountPath + "/server.key"
 	DefaultTLSCertPath                        = DefaultCertMountPath + "/server.crt"
 	DefaultCABundlePath                       = DefaultClientCertMountPath + "/tls-ca.crt"
+	DefaultLeaseDuration                      = 15 * time.Second
+	DefaultRenewDeadline                      = 10 * time.Second
+	DefaultRetryPeriod                        = 2 * time.Second
 )
 
 type Config struct {
@@ -56,6 +59,46 @@ type Config struct {
 	ServiceMonitorSelector map[string]string     `yaml:"service_monitor_selector,omitempty"`
 	CollectorSelector      *metav1.LabelSelector `yaml:"collector_selector,omitempty"`
 	HTTPS                  HTTPSServerConfig     `yaml:"https,omitempty"`
+	// ProbeSelector and ScrapeConfigSelector select the Probes and ScrapeConfigs by their labels, like the monitor
+	// selectors select the monitors.
+	ProbeSelector        map[string]string `yaml:"probe_selector,omitempty"`
+	ScrapeConfigSelector map[string]string `yaml:"scrape_config_selector,omitempty"`
+	// The namespace selectors select the namespaces of the monitors, Probes and ScrapeConfigs picked up by their
+	// selector. All namespaces are selected if not set.
+	PodMonitorNamespaceSelector     *metav1.LabelSelector `yaml:"pod_monitor_namespace_selector,omitempty"`
+	ServiceMonitorNamespaceSelector *metav1.LabelSelector `yaml:"service_monitor_namespace_selector,omitempty"`
+	ProbeNamespaceSelector          *metav1.LabelSelector `yaml:"probe_namespace_selector,omitempty"`
+	ScrapeConfigNamespaceSelector   *metav1.LabelSelector `yaml:"scrape_config_namespace_selector,omitempty"`
+	// CollectorNotReadyGracePeriod is how long a collector that is not ready anymore keeps its targets before they are
+	// reassigned. Collectors not ready lose their targets immediately by default.
+	CollectorNotReadyGracePeriod time.Duration `yaml:"collector_not_ready_grace_period,omitempty"`
+	// LeaderElection elects a leader among the target allocator replicas. Only the leader allocates the targets, the
+	// followers serve the assignments it replicates.
+	LeaderElection LeaderElectionConfig `yaml:"leader_election,omitempty"`
+	// Filter configures the hooks of the filter strategy.
+	Filter FilterConfig `yaml:"filter,omitempty"`
+}
+
+type FilterConfig struct {
+	// Namespaces are the namespaces the namespace filter allows and denies the targets of.
+	Namespaces NamespaceFilterConfig `yaml:"namespaces,omitempty"`
+	// MaxTargetsPerJob is the number of targets of a job the cardinality-guard filter keeps, unlimited if 0.
+	MaxTargetsPerJob int `yaml:"max_targets_per_job,omitempty"`
+}
+
+type NamespaceFilterConfig struct {
+	// Allow lists the only namespaces the targets are kept of, all namespaces are allowed if empty.
+	Allow []string `yaml:"allow,omitempty"`
+	Deny  []string `yaml:"deny,omitempty"`
+}
+
+type LeaderElectionConfig struct {
+	Enabled bool `yaml:"enabled,omitempty"`
+	// LeaseName is the name of the Lease the replicas elect the leader with, in the namespace of the target allocator.
+	LeaseName     string        `yaml:"lease_name,omitempty"`
+	LeaseDuration time.Duration `yaml:"lease_duration,omitempty"`
+	RenewDeadline time.Duration `yaml:"renew_deadline,omitempty"`
+	RetryPeriod   time.Duration `yaml:"retry_period,omitempty"`
 }
 
 type PrometheusCRConfig struct {
@@ -78,6 +121,14 @@ func (c Config) GetAllocationStrategy() string {
 	return DefaultAllocationStrategy
 }
 
+// GetCollectorSelector returns the selector of the collector pods, the collector selector if set, else the label selector.
+func (c Config) GetCollectorSelector() *metav1.LabelSelector {
+	if c.CollectorSelector != nil {
+		return c.CollectorSelector
+	}
+	return &metav1.LabelSelector{MatchLabels: c.LabelSelector}
+}
+
 func (c Config) GetTargetsFilterStrategy() string {
 	if c.FilterStrategy != nil {
 		return *c.FilterStrategy
@@ -172,6 +223,11 @@ func CreateDefaultConfig() Config {
 			TLSCertFilePath: DefaultTLSCertPath,
 			TLSKeyFilePath:  DefaultTLSKeyPath,
 		},
+		LeaderElection: LeaderElectionConfig{
+			LeaseDuration: DefaultLeaseDuration,
+			RenewDeadline: DefaultRenewDeadline,
+			RetryPeriod:   DefaultRetryPeriod,
+		},
 	}
 }
 
@@ -210,6 +266,12 @@ func ValidateConfig(config *Config) error {
 	if !config.PrometheusCR.Enabled && !scrapeConfigsPresent {
 		return fmt.Errorf("at least one scrape config must be defined, or Prometheus CR watching must be enabled")
 	}
+	if config.LeaderElection.Enabled && config.LeaderElection.LeaseName == "" {
+		return fmt.Errorf("the lease name must be set when leader election is enabled")
+	}
+	if config.Filter.MaxTargetsPerJob < 0 {
+		return fmt.Errorf("the maximum number of targets per job must not be negative")
+	}
 	return nil
 }
 
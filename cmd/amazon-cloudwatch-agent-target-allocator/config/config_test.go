@@ -11,6 +11,7 @@ import (
 	"github.com/prometheus/common/model"
 	promconfig "github.com/prometheus/prometheus/config"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestLoad(t *testing.T) {
@@ -19,16 +20,25 @@ func TestLoad(t *testing.T) {
 		file string
 	}
 	tests := []struct {
-		name           string
-		args           args
-		wantErr        assert.ErrorAssertionFunc
-		wantHTTPS      HTTPSServerConfig
-		wantLabels     map[string]string
-		wantPromCR     PrometheusCRConfig
-		wantAlloc      *string
-		wantPodMonSel  map[string]string
-		wantSvcMonSel  map[string]string
-		wantJobNames   []string
+		name            string
+		args            args
+		wantErr         assert.ErrorAssertionFunc
+		wantHTTPS       HTTPSServerConfig
+		wantLabels      map[string]string
+		wantPromCR      PrometheusCRConfig
+		wantAlloc       *string
+		wantPodMonSel   map[string]string
+		wantSvcMonSel   map[string]string
+		wantProbeSel    map[string]string
+		wantSConfigSel  map[string]string
+		wantSvcMonNsSel *metav1.LabelSelector
+		wantProbeNsSel  *metav1.LabelSelector
+		wantJobNames    []string
+		wantGrace       time.Duration
+		wantColSel      *metav1.LabelSelector
+		wantLeader      LeaderElectionConfig
+		wantFilter      FilterConfig
+		wantFilterStr   string
 	}{
 		{
 			name: "file sd load",
@@ -52,14 +62,27 @@ func TestLoad(t *testing.T) {
 			},
 			wantAlloc:    &defaulAllocationStrategy,
 			wantJobNames: []string{"prometheus"},
+			wantGrace:    30 * time.Second,
+			wantLeader: LeaderElectionConfig{
+				Enabled:       true,
+				LeaseName:     "test-target-allocator",
+				LeaseDuration: 30 * time.Second,
+				RenewDeadline: DefaultRenewDeadline,
+				RetryPeriod:   DefaultRetryPeriod,
+			},
+			wantFilter: FilterConfig{
+				Namespaces:       NamespaceFilterConfig{Deny: []string{"kube-system"}},
+				MaxTargetsPerJob: 1000,
+			},
+			wantFilterStr: "relabel-config,namespace,cardinality-guard",
 		},
 		{
 			name: "no config",
 			args: args{
 				file: "./testdata/no_config.yaml",
 			},
-			wantErr:   assert.NoError,
-			wantHTTPS: CreateDefaultConfig().HTTPS,
+			wantErr:    assert.NoError,
+			wantHTTPS:  CreateDefaultConfig().HTTPS,
 			wantLabels: nil,
 			wantPromCR: CreateDefaultConfig().PrometheusCR,
 			wantAlloc:  CreateDefaultConfig().AllocationStrategy,
@@ -91,6 +114,55 @@ func TestLoad(t *testing.T) {
 			wantSvcMonSel: map[string]string{
 				"release": "test",
 			},
+			wantProbeSel: map[string]string{
+				"release": "test",
+			},
+			wantSConfigSel: map[string]string{
+				"release": "test",
+			},
+			wantSvcMonNsSel: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "a"},
+			},
+			wantProbeNsSel: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "team", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}},
+				},
+			},
+			wantJobNames: []string{"prometheus"},
+		},
+		{
+			name: "collector selector",
+			args: args{
+				file: "./testdata/collector_selector_test.yaml",
+			},
+			wantErr: assert.NoError,
+			wantHTTPS: HTTPSServerConfig{
+				Enabled:         true,
+				ListenAddr:      DefaultListenAddr,
+				CAFilePath:      DefaultCABundlePath,
+				TLSCertFilePath: DefaultTLSCertPath,
+				TLSKeyFilePath:  DefaultTLSKeyPath,
+			},
+			wantLabels: map[string]string{
+				"app.kubernetes.io/instance":   "default.test",
+				"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+			},
+			wantPromCR: PrometheusCRConfig{
+				ScrapeInterval: DefaultCRScrapeInterval,
+			},
+			wantAlloc: &defaulAllocationStrategy,
+			wantColSel: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app.kubernetes.io/managed-by": "amazon-cloudwatch-agent-operator",
+				},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      "app.kubernetes.io/instance",
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   []string{"default.test", "default.other"},
+					},
+				},
+			},
 			wantJobNames: []string{"prometheus"},
 		},
 	}
@@ -107,6 +179,25 @@ func TestLoad(t *testing.T) {
 			assert.Equal(t, tt.wantAlloc, got.AllocationStrategy)
 			assert.Equal(t, tt.wantPodMonSel, got.PodMonitorSelector)
 			assert.Equal(t, tt.wantSvcMonSel, got.ServiceMonitorSelector)
+			assert.Equal(t, tt.wantProbeSel, got.ProbeSelector)
+			assert.Equal(t, tt.wantSConfigSel, got.ScrapeConfigSelector)
+			assert.Equal(t, tt.wantSvcMonNsSel, got.ServiceMonitorNamespaceSelector)
+			assert.Equal(t, tt.wantProbeNsSel, got.ProbeNamespaceSelector)
+			assert.Nil(t, got.PodMonitorNamespaceSelector)
+			assert.Nil(t, got.ScrapeConfigNamespaceSelector)
+			assert.Equal(t, tt.wantGrace, got.CollectorNotReadyGracePeriod)
+			assert.Equal(t, tt.wantFilter, got.Filter)
+			assert.Equal(t, tt.wantFilterStr, got.GetTargetsFilterStrategy())
+			if tt.wantLeader.Enabled {
+				assert.Equal(t, tt.wantLeader, got.LeaderElection)
+			} else {
+				assert.Equal(t, CreateDefaultConfig().LeaderElection, got.LeaderElection)
+			}
+			if tt.wantColSel != nil {
+				assert.Equal(t, tt.wantColSel, got.GetCollectorSelector())
+			} else {
+				assert.Equal(t, &metav1.LabelSelector{MatchLabels: tt.wantLabels}, got.GetCollectorSelector())
+			}
 			if tt.wantJobNames != nil {
 				var gotJobNames []string
 				for _, sc := range got.PromConfig.ScrapeConfigs {
@@ -154,6 +245,30 @@ func TestValidateConfig(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "leader election enabled, no lease name",
+			fileConfig: Config{
+				PrometheusCR:   PrometheusCRConfig{Enabled: true},
+				LeaderElection: LeaderElectionConfig{Enabled: true},
+			},
+			expectedErr: fmt.Errorf("the lease name must be set when leader election is enabled"),
+		},
+		{
+			name: "leader election enabled, lease name present",
+			fileConfig: Config{
+				PrometheusCR:   PrometheusCRConfig{Enabled: true},
+				LeaderElection: LeaderElectionConfig{Enabled: true, LeaseName: "test-target-allocator"},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "negative maximum number of targets per job",
+			fileConfig: Config{
+				PrometheusCR: PrometheusCRConfig{Enabled: true},
+				Filter:       FilterConfig{MaxTargetsPerJob: -1},
+			},
+			expectedErr: fmt.Errorf("the maximum number of targets per job must not be negative"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -21,6 +21,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/collector"
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/prehook"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/replication"
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/server"
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
 	allocatorWatcher "github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/watcher"
@@ -45,6 +46,7 @@ func main() {
 		fileWatcher      allocatorWatcher.Watcher
 		promWatcher      allocatorWatcher.Watcher
 		targetDiscoverer *target.Discoverer
+		replicator       *replication.Replicator
 
 		discoveryCancel context.CancelFunc
 		runGroup        run.Group
@@ -71,14 +73,27 @@ func main() {
 	ctx := context.Background()
 	log := ctrl.Log.WithName("allocator")
 
-	allocatorPrehook = prehook.New(cfg.GetTargetsFilterStrategy(), log)
-	allocator, err = allocation.New(cfg.GetAllocationStrategy(), log, allocation.WithFilter(allocatorPrehook))
+	allocatorPrehook = prehook.New(cfg.GetTargetsFilterStrategy(), log,
+		prehook.WithNamespaces(cfg.Filter.Namespaces.Allow, cfg.Filter.Namespaces.Deny),
+		prehook.WithMaxTargetsPerJob(cfg.Filter.MaxTargetsPerJob))
+	weights := allocation.NewWeights()
+	allocator, err = allocation.New(cfg.GetAllocationStrategy(), log, allocation.WithFilter(allocatorPrehook), allocation.WithWeights(weights))
 	if err != nil {
 		setupLog.Error(err, "Unable to initialize allocation strategy")
 		os.Exit(1)
 	}
+	var replicatedAllocator *replication.Allocator
+	if cfg.LeaderElection.Enabled {
+		replicatedAllocator = replication.NewAllocator(log, allocator)
+		replicator, err = replication.NewReplicator(log, cfg.ClusterConfig, cfg.LeaderElection, replicatedAllocator)
+		if err != nil {
+			setupLog.Error(err, "Unable to initialize leader election")
+			os.Exit(1)
+		}
+		allocator = replicatedAllocator
+	}
 
-	httpOptions := []server.Option{}
+	httpOptions := []server.Option{server.WithWeights(weights), server.WithPrehook(allocatorPrehook)}
 	tlsConfig, confErr := cfg.HTTPS.NewTLSConfig(ctx)
 	if confErr != nil {
 		setupLog.Error(confErr, "Unable to initialize TLS configuration", "Config", cfg.HTTPS)
@@ -86,12 +101,15 @@ func main() {
 	}
 	httpOptions = append(httpOptions, server.WithTLSConfig(tlsConfig, cfg.HTTPS.ListenAddr))
 	srv := server.NewServer(log, allocator, cfg.ListenAddr, httpOptions...)
+	if replicatedAllocator != nil {
+		replicatedAllocator.OnChange(srv.AllocationChanged)
+	}
 
 	discoveryCtx, discoveryCancel := context.WithCancel(ctx)
 	discoveryManager = discovery.NewManager(discoveryCtx, nil, prometheus.NewRegistry(), nil)
 
 	targetDiscoverer = target.NewDiscoverer(log, discoveryManager, allocatorPrehook, srv)
-	collectorWatcher, collectorWatcherErr := collector.NewClient(log, cfg.ClusterConfig)
+	collectorWatcher, collectorWatcherErr := collector.NewClient(log, cfg.ClusterConfig, cfg.CollectorNotReadyGracePeriod)
 	if collectorWatcherErr != nil {
 		setupLog.Error(collectorWatcherErr, "Unable to initialize collector watcher")
 		os.Exit(1)
@@ -159,7 +177,10 @@ func main() {
 				setupLog.Error(err, "Unable to apply initial configuration")
 				return err
 			}
-			err := targetDiscoverer.Watch(allocator.SetTargets)
+			err := targetDiscoverer.Watch(func(targets map[string]*target.Item) {
+				allocator.SetTargets(targets)
+				srv.AllocationChanged()
+			})
 			setupLog.Info("Target discoverer exited")
 			return err
 		},
@@ -169,7 +190,10 @@ func main() {
 		})
 	runGroup.Add(
 		func() error {
-			err := collectorWatcher.Watch(ctx, cfg.LabelSelector, allocator.SetCollectors)
+			err := collectorWatcher.Watch(ctx, cfg.GetCollectorSelector(), func(collectors map[string]*allocation.Collector) {
+				allocator.SetCollectors(collectors)
+				srv.AllocationChanged()
+			})
 			setupLog.Info("Collector watcher exited")
 			return err
 		},
@@ -177,6 +201,18 @@ func main() {
 			setupLog.Info("Closing collector watcher")
 			collectorWatcher.Close()
 		})
+	if cfg.LeaderElection.Enabled {
+		runGroup.Add(
+			func() error {
+				err := replicator.Run(ctx)
+				setupLog.Info("Leader election exited")
+				return err
+			},
+			func(_ error) {
+				setupLog.Info("Closing leader election")
+				replicator.Close()
+			})
+	}
 	runGroup.Add(
 		func() error {
 			err := srv.StartHTTPS()
@@ -0,0 +1,81 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+var (
+	targetsOverLimit = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cloudwatch_agent_allocator_targets_over_limit",
+		Help: "Number of targets of a job dropped for exceeding the maximum number of targets per job.",
+	}, []string{"job_name"})
+)
+
+// CardinalityGuardFilter caps the number of targets of each job, so that a misconfigured job discovering too many
+// targets doesn't overload the collectors. The targets kept are the same from one Apply to the next while the job's
+// targets don't change.
+type CardinalityGuardFilter struct {
+	log              logr.Logger
+	maxTargetsPerJob int
+	// overLimitJobs are the jobs whose targets were dropped by the last Apply, to reset their metric
+	overLimitJobs map[string]bool
+	relabelConfigStore
+	droppedTargets
+}
+
+func NewCardinalityGuardFilter(log logr.Logger, opts Options) Hook {
+	return &CardinalityGuardFilter{
+		log:              log,
+		maxTargetsPerJob: opts.MaxTargetsPerJob,
+		overLimitJobs:    make(map[string]bool),
+	}
+}
+
+func (cf *CardinalityGuardFilter) Apply(targets map[string]*target.Item) map[string]*target.Item {
+	dropped := make(map[string]DroppedTarget)
+	defer cf.set(dropped)
+
+	if cf.maxTargetsPerJob <= 0 {
+		return targets
+	}
+
+	// job -> hashes of its targets
+	hashesPerJob := make(map[string][]string)
+	for hash, item := range targets {
+		hashesPerJob[item.JobName] = append(hashesPerJob[item.JobName], hash)
+	}
+	overLimitJobs := make(map[string]bool)
+	for job, hashes := range hashesPerJob {
+		if len(hashes) <= cf.maxTargetsPerJob {
+			continue
+		}
+		sort.Strings(hashes)
+		reason := fmt.Sprintf("cardinality-guard: job %s has %d targets, more than the maximum of %d", job, len(hashes), cf.maxTargetsPerJob)
+		for _, hash := range hashes[cf.maxTargetsPerJob:] {
+			dropped[hash] = DroppedTarget{Item: targets[hash], Reason: reason}
+			delete(targets, hash)
+		}
+		overLimitJobs[job] = true
+		targetsOverLimit.WithLabelValues(job).Set(float64(len(hashes) - cf.maxTargetsPerJob))
+		if !cf.overLimitJobs[job] {
+			cf.log.Info("Dropping the targets of the job over the limit", "job", job, "targets", len(hashes), "max", cf.maxTargetsPerJob)
+		}
+	}
+	for job := range cf.overLimitJobs {
+		if !overLimitJobs[job] {
+			targetsOverLimit.DeleteLabelValues(job)
+		}
+	}
+	cf.overLimitJobs = overLimitJobs
+	return targets
+}
@@ -0,0 +1,63 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+func makeJobTargets(job string, n int) map[string]*target.Item {
+	targets := map[string]*target.Item{}
+	for i := 0; i < n; i++ {
+		item := target.NewItem(job, fmt.Sprintf("test-url-%d", i), model.LabelSet{}, "")
+		targets[item.Hash()] = item
+	}
+	return targets
+}
+
+func TestCardinalityGuardFilter(t *testing.T) {
+	hook := New("cardinality-guard", logger, WithMaxTargetsPerJob(10))
+	targets := makeJobTargets("test-job-0", 25)
+	// a job under the limit
+	for hash, item := range makeJobTargets("small-job", 5) {
+		targets[hash] = item
+	}
+
+	remaining := hook.Apply(targets)
+	assert.Len(t, remaining, 15)
+	dropped := hook.DroppedTargets()
+	assert.Len(t, dropped, 15)
+	for hash, d := range dropped {
+		assert.NotContains(t, remaining, hash)
+		assert.Equal(t, "test-job-0", d.Item.JobName)
+		assert.Equal(t, "cardinality-guard: job test-job-0 has 25 targets, more than the maximum of 10", d.Reason)
+	}
+	assert.Equal(t, 15.0, testutil.ToFloat64(targetsOverLimit.WithLabelValues("test-job-0")))
+
+	// the same targets are kept
+	again := hook.Apply(makeJobTargets("test-job-0", 25))
+	for hash := range again {
+		assert.Contains(t, remaining, hash)
+	}
+
+	// the metric is reset once the job is back under the limit
+	hook.Apply(makeJobTargets("test-job-0", 5))
+	assert.Empty(t, hook.DroppedTargets())
+	assert.Equal(t, 0, testutil.CollectAndCount(targetsOverLimit))
+}
+
+func TestCardinalityGuardFilterUnlimited(t *testing.T) {
+	hook := New("cardinality-guard", logger)
+	remaining := hook.Apply(makeJobTargets("test-job-0", 25))
+	assert.Len(t, remaining, 25)
+	assert.Empty(t, hook.DroppedTargets())
+}
@@ -0,0 +1,45 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+// chain runs several hooks one after the other, each filtering the targets kept by the previous one.
+type chain struct {
+	hooks []Hook
+	relabelConfigStore
+}
+
+func newChain(hooks []Hook) Hook {
+	return &chain{hooks: hooks}
+}
+
+func (c *chain) Apply(targets map[string]*target.Item) map[string]*target.Item {
+	for _, hook := range c.hooks {
+		targets = hook.Apply(targets)
+	}
+	return targets
+}
+
+func (c *chain) SetConfig(cfgs map[string][]*relabel.Config) {
+	c.relabelConfigStore.SetConfig(cfgs)
+	for _, hook := range c.hooks {
+		hook.SetConfig(cfgs)
+	}
+}
+
+// DroppedTargets returns the targets dropped by any of the hooks.
+func (c *chain) DroppedTargets() map[string]DroppedTarget {
+	dropped := make(map[string]DroppedTarget)
+	for _, hook := range c.hooks {
+		for k, v := range hook.DroppedTargets() {
+			dropped[k] = v
+		}
+	}
+	return dropped
+}
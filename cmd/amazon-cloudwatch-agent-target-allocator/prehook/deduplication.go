@@ -0,0 +1,109 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+const (
+	defaultScheme      = "http"
+	defaultMetricsPath = "/metrics"
+)
+
+// DeduplicationFilter keeps a single target among the targets of different jobs resolving to the same scrape URL, so
+// that the same endpoint isn't scraped several times. The target of the job first in alphabetical order is kept.
+type DeduplicationFilter struct {
+	log logr.Logger
+	relabelConfigStore
+	droppedTargets
+}
+
+func NewDeduplicationFilter(log logr.Logger, _ Options) Hook {
+	return &DeduplicationFilter{log: log}
+}
+
+// scrapeURL returns the URL the target is scraped at, from its scheme, address, metrics path and URL parameters labels
+// after the relabel configs of its job, as Prometheus builds it. The labels of a target the relabel configs drop are
+// used as discovered.
+func (df *DeduplicationFilter) scrapeURL(item *target.Item) string {
+	lb := labels.NewBuilder(convertLabelToPromLabelSet(item.Labels))
+	lset := lb.Labels()
+	if relabel.ProcessBuilder(lb, df.relabelCfg[item.JobName]...) {
+		lset = lb.Labels()
+	}
+	scheme := lset.Get(model.SchemeLabel)
+	if scheme == "" {
+		scheme = defaultScheme
+	}
+	address := lset.Get(model.AddressLabel)
+	if address == "" {
+		address = strings.Join(item.TargetURL, "")
+	}
+	metricsPath := lset.Get(model.MetricsPathLabel)
+	if metricsPath == "" {
+		metricsPath = defaultMetricsPath
+	}
+	params := url.Values{}
+	lset.Range(func(l labels.Label) {
+		if name, ok := strings.CutPrefix(l.Name, model.ParamLabelPrefix); ok {
+			params.Set(name, l.Value)
+		}
+	})
+	return (&url.URL{Scheme: scheme, Host: address, Path: metricsPath, RawQuery: params.Encode()}).String()
+}
+
+// precedes returns whether the target is kept over the other one resolving to the same scrape URL.
+func precedes(hash string, item *target.Item, otherHash string, other *target.Item) bool {
+	if item.JobName != other.JobName {
+		return item.JobName < other.JobName
+	}
+	return hash < otherHash
+}
+
+func (df *DeduplicationFilter) Apply(targets map[string]*target.Item) map[string]*target.Item {
+	numTargets := len(targets)
+	dropped := make(map[string]DroppedTarget)
+	defer df.set(dropped)
+
+	// scrape URL -> hash of the target kept
+	kept := make(map[string]string, len(targets))
+	// hash -> scrape URL of the target
+	urls := make(map[string]string, len(targets))
+	for hash, item := range targets {
+		scrapeURL := df.scrapeURL(item)
+		urls[hash] = scrapeURL
+		keptHash, ok := kept[scrapeURL]
+		if !ok {
+			kept[scrapeURL] = hash
+			continue
+		}
+		if precedes(hash, item, keptHash, targets[keptHash]) {
+			kept[scrapeURL] = hash
+		}
+	}
+	for hash, item := range targets {
+		scrapeURL := urls[hash]
+		keptHash := kept[scrapeURL]
+		if keptHash == hash {
+			continue
+		}
+		delete(targets, hash)
+		dropped[hash] = DroppedTarget{
+			Item:   item,
+			Reason: fmt.Sprintf("deduplication: %s is also scraped by job %s", scrapeURL, targets[keptHash].JobName),
+		}
+	}
+	df.log.V(2).Info("Filtering complete", "seen", numTargets, "kept", len(targets))
+	return targets
+}
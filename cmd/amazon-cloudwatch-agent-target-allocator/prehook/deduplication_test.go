@@ -0,0 +1,108 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+func TestDeduplicationFilter(t *testing.T) {
+	hook := New("deduplication", logger)
+	pods := target.NewItem("kubernetes-pods", "10.0.0.1:8080", model.LabelSet{}, "")
+	services := target.NewItem("kubernetes-services", "10.0.0.1:8080", model.LabelSet{}, "")
+	otherPath := target.NewItem("kubernetes-services", "10.0.0.1:8080", model.LabelSet{model.MetricsPathLabel: "/stats"}, "")
+	otherScheme := target.NewItem("kubernetes-endpoints", "10.0.0.1:8080", model.LabelSet{model.SchemeLabel: "https"}, "")
+	explicit := target.NewItem("static", "10.0.0.1:8080", model.LabelSet{model.SchemeLabel: "http", model.MetricsPathLabel: "/metrics"}, "")
+	other := target.NewItem("kubernetes-pods", "10.0.0.2:8080", model.LabelSet{}, "")
+	targets := map[string]*target.Item{}
+	for _, item := range []*target.Item{pods, services, otherPath, otherScheme, explicit, other} {
+		targets[item.Hash()] = item
+	}
+
+	remaining := hook.Apply(targets)
+	assert.Equal(t, map[string]*target.Item{
+		pods.Hash():        pods,
+		otherPath.Hash():   otherPath,
+		otherScheme.Hash(): otherScheme,
+		other.Hash():       other,
+	}, remaining)
+	assert.Equal(t, map[string]DroppedTarget{
+		services.Hash(): {
+			Item:   services,
+			Reason: "deduplication: http://10.0.0.1:8080/metrics is also scraped by job kubernetes-pods",
+		},
+		explicit.Hash(): {
+			Item:   explicit,
+			Reason: "deduplication: http://10.0.0.1:8080/metrics is also scraped by job kubernetes-pods",
+		},
+	}, hook.DroppedTargets())
+}
+
+func TestDeduplicationFilterRelabeled(t *testing.T) {
+	hook := New("deduplication", logger)
+	hook.SetConfig(map[string][]*relabel.Config{
+		// scrapes the pods at the path and port of their annotations
+		"kubernetes-pods": {
+			{
+				SourceLabels:         model.LabelNames{"__meta_kubernetes_pod_annotation_prometheus_io_path"},
+				Regex:                relabel.MustNewRegexp("(.+)"),
+				Separator:            ";",
+				Action:               "replace",
+				Replacement:          "$1",
+				TargetLabel:          model.MetricsPathLabel,
+				NameValidationScheme: model.UTF8Validation,
+			},
+			{
+				SourceLabels:         model.LabelNames{model.AddressLabel, "__meta_kubernetes_pod_annotation_prometheus_io_port"},
+				Regex:                relabel.MustNewRegexp(`([^:]+)(?::\d+)?;(\d+)`),
+				Separator:            ";",
+				Action:               "replace",
+				Replacement:          "$1:$2",
+				TargetLabel:          model.AddressLabel,
+				NameValidationScheme: model.UTF8Validation,
+			},
+		},
+	})
+	newItem := func(job string, address string, lset model.LabelSet) *target.Item {
+		discovered := model.LabelSet{model.AddressLabel: model.LabelValue(address), model.SchemeLabel: "http", model.MetricsPathLabel: "/metrics"}
+		return target.NewItem(job, address, discovered.Merge(lset), "")
+	}
+	// the pod scraped at another path than the endpoint with the same address
+	otherPath := newItem("kubernetes-pods", "10.0.0.1:8080", model.LabelSet{"__meta_kubernetes_pod_annotation_prometheus_io_path": "/stats"})
+	endpoint := newItem("kubernetes-endpoints", "10.0.0.1:8080", nil)
+	// the pod scraped at the port of the endpoint with another address
+	samePort := newItem("kubernetes-pods", "10.0.0.2:8080", model.LabelSet{"__meta_kubernetes_pod_annotation_prometheus_io_port": "9090"})
+	sameEndpoint := newItem("kubernetes-endpoints", "10.0.0.2:9090", nil)
+	// the probes of different modules
+	httpModule := newItem("blackbox-http", "10.0.0.3:9115", model.LabelSet{model.ParamLabelPrefix + "module": "http_2xx"})
+	tcpModule := newItem("blackbox-tcp", "10.0.0.3:9115", model.LabelSet{model.ParamLabelPrefix + "module": "tcp_connect"})
+	// the job scraping the endpoint with another scheme
+	otherScheme := newItem("kubernetes-endpoints-tls", "10.0.0.1:8080", model.LabelSet{model.SchemeLabel: "https"})
+	targets := map[string]*target.Item{}
+	for _, item := range []*target.Item{otherPath, endpoint, samePort, sameEndpoint, httpModule, tcpModule, otherScheme} {
+		targets[item.Hash()] = item
+	}
+
+	remaining := hook.Apply(targets)
+	assert.Equal(t, map[string]*target.Item{
+		otherPath.Hash():    otherPath,
+		endpoint.Hash():     endpoint,
+		sameEndpoint.Hash(): sameEndpoint,
+		httpModule.Hash():   httpModule,
+		tcpModule.Hash():    tcpModule,
+		otherScheme.Hash():  otherScheme,
+	}, remaining)
+	assert.Equal(t, map[string]DroppedTarget{
+		samePort.Hash(): {
+			Item:   samePort,
+			Reason: "deduplication: http://10.0.0.2:9090/metrics is also scraped by job kubernetes-endpoints",
+		},
+	}, hook.DroppedTargets())
+}
@@ -0,0 +1,69 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/common/model"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+// namespaceLabel is the label of the namespace of the targets discovered in Kubernetes.
+const namespaceLabel model.LabelName = "__meta_kubernetes_namespace"
+
+// NamespaceFilter drops the targets of the denied namespaces and, if any namespace is allowed, the targets of the
+// namespaces not allowed. The targets discovered outside of Kubernetes, without a namespace, are kept.
+type NamespaceFilter struct {
+	log     logr.Logger
+	allowed map[string]bool
+	denied  map[string]bool
+	relabelConfigStore
+	droppedTargets
+}
+
+func NewNamespaceFilter(log logr.Logger, opts Options) Hook {
+	nf := &NamespaceFilter{
+		log:     log,
+		allowed: make(map[string]bool, len(opts.AllowedNamespaces)),
+		denied:  make(map[string]bool, len(opts.DeniedNamespaces)),
+	}
+	for _, namespace := range opts.AllowedNamespaces {
+		nf.allowed[namespace] = true
+	}
+	for _, namespace := range opts.DeniedNamespaces {
+		nf.denied[namespace] = true
+	}
+	return nf
+}
+
+// dropReason returns why the targets of the namespace are dropped, empty if they are kept.
+func (nf *NamespaceFilter) dropReason(namespace string) string {
+	switch {
+	case namespace == "":
+		return ""
+	case nf.denied[namespace]:
+		return fmt.Sprintf("namespace: %s is denied", namespace)
+	case len(nf.allowed) > 0 && !nf.allowed[namespace]:
+		return fmt.Sprintf("namespace: %s is not allowed", namespace)
+	}
+	return ""
+}
+
+func (nf *NamespaceFilter) Apply(targets map[string]*target.Item) map[string]*target.Item {
+	numTargets := len(targets)
+	dropped := make(map[string]DroppedTarget)
+	defer nf.set(dropped)
+
+	for hash, item := range targets {
+		if reason := nf.dropReason(string(item.Labels[namespaceLabel])); reason != "" {
+			delete(targets, hash)
+			dropped[hash] = DroppedTarget{Item: item, Reason: reason}
+		}
+	}
+	nf.log.V(2).Info("Filtering complete", "seen", numTargets, "kept", len(targets))
+	return targets
+}
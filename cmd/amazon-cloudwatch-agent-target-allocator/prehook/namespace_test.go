@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+func TestNamespaceFilter(t *testing.T) {
+	inNamespace := func(namespace string) *target.Item {
+		return target.NewItem("test-job", "url-"+namespace, model.LabelSet{namespaceLabel: model.LabelValue(namespace)}, "")
+	}
+	tests := []struct {
+		name        string
+		allowed     []string
+		denied      []string
+		wantKept    []string
+		wantDropped map[string]string
+	}{
+		{
+			name:     "no namespace configured",
+			wantKept: []string{"", "default", "kube-system", "monitoring"},
+		},
+		{
+			name:     "denied",
+			denied:   []string{"kube-system"},
+			wantKept: []string{"", "default", "monitoring"},
+			wantDropped: map[string]string{
+				"kube-system": "namespace: kube-system is denied",
+			},
+		},
+		{
+			name:     "allowed",
+			allowed:  []string{"default", "monitoring"},
+			wantKept: []string{"", "default", "monitoring"},
+			wantDropped: map[string]string{
+				"kube-system": "namespace: kube-system is not allowed",
+			},
+		},
+		{
+			name:     "allowed and denied",
+			allowed:  []string{"default", "monitoring"},
+			denied:   []string{"monitoring"},
+			wantKept: []string{"", "default"},
+			wantDropped: map[string]string{
+				"kube-system": "namespace: kube-system is not allowed",
+				"monitoring":  "namespace: monitoring is denied",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := New("namespace", logger, WithNamespaces(tt.allowed, tt.denied))
+			items := map[string]*target.Item{}
+			targets := map[string]*target.Item{}
+			for _, namespace := range []string{"", "default", "kube-system", "monitoring"} {
+				item := inNamespace(namespace)
+				items[namespace] = item
+				targets[item.Hash()] = item
+			}
+			// the targets discovered outside of Kubernetes have no namespace label
+			delete(items[""].Labels, namespaceLabel)
+
+			remaining := hook.Apply(targets)
+			wantRemaining := map[string]*target.Item{}
+			for _, namespace := range tt.wantKept {
+				wantRemaining[items[namespace].Hash()] = items[namespace]
+			}
+			assert.Equal(t, wantRemaining, remaining)
+			wantDropped := map[string]DroppedTarget{}
+			for namespace, reason := range tt.wantDropped {
+				wantDropped[items[namespace].Hash()] = DroppedTarget{Item: items[namespace], Reason: reason}
+			}
+			assert.Equal(t, wantDropped, hook.DroppedTargets())
+		})
+	}
+}
@@ -5,6 +5,8 @@ package prehook
 
 import (
 	"errors"
+	"strings"
+	"sync"
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus/prometheus/model/relabel"
@@ -14,27 +16,83 @@ import (
 
 const (
 	relabelConfigTargetFilterName = "relabel-config"
+	deduplicationFilterName       = "deduplication"
+	namespaceFilterName           = "namespace"
+	cardinalityGuardFilterName    = "cardinality-guard"
+
+	// strategySeparator separates the names of the hooks chained by a filter strategy.
+	strategySeparator = ","
 )
 
 type Hook interface {
 	Apply(map[string]*target.Item) map[string]*target.Item
 	SetConfig(map[string][]*relabel.Config)
 	GetConfig() map[string][]*relabel.Config
+	// DroppedTargets returns the targets dropped by the last Apply, keyed by target item hash.
+	DroppedTargets() map[string]DroppedTarget
+}
+
+// DroppedTarget is a target dropped by a hook, with the reason it was.
+type DroppedTarget struct {
+	Item *target.Item
+	// Reason tells why the target was dropped, e.g. the relabel config dropping it.
+	Reason string
+}
+
+// Options are the settings of the hooks, each hook using the ones it needs.
+type Options struct {
+	// AllowedNamespaces are the only namespaces the namespace hook keeps the targets of, if any.
+	AllowedNamespaces []string
+	// DeniedNamespaces are the namespaces the namespace hook drops the targets of.
+	DeniedNamespaces []string
+	// MaxTargetsPerJob is the number of targets of a job the cardinality guard hook keeps, unlimited if 0.
+	MaxTargetsPerJob int
+}
+
+type Option func(*Options)
+
+// WithNamespaces sets the namespaces the namespace hook allows and denies the targets of.
+func WithNamespaces(allowed []string, denied []string) Option {
+	return func(opts *Options) {
+		opts.AllowedNamespaces = allowed
+		opts.DeniedNamespaces = denied
+	}
+}
+
+// WithMaxTargetsPerJob sets the number of targets of a job the cardinality guard hook keeps.
+func WithMaxTargetsPerJob(max int) Option {
+	return func(opts *Options) {
+		opts.MaxTargetsPerJob = max
+	}
 }
 
-type HookProvider func(log logr.Logger) Hook
+type HookProvider func(log logr.Logger, opts Options) Hook
 
 var (
 	registry = map[string]HookProvider{}
 )
 
-func New(name string, log logr.Logger) Hook {
-	if p, ok := registry[name]; ok {
-		return p(log.WithName("Prehook").WithName(name))
+// New returns the hook of the filter strategy. The strategy is the name of a hook, or the names of several hooks
+// separated by commas, e.g. relabel-config,deduplication, chained in this order.
+func New(name string, log logr.Logger, opts ...Option) Hook {
+	var options Options
+	for _, opt := range opts {
+		opt(&options)
 	}
-
-	log.Info("Unrecognized filter strategy; filtering disabled")
-	return nil
+	var hooks []Hook
+	for _, hookName := range strings.Split(name, strategySeparator) {
+		hookName = strings.TrimSpace(hookName)
+		p, ok := registry[hookName]
+		if !ok {
+			log.Info("Unrecognized filter strategy; filtering disabled", "filterStrategy", name)
+			return nil
+		}
+		hooks = append(hooks, p(log.WithName("Prehook").WithName(hookName), options))
+	}
+	if len(hooks) == 1 {
+		return hooks[0]
+	}
+	return newChain(hooks)
 }
 
 func Register(name string, provider HookProvider) error {
@@ -45,9 +103,59 @@ func Register(name string, provider HookProvider) error {
 	return nil
 }
 
+// droppedTargets records the targets dropped by the last Apply of a hook, read by the debug handlers.
+type droppedTargets struct {
+	mtx     sync.RWMutex
+	dropped map[string]DroppedTarget
+}
+
+func (d *droppedTargets) set(dropped map[string]DroppedTarget) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.dropped = dropped
+}
+
+// DroppedTargets returns a copy of the targets dropped by the last Apply.
+func (d *droppedTargets) DroppedTargets() map[string]DroppedTarget {
+	d.mtx.RLock()
+	defer d.mtx.RUnlock()
+	droppedCopy := make(map[string]DroppedTarget, len(d.dropped))
+	for k, v := range d.dropped {
+		droppedCopy[k] = v
+	}
+	return droppedCopy
+}
+
+// relabelConfigStore keeps the relabel configs of the jobs for the hooks not filtering with them.
+type relabelConfigStore struct {
+	relabelCfg map[string][]*relabel.Config
+}
+
+func (r *relabelConfigStore) SetConfig(cfgs map[string][]*relabel.Config) {
+	relabelCfgCopy := make(map[string][]*relabel.Config, len(cfgs))
+	for k, v := range cfgs {
+		relabelCfgCopy[k] = v
+	}
+	r.relabelCfg = relabelCfgCopy
+}
+
+func (r *relabelConfigStore) GetConfig() map[string][]*relabel.Config {
+	relabelCfgCopy := make(map[string][]*relabel.Config, len(r.relabelCfg))
+	for k, v := range r.relabelCfg {
+		relabelCfgCopy[k] = v
+	}
+	return relabelCfgCopy
+}
+
 func init() {
-	err := Register(relabelConfigTargetFilterName, NewRelabelConfigTargetFilter)
-	if err != nil {
-		panic(err)
+	for name, provider := range map[string]HookProvider{
+		relabelConfigTargetFilterName: NewRelabelConfigTargetFilter,
+		deduplicationFilterName:       NewDeduplicationFilter,
+		namespaceFilterName:           NewNamespaceFilter,
+		cardinalityGuardFilterName:    NewCardinalityGuardFilter,
+	} {
+		if err := Register(name, provider); err != nil {
+			panic(err)
+		}
 	}
 }
@@ -0,0 +1,84 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package prehook
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		want     interface{}
+	}{
+		{name: "relabel config", strategy: "relabel-config", want: &RelabelConfigTargetFilter{}},
+		{name: "deduplication", strategy: "deduplication", want: &DeduplicationFilter{}},
+		{name: "namespace", strategy: "namespace", want: &NamespaceFilter{}},
+		{name: "cardinality guard", strategy: "cardinality-guard", want: &CardinalityGuardFilter{}},
+		{name: "chain", strategy: "relabel-config, namespace", want: &chain{}},
+		{name: "unrecognized", strategy: "unknown", want: nil},
+		{name: "unrecognized in chain", strategy: "relabel-config,unknown", want: nil},
+		{name: "empty", strategy: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := New(tt.strategy, logger)
+			if tt.want == nil {
+				assert.Nil(t, hook)
+				return
+			}
+			assert.IsType(t, tt.want, hook)
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	hook := New("relabel-config,namespace,cardinality-guard", logger,
+		WithNamespaces(nil, []string{"denied"}),
+		WithMaxTargetsPerJob(1))
+	require.NotNil(t, hook)
+	relabelCfg := map[string][]*relabel.Config{
+		"test-job": {
+			{
+				SourceLabels:         model.LabelNames{"i"},
+				Regex:                relabel.MustNewRegexp("0"),
+				Separator:            ";",
+				Action:               "drop",
+				Replacement:          "$1",
+				NameValidationScheme: model.UTF8Validation,
+			},
+		},
+	}
+	hook.SetConfig(relabelCfg)
+	assert.Equal(t, relabelCfg, hook.GetConfig())
+
+	relabeled := target.NewItem("test-job", "url-0", model.LabelSet{"i": "0", namespaceLabel: "allowed"}, "")
+	denied := target.NewItem("test-job", "url-1", model.LabelSet{"i": "1", namespaceLabel: "denied"}, "")
+	first := target.NewItem("test-job", "url-2", model.LabelSet{"i": "2", namespaceLabel: "allowed"}, "")
+	second := target.NewItem("test-job", "url-3", model.LabelSet{"i": "3", namespaceLabel: "allowed"}, "")
+	kept, overLimit := first, second
+	if second.Hash() < first.Hash() {
+		kept, overLimit = second, first
+	}
+	targets := map[string]*target.Item{}
+	for _, item := range []*target.Item{relabeled, denied, first, second} {
+		targets[item.Hash()] = item
+	}
+
+	remaining := hook.Apply(targets)
+	assert.Equal(t, map[string]*target.Item{kept.Hash(): kept}, remaining)
+	dropped := hook.DroppedTargets()
+	require.Len(t, dropped, 3)
+	assert.Contains(t, dropped[relabeled.Hash()].Reason, "relabel_configs[0]")
+	assert.Equal(t, "namespace: denied is denied", dropped[denied.Hash()].Reason)
+	assert.Contains(t, dropped[overLimit.Hash()].Reason, "cardinality-guard")
+}
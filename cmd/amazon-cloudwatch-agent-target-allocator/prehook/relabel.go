@@ -4,6 +4,8 @@
 package prehook
 
 import (
+	"fmt"
+
 	"github.com/go-logr/logr"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
@@ -15,9 +17,10 @@ import (
 type RelabelConfigTargetFilter struct {
 	log        logr.Logger
 	relabelCfg map[string][]*relabel.Config
+	droppedTargets
 }
 
-func NewRelabelConfigTargetFilter(log logr.Logger) Hook {
+func NewRelabelConfigTargetFilter(log logr.Logger, _ Options) Hook {
 	return &RelabelConfigTargetFilter{
 		log:        log,
 		relabelCfg: make(map[string][]*relabel.Config),
@@ -35,6 +38,8 @@ func convertLabelToPromLabelSet(lbls model.LabelSet) labels.Labels {
 
 func (tf *RelabelConfigTargetFilter) Apply(targets map[string]*target.Item) map[string]*target.Item {
 	numTargets := len(targets)
+	dropped := make(map[string]DroppedTarget)
+	defer tf.set(dropped)
 
 	// need to wait until relabelCfg is set
 	if len(tf.relabelCfg) == 0 {
@@ -45,17 +50,24 @@ func (tf *RelabelConfigTargetFilter) Apply(targets map[string]*target.Item) map[
 	for jobNameKey, tItem := range targets {
 		lset := convertLabelToPromLabelSet(tItem.Labels)
 		lb := labels.NewBuilder(lset)
-		keep := relabel.ProcessBuilder(lb, tf.relabelCfg[tItem.JobName]...)
-
-		if !keep {
-			delete(targets, jobNameKey)
+		// The relabel configs are processed one at a time to tell which one drops the target
+		for i, cfg := range tf.relabelCfg[tItem.JobName] {
+			if !relabel.ProcessBuilder(lb, cfg) {
+				delete(targets, jobNameKey)
+				dropped[jobNameKey] = DroppedTarget{Item: tItem, Reason: describeRelabelConfig(i, cfg)}
+				break
+			}
 		}
 	}
-
 	tf.log.V(2).Info("Filtering complete", "seen", numTargets, "kept", len(targets))
 	return targets
 }
 
+// describeRelabelConfig describes the relabel config of a job at the index.
+func describeRelabelConfig(index int, cfg *relabel.Config) string {
+	return fmt.Sprintf("relabel_configs[%d]: action=%s source_labels=[%s] regex=%s", index, cfg.Action, cfg.SourceLabels, cfg.Regex.String())
+}
+
 func (tf *RelabelConfigTargetFilter) SetConfig(cfgs map[string][]*relabel.Config) {
 	relabelCfgCopy := make(map[string][]*relabel.Config)
 	for key, val := range cfgs {
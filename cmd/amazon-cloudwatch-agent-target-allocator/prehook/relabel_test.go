@@ -268,3 +268,37 @@ func TestSetConfig(t *testing.T) {
 	allocatorPrehook.SetConfig(relabelCfg)
 	assert.Equal(t, relabelCfg, allocatorPrehook.GetConfig())
 }
+
+func TestDroppedTargets(t *testing.T) {
+	allocatorPrehook := New("relabel-config", logger)
+	assert.NotNil(t, allocatorPrehook)
+
+	kept := target.NewItem("test-job", "kept-url", model.LabelSet{"i": "0"}, "")
+	dropped := target.NewItem("test-job", "dropped-url", model.LabelSet{"i": "1"}, "")
+	allocatorPrehook.SetConfig(map[string][]*relabel.Config{
+		"test-job": {
+			relabelConfigs[0].cfg[0],
+			{
+				SourceLabels:         model.LabelNames{"i"},
+				Regex:                relabel.MustNewRegexp("1"),
+				Separator:            ";",
+				Action:               "drop",
+				Replacement:          "$1",
+				NameValidationScheme: model.UTF8Validation,
+			},
+		},
+	})
+	remainingItems := allocatorPrehook.Apply(map[string]*target.Item{kept.Hash(): kept, dropped.Hash(): dropped})
+	assert.Equal(t, map[string]*target.Item{kept.Hash(): kept}, remainingItems)
+	assert.Equal(t, map[string]DroppedTarget{
+		dropped.Hash(): {
+			Item:   dropped,
+			Reason: "relabel_configs[1]: action=drop source_labels=[i] regex=1",
+		},
+	}, allocatorPrehook.DroppedTargets())
+
+	// the dropped targets are the ones of the last filtering
+	allocatorPrehook.SetConfig(map[string][]*relabel.Config{})
+	allocatorPrehook.Apply(map[string]*target.Item{kept.Hash(): kept, dropped.Hash(): dropped})
+	assert.Empty(t, allocatorPrehook.DroppedTargets())
+}
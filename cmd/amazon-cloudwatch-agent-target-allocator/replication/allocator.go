@@ -0,0 +1,253 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package replication
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/allocation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+var _ allocation.Allocator = &Allocator{}
+
+// Assignments is a map from a target item's hash to the name of the collector it is assigned to.
+type Assignments map[string]string
+
+// Allocator allocates the targets with the allocation strategy once its replica leads. Until then, it serves the
+// assignments replicated from the leader for the targets its replica discovers, so that every replica serves the
+// same targets to the collectors.
+type Allocator struct {
+	// m protects the fields below for concurrent use.
+	m sync.RWMutex
+
+	// allocator is the allocation strategy, only used once leading
+	allocator allocation.Allocator
+	leading   bool
+
+	// targets and collectors are the last ones discovered
+	targets    map[string]*target.Item
+	collectors map[string]*allocation.Collector
+
+	// assignments are the last ones replicated from the leader
+	assignments Assignments
+
+	// targetItems is a map from a target item's hash to the discovered target assigned by the leader
+	// targetItem hash -> target item pointer
+	targetItems map[string]*target.Item
+
+	// assignedCollectors is a map from a Collector's name to the Collector with the number of targets assigned to it
+	// collectorKey -> collector pointer
+	assignedCollectors map[string]*allocation.Collector
+
+	// collectorKey -> job -> target item hash -> true
+	targetItemsPerJobPerCollector map[string]map[string]map[string]bool
+
+	// onChange is called when the assignments replicated from the leader change or once leading
+	onChange func()
+
+	log logr.Logger
+}
+
+func NewAllocator(log logr.Logger, allocator allocation.Allocator) *Allocator {
+	return &Allocator{
+		allocator:                     allocator,
+		targetItems:                   make(map[string]*target.Item),
+		assignedCollectors:            make(map[string]*allocation.Collector),
+		targetItemsPerJobPerCollector: make(map[string]map[string]map[string]bool),
+		log:                           log,
+	}
+}
+
+// SetFilter sets the filtering hook of the allocation strategy.
+func (a *Allocator) SetFilter(filter allocation.Filter) {
+	a.allocator.SetFilter(filter)
+}
+
+// SetWeights sets the scrape cost of the targets of the allocation strategy.
+func (a *Allocator) SetWeights(weights *allocation.Weights) {
+	a.allocator.SetWeights(weights)
+}
+
+// assign assigns the discovered targets to the collectors with the assignments replicated from the leader. The
+// targets the leader didn't assign, e.g. filtered or not discovered yet by the leader, aren't served.
+// The caller of this method has to acquire a lock.
+func (a *Allocator) assign() {
+	a.targetItems = make(map[string]*target.Item, len(a.assignments))
+	a.assignedCollectors = make(map[string]*allocation.Collector, len(a.collectors))
+	a.targetItemsPerJobPerCollector = make(map[string]map[string]map[string]bool, len(a.collectors))
+	for name, col := range a.collectors {
+		a.assignedCollectors[name] = allocation.NewCollector(name, col.NodeName)
+	}
+	for hash, item := range a.targets {
+		name, ok := a.assignments[hash]
+		if !ok {
+			continue
+		}
+		assigned := target.NewItem(item.JobName, strings.Join(item.TargetURL, ""), item.Labels, name)
+		a.targetItems[hash] = assigned
+		if a.targetItemsPerJobPerCollector[name] == nil {
+			a.targetItemsPerJobPerCollector[name] = make(map[string]map[string]bool)
+		}
+		if a.targetItemsPerJobPerCollector[name][item.JobName] == nil {
+			a.targetItemsPerJobPerCollector[name][item.JobName] = make(map[string]bool)
+		}
+		a.targetItemsPerJobPerCollector[name][item.JobName][hash] = true
+		if col, ok := a.assignedCollectors[name]; ok {
+			col.NumTargets++
+		}
+	}
+}
+
+// SetTargets sets the discovered targets, allocated by the allocation strategy once leading.
+func (a *Allocator) SetTargets(targets map[string]*target.Item) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	a.targets = targets
+	if a.leading {
+		a.allocator.SetTargets(targets)
+		return
+	}
+	a.assign()
+}
+
+// SetCollectors sets the discovered collectors, used by the allocation strategy once leading.
+func (a *Allocator) SetCollectors(collectors map[string]*allocation.Collector) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	// the collector watcher keeps updating its map
+	a.collectors = make(map[string]*allocation.Collector, len(collectors))
+	for k, v := range collectors {
+		a.collectors[k] = v
+	}
+	if a.leading {
+		a.allocator.SetCollectors(collectors)
+		return
+	}
+	a.assign()
+}
+
+// OnChange sets the function called when the assignments replicated from the leader change or once leading, the
+// changes SetTargets and SetCollectors callers don't know about.
+func (a *Allocator) OnChange(fn func()) {
+	a.m.Lock()
+	defer a.m.Unlock()
+	a.onChange = fn
+}
+
+// changed calls the onChange function, if any.
+func (a *Allocator) changed() {
+	a.m.RLock()
+	onChange := a.onChange
+	a.m.RUnlock()
+	if onChange != nil {
+		onChange()
+	}
+}
+
+// SetAssignments sets the assignments replicated from the leader. They are ignored once leading.
+func (a *Allocator) SetAssignments(assignments Assignments) {
+	a.m.Lock()
+	if a.leading {
+		a.m.Unlock()
+		return
+	}
+	a.assignments = assignments
+	a.assign()
+	a.m.Unlock()
+	a.changed()
+}
+
+// Lead allocates the targets with the allocation strategy from now on. The targets start from the collector the
+// previous leader assigned them to, which the allocation strategy keeps if it allows it, so that the collectors
+// don't drop their targets when the leader changes.
+func (a *Allocator) Lead() {
+	a.m.Lock()
+	if a.leading {
+		a.m.Unlock()
+		return
+	}
+	a.leading = true
+	targets := make(map[string]*target.Item, len(a.targets))
+	for hash, item := range a.targets {
+		targets[hash] = item
+		if name, ok := a.assignments[hash]; ok {
+			targets[hash] = target.NewItem(item.JobName, strings.Join(item.TargetURL, ""), item.Labels, name)
+		}
+	}
+	if len(a.collectors) > 0 {
+		a.allocator.SetCollectors(a.collectors)
+	}
+	a.allocator.SetTargets(targets)
+	a.targetItems = nil
+	a.assignedCollectors = nil
+	a.targetItemsPerJobPerCollector = nil
+	a.m.Unlock()
+	a.changed()
+}
+
+// Leading returns whether the targets are allocated by the allocation strategy.
+func (a *Allocator) Leading() bool {
+	a.m.RLock()
+	defer a.m.RUnlock()
+	return a.leading
+}
+
+// Assignments returns the assignments of the targets allocated by the allocation strategy, nil until leading.
+func (a *Allocator) Assignments() Assignments {
+	if !a.Leading() {
+		return nil
+	}
+	assignments := Assignments{}
+	for hash, item := range a.allocator.TargetItems() {
+		if item.CollectorName != "" {
+			assignments[hash] = item.CollectorName
+		}
+	}
+	return assignments
+}
+
+func (a *Allocator) GetTargetsForCollectorAndJob(collector string, job string) []*target.Item {
+	a.m.RLock()
+	defer a.m.RUnlock()
+	if a.leading {
+		return a.allocator.GetTargetsForCollectorAndJob(collector, job)
+	}
+	targetItemsCopy := make([]*target.Item, 0, len(a.targetItemsPerJobPerCollector[collector][job]))
+	for targetHash := range a.targetItemsPerJobPerCollector[collector][job] {
+		targetItemsCopy = append(targetItemsCopy, a.targetItems[targetHash])
+	}
+	return targetItemsCopy
+}
+
+// TargetItems returns a shallow copy of the targetItems map.
+func (a *Allocator) TargetItems() map[string]*target.Item {
+	a.m.RLock()
+	defer a.m.RUnlock()
+	if a.leading {
+		return a.allocator.TargetItems()
+	}
+	targetItemsCopy := make(map[string]*target.Item, len(a.targetItems))
+	for k, v := range a.targetItems {
+		targetItemsCopy[k] = v
+	}
+	return targetItemsCopy
+}
+
+// Collectors returns a shallow copy of the collectors map.
+func (a *Allocator) Collectors() map[string]*allocation.Collector {
+	a.m.RLock()
+	defer a.m.RUnlock()
+	if a.leading {
+		return a.allocator.Collectors()
+	}
+	collectorsCopy := make(map[string]*allocation.Collector, len(a.assignedCollectors))
+	for k, v := range a.assignedCollectors {
+		collectorsCopy[k] = v
+	}
+	return collectorsCopy
+}
@@ -0,0 +1,119 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package replication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/allocation"
+)
+
+var logger = logf.Log.WithName("replication-unit-tests")
+
+func newTestAllocator(t *testing.T, strategy string) *Allocator {
+	allocator, err := allocation.New(strategy, logger)
+	require.NoError(t, err)
+	return NewAllocator(logger, allocator)
+}
+
+func TestFollowerServesReplicatedAssignments(t *testing.T) {
+	a := newTestAllocator(t, "least-weighted")
+	targets := allocation.MakeNNewTargetsWithEmptyCollectors(4, 0)
+	a.SetCollectors(allocation.MakeNCollectors(2, 0))
+	a.SetTargets(targets)
+	assert.Empty(t, a.TargetItems(), "targets aren't served until the leader assigns them")
+
+	assignments := Assignments{}
+	i := 0
+	for hash := range targets {
+		// the leader didn't discover the last target yet
+		if i == 3 {
+			break
+		}
+		assignments[hash] = "collector-0"
+		if i == 2 {
+			assignments[hash] = "collector-1"
+		}
+		i++
+	}
+	a.SetAssignments(assignments)
+
+	assert.False(t, a.Leading())
+	assert.Nil(t, a.Assignments())
+	items := a.TargetItems()
+	assert.Len(t, items, 3)
+	for hash, item := range items {
+		assert.Equal(t, assignments[hash], item.CollectorName)
+		assert.Empty(t, targets[hash].CollectorName, "the discovered targets must not be modified")
+		assert.Contains(t, a.GetTargetsForCollectorAndJob(item.CollectorName, item.JobName), item)
+	}
+	collectors := a.Collectors()
+	assert.Len(t, collectors, 2)
+	assert.Equal(t, 2, collectors["collector-0"].NumTargets)
+	assert.Equal(t, 1, collectors["collector-1"].NumTargets)
+	assert.Empty(t, a.GetTargetsForCollectorAndJob("collector-2", "test-job-0"))
+}
+
+func TestLeaderKeepsReplicatedAssignments(t *testing.T) {
+	a := newTestAllocator(t, "least-weighted")
+	targets := allocation.MakeNNewTargetsWithEmptyCollectors(4, 0)
+	a.SetCollectors(allocation.MakeNCollectors(2, 0))
+	a.SetTargets(targets)
+	assignments := Assignments{}
+	for hash := range targets {
+		assignments[hash] = "collector-1"
+	}
+	a.SetAssignments(assignments)
+
+	a.Lead()
+	assert.True(t, a.Leading())
+	assert.Equal(t, assignments, a.Assignments(), "the collectors must keep their targets when the leader changes")
+	assert.Equal(t, 4, a.Collectors()["collector-1"].NumTargets)
+
+	// the assignments of the previous leader are ignored, new targets are allocated by the allocation strategy
+	a.SetAssignments(Assignments{})
+	for hash, item := range allocation.MakeNNewTargetsWithEmptyCollectors(2, 4) {
+		targets[hash] = item
+	}
+	a.SetTargets(targets)
+	assert.Len(t, a.Assignments(), 6)
+	assert.Equal(t, 2, a.Collectors()["collector-0"].NumTargets)
+	assert.Equal(t, 4, a.Collectors()["collector-1"].NumTargets)
+}
+
+func TestLeaderAllocatesWithoutReplicatedAssignments(t *testing.T) {
+	a := newTestAllocator(t, "consistent-hashing")
+	a.SetTargets(allocation.MakeNNewTargetsWithEmptyCollectors(10, 0))
+	a.Lead()
+	assert.Empty(t, a.Assignments(), "targets aren't assigned without collectors")
+
+	a.SetCollectors(allocation.MakeNCollectors(3, 0))
+	assignments := a.Assignments()
+	assert.Len(t, assignments, 10)
+	total := 0
+	for _, col := range a.Collectors() {
+		total += col.NumTargets
+	}
+	assert.Equal(t, 10, total)
+}
+
+func TestAllocatorOnChange(t *testing.T) {
+	a := newTestAllocator(t, "consistent-hashing")
+	changes := 0
+	a.OnChange(func() { changes++ })
+
+	a.SetCollectors(allocation.MakeNCollectors(1, 0))
+	a.SetTargets(allocation.MakeNNewTargetsWithEmptyCollectors(1, 0))
+	assert.Equal(t, 0, changes, "the callers of SetTargets and SetCollectors know about their changes")
+	a.SetAssignments(Assignments{})
+	assert.Equal(t, 1, changes)
+	a.Lead()
+	assert.Equal(t, 2, changes)
+	a.SetAssignments(Assignments{})
+	assert.Equal(t, 2, changes, "the replicated assignments are ignored once leading")
+}
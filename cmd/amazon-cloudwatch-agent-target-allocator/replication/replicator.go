@@ -0,0 +1,300 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package replication
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	allocatorconfig "github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/config"
+)
+
+const (
+	// publishInterval is how often the leader publishes the assignments, if they changed.
+	publishInterval = 5 * time.Second
+
+	// assignmentsKey is the key of the gzipped assignments in the binary data of the assignments ConfigMap.
+	assignmentsKey = "assignments.json.gz"
+)
+
+var (
+	errLostLeadership = errors.New("lost the leadership of the target allocator replicas")
+
+	leader = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "cloudwatch_agent_allocator_leader",
+		Help: "Whether the target allocator replica is the leader.",
+	})
+)
+
+// Replicator elects the leader among the target allocator replicas with a Lease. The leader allocates the targets
+// and publishes the assignments to a ConfigMap, which the followers replicate.
+type Replicator struct {
+	log       logr.Logger
+	k8sClient kubernetes.Interface
+	close     chan struct{}
+
+	allocator *Allocator
+	cfg       allocatorconfig.LeaderElectionConfig
+	namespace string
+	identity  string
+}
+
+func NewReplicator(logger logr.Logger, kubeConfig *rest.Config, cfg allocatorconfig.LeaderElectionConfig, allocator *Allocator) (*Replicator, error) {
+	clientset, err := kubernetes.NewForConfig(kubeConfig)
+	if err != nil {
+		return &Replicator{}, err
+	}
+	namespace := os.Getenv("OTELCOL_NAMESPACE")
+	if namespace == "" {
+		return &Replicator{}, errors.New("the OTELCOL_NAMESPACE environment variable must be set for leader election")
+	}
+	// the pod name
+	identity, err := os.Hostname()
+	if err != nil {
+		return &Replicator{}, err
+	}
+
+	return &Replicator{
+		log:       logger.WithValues("component", "amazon-cloudwatch-agent-target-allocator"),
+		k8sClient: clientset,
+		close:     make(chan struct{}),
+		allocator: allocator,
+		cfg:       cfg,
+		namespace: namespace,
+		identity:  identity,
+	}, nil
+}
+
+// assignmentsConfigMapName returns the name of the ConfigMap the leader publishes the assignments to.
+func assignmentsConfigMapName(leaseName string) string {
+	return leaseName + "-assignments"
+}
+
+// encodeAssignments encodes the assignments grouped by collector, as gzipped JSON.
+func encodeAssignments(assignments Assignments) ([]byte, error) {
+	targetsPerCollector := map[string][]string{}
+	for hash, name := range assignments {
+		targetsPerCollector[name] = append(targetsPerCollector[name], hash)
+	}
+	for _, hashes := range targetsPerCollector {
+		sort.Strings(hashes)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(targetsPerCollector); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeAssignments(data []byte) (Assignments, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	targetsPerCollector := map[string][]string{}
+	if err = json.Unmarshal(raw, &targetsPerCollector); err != nil {
+		return nil, err
+	}
+	assignments := Assignments{}
+	for name, hashes := range targetsPerCollector {
+		for _, hash := range hashes {
+			assignments[hash] = name
+		}
+	}
+	return assignments, nil
+}
+
+// saveAssignments creates or updates the assignments ConfigMap.
+func (r *Replicator) saveAssignments(ctx context.Context, assignments Assignments) error {
+	data, err := encodeAssignments(assignments)
+	if err != nil {
+		return err
+	}
+	name := assignmentsConfigMapName(r.cfg.LeaseName)
+	configMap, err := r.k8sClient.CoreV1().ConfigMaps(r.namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		configMap = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: r.namespace},
+			BinaryData: map[string][]byte{assignmentsKey: data},
+		}
+		_, err = r.k8sClient.CoreV1().ConfigMaps(r.namespace).Create(ctx, configMap, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	configMap.BinaryData = map[string][]byte{assignmentsKey: data}
+	_, err = r.k8sClient.CoreV1().ConfigMaps(r.namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
+
+// publish publishes the assignments of the leader whenever they change until the leadership is lost. Nothing is
+// published until targets are assigned, so that the followers keep the previous leader's assignments meanwhile.
+func (r *Replicator) publish(ctx context.Context) {
+	ticker := time.NewTicker(publishInterval)
+	defer ticker.Stop()
+	var published Assignments
+	for {
+		assignments := r.allocator.Assignments()
+		if len(assignments) > 0 && !maps.Equal(assignments, published) {
+			if err := r.saveAssignments(ctx, assignments); err != nil {
+				r.log.Error(err, "Unable to publish the target assignments")
+			} else {
+				published = assignments
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// replicate sets the assignments of the ConfigMap published by the leader.
+func (r *Replicator) replicate(obj interface{}) {
+	configMap, ok := obj.(*v1.ConfigMap)
+	if !ok {
+		return
+	}
+	assignments, err := decodeAssignments(configMap.BinaryData[assignmentsKey])
+	if err != nil {
+		r.log.Error(err, "Unable to read the target assignments of the leader")
+		return
+	}
+	r.allocator.SetAssignments(assignments)
+}
+
+// follow replicates the assignments published by the leader until the context is done.
+func (r *Replicator) follow(ctx context.Context) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(r.k8sClient, allocatorconfig.DefaultResyncTime,
+		informers.WithNamespace(r.namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", assignmentsConfigMapName(r.cfg.LeaseName)).String()
+		}))
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    r.replicate,
+		UpdateFunc: func(_, obj interface{}) { r.replicate(obj) },
+	})
+	if err != nil {
+		return fmt.Errorf("unable to watch the target assignments: %w", err)
+	}
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	factory.Shutdown()
+	return nil
+}
+
+// Run takes part in the leader election until the replicator is closed or the context is done. The followers serve
+// the assignments replicated from the leader. It returns an error if the leadership is lost, so that the replica
+// restarts as a follower.
+func (r *Replicator) Run(ctx context.Context) error {
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-r.close:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta:  metav1.ObjectMeta{Name: r.cfg.LeaseName, Namespace: r.namespace},
+			Client:     r.k8sClient.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: r.identity},
+		},
+		LeaseDuration:   r.cfg.LeaseDuration,
+		RenewDeadline:   r.cfg.RenewDeadline,
+		RetryPeriod:     r.cfg.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            r.cfg.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				r.log.Info("Leading the target allocator replicas", "identity", r.identity)
+				leader.Set(1)
+				r.allocator.Lead()
+				r.publish(ctx)
+			},
+			OnStoppedLeading: func() {
+				leader.Set(0)
+			},
+			OnNewLeader: func(identity string) {
+				if identity != r.identity {
+					r.log.Info("Following the target allocator leader", "leader", identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("invalid leader election configuration: %w", err)
+	}
+
+	followErr := make(chan error, 1)
+	go func() {
+		followErr <- r.follow(ctx)
+	}()
+	elected := make(chan struct{})
+	go func() {
+		elector.Run(ctx)
+		close(elected)
+	}()
+
+	select {
+	case err = <-followErr:
+		cancel()
+		<-elected
+		return err
+	case <-elected:
+		cancel()
+		<-followErr
+	}
+	select {
+	case <-r.close:
+		return nil
+	case <-parent.Done():
+		return nil
+	default:
+		return errLostLeadership
+	}
+}
+
+func (r *Replicator) Close() {
+	close(r.close)
+}
@@ -0,0 +1,152 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package replication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/allocation"
+	allocatorconfig "github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/config"
+)
+
+const (
+	testNamespace = "test-ns"
+	testLeaseName = "test-target-allocator"
+)
+
+func getTestReplicator(t *testing.T, identity string, allocator *Allocator) *Replicator {
+	return &Replicator{
+		log:       logger,
+		k8sClient: fake.NewSimpleClientset(),
+		close:     make(chan struct{}),
+		allocator: allocator,
+		cfg: allocatorconfig.LeaderElectionConfig{
+			Enabled:       true,
+			LeaseName:     testLeaseName,
+			LeaseDuration: allocatorconfig.DefaultLeaseDuration,
+			RenewDeadline: allocatorconfig.DefaultRenewDeadline,
+			RetryPeriod:   100 * time.Millisecond,
+		},
+		namespace: testNamespace,
+		identity:  identity,
+	}
+}
+
+func runReplicator(r *Replicator) chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- r.Run(context.Background())
+	}()
+	return done
+}
+
+func assertStopped(t *testing.T, done chan error) {
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the replicator didn't stop once closed")
+	}
+}
+
+func TestEncodeAssignments(t *testing.T) {
+	assignments := Assignments{
+		"test-job-0test-url-0": "collector-0",
+		"test-job-1test-url-1": "collector-1",
+		"test-job-2test-url-2": "collector-0",
+	}
+	data, err := encodeAssignments(assignments)
+	require.NoError(t, err)
+	decoded, err := decodeAssignments(data)
+	require.NoError(t, err)
+	assert.Equal(t, assignments, decoded)
+
+	_, err = decodeAssignments([]byte("not gzipped"))
+	assert.Error(t, err)
+}
+
+func TestReplicatorLeads(t *testing.T) {
+	a := newTestAllocator(t, "consistent-hashing")
+	a.SetCollectors(allocation.MakeNCollectors(3, 0))
+	a.SetTargets(allocation.MakeNNewTargetsWithEmptyCollectors(6, 0))
+	r := getTestReplicator(t, "ta-0", a)
+	done := runReplicator(r)
+
+	assert.Eventually(t, a.Leading, 10*time.Second, 10*time.Millisecond)
+	var published Assignments
+	assert.Eventually(t, func() bool {
+		configMap, err := r.k8sClient.CoreV1().ConfigMaps(testNamespace).Get(context.Background(), assignmentsConfigMapName(testLeaseName), metav1.GetOptions{})
+		if err != nil {
+			return false
+		}
+		published, err = decodeAssignments(configMap.BinaryData[assignmentsKey])
+		return err == nil
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, a.Assignments(), published)
+
+	r.Close()
+	assertStopped(t, done)
+	lease, err := r.k8sClient.CoordinationV1().Leases(testNamespace).Get(context.Background(), testLeaseName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, ptr.Deref(lease.Spec.HolderIdentity, ""), "the lease must be released on shutdown")
+}
+
+func TestReplicatorFollows(t *testing.T) {
+	a := newTestAllocator(t, "least-weighted")
+	targets := allocation.MakeNNewTargetsWithEmptyCollectors(4, 0)
+	a.SetCollectors(allocation.MakeNCollectors(2, 0))
+	a.SetTargets(targets)
+	assignments := Assignments{}
+	for hash := range targets {
+		assignments[hash] = "collector-1"
+	}
+	data, err := encodeAssignments(assignments)
+	require.NoError(t, err)
+
+	r := getTestReplicator(t, "ta-1", a)
+	_, err = r.k8sClient.CoordinationV1().Leases(testNamespace).Create(context.Background(), &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: testLeaseName, Namespace: testNamespace},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To("ta-0"),
+			LeaseDurationSeconds: ptr.To(int32(3600)),
+			AcquireTime:          &metav1.MicroTime{Time: time.Now()},
+			RenewTime:            &metav1.MicroTime{Time: time.Now()},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = r.k8sClient.CoreV1().ConfigMaps(testNamespace).Create(context.Background(), &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: assignmentsConfigMapName(testLeaseName), Namespace: testNamespace},
+		BinaryData: map[string][]byte{assignmentsKey: data},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	done := runReplicator(r)
+
+	assert.Eventually(t, func() bool {
+		return len(a.TargetItems()) == 4
+	}, 10*time.Second, 10*time.Millisecond)
+	for _, item := range a.TargetItems() {
+		assert.Equal(t, "collector-1", item.CollectorName)
+	}
+	assert.False(t, a.Leading())
+
+	r.Close()
+	assertStopped(t, done)
+}
+
+func TestReplicatorInvalidConfig(t *testing.T) {
+	r := getTestReplicator(t, "ta-0", newTestAllocator(t, "consistent-hashing"))
+	r.cfg.RenewDeadline = r.cfg.LeaseDuration
+	err := r.Run(context.Background())
+	assert.ErrorContains(t, err, "invalid leader election configuration")
+}
@@ -0,0 +1,275 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/common/model"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+// maxDebugPageTargets is the maximum number of targets listed in a table of the debug page.
+const maxDebugPageTargets = 500
+
+type collectorDebugJSON struct {
+	Name       string `json:"name"`
+	NodeName   string `json:"node_name,omitempty"`
+	NumTargets int    `json:"num_targets"`
+	Weight     int    `json:"weight"`
+	Link       string `json:"_link"`
+}
+
+type targetDebugJSON struct {
+	Job       string         `json:"job"`
+	TargetURL []string       `json:"targets"`
+	Labels    model.LabelSet `json:"labels"`
+	// Collector is the collector the target is assigned to, empty if unassigned or dropped.
+	Collector string `json:"collector,omitempty"`
+	// DroppedReason is the reason the target was dropped before allocation, if it was.
+	DroppedReason string `json:"dropped_reason,omitempty"`
+}
+
+// targetQuery selects targets by URL and labels.
+type targetQuery struct {
+	// url selects the targets whose URL contains it, e.g. the IP of a pod
+	url string
+	// labels selects the targets having all of them
+	labels model.LabelSet
+}
+
+// parseTargetQuery parses the url parameter and the label parameters, in the name=value form.
+func parseTargetQuery(c *gin.Context) (targetQuery, bool) {
+	query := targetQuery{url: c.Query("url"), labels: model.LabelSet{}}
+	for _, label := range c.QueryArray("label") {
+		name, value, ok := strings.Cut(label, "=")
+		if !ok || name == "" {
+			return query, false
+		}
+		query.labels[model.LabelName(name)] = model.LabelValue(value)
+	}
+	return query, true
+}
+
+func (q targetQuery) empty() bool {
+	return q.url == "" && len(q.labels) == 0
+}
+
+func (q targetQuery) matches(item *target.Item) bool {
+	if q.url != "" && !strings.Contains(strings.Join(item.TargetURL, ""), q.url) {
+		return false
+	}
+	for name, value := range q.labels {
+		if item.Labels[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func newTargetDebugJSON(item *target.Item, collector string, droppedReason string) targetDebugJSON {
+	return targetDebugJSON{
+		Job:           item.JobName,
+		TargetURL:     item.TargetURL,
+		Labels:        item.Labels,
+		Collector:     collector,
+		DroppedReason: droppedReason,
+	}
+}
+
+// sortTargets sorts the targets by job then URL, so the responses are stable.
+func sortTargets(targets []targetDebugJSON) {
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Job != targets[j].Job {
+			return targets[i].Job < targets[j].Job
+		}
+		return strings.Join(targets[i].TargetURL, "") < strings.Join(targets[j].TargetURL, "")
+	})
+}
+
+func (s *Server) debugCollectors() []collectorDebugJSON {
+	collectors := make([]collectorDebugJSON, 0)
+	for _, col := range s.allocator.Collectors() {
+		collectors = append(collectors, collectorDebugJSON{
+			Name:       col.Name,
+			NodeName:   col.NodeName,
+			NumTargets: col.NumTargets,
+			Weight:     col.Weight,
+			Link:       "/debug/targets?collector_id=" + col.Name,
+		})
+	}
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].Name < collectors[j].Name })
+	return collectors
+}
+
+// debugDroppedTargets returns the targets dropped by the prehook matching the query.
+func (s *Server) debugDroppedTargets(query targetQuery) []targetDebugJSON {
+	targets := make([]targetDebugJSON, 0)
+	if s.prehook == nil {
+		return targets
+	}
+	for _, dropped := range s.prehook.DroppedTargets() {
+		if query.matches(dropped.Item) {
+			targets = append(targets, newTargetDebugJSON(dropped.Item, "", dropped.Reason))
+		}
+	}
+	sortTargets(targets)
+	return targets
+}
+
+// debugTargets returns the allocated targets matching the query, assigned to the collector if set, then the dropped
+// ones matching the query.
+func (s *Server) debugTargets(query targetQuery, collector string) []targetDebugJSON {
+	targets := make([]targetDebugJSON, 0)
+	for _, item := range s.allocator.TargetItems() {
+		if (collector == "" || item.CollectorName == collector) && query.matches(item) {
+			targets = append(targets, newTargetDebugJSON(item, item.CollectorName, ""))
+		}
+	}
+	sortTargets(targets)
+	if collector == "" && !query.empty() {
+		targets = append(targets, s.debugDroppedTargets(query)...)
+	}
+	return targets
+}
+
+// DebugCollectorsHandler returns the collectors with the number and weight of their targets.
+func (s *Server) DebugCollectorsHandler(c *gin.Context) {
+	s.jsonHandler(c.Writer, s.debugCollectors())
+}
+
+// DebugTargetsHandler looks up the targets by URL, with the url parameter, and by labels, with label parameters in the
+// name=value form, to tell the collector they are assigned to or why they were dropped. The collector_id parameter
+// lists the targets of a collector instead.
+func (s *Server) DebugTargetsHandler(c *gin.Context) {
+	query, ok := parseTargetQuery(c)
+	if !ok {
+		c.Writer.WriteHeader(http.StatusBadRequest)
+		s.jsonHandler(c.Writer, "the label parameters must be in the name=value form")
+		return
+	}
+	s.jsonHandler(c.Writer, s.debugTargets(query, c.Query("collector_id")))
+}
+
+// DebugDroppedTargetsHandler returns the targets dropped by the prehook with the reason they were.
+func (s *Server) DebugDroppedTargetsHandler(c *gin.Context) {
+	s.jsonHandler(c.Writer, s.debugDroppedTargets(targetQuery{}))
+}
+
+var debugPage = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>Target Allocator</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+td.labels { font-family: monospace; font-size: smaller; }
+</style>
+</head>
+<body>
+<h1>Target Allocator</h1>
+<p>
+<a href="/jobs">Jobs</a> |
+<a href="/scrape_configs">Scrape configs</a> |
+<a href="/debug/collectors">Collectors</a> |
+<a href="/debug/targets">Targets</a> |
+<a href="/debug/dropped">Dropped targets</a> |
+<a href="/metrics">Metrics</a>
+</p>
+
+<h2>Collectors</h2>
+<table>
+<tr><th>Name</th><th>Node</th><th>Targets</th><th>Weight</th></tr>
+{{- range .Collectors}}
+<tr><td><a href="/debug?collector_id={{.Name}}">{{.Name}}</a></td><td>{{.NodeName}}</td><td>{{.NumTargets}}</td><td>{{.Weight}}</td></tr>
+{{- else}}
+<tr><td colspan="4">No collectors</td></tr>
+{{- end}}
+</table>
+
+<h2>Look up a target</h2>
+<form action="/debug" method="get">
+<label>URL contains <input name="url" value="{{.URL}}" placeholder="10.0.0.1:8080"></label>
+<label>Label <input name="label" value="{{.Label}}" placeholder="__meta_kubernetes_pod_name=my-pod"></label>
+<input type="submit" value="Look up">
+</form>
+{{- if .Searched}}
+<h3>{{len .Targets}} matching targets{{if .Truncated}}, the first {{.Max}} are listed{{end}}</h3>
+<table>
+<tr><th>Job</th><th>Target</th><th>Collector</th><th>Dropped by</th><th>Labels</th></tr>
+{{- range .ListedTargets}}
+<tr><td>{{.Job}}</td><td>{{range .TargetURL}}{{.}}{{end}}</td><td>{{.Collector}}</td><td>{{.DroppedReason}}</td><td class="labels">{{.Labels}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+<h2>Dropped targets</h2>
+<p>{{len .Dropped}} targets dropped before allocation{{if .DroppedTruncated}}, the first {{.Max}} are listed{{end}}.</p>
+<table>
+<tr><th>Job</th><th>Target</th><th>Dropped by</th><th>Labels</th></tr>
+{{- range .ListedDropped}}
+<tr><td>{{.Job}}</td><td>{{range .TargetURL}}{{.}}{{end}}</td><td>{{.DroppedReason}}</td><td class="labels">{{.Labels}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+type debugPageData struct {
+	Collectors []collectorDebugJSON
+	URL        string
+	Label      string
+	Searched   bool
+	Targets    []targetDebugJSON
+	Dropped    []targetDebugJSON
+	Max        int
+}
+
+func (d debugPageData) Truncated() bool {
+	return len(d.Targets) > d.Max
+}
+
+func (d debugPageData) ListedTargets() []targetDebugJSON {
+	return d.Targets[:min(len(d.Targets), d.Max)]
+}
+
+func (d debugPageData) DroppedTruncated() bool {
+	return len(d.Dropped) > d.Max
+}
+
+func (d debugPageData) ListedDropped() []targetDebugJSON {
+	return d.Dropped[:min(len(d.Dropped), d.Max)]
+}
+
+// DebugPageHandler renders the HTML debug page listing the collectors and the dropped targets, with a form to look
+// up targets. It takes the same parameters as DebugTargetsHandler.
+func (s *Server) DebugPageHandler(c *gin.Context) {
+	query, ok := parseTargetQuery(c)
+	if !ok {
+		c.String(http.StatusBadRequest, "the label parameters must be in the name=value form")
+		return
+	}
+	collector := c.Query("collector_id")
+	data := debugPageData{
+		Collectors: s.debugCollectors(),
+		URL:        query.url,
+		Label:      c.Query("label"),
+		Searched:   !query.empty() || collector != "",
+		Dropped:    s.debugDroppedTargets(targetQuery{}),
+		Max:        maxDebugPageTargets,
+	}
+	if data.Searched {
+		data.Targets = s.debugTargets(query, collector)
+	}
+	c.Writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := debugPage.Execute(c.Writer, data); err != nil {
+		s.logger.Error(err, "failed to render the debug page")
+	}
+}
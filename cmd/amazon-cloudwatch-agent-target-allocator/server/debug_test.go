@@ -0,0 +1,170 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/allocation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/prehook"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
+)
+
+var droppedTargetItem = target.NewItem("test-job", "dropped-url", model.LabelSet{"test_label": "dropped"}, "")
+
+func getTestDebugServer(t *testing.T) *Server {
+	hook := prehook.New("relabel-config", logger)
+	hook.SetConfig(map[string][]*relabel.Config{
+		"test-job": {
+			{
+				SourceLabels:         model.LabelNames{"test_label"},
+				Regex:                relabel.MustNewRegexp("dropped"),
+				Separator:            ";",
+				Action:               relabel.Drop,
+				Replacement:          "$1",
+				NameValidationScheme: model.UTF8Validation,
+			},
+		},
+	})
+	allocator, err := allocation.New("consistent-hashing", logger, allocation.WithFilter(hook))
+	require.NoError(t, err)
+	allocator.SetCollectors(map[string]*allocation.Collector{"test-collector": {Name: "test-collector", NodeName: "test-node"}})
+	allocator.SetTargets(map[string]*target.Item{
+		baseTargetItem.Hash():       baseTargetItem,
+		testJobTargetItemTwo.Hash(): testJobTargetItemTwo,
+		droppedTargetItem.Hash():    droppedTargetItem,
+	})
+	return NewServer(logger, allocator, ":8080", WithPrehook(hook))
+}
+
+func getDebugJSON(t *testing.T, s *Server, path string, v interface{}) int {
+	w := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), v))
+	}
+	return w.Code
+}
+
+func TestServer_DebugCollectorsHandler(t *testing.T) {
+	s := getTestDebugServer(t)
+	var collectors []collectorDebugJSON
+	assert.Equal(t, http.StatusOK, getDebugJSON(t, s, "/debug/collectors", &collectors))
+	assert.Equal(t, []collectorDebugJSON{
+		{
+			Name:       "test-collector",
+			NodeName:   "test-node",
+			NumTargets: 2,
+			Weight:     2,
+			Link:       "/debug/targets?collector_id=test-collector",
+		},
+	}, collectors)
+}
+
+func TestServer_DebugTargetsHandler(t *testing.T) {
+	s := getTestDebugServer(t)
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+		want     []targetDebugJSON
+	}{
+		{
+			name:     "by url",
+			path:     "/debug/targets?url=test-url2",
+			wantCode: http.StatusOK,
+			want: []targetDebugJSON{
+				{Job: "test-job", TargetURL: []string{"test-url2"}, Labels: testJobLabelSetTwo, Collector: "test-collector"},
+			},
+		},
+		{
+			name:     "by label",
+			path:     "/debug/targets?label=test_label=test-value",
+			wantCode: http.StatusOK,
+			want: []targetDebugJSON{
+				{Job: "test-job", TargetURL: []string{"test-url"}, Labels: baseLabelSet, Collector: "test-collector"},
+			},
+		},
+		{
+			name:     "dropped",
+			path:     "/debug/targets?url=dropped",
+			wantCode: http.StatusOK,
+			want: []targetDebugJSON{
+				{
+					Job:           "test-job",
+					TargetURL:     []string{"dropped-url"},
+					Labels:        model.LabelSet{"test_label": "dropped"},
+					DroppedReason: "relabel_configs[0]: action=drop source_labels=[test_label] regex=dropped",
+				},
+			},
+		},
+		{
+			name:     "by collector",
+			path:     "/debug/targets?collector_id=test-collector&label=test_label=test-value2",
+			wantCode: http.StatusOK,
+			want: []targetDebugJSON{
+				{Job: "test-job", TargetURL: []string{"test-url2"}, Labels: testJobLabelSetTwo, Collector: "test-collector"},
+			},
+		},
+		{
+			name:     "no match",
+			path:     "/debug/targets?url=test-url&label=test_label=other",
+			wantCode: http.StatusOK,
+			want:     []targetDebugJSON{},
+		},
+		{
+			name:     "invalid label",
+			path:     "/debug/targets?label=test_label",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var targets []targetDebugJSON
+			assert.Equal(t, tt.wantCode, getDebugJSON(t, s, tt.path, &targets))
+			assert.Equal(t, tt.want, targets)
+		})
+	}
+}
+
+func TestServer_DebugDroppedTargetsHandler(t *testing.T) {
+	s := getTestDebugServer(t)
+	var targets []targetDebugJSON
+	assert.Equal(t, http.StatusOK, getDebugJSON(t, s, "/debug/dropped", &targets))
+	require.Len(t, targets, 1)
+	assert.Equal(t, []string{"dropped-url"}, targets[0].TargetURL)
+	assert.Contains(t, targets[0].DroppedReason, "action=drop")
+
+	// without prehook, no target is dropped
+	allocator, err := allocation.New("consistent-hashing", logger)
+	require.NoError(t, err)
+	s = NewServer(logger, allocator, ":8080")
+	assert.Equal(t, http.StatusOK, getDebugJSON(t, s, "/debug/dropped", &targets))
+	assert.Empty(t, targets)
+}
+
+func TestServer_DebugPageHandler(t *testing.T) {
+	s := getTestDebugServer(t)
+	w := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/debug?url=test-url2", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.Contains(t, body, "test-collector")
+	assert.Contains(t, body, "1 matching targets")
+	assert.Contains(t, body, "1 targets dropped before allocation")
+	assert.Contains(t, body, "relabel_configs[0]: action=drop")
+
+	w = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", "/debug?label=invalid", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -9,8 +9,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,6 +30,7 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/allocation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/prehook"
 	"github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/target"
 )
 
@@ -36,11 +41,20 @@ var (
 	}, []string{"path"})
 )
 
+const (
+	// maxWait is the longest a request for the targets of a collector waits for them to change.
+	maxWait = 5 * time.Minute
+	// maxSeriesBodyBytes is the largest series count report accepted from a collector.
+	maxSeriesBodyBytes = 8 << 20
+)
+
 var (
 	jsonConfig = jsoniter.Config{
 		EscapeHTML:                    false,
 		MarshalFloatWith6Digits:       true,
 		ObjectFieldMustBeSimpleString: true,
+		// the map keys are sorted for the same data to always have the same ETag
+		SortMapKeys: true,
 	}.Froze()
 )
 
@@ -52,6 +66,7 @@ type collectorJSON struct {
 type Server struct {
 	logger         logr.Logger
 	allocator      allocation.Allocator
+	weights        *allocation.Weights
 	server         *http.Server
 	httpsServer    *http.Server
 	jsonMarshaller jsoniter.API
@@ -62,6 +77,37 @@ type Server struct {
 	mtx                                  sync.RWMutex
 	scrapeConfigResponse                 []byte
 	ScrapeConfigMarshalledSecretResponse []byte
+
+	// changes wakes up the requests waiting for the targets of their collector to change
+	changes broadcaster
+
+	// prehook tells the targets dropped before allocation to the debug endpoints, nil if not filtering
+	prehook prehook.Hook
+}
+
+// broadcaster wakes up all the goroutines waiting for a change at once.
+type broadcaster struct {
+	mtx sync.Mutex
+	ch  chan struct{}
+}
+
+// wait returns a channel closed on the next change.
+func (b *broadcaster) wait() <-chan struct{} {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.ch == nil {
+		b.ch = make(chan struct{})
+	}
+	return b.ch
+}
+
+func (b *broadcaster) broadcast() {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if b.ch != nil {
+		close(b.ch)
+		b.ch = nil
+	}
 }
 
 type Option func(*Server)
@@ -72,6 +118,8 @@ func WithTLSConfig(tlsConfig *tls.Config, httpsListenAddr string) Option {
 	return func(s *Server) {
 		httpsRouter := gin.New()
 		s.setRouter(httpsRouter)
+		// the series counts change the allocation, they are only accepted from the collectors authenticated by mTLS
+		httpsRouter.POST("/series", s.SeriesHandler)
 
 		s.httpsServer = &http.Server{Addr: httpsListenAddr, Handler: httpsRouter, ReadHeaderTimeout: 90 * time.Second, TLSConfig: tlsConfig}
 		err := s.server.Shutdown(context.Background())
@@ -85,6 +133,21 @@ func WithTLSConfig(tlsConfig *tls.Config, httpsListenAddr string) Option {
 	}
 }
 
+// WithWeights enables the endpoint the collectors report the series count of their targets to, served by the https
+// server only.
+func WithWeights(weights *allocation.Weights) Option {
+	return func(s *Server) {
+		s.weights = weights
+	}
+}
+
+// WithPrehook shows the targets dropped by the prehook on the debug endpoints.
+func WithPrehook(hook prehook.Hook) Option {
+	return func(s *Server) {
+		s.prehook = hook
+	}
+}
+
 func (s *Server) setRouter(router *gin.Engine) {
 	router.Use(gin.Recovery())
 	router.UseRawPath = true
@@ -97,6 +160,10 @@ func (s *Server) setRouter(router *gin.Engine) {
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 	router.GET("/livez", s.LivenessProbeHandler)
 	router.GET("/readyz", s.ReadinessProbeHandler)
+	router.GET("/debug", s.DebugPageHandler)
+	router.GET("/debug/collectors", s.DebugCollectorsHandler)
+	router.GET("/debug/targets", s.DebugTargetsHandler)
+	router.GET("/debug/dropped", s.DebugDroppedTargetsHandler)
 }
 
 func NewServer(log logr.Logger, allocator allocation.Allocator, listenAddr string, options ...Option) *Server {
@@ -227,6 +294,12 @@ func (s *Server) UpdateScrapeConfigResponse(configs map[string]*promconfig.Scrap
 	return nil
 }
 
+// AllocationChanged wakes up the requests waiting for the targets of their collector to change. It is called each
+// time the targets or the collectors are set on the allocator.
+func (s *Server) AllocationChanged() {
+	s.changes.broadcast()
+}
+
 // ScrapeConfigsHandler returns the available scrape configuration discovered by the target allocator.
 func (s *Server) ScrapeConfigsHandler(c *gin.Context) {
 	s.mtx.RLock()
@@ -237,11 +310,7 @@ func (s *Server) ScrapeConfigsHandler(c *gin.Context) {
 	s.mtx.RUnlock()
 
 	// We don't use the jsonHandler method because we don't want our bytes to be re-encoded
-	c.Writer.Header().Set("Content-Type", "application/json")
-	_, err := c.Writer.Write(result)
-	if err != nil {
-		s.errorHandler(c.Writer, err)
-	}
+	s.writeWithETag(c, result)
 }
 
 func (s *Server) ReadinessProbeHandler(c *gin.Context) {
@@ -261,7 +330,7 @@ func (s *Server) JobHandler(c *gin.Context) {
 	for _, v := range s.allocator.TargetItems() {
 		displayData[v.JobName] = target.LinkJSON{Link: v.Link.Link}
 	}
-	s.jsonHandler(c.Writer, displayData)
+	s.jsonWithETag(c, displayData)
 }
 
 func (s *Server) LivenessProbeHandler(c *gin.Context) {
@@ -275,6 +344,9 @@ func (s *Server) PrometheusMiddleware(c *gin.Context) {
 	timer.ObserveDuration()
 }
 
+// TargetsHandler returns the targets of a job, per collector or of the collector_id one. With a wait duration and the
+// ETag of the targets of the collector in If-None-Match, the request waits up to that long for them to change,
+// so that collectors are notified of their new targets instead of polling for them.
 func (s *Server) TargetsHandler(c *gin.Context) {
 	q := c.Request.URL.Query()["collector_id"]
 
@@ -287,24 +359,131 @@ func (s *Server) TargetsHandler(c *gin.Context) {
 
 	if len(q) == 0 {
 		displayData := GetAllTargetsByJob(s.allocator, jobId)
-		s.jsonHandler(c.Writer, displayData)
+		s.jsonWithETag(c, displayData)
+		return
+	}
 
-	} else {
-		tgs := s.allocator.GetTargetsForCollectorAndJob(q[0], jobId)
+	var wait time.Duration
+	if waitParam := c.Query("wait"); waitParam != "" {
+		wait, err = time.ParseDuration(waitParam)
+		if err != nil || wait < 0 {
+			c.Writer.WriteHeader(http.StatusBadRequest)
+			s.jsonHandler(c.Writer, fmt.Sprintf("invalid wait duration %q", waitParam))
+			return
+		}
+		wait = min(wait, maxWait)
+	}
+	timeout := time.NewTimer(wait)
+	defer timeout.Stop()
+	for {
+		// Wait for the changes made after the targets are read
+		changed := s.changes.wait()
+		tgs := sortTargetItems(s.allocator.GetTargetsForCollectorAndJob(q[0], jobId))
+		var displayData interface{} = tgs
 		// Displays empty list if nothing matches
 		if len(tgs) == 0 {
-			s.jsonHandler(c.Writer, []interface{}{})
+			displayData = []interface{}{}
+		}
+		body, err := s.marshalJSON(displayData)
+		if err != nil {
+			s.errorHandler(c.Writer, err)
+			return
+		}
+		if wait == 0 || !matchesETag(c.GetHeader("If-None-Match"), etag(body)) {
+			s.writeWithETag(c, body)
+			return
+		}
+		select {
+		case <-changed:
+		case <-timeout.C:
+			s.writeWithETag(c, body)
+			return
+		case <-c.Request.Context().Done():
 			return
 		}
-		s.jsonHandler(c.Writer, tgs)
 	}
 }
 
+// SeriesHandler records the series count of the targets reported by a collector, used as the targets' weight.
+// The counts of the targets the allocator doesn't know are ignored.
+func (s *Server) SeriesHandler(c *gin.Context) {
+	if s.weights == nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxSeriesBodyBytes))
+	if err != nil {
+		c.Writer.WriteHeader(http.StatusRequestEntityTooLarge)
+		s.jsonHandler(c.Writer, err.Error())
+		return
+	}
+	var counts []allocation.SeriesCount
+	if err = s.jsonMarshaller.Unmarshal(body, &counts); err != nil {
+		c.Writer.WriteHeader(http.StatusBadRequest)
+		s.jsonHandler(c.Writer, err.Error())
+		return
+	}
+	s.weights.Record(counts, s.allocator.TargetItems())
+	c.Status(http.StatusNoContent)
+}
+
 func (s *Server) errorHandler(w http.ResponseWriter, err error) {
 	w.WriteHeader(http.StatusInternalServerError)
 	s.jsonHandler(w, err)
 }
 
+// marshalJSON encodes the data like jsonHandler does.
+func (s *Server) marshalJSON(data interface{}) ([]byte, error) {
+	body, err := s.jsonMarshaller.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return append(body, '\n'), nil
+}
+
+// jsonWithETag writes the data as JSON like jsonHandler does, with its ETag.
+func (s *Server) jsonWithETag(c *gin.Context, data interface{}) {
+	body, err := s.marshalJSON(data)
+	if err != nil {
+		s.errorHandler(c.Writer, err)
+		return
+	}
+	s.writeWithETag(c, body)
+}
+
+// writeWithETag writes the JSON body with its ETag, or only the Not Modified status if the client already has it.
+func (s *Server) writeWithETag(c *gin.Context, body []byte) {
+	tag := etag(body)
+	c.Writer.Header().Set("ETag", tag)
+	if matchesETag(c.GetHeader("If-None-Match"), tag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Writer.Header().Set("Content-Type", "application/json")
+	_, err := c.Writer.Write(body)
+	if err != nil {
+		s.logger.Error(err, "failed to write the http response")
+	}
+}
+
+// etag returns the strong ETag of a response body.
+func etag(body []byte) string {
+	h := fnv.New64a()
+	_, _ = h.Write(body)
+	return fmt.Sprintf(`"%x"`, h.Sum64())
+}
+
+// matchesETag returns whether the ETag is one of the If-None-Match header's.
+func matchesETag(ifNoneMatch string, tag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Server) jsonHandler(w http.ResponseWriter, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	err := s.jsonMarshaller.NewEncoder(w).Encode(data)
@@ -317,8 +496,16 @@ func (s *Server) jsonHandler(w http.ResponseWriter, data interface{}) {
 func GetAllTargetsByJob(allocator allocation.Allocator, job string) map[string]collectorJSON {
 	displayData := make(map[string]collectorJSON)
 	for _, col := range allocator.Collectors() {
-		items := allocator.GetTargetsForCollectorAndJob(col.Name, job)
+		items := sortTargetItems(allocator.GetTargetsForCollectorAndJob(col.Name, job))
 		displayData[col.Name] = collectorJSON{Link: fmt.Sprintf("/jobs/%s/targets?collector_id=%s", url.QueryEscape(job), col.Name), Jobs: items}
 	}
 	return displayData
 }
+
+// sortTargetItems sorts the targets returned by the allocator in map order, so that their responses have a stable ETag.
+func sortTargetItems(items []*target.Item) []*target.Item {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Hash() < items[j].Hash()
+	})
+	return items
+}
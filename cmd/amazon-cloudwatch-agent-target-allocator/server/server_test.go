@@ -20,6 +20,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -679,6 +680,237 @@ func TestServer_Readiness(t *testing.T) {
 	}
 }
 
+func TestServer_SeriesHandler(t *testing.T) {
+	item := target.NewItem("test-job", "test-url", baseLabelSet, "")
+	allocator := &mockAllocator{targetItems: map[string]*target.Item{item.Hash(): item}}
+	svrConfig := allocatorconfig.HTTPSServerConfig{}
+	tlsConfig, _ := svrConfig.NewTLSConfig(context.TODO())
+	tests := []struct {
+		description    string
+		weights        *allocation.Weights
+		body           string
+		plainHTTP      bool
+		expectedCode   int
+		expectedWeight int
+	}{
+		{
+			description:    "series reported",
+			weights:        allocation.NewWeights(),
+			body:           `[{"job": "test-job", "target": "test-url", "series": 1200}]`,
+			expectedCode:   http.StatusNoContent,
+			expectedWeight: 1200,
+		},
+		{
+			description:    "unknown target",
+			weights:        allocation.NewWeights(),
+			body:           `[{"job": "test-job", "target": "other-url", "series": 1200}]`,
+			expectedCode:   http.StatusNoContent,
+			expectedWeight: allocation.DefaultWeight,
+		},
+		{
+			description:    "plain http",
+			weights:        allocation.NewWeights(),
+			body:           `[{"job": "test-job", "target": "test-url", "series": 1200}]`,
+			plainHTTP:      true,
+			expectedCode:   http.StatusNotFound,
+			expectedWeight: allocation.DefaultWeight,
+		},
+		{
+			description:    "invalid body",
+			weights:        allocation.NewWeights(),
+			body:           `{"job": "test-job"}`,
+			expectedCode:   http.StatusBadRequest,
+			expectedWeight: allocation.DefaultWeight,
+		},
+		{
+			description:    "body too large",
+			weights:        allocation.NewWeights(),
+			body:           `[{"job": "test-job", "target": "test-url", "series": 1200}` + strings.Repeat(" ", maxSeriesBodyBytes) + `]`,
+			expectedCode:   http.StatusRequestEntityTooLarge,
+			expectedWeight: allocation.DefaultWeight,
+		},
+		{
+			description:    "weights not enabled",
+			body:           `[{"job": "test-job", "target": "test-url", "series": 1200}]`,
+			expectedCode:   http.StatusNotFound,
+			expectedWeight: allocation.DefaultWeight,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			listenAddr := ":8080"
+			var s *Server
+			if tc.plainHTTP {
+				s = NewServer(logger, allocator, listenAddr, WithWeights(tc.weights))
+			} else {
+				s = NewServer(logger, allocator, listenAddr, WithWeights(tc.weights), WithTLSConfig(tlsConfig, ""))
+			}
+			request := httptest.NewRequest("POST", "/series", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+
+			s.server.Handler.ServeHTTP(w, request)
+			result := w.Result()
+
+			assert.Equal(t, tc.expectedCode, result.StatusCode)
+			assert.Equal(t, tc.expectedWeight, tc.weights.Weight(item))
+		})
+	}
+}
+
+// etagTestTargets returns targets of several jobs with several labels each, for the ETag tests to depend on the
+// ordering of the targets and of their labels.
+func etagTestTargets() map[string]*target.Item {
+	items := map[string]*target.Item{}
+	for _, job := range []string{"test-job", "other-job"} {
+		for i := 0; i < 10; i++ {
+			item := target.NewItem(job, fmt.Sprintf("10.0.0.%d:8080", i), model.LabelSet{
+				"__meta_kubernetes_pod_name":      model.LabelValue(fmt.Sprintf("pod-%d", i)),
+				"__meta_kubernetes_namespace":     "default",
+				"__meta_kubernetes_pod_node_name": model.LabelValue(fmt.Sprintf("node-%d", i%3)),
+				"container":                       "app",
+				"instance":                        model.LabelValue(fmt.Sprintf("10.0.0.%d:8080", i)),
+			}, "")
+			items[item.Hash()] = item
+		}
+	}
+	return items
+}
+
+func newETagTestServer(t *testing.T) *Server {
+	consistentHashing, _ := allocation.New("consistent-hashing", logger)
+	consistentHashing.SetCollectors(map[string]*allocation.Collector{
+		"test-collector":  {Name: "test-collector"},
+		"test-collector2": {Name: "test-collector2"},
+	})
+	consistentHashing.SetTargets(etagTestTargets())
+	s := NewServer(logger, consistentHashing, ":8080")
+	require.NoError(t, s.UpdateScrapeConfigResponse(map[string]*promconfig.ScrapeConfig{
+		"test-job":  {JobName: "test-job"},
+		"other-job": {JobName: "other-job"},
+	}))
+	return s
+}
+
+func TestServer_ETag(t *testing.T) {
+	s := newETagTestServer(t)
+	// another allocator, whose maps iterate over the same targets in another order
+	other := newETagTestServer(t)
+
+	for _, path := range []string{"/scrape_configs", "/jobs", "/jobs/test-job/targets", "/jobs/test-job/targets?collector_id=test-collector", "/jobs/test-job/targets?collector_id=test-collector2"} {
+		t.Run(path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+			tag := w.Header().Get("ETag")
+			require.NotEmpty(t, tag)
+			body := w.Body.String()
+
+			for i := 0; i < 10; i++ {
+				for _, server := range []*Server{s, other} {
+					w = httptest.NewRecorder()
+					server.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+					require.Equal(t, http.StatusOK, w.Code)
+					require.Equal(t, tag, w.Header().Get("ETag"))
+					require.Equal(t, body, w.Body.String())
+				}
+			}
+
+			request := httptest.NewRequest("GET", path, nil)
+			request.Header.Set("If-None-Match", tag)
+			w = httptest.NewRecorder()
+			s.server.Handler.ServeHTTP(w, request)
+			assert.Equal(t, http.StatusNotModified, w.Code)
+			assert.Equal(t, tag, w.Header().Get("ETag"))
+			assert.Empty(t, w.Body.Bytes())
+
+			request = httptest.NewRequest("GET", path, nil)
+			request.Header.Set("If-None-Match", `"outdated"`)
+			w = httptest.NewRecorder()
+			s.server.Handler.ServeHTTP(w, request)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.NotEmpty(t, w.Body.Bytes())
+		})
+	}
+}
+
+func TestServer_TargetsHandlerWait(t *testing.T) {
+	collectors := map[string]*allocation.Collector{"test-collector": {Name: "test-collector"}}
+	path := "/jobs/test-job/targets?collector_id=test-collector&wait=%s"
+	newServer := func() (*Server, allocation.Allocator, string) {
+		consistentHashing, _ := allocation.New("consistent-hashing", logger)
+		consistentHashing.SetCollectors(collectors)
+		consistentHashing.SetTargets(etagTestTargets())
+		s := NewServer(logger, consistentHashing, ":8080")
+		w := httptest.NewRecorder()
+		s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf(path, "0s"), nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		return s, consistentHashing, w.Header().Get("ETag")
+	}
+
+	t.Run("notified of new targets", func(t *testing.T) {
+		s, allocator, tag := newServer()
+		request := httptest.NewRequest("GET", fmt.Sprintf(path, "1m"), nil)
+		request.Header.Set("If-None-Match", tag)
+		w := httptest.NewRecorder()
+		done := make(chan struct{})
+		go func() {
+			s.server.Handler.ServeHTTP(w, request)
+			close(done)
+		}()
+
+		// the targets are set but didn't change
+		allocator.SetTargets(etagTestTargets())
+		s.AllocationChanged()
+		select {
+		case <-done:
+			t.Fatal("the request must wait for its targets to change")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		targets := etagTestTargets()
+		targets[testJobTargetItemTwo.Hash()] = testJobTargetItemTwo
+		allocator.SetTargets(targets)
+		s.AllocationChanged()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("the request wasn't notified of its new targets")
+		}
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, tag, w.Header().Get("ETag"))
+		var itemResponse []*target.Item
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &itemResponse))
+		assert.Len(t, itemResponse, 11)
+	})
+
+	t.Run("wait timeout", func(t *testing.T) {
+		s, _, tag := newServer()
+		request := httptest.NewRequest("GET", fmt.Sprintf(path, "50ms"), nil)
+		request.Header.Set("If-None-Match", tag)
+		w := httptest.NewRecorder()
+		s.server.Handler.ServeHTTP(w, request)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, tag, w.Header().Get("ETag"))
+	})
+
+	t.Run("outdated targets", func(t *testing.T) {
+		s, _, tag := newServer()
+		request := httptest.NewRequest("GET", fmt.Sprintf(path, "1m"), nil)
+		request.Header.Set("If-None-Match", `"outdated"`)
+		w := httptest.NewRecorder()
+		s.server.Handler.ServeHTTP(w, request)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, tag, w.Header().Get("ETag"))
+	})
+
+	t.Run("invalid wait", func(t *testing.T) {
+		s, _, _ := newServer()
+		w := httptest.NewRecorder()
+		s.server.Handler.ServeHTTP(w, httptest.NewRequest("GET", fmt.Sprintf(path, "forever"), nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestServer_ValidCAonTLS(t *testing.T) {
 	listenAddr := ":8443"
 	server, clientTlsConfig, err := createTestTLSServer(listenAddr)
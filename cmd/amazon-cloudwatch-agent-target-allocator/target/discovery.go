@@ -6,6 +6,7 @@ package target
 import (
 	"hash"
 	"hash/fnv"
+	"sync"
 
 	"github.com/go-logr/logr"
 	"github.com/prometheus/client_golang/prometheus"
@@ -34,6 +35,9 @@ type Discoverer struct {
 	hook                 discoveryHook
 	scrapeConfigsHash    hash.Hash
 	scrapeConfigsUpdater scrapeConfigsUpdater
+	// jobLabels are the labels set on the targets of each job from its scrape config.
+	jobLabels    map[string]model.LabelSet
+	jobLabelsMtx sync.RWMutex
 }
 
 type discoveryHook interface {
@@ -65,14 +69,19 @@ func (m *Discoverer) ApplyConfig(source allocatorWatcher.EventSource, cfg *confi
 
 	discoveryCfg := make(map[string]discovery.Configs)
 	relabelCfg := make(map[string][]*relabel.Config)
+	jobLabels := make(map[string]model.LabelSet)
 
 	for _, value := range m.configsMap {
 		for _, scrapeConfig := range value.ScrapeConfigs {
 			jobToScrapeConfig[scrapeConfig.JobName] = scrapeConfig
 			discoveryCfg[scrapeConfig.JobName] = scrapeConfig.ServiceDiscoveryConfigs
 			relabelCfg[scrapeConfig.JobName] = scrapeConfig.RelabelConfigs
+			jobLabels[scrapeConfig.JobName] = scrapeLabels(scrapeConfig)
 		}
 	}
+	m.jobLabelsMtx.Lock()
+	m.jobLabels = jobLabels
+	m.jobLabelsMtx.Unlock()
 
 	hash, err := getScrapeConfigHash(jobToScrapeConfig)
 	if err != nil {
@@ -104,12 +113,17 @@ func (m *Discoverer) Watch(fn func(targets map[string]*Item)) error {
 		case tsets := <-m.manager.SyncCh():
 			targets := map[string]*Item{}
 
+			m.jobLabelsMtx.RLock()
+			jobLabels := m.jobLabels
+			m.jobLabelsMtx.RUnlock()
 			for jobName, tgs := range tsets {
 				var count float64 = 0
 				for _, tg := range tgs {
 					for _, t := range tg.Targets {
 						count++
-						item := NewItem(jobName, string(t[model.AddressLabel]), t.Merge(tg.Labels), "")
+						// the labels of the target take precedence over the ones of its job, as when Prometheus
+						// populates them
+						item := NewItem(jobName, string(t[model.AddressLabel]), jobLabels[jobName].Merge(t.Merge(tg.Labels)), "")
 						targets[item.Hash()] = item
 					}
 				}
@@ -124,6 +138,24 @@ func (m *Discoverer) Close() {
 	close(m.close)
 }
 
+// scrapeLabels returns the labels Prometheus sets on the targets of a job from its scrape config before relabeling
+// them: the scheme, metrics path and URL parameters the targets are scraped with.
+func scrapeLabels(scrapeConfig *config.ScrapeConfig) model.LabelSet {
+	lset := model.LabelSet{}
+	if scrapeConfig.Scheme != "" {
+		lset[model.SchemeLabel] = model.LabelValue(scrapeConfig.Scheme)
+	}
+	if scrapeConfig.MetricsPath != "" {
+		lset[model.MetricsPathLabel] = model.LabelValue(scrapeConfig.MetricsPath)
+	}
+	for name, values := range scrapeConfig.Params {
+		if len(values) > 0 {
+			lset[model.LabelName(model.ParamLabelPrefix+name)] = model.LabelValue(values[0])
+		}
+	}
+	return lset
+}
+
 // Calculate a hash for a scrape config map.
 // This is done by marshaling to YAML because it's the most straightforward and doesn't run into problems with unexported fields.
 func getScrapeConfigHash(jobToScrapeConfig map[string]*config.ScrapeConfig) (hash.Hash64, error) {
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"hash"
 	"log/slog"
+	"net/url"
 	"sort"
 	"testing"
 	"time"
@@ -69,8 +70,11 @@ func TestDiscovery(t *testing.T) {
 	go func() {
 		err := manager.Watch(func(targets map[string]*Item) {
 			var result []string
-			for _, t := range targets {
-				result = append(result, t.TargetURL[0])
+			for _, item := range targets {
+				result = append(result, item.TargetURL[0])
+				// the targets carry the scheme and metrics path of their job
+				assert.Equal(t, model.LabelValue("http"), item.Labels[model.SchemeLabel])
+				assert.Equal(t, model.LabelValue("/metrics"), item.Labels[model.MetricsPathLabel])
 			}
 			results <- result
 		})
@@ -404,3 +408,17 @@ func (m *mockScrapeConfigUpdater) UpdateScrapeConfigResponse(cfg map[string]*pro
 	m.mockCfg = cfg
 	return nil
 }
+
+func TestScrapeLabels(t *testing.T) {
+	assert.Equal(t, model.LabelSet{
+		model.SchemeLabel:                   "https",
+		model.MetricsPathLabel:              "/probe",
+		model.ParamLabelPrefix + "module":   "http_2xx",
+		model.ParamLabelPrefix + "target[]": "a",
+	}, scrapeLabels(&promconfig.ScrapeConfig{
+		Scheme:      "https",
+		MetricsPath: "/probe",
+		Params:      url.Values{"module": {"http_2xx"}, "target[]": {"a", "b"}, "empty": {}},
+	}))
+	assert.Empty(t, scrapeLabels(&promconfig.ScrapeConfig{}))
+}
@@ -6,10 +6,31 @@ package target
 import (
 	"fmt"
 	"net/url"
+	"strconv"
 
 	"github.com/prometheus/common/model"
 )
 
+// nodeLabels are the meta labels holding the name of the node of a target, in order of precedence.
+var nodeLabels = []model.LabelName{
+	"__meta_kubernetes_pod_node_name",
+	"__meta_kubernetes_node_name",
+	"__meta_kubernetes_endpoint_node_name",
+}
+
+// WeightLabel is the target label setting the scrape cost of a target, e.g. in a static config.
+const WeightLabel model.LabelName = "__scrape_weight__"
+
+// weightLabels are the labels setting the scrape cost of a target, in order of precedence. Besides WeightLabel, they are
+// the meta labels of the cloudwatch.aws.amazon.com/scrape-weight annotation or label of the scraped pod or service.
+var weightLabels = []model.LabelName{
+	WeightLabel,
+	"__meta_kubernetes_pod_annotation_cloudwatch_aws_amazon_com_scrape_weight",
+	"__meta_kubernetes_pod_label_cloudwatch_aws_amazon_com_scrape_weight",
+	"__meta_kubernetes_service_annotation_cloudwatch_aws_amazon_com_scrape_weight",
+	"__meta_kubernetes_service_label_cloudwatch_aws_amazon_com_scrape_weight",
+}
+
 // LinkJSON This package contains common structs and methods that relate to scrape targets.
 type LinkJSON struct {
 	Link string `json:"_link"`
@@ -28,6 +49,30 @@ func (t *Item) Hash() string {
 	return t.hash
 }
 
+// GetNodeName returns the name of the node the target runs on, or an empty string if its labels don't tell.
+func (t *Item) GetNodeName() string {
+	for _, label := range nodeLabels {
+		if value, ok := t.Labels[label]; ok && value != "" {
+			return string(value)
+		}
+	}
+	return ""
+}
+
+// ConfiguredWeight returns the scrape cost set by the labels of the target, if any and valid.
+func (t *Item) ConfiguredWeight() (int, bool) {
+	for _, label := range weightLabels {
+		value, ok := t.Labels[label]
+		if !ok {
+			continue
+		}
+		if weight, err := strconv.Atoi(string(value)); err == nil && weight > 0 {
+			return weight, true
+		}
+	}
+	return 0, false
+}
+
 // NewItem Creates a new target item.
 // INVARIANTS:
 // * Item fields must not be modified after creation.
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"time"
 
@@ -16,12 +17,18 @@ import (
 	"github.com/prometheus-operator/prometheus-operator/pkg/assets"
 	monitoringclient "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned"
 	"github.com/prometheus-operator/prometheus-operator/pkg/informers"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	"github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
+	prometheusgoclient "github.com/prometheus/client_golang/prometheus"
 	promconfig "github.com/prometheus/prometheus/config"
 	kubeDiscovery "github.com/prometheus/prometheus/discovery/kubernetes"
 	"gopkg.in/yaml.v2"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/labels"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	k8sinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
@@ -43,16 +50,26 @@ func NewPrometheusCRWatcher(logger logr.Logger, cfg allocatorconfig.Config) (*Pr
 
 	factory := informers.NewMonitoringInformerFactories(map[string]struct{}{v1.NamespaceAll: {}}, map[string]struct{}{}, mClient, allocatorconfig.DefaultResyncTime, nil) //TODO decide what strategy to use regarding namespaces
 
-	monitoringInformers, err := getInformers(factory)
+	monitoringInformers, err := getInformers(factory, clientset.Discovery())
 	if err != nil {
 		return nil, err
 	}
 
 	// TODO: We should make these durations configurable
 	prom := &monitoringv1.Prometheus{
+		// the config generator reads the credentials of the Prometheus resource from its namespace
+		ObjectMeta: metav1.ObjectMeta{Namespace: promNamespace()},
 		Spec: monitoringv1.PrometheusSpec{
 			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
-				ScrapeInterval: monitoringv1.Duration(cfg.PrometheusCR.ScrapeInterval.String()),
+				ScrapeInterval:                  monitoringv1.Duration(cfg.PrometheusCR.ScrapeInterval.String()),
+				ServiceMonitorSelector:          getSelector(cfg.ServiceMonitorSelector),
+				ServiceMonitorNamespaceSelector: cfg.ServiceMonitorNamespaceSelector,
+				PodMonitorSelector:              getSelector(cfg.PodMonitorSelector),
+				PodMonitorNamespaceSelector:     cfg.PodMonitorNamespaceSelector,
+				ProbeSelector:                   getSelector(cfg.ProbeSelector),
+				ProbeNamespaceSelector:          cfg.ProbeNamespaceSelector,
+				ScrapeConfigSelector:            getSelector(cfg.ScrapeConfigSelector),
+				ScrapeConfigNamespaceSelector:   cfg.ScrapeConfigNamespaceSelector,
 			},
 		},
 	}
@@ -64,49 +81,69 @@ func NewPrometheusCRWatcher(logger logr.Logger, cfg allocatorconfig.Config) (*Pr
 		return nil, err
 	}
 
-	servMonSelector := getSelector(cfg.ServiceMonitorSelector)
-
-	podMonSelector := getSelector(cfg.PodMonitorSelector)
+	operatorMetrics := operator.NewMetrics(prometheusgoclient.WrapRegistererWithPrefix("cloudwatch_agent_allocator_", prometheusgoclient.DefaultRegisterer))
+	// the monitors rejected for their invalid configuration are logged, not recorded as events on them
+	eventRecorder := operator.NewEventRecorderFactory(false)(clientset, "amazon-cloudwatch-agent-target-allocator")(prom)
 
 	return &PrometheusCRWatcher{
-		logger:                 logger,
-		kubeMonitoringClient:   mClient,
-		k8sClient:              clientset,
-		informers:              monitoringInformers,
-		stopChannel:            make(chan struct{}),
-		eventInterval:          minEventInterval,
-		configGenerator:        generator,
-		prom:                   prom,
-		kubeConfigPath:         cfg.KubeConfigFilePath,
-		serviceMonitorSelector: servMonSelector,
-		podMonitorSelector:     podMonSelector,
+		logger:               logger,
+		promOperatorLogger:   promOperatorLogger,
+		kubeMonitoringClient: mClient,
+		k8sClient:            clientset,
+		informers:            monitoringInformers,
+		nsInformer:           getNamespaceInformer(clientset, cfg),
+		stopChannel:          make(chan struct{}),
+		eventInterval:        minEventInterval,
+		configGenerator:      generator,
+		prom:                 prom,
+		kubeConfigPath:       cfg.KubeConfigFilePath,
+		operatorMetrics:      operatorMetrics,
+		eventRecorder:        eventRecorder,
 	}, nil
 }
 
 type PrometheusCRWatcher struct {
 	logger               logr.Logger
+	promOperatorLogger   *slog.Logger
 	kubeMonitoringClient monitoringclient.Interface
 	k8sClient            kubernetes.Interface
 	informers            map[string]*informers.ForResource
-	eventInterval        time.Duration
-	stopChannel          chan struct{}
-	configGenerator      *prometheus.ConfigGenerator
-	prom                 *monitoringv1.Prometheus
-	kubeConfigPath       string
-
-	serviceMonitorSelector labels.Selector
-	podMonitorSelector     labels.Selector
+	// nsInformer lists the namespaces the namespace selectors select the monitors of, nil without namespace selector
+	nsInformer      cache.SharedIndexInformer
+	eventInterval   time.Duration
+	stopChannel     chan struct{}
+	configGenerator *prometheus.ConfigGenerator
+	// prom holds the selectors of the monitors
+	prom           *monitoringv1.Prometheus
+	kubeConfigPath string
+
+	operatorMetrics *operator.Metrics
+	eventRecorder   *operator.EventRecorder
+}
+
+// getSelector returns the selector of the monitors having the labels, selecting all the monitors if nil.
+func getSelector(s map[string]string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{MatchLabels: s}
 }
 
-func getSelector(s map[string]string) labels.Selector {
-	if s == nil {
-		return labels.NewSelector()
+// promNamespace returns the namespace of the target allocator, or the default namespace if unknown.
+func promNamespace() string {
+	if namespace := os.Getenv("OTELCOL_NAMESPACE"); namespace != "" {
+		return namespace
 	}
-	return labels.SelectorFromSet(s)
+	return v1.NamespaceDefault
 }
 
-// getInformers returns a map of informers for the given resources.
-func getInformers(factory informers.FactoriesForNamespaces) (map[string]*informers.ForResource, error) {
+// hasNamespaceSelector returns whether the monitors of any kind are selected by the labels of their namespace. The
+// monitors of the kinds without namespace selector are selected in all the namespaces.
+func hasNamespaceSelector(cfg allocatorconfig.Config) bool {
+	return cfg.ServiceMonitorNamespaceSelector != nil || cfg.PodMonitorNamespaceSelector != nil ||
+		cfg.ProbeNamespaceSelector != nil || cfg.ScrapeConfigNamespaceSelector != nil
+}
+
+// getInformers returns a map of informers for the given resources. The informers of the Probes and ScrapeConfigs
+// are only returned if their CRD is installed.
+func getInformers(factory informers.FactoriesForNamespaces, dcl discovery.DiscoveryInterface) (map[string]*informers.ForResource, error) {
 	serviceMonitorInformers, err := informers.NewInformersForResource(factory, monitoringv1.SchemeGroupVersion.WithResource(monitoringv1.ServiceMonitorName))
 	if err != nil {
 		return nil, err
@@ -117,50 +154,113 @@ func getInformers(factory informers.FactoriesForNamespaces) (map[string]*informe
 		return nil, err
 	}
 
-	return map[string]*informers.ForResource{
+	monitoringInformers := map[string]*informers.ForResource{
 		monitoringv1.ServiceMonitorName: serviceMonitorInformers,
 		monitoringv1.PodMonitorName:     podMonitorInformers,
-	}, nil
+	}
+
+	for name, resource := range map[string]schema.GroupVersionResource{
+		monitoringv1.ProbeName:        monitoringv1.SchemeGroupVersion.WithResource(monitoringv1.ProbeName),
+		promv1alpha1.ScrapeConfigName: promv1alpha1.SchemeGroupVersion.WithResource(promv1alpha1.ScrapeConfigName),
+	} {
+		available, err := isResourceAvailable(dcl, resource)
+		if err != nil {
+			return nil, err
+		}
+		if !available {
+			continue
+		}
+		monitoringInformers[name], err = informers.NewInformersForResource(factory, resource)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return monitoringInformers, nil
+}
+
+// isResourceAvailable returns whether the CRD of the resource is installed.
+func isResourceAvailable(dcl discovery.DiscoveryInterface, resource schema.GroupVersionResource) (bool, error) {
+	apiResources, err := dcl.ServerResourcesForGroupVersion(resource.GroupVersion().String())
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("unable to discover the %s resource: %w", resource.Resource, err)
+	}
+	for _, apiResource := range apiResources.APIResources {
+		if apiResource.Name == resource.Resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getNamespaceInformer returns the informer of the namespaces the namespace selectors select the monitors of, or nil if
+// there is no namespace selector, not to list and watch the namespaces of the cluster for nothing.
+func getNamespaceInformer(clientset kubernetes.Interface, cfg allocatorconfig.Config) cache.SharedIndexInformer {
+	if !hasNamespaceSelector(cfg) {
+		return nil
+	}
+	return k8sinformers.NewSharedInformerFactory(clientset, allocatorconfig.DefaultResyncTime).Core().V1().Namespaces().Informer()
 }
 
 // Watch wrapped informers and wait for an initial sync.
 func (w *PrometheusCRWatcher) Watch(upstreamEvents chan Event, upstreamErrors chan error) error {
-	success := true
 	// this channel needs to be buffered because notifications are asynchronous and neither producers nor consumers wait
 	notifyEvents := make(chan struct{}, 1)
 
-	for name, resource := range w.informers {
-		resource.Start(w.stopChannel)
-
-		if ok := cache.WaitForNamedCacheSync(name, w.stopChannel, resource.HasSynced); !ok {
-			success = false
-		}
+	// only send an event notification if there isn't one already
+	eventHandler := cache.ResourceEventHandlerFuncs{
+		// these functions only write to the notification channel if it's empty to avoid blocking
+		// if scrape config updates are being rate-limited
+		AddFunc: func(obj interface{}) {
+			select {
+			case notifyEvents <- struct{}{}:
+			default:
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			select {
+			case notifyEvents <- struct{}{}:
+			default:
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			select {
+			case notifyEvents <- struct{}{}:
+			default:
+			}
+		},
+	}
 
-		// only send an event notification if there isn't one already
-		resource.AddEventHandler(cache.ResourceEventHandlerFuncs{
-			// these functions only write to the notification channel if it's empty to avoid blocking
-			// if scrape config updates are being rate-limited
-			AddFunc: func(obj interface{}) {
-				select {
-				case notifyEvents <- struct{}{}:
-				default:
-				}
-			},
+	// start all the informers before waiting for them to sync together
+	var hasSynced []cache.InformerSynced
+	if w.nsInformer != nil {
+		// the namespaces are selected by their labels
+		_, err := w.nsInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: eventHandler.AddFunc,
 			UpdateFunc: func(oldObj, newObj interface{}) {
-				select {
-				case notifyEvents <- struct{}{}:
-				default:
-				}
-			},
-			DeleteFunc: func(obj interface{}) {
-				select {
-				case notifyEvents <- struct{}{}:
-				default:
+				oldNs, oldOk := oldObj.(*v1.Namespace)
+				newNs, newOk := newObj.(*v1.Namespace)
+				if oldOk && newOk && maps.Equal(oldNs.Labels, newNs.Labels) {
+					return
 				}
+				eventHandler.UpdateFunc(oldObj, newObj)
 			},
+			DeleteFunc: eventHandler.DeleteFunc,
 		})
+		if err != nil {
+			return err
+		}
+		go w.nsInformer.Run(w.stopChannel)
+		hasSynced = append(hasSynced, w.nsInformer.HasSynced)
+	}
+	for _, resource := range w.informers {
+		resource.AddEventHandler(eventHandler)
+		resource.Start(w.stopChannel)
+		hasSynced = append(hasSynced, resource.HasSynced)
 	}
-	if !success {
+	if ok := cache.WaitForNamedCacheSync("prometheus-cr", w.stopChannel, hasSynced...); !ok {
 		return fmt.Errorf("failed to sync cache")
 	}
 
@@ -210,34 +310,51 @@ func (w *PrometheusCRWatcher) Close() error {
 
 func (w *PrometheusCRWatcher) LoadConfig(ctx context.Context) (*promconfig.Config, error) {
 	store := assets.NewStoreBuilder(w.k8sClient.CoreV1(), w.k8sClient.CoreV1())
-	serviceMonitorInstances := make(map[string]*monitoringv1.ServiceMonitor)
-	smRetrieveErr := w.informers[monitoringv1.ServiceMonitorName].ListAll(w.serviceMonitorSelector, func(sm interface{}) {
-		monitor := sm.(*monitoringv1.ServiceMonitor)
-		key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(monitor)
-		w.addStoreAssetsForServiceMonitor(ctx, monitor.Name, monitor.Namespace, monitor.Spec.Endpoints, store)
-		serviceMonitorInstances[key] = monitor
-	})
-	if smRetrieveErr != nil {
-		return nil, smRetrieveErr
+	// The resource selector selects the monitors in the namespaces selected, and adds their credentials to the
+	// store. The monitors with an invalid configuration are rejected on their own, with a warning.
+	// A nil namespace selector selects the monitors of the namespace of the Prometheus resource. The copy given to the
+	// resource selector has none, for the monitors to be selected in all the namespaces without watching them.
+	selectorProm := w.prom.DeepCopy()
+	selectorProm.Namespace = v1.NamespaceAll
+	resourceSelector, err := prometheus.NewResourceSelector(w.promOperatorLogger, selectorProm, store, w.nsInformer, w.operatorMetrics, w.eventRecorder)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceMonitorInstances, err := resourceSelector.SelectServiceMonitors(ctx, w.informers[monitoringv1.ServiceMonitorName].ListAllByNamespace)
+	if err != nil {
+		return nil, err
 	}
 
-	podMonitorInstances := make(map[string]*monitoringv1.PodMonitor)
-	pmRetrieveErr := w.informers[monitoringv1.PodMonitorName].ListAll(w.podMonitorSelector, func(pm interface{}) {
-		monitor := pm.(*monitoringv1.PodMonitor)
-		key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(monitor)
-		w.addStoreAssetsForPodMonitor(ctx, monitor.Name, monitor.Namespace, monitor.Spec.PodMetricsEndpoints, store)
-		podMonitorInstances[key] = monitor
-	})
-	if pmRetrieveErr != nil {
-		return nil, pmRetrieveErr
+	podMonitorInstances, err := resourceSelector.SelectPodMonitors(ctx, w.informers[monitoringv1.PodMonitorName].ListAllByNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	probeInstances := map[string]*monitoringv1.Probe{}
+	if informer, ok := w.informers[monitoringv1.ProbeName]; ok {
+		probes, selectErr := resourceSelector.SelectProbes(ctx, informer.ListAllByNamespace)
+		if selectErr != nil {
+			return nil, selectErr
+		}
+		probeInstances = probes.ValidResources()
+	}
+
+	scrapeConfigInstances := map[string]*promv1alpha1.ScrapeConfig{}
+	if informer, ok := w.informers[promv1alpha1.ScrapeConfigName]; ok {
+		scrapeConfigs, selectErr := resourceSelector.SelectScrapeConfigs(ctx, informer.ListAllByNamespace)
+		if selectErr != nil {
+			return nil, selectErr
+		}
+		scrapeConfigInstances = scrapeConfigs.ValidResources()
 	}
 
 	generatedConfig, err := w.configGenerator.GenerateServerConfiguration(
 		w.prom,
-		serviceMonitorInstances,
-		podMonitorInstances,
-		map[string]*monitoringv1.Probe{},
-		map[string]*promv1alpha1.ScrapeConfig{},
+		serviceMonitorInstances.ValidResources(),
+		podMonitorInstances.ValidResources(),
+		probeInstances,
+		scrapeConfigInstances,
 		store,
 		nil,
 		nil,
@@ -265,75 +382,3 @@ func (w *PrometheusCRWatcher) LoadConfig(ctx context.Context) (*promconfig.Confi
 	}
 	return promCfg, nil
 }
-
-// addStoreAssetsForServiceMonitor adds authentication / authorization related information to the assets store,
-// based on the service monitor and endpoints specs.
-// This code borrows from
-// https://github.com/prometheus-operator/prometheus-operator/blob/06b5c4189f3f72737766d86103d049115c3aff48/pkg/prometheus/resource_selector.go#L73.
-func (w *PrometheusCRWatcher) addStoreAssetsForServiceMonitor(
-	ctx context.Context,
-	smName, smNamespace string,
-	endps []monitoringv1.Endpoint,
-	store *assets.StoreBuilder,
-) {
-	var err error
-	for _, endp := range endps {
-		if err = store.AddSafeAuthorizationCredentials(ctx, smNamespace, endp.Authorization); err != nil {
-			break
-		}
-
-		if err = store.AddBasicAuth(ctx, smNamespace, endp.BasicAuth); err != nil {
-			break
-		}
-
-		if endp.TLSConfig != nil {
-			if err = store.AddTLSConfig(ctx, smNamespace, endp.TLSConfig); err != nil {
-				break
-			}
-		}
-
-		if err = store.AddOAuth2(ctx, smNamespace, endp.OAuth2); err != nil {
-			break
-		}
-	}
-
-	if err != nil {
-		w.logger.Error(err, "Failed to obtain credentials for a ServiceMonitor", "serviceMonitor", smName)
-	}
-}
-
-// addStoreAssetsForServiceMonitor adds authentication / authorization related information to the assets store,
-// based on the service monitor and pod metrics endpoints specs.
-// This code borrows from
-// https://github.com/prometheus-operator/prometheus-operator/blob/06b5c4189f3f72737766d86103d049115c3aff48/pkg/prometheus/resource_selector.go#L314.
-func (w *PrometheusCRWatcher) addStoreAssetsForPodMonitor(
-	ctx context.Context,
-	pmName, pmNamespace string,
-	podMetricsEndps []monitoringv1.PodMetricsEndpoint,
-	store *assets.StoreBuilder,
-) {
-	var err error
-	for _, endp := range podMetricsEndps {
-		if err = store.AddSafeAuthorizationCredentials(ctx, pmNamespace, endp.Authorization); err != nil {
-			break
-		}
-
-		if err = store.AddBasicAuth(ctx, pmNamespace, endp.BasicAuth); err != nil {
-			break
-		}
-
-		if endp.TLSConfig != nil {
-			if err = store.AddSafeTLSConfig(ctx, pmNamespace, endp.TLSConfig); err != nil {
-				break
-			}
-		}
-
-		if err = store.AddOAuth2(ctx, pmNamespace, endp.OAuth2); err != nil {
-			break
-		}
-	}
-
-	if err != nil {
-		w.logger.Error(err, "Failed to obtain credentials for a PodMonitor", "podMonitor", pmName)
-	}
-}
@@ -10,9 +10,12 @@ import (
 	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	promv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	fakemonitoringclient "github.com/prometheus-operator/prometheus-operator/pkg/client/versioned/fake"
 	"github.com/prometheus-operator/prometheus-operator/pkg/informers"
+	"github.com/prometheus-operator/prometheus-operator/pkg/operator"
 	"github.com/prometheus-operator/prometheus-operator/pkg/prometheus"
+	prometheusgoclient "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
 	promconfig "github.com/prometheus/prometheus/config"
@@ -22,9 +25,12 @@ import (
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/ptr"
+
+	allocatorconfig "github.com/aws/amazon-cloudwatch-agent-operator/cmd/amazon-cloudwatch-agent-target-allocator/config"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -251,18 +257,16 @@ func TestLoadConfig(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			w := getTestPrometheusCRWatcher(t, tt.serviceMonitor, tt.podMonitor)
-			for _, informer := range w.informers {
-				// Start informers in order to populate cache.
-				informer.Start(w.stopChannel)
+			var objects []runtime.Object
+			if tt.serviceMonitor != nil {
+				objects = append(objects, tt.serviceMonitor)
 			}
-
-			// Wait for informers to sync.
-			for _, informer := range w.informers {
-				for !informer.HasSynced() {
-					time.Sleep(50 * time.Millisecond)
-				}
+			if tt.podMonitor != nil {
+				objects = append(objects, tt.podMonitor)
 			}
+			w := getTestPrometheusCRWatcher(t, allocatorconfig.Config{}, objects...)
+			defer func() { _ = w.Close() }()
+			startTestPrometheusCRWatcher(t, w)
 
 			got, err := w.LoadConfig(context.Background())
 			require.NoError(t, err)
@@ -273,6 +277,175 @@ func TestLoadConfig(t *testing.T) {
 	}
 }
 
+// loadJobNames loads the config of the watcher, returning the names of its jobs.
+func loadJobNames(t *testing.T, w *PrometheusCRWatcher) []string {
+	got, err := w.LoadConfig(context.Background())
+	require.NoError(t, err)
+	var jobNames []string
+	for _, scrapeConfig := range got.ScrapeConfigs {
+		jobNames = append(jobNames, scrapeConfig.JobName)
+	}
+	return jobNames
+}
+
+func simpleServiceMonitor(name, namespace string) *monitoringv1.ServiceMonitor {
+	return &monitoringv1.ServiceMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: monitoringv1.ServiceMonitorSpec{
+			Endpoints: []monitoringv1.Endpoint{
+				{
+					Port: "web",
+				},
+			},
+		},
+	}
+}
+
+func TestLoadConfigProbesAndScrapeConfigs(t *testing.T) {
+	probe := &monitoringv1.Probe{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "test",
+		},
+		Spec: monitoringv1.ProbeSpec{
+			ProberSpec: monitoringv1.ProberSpec{
+				URL: "blackbox-exporter.test.svc:9115",
+			},
+			Module: "http_2xx",
+			Targets: monitoringv1.ProbeTargets{
+				StaticConfig: &monitoringv1.ProbeTargetStaticConfig{
+					Targets: []string{"https://example.com"},
+				},
+			},
+		},
+	}
+	scrapeConfig := &promv1alpha1.ScrapeConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "test",
+		},
+		Spec: promv1alpha1.ScrapeConfigSpec{
+			StaticConfigs: []promv1alpha1.StaticConfig{
+				{
+					Targets: []promv1alpha1.Target{"example.com:9100"},
+				},
+			},
+		},
+	}
+	w := getTestPrometheusCRWatcher(t, allocatorconfig.Config{}, probe, scrapeConfig)
+	defer func() { _ = w.Close() }()
+	startTestPrometheusCRWatcher(t, w)
+
+	got, err := w.LoadConfig(context.Background())
+	require.NoError(t, err)
+	require.Len(t, got.ScrapeConfigs, 2)
+	assert.Equal(t, "probe/test/simple", got.ScrapeConfigs[0].JobName)
+	assert.Equal(t, []string{"http_2xx"}, got.ScrapeConfigs[0].Params["module"])
+	assert.Equal(t, "scrapeConfig/test/simple", got.ScrapeConfigs[1].JobName)
+}
+
+func TestLoadConfigNamespaceSelector(t *testing.T) {
+	objects := []runtime.Object{
+		simpleServiceMonitor("team-a", "test"),
+		simpleServiceMonitor("team-b", "other"),
+	}
+	tests := []struct {
+		name string
+		cfg  allocatorconfig.Config
+		want []string
+	}{
+		{
+			name: "all namespaces",
+			cfg:  allocatorconfig.Config{},
+			want: []string{"serviceMonitor/other/team-b/0", "serviceMonitor/test/team-a/0"},
+		},
+		{
+			name: "match labels",
+			cfg: allocatorconfig.Config{
+				ServiceMonitorNamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"team": "b"},
+				},
+			},
+			want: []string{"serviceMonitor/other/team-b/0"},
+		},
+		{
+			name: "match expressions",
+			cfg: allocatorconfig.Config{
+				ServiceMonitorNamespaceSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "team", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"b"}},
+					},
+				},
+			},
+			want: []string{"serviceMonitor/test/team-a/0"},
+		},
+		{
+			name: "other kind",
+			cfg: allocatorconfig.Config{
+				PodMonitorNamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"team": "b"},
+				},
+			},
+			want: []string{"serviceMonitor/other/team-b/0", "serviceMonitor/test/team-a/0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := getTestPrometheusCRWatcher(t, tt.cfg, objects...)
+			defer func() { _ = w.Close() }()
+			startTestPrometheusCRWatcher(t, w)
+
+			assert.Equal(t, tt.want, loadJobNames(t, w))
+		})
+	}
+}
+
+func TestLoadConfigRejectsInvalidMonitors(t *testing.T) {
+	// the secret of the basic auth doesn't exist
+	invalid := simpleServiceMonitor("invalid", "test")
+	invalid.Spec.Endpoints[0].BasicAuth = &monitoringv1.BasicAuth{
+		Username: v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{
+				Name: "missing",
+			},
+			Key: "username",
+		},
+		Password: v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{
+				Name: "missing",
+			},
+			Key: "password",
+		},
+	}
+	w := getTestPrometheusCRWatcher(t, allocatorconfig.Config{}, simpleServiceMonitor("valid", "test"), invalid)
+	defer func() { _ = w.Close() }()
+	startTestPrometheusCRWatcher(t, w)
+
+	assert.Equal(t, []string{"serviceMonitor/test/valid/0"}, loadJobNames(t, w))
+}
+
+func TestGetNamespaceInformer(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	// the namespaces aren't watched without namespace selector
+	assert.Nil(t, getNamespaceInformer(clientset, allocatorconfig.Config{}))
+	assert.NotNil(t, getNamespaceInformer(clientset, allocatorconfig.Config{
+		ProbeNamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}},
+	}))
+}
+
+func TestGetInformersWithoutCRDs(t *testing.T) {
+	mClient := fakemonitoringclient.NewSimpleClientset() //nolint:staticcheck // NewClientset causes structured merge diff schema errors in tests
+	factory := informers.NewMonitoringInformerFactories(map[string]struct{}{v1.NamespaceAll: {}}, map[string]struct{}{}, mClient, 0, nil)
+	got, err := getInformers(factory, fake.NewSimpleClientset().Discovery())
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Contains(t, got, monitoringv1.ServiceMonitorName)
+	assert.Contains(t, got, monitoringv1.PodMonitorName)
+}
+
 func TestRateLimit(t *testing.T) {
 	var err error
 	serviceMonitor := &monitoringv1.ServiceMonitor{
@@ -292,7 +465,7 @@ func TestRateLimit(t *testing.T) {
 	events := make(chan Event, 1)
 	eventInterval := 5 * time.Millisecond
 
-	w := getTestPrometheusCRWatcher(t, nil, nil)
+	w := getTestPrometheusCRWatcher(t, allocatorconfig.Config{})
 	defer func() { _ = w.Close() }()
 	w.eventInterval = eventInterval
 
@@ -354,23 +527,51 @@ func TestRateLimit(t *testing.T) {
 }
 
 // getTestPrometheuCRWatcher creates a test instance of PrometheusCRWatcher with fake clients
-// and test secrets.
-func getTestPrometheusCRWatcher(t *testing.T, sm *monitoringv1.ServiceMonitor, pm *monitoringv1.PodMonitor) *PrometheusCRWatcher {
+// and test secrets, watching the monitors, Probes and ScrapeConfigs given.
+func getTestPrometheusCRWatcher(t *testing.T, cfg allocatorconfig.Config, objects ...runtime.Object) *PrometheusCRWatcher {
 	mClient := fakemonitoringclient.NewSimpleClientset() //nolint:staticcheck // NewClientset causes structured merge diff schema errors in tests
-	if sm != nil {
-		_, err := mClient.MonitoringV1().ServiceMonitors("test").Create(context.Background(), sm, metav1.CreateOptions{})
+	for _, object := range objects {
+		var err error
+		switch obj := object.(type) {
+		case *monitoringv1.ServiceMonitor:
+			_, err = mClient.MonitoringV1().ServiceMonitors(obj.Namespace).Create(context.Background(), obj, metav1.CreateOptions{})
+		case *monitoringv1.PodMonitor:
+			_, err = mClient.MonitoringV1().PodMonitors(obj.Namespace).Create(context.Background(), obj, metav1.CreateOptions{})
+		case *monitoringv1.Probe:
+			_, err = mClient.MonitoringV1().Probes(obj.Namespace).Create(context.Background(), obj, metav1.CreateOptions{})
+		case *promv1alpha1.ScrapeConfig:
+			_, err = mClient.MonitoringV1alpha1().ScrapeConfigs(obj.Namespace).Create(context.Background(), obj, metav1.CreateOptions{})
+		default:
+			t.Fatalf("unexpected object %T", object)
+		}
 		if err != nil {
 			t.Fatal(t, err)
 		}
 	}
-	if pm != nil {
-		_, err := mClient.MonitoringV1().PodMonitors("test").Create(context.Background(), pm, metav1.CreateOptions{})
+
+	k8sClient := fake.NewSimpleClientset()
+	// the Probe and ScrapeConfig CRDs are installed
+	k8sClient.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: monitoringv1.SchemeGroupVersion.String(),
+			APIResources: []metav1.APIResource{{Name: monitoringv1.ProbeName}},
+		},
+		{
+			GroupVersion: promv1alpha1.SchemeGroupVersion.String(),
+			APIResources: []metav1.APIResource{{Name: promv1alpha1.ScrapeConfigName}},
+		},
+	}
+	for name, team := range map[string]string{"test": "a", "other": "b"} {
+		_, err := k8sClient.CoreV1().Namespaces().Create(context.Background(), &v1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"team": team},
+			},
+		}, metav1.CreateOptions{})
 		if err != nil {
 			t.Fatal(t, err)
 		}
 	}
-
-	k8sClient := fake.NewSimpleClientset()
 	_, err := k8sClient.CoreV1().Secrets("test").Create(context.Background(), &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "basic-auth",
@@ -393,7 +594,7 @@ func getTestPrometheusCRWatcher(t *testing.T, sm *monitoringv1.ServiceMonitor, p
 	}
 
 	factory := informers.NewMonitoringInformerFactories(map[string]struct{}{v1.NamespaceAll: {}}, map[string]struct{}{}, mClient, 0, nil)
-	informers, err := getInformers(factory)
+	informers, err := getInformers(factory, k8sClient.Discovery())
 	if err != nil {
 		t.Fatal(t, err)
 	}
@@ -405,7 +606,15 @@ func getTestPrometheusCRWatcher(t *testing.T, sm *monitoringv1.ServiceMonitor, p
 		},
 		Spec: monitoringv1.PrometheusSpec{
 			CommonPrometheusFields: monitoringv1.CommonPrometheusFields{
-				ScrapeInterval: monitoringv1.Duration("30s"),
+				ScrapeInterval:                  monitoringv1.Duration("30s"),
+				ServiceMonitorSelector:          getSelector(cfg.ServiceMonitorSelector),
+				ServiceMonitorNamespaceSelector: cfg.ServiceMonitorNamespaceSelector,
+				PodMonitorSelector:              getSelector(cfg.PodMonitorSelector),
+				PodMonitorNamespaceSelector:     cfg.PodMonitorNamespaceSelector,
+				ProbeSelector:                   getSelector(cfg.ProbeSelector),
+				ProbeNamespaceSelector:          cfg.ProbeNamespaceSelector,
+				ScrapeConfigSelector:            getSelector(cfg.ScrapeConfigSelector),
+				ScrapeConfigNamespaceSelector:   cfg.ScrapeConfigNamespaceSelector,
 			},
 			EvaluationInterval: monitoringv1.Duration("30s"),
 		},
@@ -417,14 +626,35 @@ func getTestPrometheusCRWatcher(t *testing.T, sm *monitoringv1.ServiceMonitor, p
 	}
 
 	return &PrometheusCRWatcher{
-		kubeMonitoringClient:   mClient,
-		k8sClient:              k8sClient,
-		informers:              informers,
-		configGenerator:        generator,
-		prom:                   prom,
-		serviceMonitorSelector: getSelector(nil),
-		podMonitorSelector:     getSelector(nil),
-		stopChannel:            make(chan struct{}),
+		promOperatorLogger:   slog.Default(),
+		kubeMonitoringClient: mClient,
+		k8sClient:            k8sClient,
+		informers:            informers,
+		nsInformer:           getNamespaceInformer(k8sClient, cfg),
+		configGenerator:      generator,
+		prom:                 prom,
+		stopChannel:          make(chan struct{}),
+		operatorMetrics:      operator.NewMetrics(prometheusgoclient.NewRegistry()),
+		eventRecorder:        operator.NewEventRecorderFactory(false)(k8sClient, "test")(prom),
+	}
+}
+
+// startTestPrometheusCRWatcher starts the informers of the watcher and waits for them to sync.
+func startTestPrometheusCRWatcher(t *testing.T, w *PrometheusCRWatcher) {
+	if w.nsInformer != nil {
+		go w.nsInformer.Run(w.stopChannel)
+		require.True(t, cache.WaitForCacheSync(w.stopChannel, w.nsInformer.HasSynced))
+	}
+	for _, informer := range w.informers {
+		// Start informers in order to populate cache.
+		informer.Start(w.stopChannel)
+	}
+
+	// Wait for informers to sync.
+	for _, informer := range w.informers {
+		for !informer.HasSynced() {
+			time.Sleep(50 * time.Millisecond)
+		}
 	}
 }
 
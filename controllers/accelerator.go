@@ -0,0 +1,125 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+var (
+	// gpuResources are the extended resources advertised by the NVIDIA device plugin on GPU nodes.
+	gpuResources = []corev1.ResourceName{"nvidia.com/gpu"}
+	// neuronResources are the extended resources advertised by the Neuron device plugin on Inferentia and Trainium nodes.
+	neuronResources = []corev1.ResourceName{"aws.amazon.com/neuron", "aws.amazon.com/neuroncore", "aws.amazon.com/neurondevice"}
+
+	// nodeGroupLabels are the labels identifying the node group of an accelerator node, by order of preference.
+	nodeGroupLabels = []string{corev1.LabelInstanceTypeStable, corev1.LabelHostname}
+)
+
+// hasAllocatable tells whether the node can allocate any of the resources.
+func hasAllocatable(node *corev1.Node, resources []corev1.ResourceName) bool {
+	for _, resource := range resources {
+		if quantity, ok := node.Status.Allocatable[resource]; ok && !quantity.IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// acceleratorAffinity returns the node affinity scheduling an exporter on the node groups whose nodes can allocate any
+// of the resources, nil if there is none. A node group is the set of nodes sharing the first of the node group labels
+// the accelerator node has.
+func acceleratorAffinity(nodes []corev1.Node, resources []corev1.ResourceName) *corev1.Affinity {
+	nodeGroups := map[string]map[string]struct{}{}
+	for i := range nodes {
+		if !hasAllocatable(&nodes[i], resources) {
+			continue
+		}
+		for _, key := range nodeGroupLabels {
+			if value, ok := nodes[i].Labels[key]; ok {
+				if nodeGroups[key] == nil {
+					nodeGroups[key] = map[string]struct{}{}
+				}
+				nodeGroups[key][value] = struct{}{}
+				break
+			}
+		}
+	}
+	if len(nodeGroups) == 0 {
+		return nil
+	}
+
+	var terms []corev1.NodeSelectorTerm
+	for _, key := range nodeGroupLabels {
+		if len(nodeGroups[key]) == 0 {
+			continue
+		}
+		values := make([]string, 0, len(nodeGroups[key]))
+		for value := range nodeGroups[key] {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		terms = append(terms, corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{
+				Key:      key,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   values,
+			}},
+		})
+	}
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		},
+	}
+}
+
+// getAcceleratorAffinity lists the nodes of the cluster to return the node affinity of an exporter of the resources.
+func getAcceleratorAffinity(ctx context.Context, c client.Client, resources []corev1.ResourceName) (*corev1.Affinity, error) {
+	var nodes corev1.NodeList
+	if err := c.List(ctx, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	return acceleratorAffinity(nodes.Items, resources), nil
+}
+
+// acceleratorNodePredicate filters the node events that change the node groups able to allocate any of the resources.
+func acceleratorNodePredicate(resources []corev1.ResourceName) predicate.Predicate {
+	isAccelerator := func(obj client.Object) bool {
+		node, ok := obj.(*corev1.Node)
+		return ok && hasAllocatable(node, resources)
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isAccelerator(e.Object)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return isAccelerator(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if isAccelerator(e.ObjectOld) != isAccelerator(e.ObjectNew) {
+				return true
+			}
+			if !isAccelerator(e.ObjectNew) {
+				return false
+			}
+			for _, key := range nodeGroupLabels {
+				if e.ObjectOld.GetLabels()[key] != e.ObjectNew.GetLabels()[key] {
+					return true
+				}
+			}
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return isAccelerator(e.Object)
+		},
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func acceleratorNode(name string, labels map[string]string, allocatable corev1.ResourceList) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status:     corev1.NodeStatus{Allocatable: allocatable},
+	}
+}
+
+func TestAcceleratorAffinity(t *testing.T) {
+	nodes := []corev1.Node{
+		acceleratorNode("gpu-1", map[string]string{corev1.LabelInstanceTypeStable: "p4d.24xlarge", corev1.LabelHostname: "gpu-1"}, corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("8")}),
+		acceleratorNode("gpu-2", map[string]string{corev1.LabelInstanceTypeStable: "g5.xlarge", corev1.LabelHostname: "gpu-2"}, corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}),
+		acceleratorNode("gpu-3", map[string]string{corev1.LabelInstanceTypeStable: "g5.xlarge", corev1.LabelHostname: "gpu-3"}, corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}),
+		acceleratorNode("gpu-4", map[string]string{corev1.LabelHostname: "gpu-4"}, corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}),
+		acceleratorNode("no-gpu", map[string]string{corev1.LabelInstanceTypeStable: "g5.2xlarge"}, corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("0")}),
+		acceleratorNode("neuron", map[string]string{corev1.LabelInstanceTypeStable: "inf2.xlarge"}, corev1.ResourceList{"aws.amazon.com/neuroncore": resource.MustParse("2")}),
+		acceleratorNode("cpu", map[string]string{corev1.LabelInstanceTypeStable: "m5.large"}, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}),
+	}
+
+	expected := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelInstanceTypeStable, Operator: corev1.NodeSelectorOpIn, Values: []string{"g5.xlarge", "p4d.24xlarge"}}}},
+					{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: corev1.LabelHostname, Operator: corev1.NodeSelectorOpIn, Values: []string{"gpu-4"}}}},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, acceleratorAffinity(nodes, gpuResources))
+
+	neuronAffinity := acceleratorAffinity(nodes, neuronResources)
+	if assert.NotNil(t, neuronAffinity) {
+		assert.Equal(t, []string{"inf2.xlarge"}, neuronAffinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0].Values)
+	}
+
+	assert.Nil(t, acceleratorAffinity(nodes[5:], gpuResources))
+}
+
+func TestAcceleratorNodePredicate(t *testing.T) {
+	gpu := acceleratorNode("node", map[string]string{corev1.LabelInstanceTypeStable: "g5.xlarge"}, corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")})
+	cpu := acceleratorNode("node", map[string]string{corev1.LabelInstanceTypeStable: "g5.xlarge"}, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")})
+	relabeled := *gpu.DeepCopy()
+	relabeled.Labels[corev1.LabelInstanceTypeStable] = "g5.2xlarge"
+	annotated := *gpu.DeepCopy()
+	annotated.Annotations = map[string]string{"node.alpha.kubernetes.io/ttl": "0"}
+
+	p := acceleratorNodePredicate(gpuResources)
+	assert.True(t, p.Create(event.CreateEvent{Object: &gpu}))
+	assert.False(t, p.Create(event.CreateEvent{Object: &cpu}))
+	assert.True(t, p.Delete(event.DeleteEvent{Object: &gpu}))
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: &cpu, ObjectNew: &gpu}))
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: &gpu, ObjectNew: &relabeled}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: &gpu, ObjectNew: &annotated}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: &cpu, ObjectNew: &cpu}))
+}
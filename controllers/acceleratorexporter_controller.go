@@ -0,0 +1,231 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/acceleratorexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	acceleratorexporterStatus "github.com/aws/amazon-cloudwatch-agent-operator/internal/status/acceleratorexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/status/conditions"
+)
+
+// acceleratorExporterKind describes a kind of accelerator exporter to the AcceleratorExporterReconciler.
+type acceleratorExporterKind struct {
+	acceleratorexporter.Kind
+	// resources are the extended resources of the accelerator nodes the exporter runs on.
+	resources []corev1.ResourceName
+	// newObject returns an empty resource of the kind.
+	newObject func() v1alpha1.AcceleratorExporter
+	// newList returns an empty list of resources of the kind.
+	newList func() client.ObjectList
+	// exporter returns the resource as the accelerator exporter its manifests are built for.
+	exporter func(obj v1alpha1.AcceleratorExporter) acceleratorexporter.Exporter
+}
+
+// AcceleratorExporterReconciler reconciles the resources of a kind of accelerator exporter.
+type AcceleratorExporterReconciler struct {
+	client.Client
+	recorder record.EventRecorder
+	scheme   *runtime.Scheme
+	log      logr.Logger
+	config   config.Config
+	kind     acceleratorExporterKind
+}
+
+func newAcceleratorExporterReconciler(p Params, kind acceleratorExporterKind) *AcceleratorExporterReconciler {
+	return &AcceleratorExporterReconciler{
+		Client:   p.Client,
+		log:      p.Log,
+		scheme:   p.Scheme,
+		config:   p.Config,
+		recorder: p.Recorder,
+		kind:     kind,
+	}
+}
+
+func (r *AcceleratorExporterReconciler) getParams() manifests.Params {
+	return manifests.Params{
+		Config:   r.config,
+		Client:   r.Client,
+		Log:      r.log,
+		Scheme:   r.scheme,
+		Recorder: r.recorder,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=pods;configmaps;services;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets;deployments;statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=route.openshift.io,resources=routes;routes/custom-host,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=get;list;watch
+
+// Reconcile the current state of an accelerator exporter resource with the desired state.
+func (r *AcceleratorExporterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues(r.kind.Name, req.NamespacedName)
+
+	instance := r.kind.newObject()
+	if err := r.Get(ctx, req.NamespacedName, instance); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch "+r.kind.Name)
+		}
+
+		// we'll ignore not-found errors, since they can't be fixed by an immediate
+		// requeue (we'll need to wait for a new notification), and we can get them
+		// on deleted requests.
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// We have a deletion, short circuit and let the deletion happen
+	if deletionTimestamp := instance.GetDeletionTimestamp(); deletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	enabled, err := enabledAcceleratedComputeByAgentConfig(ctx, r.Client, log)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	affinity, err := getAcceleratorAffinity(ctx, r.Client, r.kind.resources)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	exporter := r.kind.exporter(instance)
+	if exporter.Spec.Affinity == nil {
+		// without an affinity of its own, the exporter runs on the accelerator node groups of the cluster
+		exporter.Spec.Affinity = affinity
+	}
+	desiredObjects := r.kind.Build(r.config, exporter)
+
+	if !enabled || exporter.Spec.Affinity == nil {
+		notDeployedErr := conditions.NewNotDeployedError(conditions.ReasonDisabled, "enhanced_container_insights or accelerated_compute_metrics is disabled")
+		if enabled {
+			notDeployedErr = conditions.NewNotDeployedError(conditions.ReasonNoAcceleratorNodes, "no node of the cluster can allocate the accelerator resources")
+		}
+		log.Info(notDeployedErr.Error(), "resources", r.kind.resources)
+		err = r.deleteOwnedObjects(ctx, log, instance)
+		if err == nil {
+			err = notDeployedErr
+		}
+		return acceleratorexporterStatus.HandleReconcileStatus(ctx, log, r.getParams(), r.kind.Kind, instance, err)
+	}
+
+	err = reconcileDesiredObjectsWPrune(ctx, r.Client, log, instance, r.scheme, desiredObjects, r.findOwnedObjects)
+	return acceleratorexporterStatus.HandleReconcileStatus(ctx, log, r.getParams(), r.kind.Kind, instance, err)
+}
+
+// deleteOwnedObjects deletes the objects of the exporter when it is not deployed.
+func (r *AcceleratorExporterReconciler) deleteOwnedObjects(ctx context.Context, log logr.Logger, instance v1alpha1.AcceleratorExporter) error {
+	ownedObjects, err := r.findOwnedObjects(ctx, instance)
+	if err != nil {
+		return fmt.Errorf("failed to search owned objects: %w", err)
+	}
+	if err := pruneStaleObjects(ctx, r.Client, log, ownedObjects, nil); err != nil {
+		return fmt.Errorf("failed to delete objects for %s: %w", instance.GetName(), err)
+	}
+	return nil
+}
+
+// findOwnedObjects returns the objects of the exporter, labeled with its component and controlled by it.
+func (r *AcceleratorExporterReconciler) findOwnedObjects(ctx context.Context, owner v1alpha1.AcceleratorExporter) (map[types.UID]client.Object, error) {
+	ownedObjects := make(map[types.UID]client.Object)
+	selector := manifestutils.SelectorLabels(r.kind.exporter(owner).ObjectMeta, r.kind.Component)
+	listOps := &client.ListOptions{
+		Namespace:     owner.GetNamespace(),
+		LabelSelector: labels.SelectorFromSet(selector),
+	}
+	lists := []client.ObjectList{
+		&corev1.ConfigMapList{},
+		&corev1.ServiceList{},
+		&corev1.ServiceAccountList{},
+		&appsv1.DaemonSetList{},
+	}
+	for _, list := range lists {
+		if err := r.List(ctx, list, listOps); err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			obj, ok := item.(client.Object)
+			if ok && metav1.IsControlledBy(obj, owner) {
+				ownedObjects[obj.GetUID()] = obj
+			}
+		}
+	}
+	return ownedObjects, nil
+}
+
+// SetupWithManager tells the manager what our controller is interested in.
+func (r *AcceleratorExporterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(r.kind.newObject()).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.ServiceAccount{}).
+		Owns(&corev1.Service{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&appsv1.DaemonSet{}).
+		Watches(
+			&v1alpha1.AmazonCloudWatchAgent{},
+			handler.EnqueueRequestsFromMapFunc(r.allExporters),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(r.allExporters),
+			builder.WithPredicates(acceleratorNodePredicate(r.kind.resources)),
+		).
+		Complete(r)
+}
+
+// allExporters maps a change of the agent configurations or of the accelerator node groups to all the resources of
+// the exporter kind.
+func (r *AcceleratorExporterReconciler) allExporters(ctx context.Context, _ client.Object) []reconcile.Request {
+	list := r.kind.newList()
+	if err := r.List(ctx, list); err != nil {
+		r.log.Error(err, "unable to list "+r.kind.Name+" resources")
+		return nil
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		r.log.Error(err, "unable to list "+r.kind.Name+" resources")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(client.Object); ok {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(obj)})
+		}
+	}
+	return requests
+}
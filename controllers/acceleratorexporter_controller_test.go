@@ -0,0 +1,145 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/dcgmexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/status/conditions"
+)
+
+func TestAcceleratorExporterReconcilePrunesObjects(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent", Namespace: "amazon-cloudwatch"},
+		Spec: v1alpha1.AmazonCloudWatchAgentSpec{
+			Config: `{"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":true}}}}`,
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu", Labels: map[string]string{corev1.LabelInstanceTypeStable: "g5.xlarge"}},
+		Status:     corev1.NodeStatus{Allocatable: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}},
+	}
+	exporter := &v1alpha1.DcgmExporter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dcgm-exporter", Namespace: "amazon-cloudwatch", UID: "dcgm-exporter-uid"},
+	}
+	controllerRef := metav1.OwnerReference{
+		APIVersion: v1alpha1.GroupVersion.String(),
+		Kind:       "DcgmExporter",
+		Name:       exporter.Name,
+		UID:        exporter.UID,
+		Controller: ptr.To(true),
+	}
+	// a config map of the exporter which is not desired anymore
+	stale := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "dcgm-exporter-old-config-map",
+			Namespace:       "amazon-cloudwatch",
+			UID:             "stale-uid",
+			Labels:          manifestutils.SelectorLabels(exporter.ObjectMeta, dcgmexporter.ComponentDcgmExporter),
+			OwnerReferences: []metav1.OwnerReference{controllerRef},
+		},
+	}
+	// a config map with the same labels, not controlled by the exporter
+	unowned := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unowned-config-map",
+			Namespace: "amazon-cloudwatch",
+			UID:       "unowned-uid",
+			Labels:    manifestutils.SelectorLabels(exporter.ObjectMeta, dcgmexporter.ComponentDcgmExporter),
+		},
+	}
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(agent, node, exporter, stale, unowned).
+		WithStatusSubresource(exporter).
+		WithInterceptorFuncs(interceptor.Funcs{
+			// the owned objects are told apart by their UID, set by the API server
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				obj.SetUID(uuid.NewUUID())
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	r := NewDcgmExporterReconciler(Params{
+		Client:   cli,
+		Log:      logf.Log.WithName("unit-tests"),
+		Scheme:   scheme,
+		Config:   config.New(),
+		Recorder: record.NewFakeRecorder(100),
+	})
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: exporter.Namespace, Name: exporter.Name}}
+
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	var daemonSet appsv1.DaemonSet
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(exporter), &daemonSet))
+	assert.Equal(t, acceleratorAffinity([]corev1.Node{*node}, gpuResources), daemonSet.Spec.Template.Spec.Affinity)
+	var configMap corev1.ConfigMap
+	require.NoError(t, cli.Get(ctx, types.NamespacedName{Namespace: exporter.Namespace, Name: dcgmexporter.DcgmConfigMapName}, &configMap))
+	assert.True(t, apierrors.IsNotFound(cli.Get(ctx, client.ObjectKeyFromObject(stale), &corev1.ConfigMap{})))
+	assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(unowned), &corev1.ConfigMap{}))
+
+	// the accelerated compute metrics are disabled, the objects of the exporter are deleted
+	agent.Spec.Config = `{"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":false}}}}`
+	require.NoError(t, cli.Update(ctx, agent))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	owned, err := r.findOwnedObjects(ctx, exporter)
+	require.NoError(t, err)
+	assert.Empty(t, owned)
+	assert.True(t, apierrors.IsNotFound(cli.Get(ctx, client.ObjectKeyFromObject(exporter), &appsv1.DaemonSet{})))
+	assert.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(unowned), &corev1.ConfigMap{}))
+	assertReadyReason(t, cli, exporter, conditions.ReasonDisabled)
+
+	// the accelerated compute metrics are enabled again, but no node can allocate a GPU anymore
+	agent.Spec.Config = `{"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":true}}}}`
+	require.NoError(t, cli.Update(ctx, agent))
+	require.NoError(t, cli.Delete(ctx, node))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assertReadyReason(t, cli, exporter, conditions.ReasonNoAcceleratorNodes)
+}
+
+func assertReadyReason(t *testing.T, cli client.Client, exporter *v1alpha1.DcgmExporter, reason string) {
+	t.Helper()
+	var got v1alpha1.DcgmExporter
+	require.NoError(t, cli.Get(context.Background(), client.ObjectKeyFromObject(exporter), &got))
+	ready := meta.FindStatusCondition(got.Status.Conditions, v1alpha1.ConditionTypeReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, reason, ready.Reason)
+	assert.Empty(t, got.Status.LastReconcileError)
+}
@@ -6,25 +6,38 @@ package controllers
 
 import (
 	"context"
+	"slices"
 
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/targetallocator"
 	collectorStatus "github.com/aws/amazon-cloudwatch-agent-operator/internal/status/collector"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/status/conditions"
 )
 
+// clusterObjectsFinalizer holds the deletion of the AmazonCloudWatchAgent resources until their objects that can't be
+// owned by them, cluster-scoped or in other namespaces, are deleted.
+const clusterObjectsFinalizer = "cloudwatch.aws.amazon.com/cluster-objects"
+
 // AmazonCloudWatchAgentReconciler reconciles a AmazonCloudWatchAgent object.
 type AmazonCloudWatchAgentReconciler struct {
 	client.Client
@@ -43,7 +56,7 @@ type Params struct {
 	Config   config.Config
 }
 
-func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx context.Context, owner v1alpha1.AmazonCloudWatchAgent) (map[types.UID]client.Object, error) {
+func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx context.Context, owner *v1alpha1.AmazonCloudWatchAgent) (map[types.UID]client.Object, error) {
 	// Define a map to store the owned objects
 	ownedObjects := make(map[types.UID]client.Object)
 	selector := manifestutils.SelectorLabelsForAllOperatorManaged(owner.ObjectMeta)
@@ -58,6 +71,8 @@ func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx co
 	deploymentList := &appsv1.DeploymentList{}
 	statefulSetList := &appsv1.StatefulSetList{}
 	daemonSetList := &appsv1.DaemonSetList{}
+	roleList := &rbacv1.RoleList{}
+	roleBindingList := &rbacv1.RoleBindingList{}
 	var err error
 
 	// List ConfigMaps
@@ -113,9 +128,85 @@ func (r *AmazonCloudWatchAgentReconciler) findCloudWatchAgentOwnedObjects(ctx co
 		ownedObjects[daemonSetList.Items[i].GetUID()] = &daemonSetList.Items[i]
 	}
 
+	// List Roles
+	err = r.List(ctx, roleList, listOps)
+	if err != nil {
+		return nil, err
+	}
+	for i := range roleList.Items {
+		ownedObjects[roleList.Items[i].GetUID()] = &roleList.Items[i]
+	}
+
+	// List RoleBindings
+	err = r.List(ctx, roleBindingList, listOps)
+	if err != nil {
+		return nil, err
+	}
+	for i := range roleBindingList.Items {
+		ownedObjects[roleBindingList.Items[i].GetUID()] = &roleBindingList.Items[i]
+	}
+
+	clusterObjects, err := r.findClusterObjects(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	for uid, obj := range clusterObjects {
+		ownedObjects[uid] = obj
+	}
+
 	return ownedObjects, nil
 
 }
+
+// findClusterObjects returns the cluster-scoped objects of the instance and its role bindings in other namespaces,
+// found by its labels as it can't own them.
+func (r *AmazonCloudWatchAgentReconciler) findClusterObjects(ctx context.Context, owner *v1alpha1.AmazonCloudWatchAgent) (map[types.UID]client.Object, error) {
+	clusterObjects := make(map[types.UID]client.Object)
+	selector := manifestutils.SelectorLabelsForAllOperatorManaged(owner.ObjectMeta)
+	listOps := &client.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selector),
+	}
+
+	// List ClusterRoles
+	clusterRoleList := &rbacv1.ClusterRoleList{}
+	if err := r.List(ctx, clusterRoleList, listOps); err != nil {
+		return nil, err
+	}
+	for i := range clusterRoleList.Items {
+		clusterObjects[clusterRoleList.Items[i].GetUID()] = &clusterRoleList.Items[i]
+	}
+
+	// List ClusterRoleBindings
+	clusterRoleBindingList := &rbacv1.ClusterRoleBindingList{}
+	if err := r.List(ctx, clusterRoleBindingList, listOps); err != nil {
+		return nil, err
+	}
+	for i := range clusterRoleBindingList.Items {
+		clusterObjects[clusterRoleBindingList.Items[i].GetUID()] = &clusterRoleBindingList.Items[i]
+	}
+
+	// List RoleBindings in all the namespaces
+	roleBindingList := &rbacv1.RoleBindingList{}
+	if err := r.List(ctx, roleBindingList, listOps); err != nil {
+		return nil, err
+	}
+	for i := range roleBindingList.Items {
+		if roleBindingList.Items[i].Namespace != owner.Namespace {
+			clusterObjects[roleBindingList.Items[i].GetUID()] = &roleBindingList.Items[i]
+		}
+	}
+	return clusterObjects, nil
+}
+
+// deleteClusterObjects deletes the cluster-scoped objects of the instance being deleted.
+func (r *AmazonCloudWatchAgentReconciler) deleteClusterObjects(ctx context.Context, owner *v1alpha1.AmazonCloudWatchAgent) error {
+	clusterObjects, err := r.findClusterObjects(ctx, owner)
+	if err != nil {
+		return err
+	}
+	return pruneStaleObjects(ctx, r.Client, r.log.WithValues("amazoncloudwatchagent", client.ObjectKeyFromObject(owner)), clusterObjects, nil)
+}
+
 func (r *AmazonCloudWatchAgentReconciler) getParams(instance v1alpha1.AmazonCloudWatchAgent) manifests.Params {
 	return manifests.Params{
 		Config:   r.config,
@@ -141,12 +232,17 @@ func NewReconciler(p Params) *AmazonCloudWatchAgentReconciler {
 
 // +kubebuilder:rbac:groups="",resources=pods;configmaps;services;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=endpoints;namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups=apps,resources=daemonsets;deployments;statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;create;update
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=probes;scrapeconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings;clusterroles;clusterrolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind,resourceNames=cloudwatch-target-allocator-credentials-role
 // +kubebuilder:rbac:groups=route.openshift.io,resources=routes;routes/custom-host,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=amazoncloudwatchagents/status,verbs=get;update;patch
@@ -169,6 +265,17 @@ func (r *AmazonCloudWatchAgentReconciler) Reconcile(ctx context.Context, req ctr
 	}
 	// We have a deletion, short circuit and let the deletion happen
 	if deletionTimestamp := instance.GetDeletionTimestamp(); deletionTimestamp != nil {
+		if controllerutil.ContainsFinalizer(&instance, clusterObjectsFinalizer) {
+			// the cluster-scoped objects can't be owned by the instance to be garbage collected with it
+			if err := r.deleteClusterObjects(ctx, &instance); err != nil {
+				return ctrl.Result{}, err
+			}
+			patch := client.MergeFromWithOptions(instance.DeepCopy(), client.MergeFromWithOptimisticLock{})
+			controllerutil.RemoveFinalizer(&instance, clusterObjectsFinalizer)
+			if err := r.Patch(ctx, &instance, patch); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -182,10 +289,21 @@ func (r *AmazonCloudWatchAgentReconciler) Reconcile(ctx context.Context, req ctr
 
 	desiredObjects, buildErr := BuildCollector(params)
 	if buildErr != nil {
-		return ctrl.Result{}, buildErr
+		return collectorStatus.HandleReconcileStatus(ctx, log, params, conditions.NewConfigError(buildErr))
+	}
+
+	notOwnable := func(obj client.Object) bool { return !isOwnable(&instance, obj) }
+	if slices.ContainsFunc(desiredObjects, notOwnable) && !controllerutil.ContainsFinalizer(&instance, clusterObjectsFinalizer) {
+		// the finalizer is patched in, not to send the whole instance back
+		patch := client.MergeFromWithOptions(instance.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		controllerutil.AddFinalizer(&instance, clusterObjectsFinalizer)
+		if err := r.Patch(ctx, &instance, patch); err != nil {
+			return ctrl.Result{}, err
+		}
+		params.OtelCol = instance
 	}
 
-	err := reconcileDesiredObjectsWPrune(ctx, r.Client, log, params.OtelCol, params.Scheme, desiredObjects, r.findCloudWatchAgentOwnedObjects)
+	err := reconcileDesiredObjectsWPrune(ctx, r.Client, log, &params.OtelCol, params.Scheme, desiredObjects, r.findCloudWatchAgentOwnedObjects)
 	return collectorStatus.HandleReconcileStatus(ctx, log, params, err)
 }
 
@@ -198,7 +316,31 @@ func (r *AmazonCloudWatchAgentReconciler) SetupWithManager(mgr ctrl.Manager) err
 		Owns(&corev1.Service{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.DaemonSet{}).
-		Owns(&appsv1.StatefulSet{})
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{}).
+		Watches(
+			&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.namespaceSelectingAgents),
+			ctrlbuilder.WithPredicates(predicate.LabelChangedPredicate{}),
+		)
 
 	return builder.Complete(r)
 }
+
+// namespaceSelectingAgents maps a change of the namespaces or of their labels to the instances whose TargetAllocator
+// selects the monitors by the labels of their namespace, for it to be bound to the credentials of their namespaces.
+func (r *AmazonCloudWatchAgentReconciler) namespaceSelectingAgents(ctx context.Context, _ client.Object) []reconcile.Request {
+	var list v1alpha1.AmazonCloudWatchAgentList
+	if err := r.List(ctx, &list); err != nil {
+		r.log.Error(err, "failed to list the AmazonCloudWatchAgent resources selecting namespaces")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, agent := range list.Items {
+		if agent.Spec.TargetAllocator.Enabled && agent.Spec.TargetAllocator.PrometheusCR.Enabled && targetallocator.HasNamespaceSelector(agent) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&agent)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,112 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+)
+
+func TestAmazonCloudWatchAgentReconcileDeletesClusterObjects(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, rbacv1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	now := metav1.Now()
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "cloudwatch-agent",
+			Namespace:         "amazon-cloudwatch",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{clusterObjectsFinalizer},
+		},
+	}
+	labels := manifestutils.SelectorLabelsForAllOperatorManaged(agent.ObjectMeta)
+	clusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent-amazon-cloudwatch-target-allocator", Labels: labels, UID: "cluster-role-uid"},
+	}
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent-amazon-cloudwatch-target-allocator", Labels: labels, UID: "cluster-role-binding-uid"},
+	}
+	// the binding to the credentials of the monitors of another namespace
+	otherNamespaceBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent-amazon-cloudwatch-target-allocator-credentials", Namespace: "team-a", Labels: labels, UID: "role-binding-uid"},
+	}
+	// the cluster role of another instance
+	otherClusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "cloudwatch-agent-other-target-allocator",
+			Labels: manifestutils.SelectorLabelsForAllOperatorManaged(metav1.ObjectMeta{Name: "cloudwatch-agent", Namespace: "other"}),
+			UID:    "other-cluster-role-uid",
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent, clusterRole, clusterRoleBinding, otherNamespaceBinding, otherClusterRole).Build()
+	r := NewReconciler(Params{
+		Client:   c,
+		Log:      logf.Log.WithName("unit-tests"),
+		Scheme:   scheme,
+		Config:   config.New(),
+		Recorder: &record.FakeRecorder{},
+	})
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(agent)})
+	require.NoError(t, err)
+
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(clusterRole), &rbacv1.ClusterRole{})))
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(clusterRoleBinding), &rbacv1.ClusterRoleBinding{})))
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(otherNamespaceBinding), &rbacv1.RoleBinding{})))
+	assert.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(otherClusterRole), &rbacv1.ClusterRole{}))
+	// the finalizer is removed for the instance to be deleted
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, client.ObjectKeyFromObject(agent), &v1alpha1.AmazonCloudWatchAgent{})))
+}
+
+func TestNamespaceSelectingAgents(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	newAgent := func(name string, namespaceSelector *metav1.LabelSelector) *v1alpha1.AmazonCloudWatchAgent {
+		agent := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "amazon-cloudwatch"}}
+		agent.Spec.TargetAllocator.Enabled = true
+		agent.Spec.TargetAllocator.PrometheusCR.Enabled = true
+		agent.Spec.TargetAllocator.PrometheusCR.ServiceMonitorNamespaceSelector = namespaceSelector
+		return agent
+	}
+	selecting := newAgent("selecting", &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}})
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(selecting, newAgent("all-namespaces", nil)).Build()
+	r := NewReconciler(Params{
+		Client:   c,
+		Log:      logf.Log.WithName("unit-tests"),
+		Scheme:   scheme,
+		Config:   config.New(),
+		Recorder: &record.FakeRecorder{},
+	})
+
+	requests := r.namespaceSelectingAgents(context.Background(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}})
+	assert.Equal(t, []reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(selecting)}}, requests)
+}
+
+func TestIsOwnable(t *testing.T) {
+	owner := &v1alpha1.AmazonCloudWatchAgent{ObjectMeta: metav1.ObjectMeta{Name: "cloudwatch-agent", Namespace: "amazon-cloudwatch"}}
+	assert.True(t, isOwnable(owner, &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "amazon-cloudwatch"}}))
+	assert.False(t, isOwnable(owner, &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Namespace: "team-a"}}))
+	assert.False(t, isOwnable(owner, &rbacv1.ClusterRoleBinding{}))
+}
@@ -0,0 +1,190 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/auto"
+)
+
+// autoMonitorStatusRefreshInterval is how often the workload counts of the AutoMonitor status are refreshed.
+const autoMonitorStatusRefreshInterval = time.Minute
+
+// AutoMonitorConfigurer is the auto-monitor configured by the AutoMonitor resource.
+type AutoMonitorConfigurer interface {
+	InitialConfig() auto.MonitorConfig
+	SetConfig(config auto.MonitorConfig) (bool, error)
+	SelectedWorkloads() map[instrumentation.Type]int32
+	MutateAndPatchAll(ctx context.Context)
+}
+
+// AutoMonitorReconciler applies the AutoMonitor resource to the auto-monitor without restarting the operator. The
+// AutoMonitor only replaces the --auto-monitor-config flag: the deprecated --auto-annotation-config flag disables the
+// auto-monitor, and the AutoMonitor is then reported as not applied.
+type AutoMonitorReconciler struct {
+	client.Client
+	scheme  *runtime.Scheme
+	log     logr.Logger
+	monitor AutoMonitorConfigurer
+}
+
+// NewAutoMonitorReconciler creates a new reconciler for AutoMonitor objects. The monitor is nil when the auto-monitor
+// is disabled by the auto-annotation.
+func NewAutoMonitorReconciler(p Params, monitor AutoMonitorConfigurer) *AutoMonitorReconciler {
+	return &AutoMonitorReconciler{
+		Client:  p.Client,
+		log:     p.Log,
+		scheme:  p.Scheme,
+		monitor: monitor,
+	}
+}
+
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=automonitors,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=automonitors/status,verbs=get;update;patch
+
+// Reconcile applies the AutoMonitor to the auto-monitor, or restores the config of the --auto-monitor-config flag when
+// it is deleted, and reports the workloads it selects on its status.
+func (r *AutoMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("automonitor", req.Name)
+
+	var instance v1alpha1.AutoMonitor
+	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch AutoMonitor")
+			return ctrl.Result{}, err
+		}
+		if req.Name == v1alpha1.AutoMonitorName && r.monitor != nil {
+			return ctrl.Result{}, r.applyConfig(ctx, log, r.monitor.InitialConfig())
+		}
+		return ctrl.Result{}, nil
+	}
+	if deletionTimestamp := instance.GetDeletionTimestamp(); deletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	changed := instance.DeepCopy()
+	if instance.Name != v1alpha1.AutoMonitorName {
+		meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.AutoMonitorConditionApplied,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Ignored",
+			Message:            fmt.Sprintf("only the AutoMonitor named %s is applied", v1alpha1.AutoMonitorName),
+			ObservedGeneration: instance.Generation,
+		})
+		return ctrl.Result{}, r.patchStatus(ctx, &instance, changed)
+	}
+
+	if r.monitor == nil {
+		meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.AutoMonitorConditionApplied,
+			Status:             metav1.ConditionFalse,
+			Reason:             "AutoAnnotationConfigured",
+			Message:            "the deprecated --auto-annotation-config flag disables the auto-monitor, remove it to apply the AutoMonitor",
+			ObservedGeneration: instance.Generation,
+		})
+		return ctrl.Result{}, r.patchStatus(ctx, &instance, changed)
+	}
+
+	config := auto.MonitorConfigFromSpec(instance.Spec)
+	if err := config.Validate(); err != nil {
+		meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.AutoMonitorConditionApplied,
+			Status:             metav1.ConditionFalse,
+			Reason:             "InvalidConfig",
+			Message:            err.Error(),
+			ObservedGeneration: instance.Generation,
+		})
+		return ctrl.Result{}, r.patchStatus(ctx, &instance, changed)
+	}
+	if err := r.applyConfig(ctx, log, config); err != nil {
+		meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+			Type:               v1alpha1.AutoMonitorConditionApplied,
+			Status:             metav1.ConditionFalse,
+			Reason:             "ApplyFailed",
+			Message:            err.Error(),
+			ObservedGeneration: instance.Generation,
+		})
+		if patchErr := r.patchStatus(ctx, &instance, changed); patchErr != nil {
+			log.Error(patchErr, "failed to report the AutoMonitor apply failure")
+		}
+		return ctrl.Result{}, err
+	}
+
+	changed.Status.ObservedGeneration = instance.Generation
+	changed.Status.Languages = languageStatuses(r.monitor.SelectedWorkloads())
+	meta.SetStatusCondition(&changed.Status.Conditions, metav1.Condition{
+		Type:               v1alpha1.AutoMonitorConditionApplied,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Applied",
+		Message:            "the AutoMonitor is used by the auto-monitor",
+		ObservedGeneration: instance.Generation,
+	})
+	if err := r.patchStatus(ctx, &instance, changed); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: autoMonitorStatusRefreshInterval}, nil
+}
+
+// applyConfig sets the config of the auto-monitor, and applies it to the existing workloads and namespaces if it
+// changed.
+func (r *AutoMonitorReconciler) applyConfig(ctx context.Context, log logr.Logger, config auto.MonitorConfig) error {
+	changed, err := r.monitor.SetConfig(config)
+	if err != nil || !changed {
+		return err
+	}
+	log.Info("auto-monitor config changed, applying it")
+	r.monitor.MutateAndPatchAll(ctx)
+	return nil
+}
+
+func (r *AutoMonitorReconciler) patchStatus(ctx context.Context, instance, changed *v1alpha1.AutoMonitor) error {
+	if reflect.DeepEqual(instance.Status, changed.Status) {
+		return nil
+	}
+	now := metav1.Now()
+	changed.Status.LastUpdateTime = &now
+	if err := r.Status().Patch(ctx, changed, client.MergeFrom(instance)); err != nil {
+		return fmt.Errorf("failed to apply status changes to the AutoMonitor CR: %w", err)
+	}
+	return nil
+}
+
+// languageStatuses converts the number of selected workloads per language to the AutoMonitor status.
+func languageStatuses(selected map[instrumentation.Type]int32) []v1alpha1.AutoMonitorLanguageStatus {
+	statuses := make([]v1alpha1.AutoMonitorLanguageStatus, 0, len(instrumentation.SupportedTypes))
+	for t := range instrumentation.SupportedTypes {
+		statuses = append(statuses, v1alpha1.AutoMonitorLanguageStatus{
+			Language:  v1alpha1.AutoMonitorLanguage(t),
+			Workloads: selected[t],
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Language < statuses[j].Language
+	})
+	return statuses
+}
+
+// SetupWithManager tells the manager what our controller is interested in.
+func (r *AutoMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.AutoMonitor{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,180 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/auto"
+)
+
+type fakeAutoMonitor struct {
+	initial  auto.MonitorConfig
+	config   auto.MonitorConfig
+	selected map[instrumentation.Type]int32
+	applied  int
+	setErr   error
+}
+
+func (m *fakeAutoMonitor) InitialConfig() auto.MonitorConfig {
+	return m.initial
+}
+
+func (m *fakeAutoMonitor) SetConfig(config auto.MonitorConfig) (bool, error) {
+	if m.setErr != nil {
+		return false, m.setErr
+	}
+	changed := !assert.ObjectsAreEqual(m.config, config)
+	m.config = config
+	return changed, nil
+}
+
+func (m *fakeAutoMonitor) SelectedWorkloads() map[instrumentation.Type]int32 {
+	return m.selected
+}
+
+func (m *fakeAutoMonitor) MutateAndPatchAll(context.Context) {
+	m.applied++
+}
+
+func TestAutoMonitorReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	autoMonitor := &v1alpha1.AutoMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.AutoMonitorName, Generation: 2},
+		Spec: v1alpha1.AutoMonitorSpec{
+			MonitorAllServices: true,
+			Languages:          []v1alpha1.AutoMonitorLanguage{"java"},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(autoMonitor).WithStatusSubresource(autoMonitor).Build()
+	monitor := &fakeAutoMonitor{
+		initial:  auto.MonitorConfig{RestartPods: true},
+		selected: map[instrumentation.Type]int32{instrumentation.TypeJava: 3},
+	}
+	reconciler := NewAutoMonitorReconciler(Params{Client: cl, Scheme: scheme, Log: logf.Log.WithName("unit-tests")}, monitor)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: v1alpha1.AutoMonitorName}}
+
+	// the AutoMonitor is applied
+	res, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, autoMonitorStatusRefreshInterval, res.RequeueAfter)
+	assert.Equal(t, auto.MonitorConfig{MonitorAllServices: true, Languages: instrumentation.NewTypeSet(instrumentation.TypeJava)}, monitor.config)
+	assert.Equal(t, 1, monitor.applied)
+
+	var actual v1alpha1.AutoMonitor
+	require.NoError(t, cl.Get(ctx, req.NamespacedName, &actual))
+	assert.EqualValues(t, 2, actual.Status.ObservedGeneration)
+	assert.Contains(t, actual.Status.Languages, v1alpha1.AutoMonitorLanguageStatus{Language: "java", Workloads: 3})
+	assert.True(t, meta.IsStatusConditionTrue(actual.Status.Conditions, v1alpha1.AutoMonitorConditionApplied))
+	assert.NotNil(t, actual.Status.LastUpdateTime)
+
+	// nothing changed, so the config isn't applied again
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, monitor.applied)
+
+	// the initial config is restored once the AutoMonitor is deleted
+	require.NoError(t, cl.Delete(ctx, &actual))
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, monitor.initial, monitor.config)
+	assert.Equal(t, 2, monitor.applied)
+}
+
+func TestAutoMonitorReconcileRejected(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	invalid := &v1alpha1.AutoMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.AutoMonitorName},
+		Spec: v1alpha1.AutoMonitorSpec{
+			Exclude: v1alpha1.AutoMonitorSelector{
+				Java: v1alpha1.AutoMonitorResources{Namespaces: []string{"team-[a"}},
+			},
+		},
+	}
+	other := &v1alpha1.AutoMonitor{ObjectMeta: metav1.ObjectMeta{Name: "other"}}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(invalid, other).WithStatusSubresource(invalid, other).Build()
+	monitor := &fakeAutoMonitor{}
+	reconciler := NewAutoMonitorReconciler(Params{Client: cl, Scheme: scheme, Log: logf.Log.WithName("unit-tests")}, monitor)
+
+	for _, name := range []string{invalid.Name, other.Name} {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name}})
+		require.NoError(t, err)
+
+		var actual v1alpha1.AutoMonitor
+		require.NoError(t, cl.Get(ctx, types.NamespacedName{Name: name}, &actual))
+		assert.True(t, meta.IsStatusConditionFalse(actual.Status.Conditions, v1alpha1.AutoMonitorConditionApplied))
+	}
+	assert.Equal(t, 0, monitor.applied)
+}
+
+func TestAutoMonitorReconcileApplyFailed(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	autoMonitor := &v1alpha1.AutoMonitor{ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.AutoMonitorName}}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(autoMonitor).WithStatusSubresource(autoMonitor).Build()
+	monitor := &fakeAutoMonitor{setErr: errors.New("namespace informer failed to sync")}
+	reconciler := NewAutoMonitorReconciler(Params{Client: cl, Scheme: scheme, Log: logf.Log.WithName("unit-tests")}, monitor)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: v1alpha1.AutoMonitorName}}
+
+	// the error is returned so the AutoMonitor is reconciled again
+	_, err := reconciler.Reconcile(ctx, req)
+	require.ErrorIs(t, err, monitor.setErr)
+	assert.Equal(t, 0, monitor.applied)
+
+	var actual v1alpha1.AutoMonitor
+	require.NoError(t, cl.Get(ctx, req.NamespacedName, &actual))
+	condition := meta.FindStatusCondition(actual.Status.Conditions, v1alpha1.AutoMonitorConditionApplied)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "ApplyFailed", condition.Reason)
+}
+
+func TestAutoMonitorReconcileWithAutoAnnotation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	autoMonitor := &v1alpha1.AutoMonitor{ObjectMeta: metav1.ObjectMeta{Name: v1alpha1.AutoMonitorName}}
+	cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(autoMonitor).WithStatusSubresource(autoMonitor).Build()
+	// the auto-monitor is disabled by the auto-annotation
+	reconciler := NewAutoMonitorReconciler(Params{Client: cl, Scheme: scheme, Log: logf.Log.WithName("unit-tests")}, nil)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: v1alpha1.AutoMonitorName}}
+
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	var actual v1alpha1.AutoMonitor
+	require.NoError(t, cl.Get(ctx, req.NamespacedName, &actual))
+	condition := meta.FindStatusCondition(actual.Status.Conditions, v1alpha1.AutoMonitorConditionApplied)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "AutoAnnotationConfigured", condition.Reason)
+
+	// deleting the AutoMonitor has nothing to restore
+	require.NoError(t, cl.Delete(ctx, &actual))
+	_, err = reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+}
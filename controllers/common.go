@@ -26,11 +26,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/targetallocator"
 )
 
-const (
-	acceleratedComputeMetrics = "accelerated_compute_metrics"
-	amazonCloudWatchNamespace = "amazon-cloudwatch"
-	amazonCloudWatchAgentName = "cloudwatch-agent"
-)
+const acceleratedComputeMetrics = "accelerated_compute_metrics"
 
 func isNamespaceScoped(obj client.Object) bool {
 	switch obj.(type) {
@@ -41,6 +37,12 @@ func isNamespaceScoped(obj client.Object) bool {
 	}
 }
 
+// isOwnable returns whether the object can be owned by the owner, to be garbage collected with it. Owner references
+// are only allowed in the namespace of the owner.
+func isOwnable(owner metav1.Object, obj client.Object) bool {
+	return isNamespaceScoped(obj) && obj.GetNamespace() == owner.GetNamespace()
+}
+
 // BuildCollector returns the generation and collected errors of all manifests for a given instance.
 func BuildCollector(params manifests.Params) ([]client.Object, error) {
 	builders := []manifests.Builder{
@@ -68,7 +70,7 @@ func reconcileDesiredObjectUIDs(ctx context.Context, kubeClient client.Client, l
 			"object_name", desired.GetName(),
 			"object_kind", desired.GetObjectKind(),
 		)
-		if isNamespaceScoped(desired) {
+		if isOwnable(owner, desired) {
 			if setErr := ctrl.SetControllerReference(owner, desired, scheme); setErr != nil {
 				l.Error(setErr, "failed to set controller owner reference to desired")
 				errs = append(errs, setErr)
@@ -112,16 +114,16 @@ func reconcileDesiredObjectUIDs(ctx context.Context, kubeClient client.Client, l
 	return existingObjectMap, nil
 }
 
-func reconcileDesiredObjectsWPrune(ctx context.Context, kubeClient client.Client, logger logr.Logger, owner v1alpha1.AmazonCloudWatchAgent, scheme *runtime.Scheme,
+func reconcileDesiredObjectsWPrune[T client.Object](ctx context.Context, kubeClient client.Client, logger logr.Logger, owner T, scheme *runtime.Scheme,
 	desiredObjects []client.Object,
-	searchOwnedObjectsFunc func(ctx context.Context, owner v1alpha1.AmazonCloudWatchAgent) (map[types.UID]client.Object, error),
+	searchOwnedObjectsFunc func(ctx context.Context, owner T) (map[types.UID]client.Object, error),
 ) error {
 	previouslyOwnedObjects, err := searchOwnedObjectsFunc(ctx, owner)
 	if err != nil {
 		return fmt.Errorf("failed to search owned objects: %w", err)
 	}
 
-	desiredObjectMap, err := reconcileDesiredObjectUIDs(ctx, kubeClient, logger, &owner, scheme, desiredObjects...)
+	desiredObjectMap, err := reconcileDesiredObjectUIDs(ctx, kubeClient, logger, owner, scheme, desiredObjects...)
 	if err != nil {
 		return fmt.Errorf("failed to reconcile desired objects: %w", err)
 	}
@@ -162,13 +164,27 @@ func pruneStaleObjects(ctx context.Context, kubeClient client.Client, logger log
 	return errors.Join(pruneErrs...)
 }
 
-func enabledAcceleratedComputeByAgentConfig(ctx context.Context, c client.Client, log logr.Logger) bool {
-	agentResource := getAmazonCloudWatchAgentResource(ctx, c)
+// enabledAcceleratedComputeByAgentConfig tells whether any of the AmazonCloudWatchAgent resources of the cluster
+// enables the accelerated compute metrics.
+func enabledAcceleratedComputeByAgentConfig(ctx context.Context, c client.Client, log logr.Logger) (bool, error) {
+	agentResources, err := listAmazonCloudWatchAgentResources(ctx, c)
+	if err != nil {
+		return false, fmt.Errorf("failed to list the AmazonCloudWatchAgent resources: %w", err)
+	}
+	for _, agentResource := range agentResources {
+		if enabledAcceleratedCompute(agentResource, log) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func enabledAcceleratedCompute(agentResource v1alpha1.AmazonCloudWatchAgent, log logr.Logger) bool {
 	// missing feature flag means it's on by default
 	featureConfigExists := strings.Contains(agentResource.Spec.Config, acceleratedComputeMetrics)
 	conf, err := adapters.ConfigStructFromJSONString(agentResource.Spec.Config)
 	if err != nil {
-		log.Error(err, "Failed to unmarshall agent configuration")
+		log.Error(err, "Failed to unmarshall agent configuration", "agent", client.ObjectKeyFromObject(&agentResource))
 		return false
 	}
 
@@ -183,13 +199,10 @@ func enabledAcceleratedComputeByAgentConfig(ctx context.Context, c client.Client
 	return false
 }
 
-var getAmazonCloudWatchAgentResource = func(ctx context.Context, c client.Client) v1alpha1.AmazonCloudWatchAgent {
-	cr := &v1alpha1.AmazonCloudWatchAgent{}
-
-	_ = c.Get(ctx, client.ObjectKey{
-		Namespace: amazonCloudWatchNamespace,
-		Name:      amazonCloudWatchAgentName,
-	}, cr)
-
-	return *cr
+var listAmazonCloudWatchAgentResources = func(ctx context.Context, c client.Client) ([]v1alpha1.AmazonCloudWatchAgent, error) {
+	var agents v1alpha1.AmazonCloudWatchAgentList
+	if err := c.List(ctx, &agents); err != nil {
+		return nil, err
+	}
+	return agents.Items, nil
 }
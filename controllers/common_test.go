@@ -5,6 +5,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -61,15 +62,46 @@ func TestEnabledAcceleratedComputeByAgentConfig(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		getAmazonCloudWatchAgentResource = func(ctx context.Context, c client.Client) v1alpha1.AmazonCloudWatchAgent {
-			return v1alpha1.AmazonCloudWatchAgent{
+		listAmazonCloudWatchAgentResources = func(ctx context.Context, c client.Client) ([]v1alpha1.AmazonCloudWatchAgent, error) {
+			return []v1alpha1.AmazonCloudWatchAgent{{
 				ObjectMeta: metav1.ObjectMeta{},
 				Spec: v1alpha1.AmazonCloudWatchAgentSpec{
 					Config: tc.config,
 				},
-			}
+			}}, nil
 		}
-		actual := enabledAcceleratedComputeByAgentConfig(ctx, nil, logger)
+		actual, err := enabledAcceleratedComputeByAgentConfig(ctx, nil, logger)
+		assert.NoError(t, err)
 		assert.Equal(t, tc.expected, actual)
 	}
 }
+
+func TestEnabledAcceleratedComputeByAnyAgentConfig(t *testing.T) {
+	ctx := context.Background()
+	logger := logf.Log.WithName("unit-tests")
+	original := listAmazonCloudWatchAgentResources
+	defer func() { listAmazonCloudWatchAgentResources = original }()
+
+	listAmazonCloudWatchAgentResources = func(ctx context.Context, c client.Client) ([]v1alpha1.AmazonCloudWatchAgent, error) {
+		return []v1alpha1.AmazonCloudWatchAgent{
+			{Spec: v1alpha1.AmazonCloudWatchAgentSpec{Config: `{"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":false}}}}`}},
+			{Spec: v1alpha1.AmazonCloudWatchAgentSpec{Config: `{"logs":{"metrics_collected":{"kubernetes":{"enhanced_container_insights":true}}}}`}},
+		}, nil
+	}
+	actual, err := enabledAcceleratedComputeByAgentConfig(ctx, nil, logger)
+	assert.NoError(t, err)
+	assert.True(t, actual)
+
+	listAmazonCloudWatchAgentResources = func(ctx context.Context, c client.Client) ([]v1alpha1.AmazonCloudWatchAgent, error) {
+		return nil, errors.New("list failed")
+	}
+	_, err = enabledAcceleratedComputeByAgentConfig(ctx, nil, logger)
+	assert.Error(t, err)
+
+	listAmazonCloudWatchAgentResources = func(ctx context.Context, c client.Client) ([]v1alpha1.AmazonCloudWatchAgent, error) {
+		return nil, nil
+	}
+	actual, err = enabledAcceleratedComputeByAgentConfig(ctx, nil, logger)
+	assert.NoError(t, err)
+	assert.False(t, actual)
+}
@@ -0,0 +1,160 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	instrumentationStatus "github.com/aws/amazon-cloudwatch-agent-operator/internal/status/instrumentation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
+
+// instrumentationRefField indexes pods by the namespace/name of the Instrumentations they requested.
+const instrumentationRefField = ".metadata.annotations.instrumentationRef"
+
+// InstrumentationReconciler reports the injection coverage of workloads on Instrumentation objects.
+type InstrumentationReconciler struct {
+	client.Client
+	scheme *runtime.Scheme
+	log    logr.Logger
+}
+
+// NewInstrumentationReconciler creates a new reconciler for Instrumentation objects.
+func NewInstrumentationReconciler(p Params) *InstrumentationReconciler {
+	return &InstrumentationReconciler{
+		Client: p.Client,
+		log:    p.Log,
+		scheme: p.Scheme,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=instrumentations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cloudwatch.aws.amazon.com,resources=instrumentations/status,verbs=get;update;patch
+
+// Reconcile refreshes the status of an Instrumentation from the injection outcomes recorded on the pods requesting it.
+func (r *InstrumentationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("instrumentation", req.NamespacedName)
+
+	var instance v1alpha1.Instrumentation
+	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch Instrumentation")
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if deletionTimestamp := instance.GetDeletionTimestamp(); deletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, client.MatchingFields{instrumentationRefField: req.NamespacedName.String()}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list pods requesting the Instrumentation: %w", err)
+	}
+
+	// pods annotated with "true" use the Instrumentation only if it is the single one in their namespace
+	var instances v1alpha1.InstrumentationList
+	if err := r.List(ctx, &instances, client.InNamespace(instance.Namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list Instrumentations: %w", err)
+	}
+	includeUnresolved := len(instances.Items) == 1
+	if includeUnresolved {
+		var unresolvedPods corev1.PodList
+		unresolvedRef := types.NamespacedName{Namespace: instance.Namespace, Name: instrumentation.UnresolvedInstrumentationName}
+		if err := r.List(ctx, &unresolvedPods, client.MatchingFields{instrumentationRefField: unresolvedRef.String()}); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to list pods requesting the Instrumentation: %w", err)
+		}
+		pods.Items = append(pods.Items, unresolvedPods.Items...)
+	}
+
+	changed := instance.DeepCopy()
+	if !instrumentationStatus.UpdateInstrumentationStatus(changed, pods.Items, includeUnresolved) {
+		return ctrl.Result{}, nil
+	}
+	if err := r.Status().Patch(ctx, changed, client.MergeFrom(&instance)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to apply status changes to the Instrumentation CR: %w", err)
+	}
+	log.V(2).Info("updated instrumentation status", "injected", changed.Status.Injected, "skipped", changed.Status.Skipped, "failed", changed.Status.Failed)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager tells the manager what our controller is interested in.
+func (r *InstrumentationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, instrumentationRefField, indexInstrumentationRefs); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.Instrumentation{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(
+			&corev1.Pod{},
+			handler.EnqueueRequestsFromMapFunc(r.podToInstrumentations),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				pod, ok := obj.(*corev1.Pod)
+				return ok && instrumentation.RequestsInstrumentation(*pod)
+			})),
+		).
+		Complete(r)
+}
+
+// podToInstrumentations maps a pod to the Instrumentations it requested.
+func (r *InstrumentationReconciler) podToInstrumentations(ctx context.Context, obj client.Object) []reconcile.Request {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, ref := range indexInstrumentationRefs(pod) {
+		namespace, name, _ := strings.Cut(ref, string(types.Separator))
+		if name != instrumentation.UnresolvedInstrumentationName {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}})
+			continue
+		}
+		var instances v1alpha1.InstrumentationList
+		if err := r.List(ctx, &instances, client.InNamespace(namespace)); err != nil {
+			r.log.Error(err, "unable to list Instrumentations", "namespace", namespace)
+			continue
+		}
+		for _, inst := range instances.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: inst.Namespace, Name: inst.Name}})
+		}
+	}
+	return requests
+}
+
+// indexInstrumentationRefs returns the namespace/name of the Instrumentations requested by a pod.
+func indexInstrumentationRefs(obj client.Object) []string {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+	seen := map[string]struct{}{}
+	var refs []string
+	for _, outcome := range instrumentation.InjectionOutcomes(*pod) {
+		if outcome.Instrumentation == "" {
+			continue
+		}
+		if _, ok := seen[outcome.Instrumentation]; ok {
+			continue
+		}
+		seen[outcome.Instrumentation] = struct{}{}
+		refs = append(refs, outcome.Instrumentation)
+	}
+	return refs
+}
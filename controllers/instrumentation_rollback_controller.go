@@ -0,0 +1,115 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/auto"
+)
+
+const (
+	// defaultRollbackWindow is how long after the auto-instrumentation was injected the failures of instrumented pods
+	// are blamed on it.
+	defaultRollbackWindow = 10 * time.Minute
+
+	eventReasonInstrumentationRolledBack = "InstrumentationRolledBack"
+)
+
+// InstrumentationRollbackReconciler rolls back the auto-instrumentation of the workloads whose instrumented pods fail
+// shortly after it was injected, and quarantines them.
+type InstrumentationRollbackReconciler struct {
+	client.Client
+	scheme   *runtime.Scheme
+	log      logr.Logger
+	recorder record.EventRecorder
+	window   time.Duration
+	now      func() time.Time
+}
+
+// NewInstrumentationRollbackReconciler creates a new reconciler for the pods instrumented by the operator.
+func NewInstrumentationRollbackReconciler(p Params) *InstrumentationRollbackReconciler {
+	return &InstrumentationRollbackReconciler{
+		Client:   p.Client,
+		log:      p.Log,
+		scheme:   p.Scheme,
+		recorder: p.Recorder,
+		window:   defaultRollbackWindow,
+		now:      time.Now,
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=daemonsets;deployments;statefulsets,verbs=get;list;watch;patch
+
+// Reconcile quarantines the workload owning the pod if the auto-instrumentation fails the pod within the rollback
+// window.
+func (r *InstrumentationRollbackReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("pod", req.NamespacedName)
+
+	var pod corev1.Pod
+	if err := r.Get(ctx, req.NamespacedName, &pod); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to fetch Pod")
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if pod.GetDeletionTimestamp() != nil || r.now().Sub(instrumentation.InjectedAt(pod)) > r.window {
+		return ctrl.Result{}, nil
+	}
+	reason, failed := instrumentation.InstrumentationFailure(pod)
+	if !failed {
+		return ctrl.Result{}, nil
+	}
+
+	workload, err := instrumentation.OwningWorkload(ctx, r.Client, pod)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if workload == nil || auto.IsQuarantined(workload) {
+		return ctrl.Result{}, nil
+	}
+
+	patch := client.MergeFrom(workload.DeepCopyObject().(client.Object))
+	removed := auto.Quarantine(workload, fmt.Sprintf("%s: %s", pod.Name, reason))
+	if err = r.Patch(ctx, workload, patch); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to quarantine the workload %s: %w", client.ObjectKeyFromObject(workload), err)
+	}
+
+	message := fmt.Sprintf("auto-instrumentation rolled back and workload quarantined, pod %s: %s. Remove the %s annotation from the workload to instrument it again", pod.Name, reason, auto.AnnotationQuarantined)
+	if len(removed) == 0 {
+		message = fmt.Sprintf("workload quarantined, pod %s: %s. The instrumentation wasn't added by the operator and must be removed manually", pod.Name, reason)
+	}
+	log.Info("quarantined the workload of a pod failed by its auto-instrumentation", "workload", client.ObjectKeyFromObject(workload), "reason", reason)
+	if r.recorder != nil {
+		r.recorder.Event(workload, corev1.EventTypeWarning, eventReasonInstrumentationRolledBack, message)
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager tells the manager what our controller is interested in.
+func (r *InstrumentationRollbackReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("instrumentation-rollback").
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			pod, ok := obj.(*corev1.Pod)
+			return ok && instrumentation.RequestsInstrumentation(*pod)
+		}))).
+		Complete(r)
+}
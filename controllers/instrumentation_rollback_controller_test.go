@@ -0,0 +1,116 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation/auto"
+)
+
+func TestInstrumentationRollbackReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	ctx := context.Background()
+	now := time.Now()
+
+	autoAnnotations := map[string]string{
+		"instrumentation.opentelemetry.io/inject-java": "true",
+		"cloudwatch.aws.amazon.com/auto-annotate-java": "true",
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default", UID: "deployment-uid"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Annotations: autoAnnotations}},
+		},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "shop-5d4f",
+			Namespace:       "default",
+			UID:             "replicaset-uid",
+			OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "shop", UID: "deployment-uid", Controller: ptr.To(true)}},
+		},
+	}
+	newPod := func(name string, created time.Time, state corev1.ContainerState) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(created),
+				Annotations:       autoAnnotations,
+				OwnerReferences:   []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "shop-5d4f", UID: "replicaset-uid", Controller: ptr.To(true)}},
+			},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "opentelemetry-auto-instrumentation-java"}},
+				Containers:     []corev1.Container{{Name: "app", Env: []corev1.EnvVar{{Name: constants.EnvNodeName}}}},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "app", State: state}},
+			},
+		}
+	}
+	crashLoopBackOff := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	// the pod is created long after the auto-monitor instrumented its workload, e.g. on a scale up
+	instrumentedLongAgo := newPod("scaled-up", now.Add(-time.Minute), crashLoopBackOff)
+	instrumentedLongAgo.Annotations = map[string]string{instrumentation.AnnotationInstrumentedAt: now.Add(-time.Hour).Format(time.RFC3339)}
+	// the crash-looping container wasn't injected into
+	notInjected := newPod("not-injected", now.Add(-time.Minute), running)
+	notInjected.Spec.Containers = append(notInjected.Spec.Containers, corev1.Container{Name: "proxy"})
+	notInjected.Status.ContainerStatuses = append(notInjected.Status.ContainerStatuses, corev1.ContainerStatus{Name: "proxy", State: crashLoopBackOff})
+
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		quarantined bool
+	}{
+		{name: "healthy", pod: newPod("healthy", now, running)},
+		{name: "crash-looping long after the injection", pod: newPod("old", now.Add(-time.Hour), crashLoopBackOff)},
+		{name: "crash-looping long after the workload was instrumented", pod: instrumentedLongAgo},
+		{name: "container not injected crash-looping", pod: notInjected},
+		{name: "crash-looping", pod: newPod("crashing", now.Add(-time.Minute), crashLoopBackOff), quarantined: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deployment.DeepCopy(), replicaSet, tt.pod).Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := NewInstrumentationRollbackReconciler(Params{Client: cl, Scheme: scheme, Log: logf.Log.WithName("unit-tests"), Recorder: recorder})
+
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tt.pod)})
+			require.NoError(t, err)
+
+			var actual appsv1.Deployment
+			require.NoError(t, cl.Get(ctx, types.NamespacedName{Namespace: "default", Name: "shop"}, &actual))
+			assert.Equal(t, tt.quarantined, auto.IsQuarantined(&actual))
+			if tt.quarantined {
+				// the quarantine marker is only set on the workload
+				assert.Empty(t, actual.Spec.Template.Annotations)
+				assert.Len(t, recorder.Events, 1)
+			} else {
+				assert.Equal(t, autoAnnotations, actual.Spec.Template.Annotations)
+				assert.Empty(t, recorder.Events)
+			}
+		})
+	}
+}
@@ -31,6 +31,8 @@ type Config struct {
 	autoInstrumentationNginxImage       string
 	autoInstrumentationNodeJSImage      string
 	autoInstrumentationJavaImage        string
+	autoInstrumentationRubyImage        string
+	autoInstrumentationPHPImage         string
 	dcgmExporterImage                   string
 	neuronMonitorImage                  string
 	targetAllocatorImage                string
@@ -66,6 +68,8 @@ func New(opts ...Option) Config {
 		autoInstrumentationGoImage:          o.autoInstrumentationGoImage,
 		autoInstrumentationApacheHttpdImage: o.autoInstrumentationApacheHttpdImage,
 		autoInstrumentationNginxImage:       o.autoInstrumentationNginxImage,
+		autoInstrumentationRubyImage:        o.autoInstrumentationRubyImage,
+		autoInstrumentationPHPImage:         o.autoInstrumentationPHPImage,
 		dcgmExporterImage:                   o.dcgmExporterImage,
 		neuronMonitorImage:                  o.neuronMonitorImage,
 		targetAllocatorImage:                o.targetAllocatorImage,
@@ -125,6 +129,16 @@ func (c *Config) AutoInstrumentationNginxImage() string {
 	return c.autoInstrumentationNginxImage
 }
 
+// AutoInstrumentationRubyImage returns OpenTelemetry Ruby auto-instrumentation container image.
+func (c *Config) AutoInstrumentationRubyImage() string {
+	return c.autoInstrumentationRubyImage
+}
+
+// AutoInstrumentationPHPImage returns OpenTelemetry PHP auto-instrumentation container image.
+func (c *Config) AutoInstrumentationPHPImage() string {
+	return c.autoInstrumentationPHPImage
+}
+
 // DcgmExporterImage returns Nvidia DCGM Exporter container image.
 func (c *Config) DcgmExporterImage() string {
 	return c.dcgmExporterImage
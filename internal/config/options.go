@@ -25,6 +25,8 @@ type options struct {
 	autoInstrumentationPythonImage      string
 	autoInstrumentationApacheHttpdImage string
 	autoInstrumentationNginxImage       string
+	autoInstrumentationRubyImage        string
+	autoInstrumentationPHPImage         string
 	collectorImage                      string
 	collectorConfigMapEntry             string
 	otelCollectorConfigMapEntry         string
@@ -114,6 +116,18 @@ func WithAutoInstrumentationNginxImage(s string) Option {
 	}
 }
 
+func WithAutoInstrumentationRubyImage(s string) Option {
+	return func(o *options) {
+		o.autoInstrumentationRubyImage = s
+	}
+}
+
+func WithAutoInstrumentationPHPImage(s string) Option {
+	return func(o *options) {
+		o.autoInstrumentationPHPImage = s
+	}
+}
+
 func WithDcgmExporterImage(s string) Option {
 	return func(o *options) {
 		o.dcgmExporterImage = s
@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package acceleratorexporter builds the manifests of the exporters of accelerated device telemetry, which only differ
+// by their container, configuration files and port.
+package acceleratorexporter
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+// Kind describes what is specific to a kind of accelerator exporter.
+type Kind struct {
+	// Name is the kind of the exporter resource.
+	Name string
+	// Component names the exporter container, and labels its objects.
+	Component string
+	// ServiceAppLabel is the k8s-app label of the service, used by the scrape config of the agent.
+	ServiceAppLabel string
+	// DefaultServiceAccountName is the name of the service account of the pods when the resource does not set one.
+	DefaultServiceAccountName string
+	// ConfigMapName is the name of the config map holding the configuration files.
+	ConfigMapName string
+	// ConfigVolumeName is the name of the volume of the config map.
+	ConfigVolumeName string
+	// ConfigMountPath is the directory the configuration files are mounted in.
+	ConfigMountPath string
+	// ConfigFile is the configuration file whose hash is annotated on the objects, to roll out the pods on change.
+	ConfigFile string
+	// Port is the default port the exporter serves its metrics on.
+	Port int32
+	// Image returns the default image of the exporter.
+	Image func(cfg config.Config) string
+	// Version returns the version of the exporter reported on the status.
+	Version func() string
+	// Args returns the arguments of the exporter container.
+	Args func(exporter Exporter) []string
+	// Env returns the environment variables the exporter container sets in addition to the ones of the resource,
+	// nil if none.
+	Env func(exporter Exporter) []corev1.EnvVar
+}
+
+// Exporter is the accelerator exporter resource the manifests are built for.
+type Exporter struct {
+	metav1.ObjectMeta
+	Spec Spec
+}
+
+// Spec holds the settings of the accelerator exporter resource.
+type Spec struct {
+	Resources       corev1.ResourceRequirements
+	NodeSelector    map[string]string
+	SecurityContext *corev1.SecurityContext
+	Command         []string
+	Args            map[string]string
+	ServiceAccount  string
+	Image           string
+	Ports           []corev1.ServicePort
+	Env             []corev1.EnvVar
+	Tolerations     []corev1.Toleration
+	Volumes         []corev1.Volume
+	VolumeMounts    []corev1.VolumeMount
+	Affinity        *corev1.Affinity
+	// Config holds the content of the configuration files of the exporter, by file name.
+	Config map[string]string
+}
+
+// Build creates the manifests of the exporter resource.
+func (k Kind) Build(cfg config.Config, exporter Exporter) []client.Object {
+	return []client.Object{
+		k.DaemonSet(cfg, exporter),
+		k.ConfigMap(exporter),
+		k.ServiceAccount(exporter),
+		k.Service(exporter),
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package acceleratorexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+)
+
+// efaKind is an exporter kind only defined by its container, configuration files and port.
+var efaKind = Kind{
+	Name:                      "EfaExporter",
+	Component:                 "efa-exporter",
+	ServiceAppLabel:           "efa-exporter-service",
+	DefaultServiceAccountName: "efa-exporter-service-acct",
+	ConfigMapName:             "efa-exporter-config-map",
+	ConfigVolumeName:          "efa-config",
+	ConfigMountPath:           "/etc/efa-exporter",
+	ConfigFile:                "config.yaml",
+	Port:                      9809,
+	Image: func(cfg config.Config) string {
+		return "efa-exporter:latest"
+	},
+	Version: func() string {
+		return "1.0"
+	},
+	Args: func(exporter Exporter) []string {
+		return []string{"--config", "/etc/efa-exporter/config.yaml"}
+	},
+	Env: func(exporter Exporter) []corev1.EnvVar {
+		return []corev1.EnvVar{{Name: "EFA_EXPORTER_PORT", Value: "9809"}}
+	},
+}
+
+func TestBuild(t *testing.T) {
+	exporter := Exporter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "efa",
+			Namespace:   "amazon-cloudwatch",
+			Annotations: map[string]string{"prometheus.io/port": "9809"},
+		},
+		Spec: Spec{
+			Env:    []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+			Config: map[string]string{"config.yaml": "collectors: [efa]"},
+		},
+	}
+
+	objects := efaKind.Build(config.New(), exporter)
+	require.Len(t, objects, 4)
+
+	daemonSet, ok := objects[0].(*appsv1.DaemonSet)
+	require.True(t, ok)
+	assert.Equal(t, "efa", daemonSet.Name)
+	assert.Equal(t, "efa-exporter-service-acct", daemonSet.Spec.Template.Spec.ServiceAccountName)
+	container := daemonSet.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "efa-exporter", container.Name)
+	assert.Equal(t, "efa-exporter:latest", container.Image)
+	assert.Equal(t, []string{"--config", "/etc/efa-exporter/config.yaml"}, container.Args)
+	assert.Equal(t, []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "EFA_EXPORTER_PORT", Value: "9809"}}, container.Env)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "efa-config", MountPath: "/etc/efa-exporter"}}, container.VolumeMounts)
+	assert.Equal(t, "efa-exporter-config-map", daemonSet.Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+	assert.Equal(t, "9809", daemonSet.Annotations["prometheus.io/port"])
+	assert.Equal(t, getConfigMapSHA("collectors: [efa]"), daemonSet.Annotations["amazon-cloudwatch-agent-operator-config/sha256"])
+
+	configMap, ok := objects[1].(*corev1.ConfigMap)
+	require.True(t, ok)
+	assert.Equal(t, "efa-exporter-config-map", configMap.Name)
+	assert.Equal(t, map[string]string{"config.yaml": "collectors: [efa]"}, configMap.Data)
+
+	serviceAccount, ok := objects[2].(*corev1.ServiceAccount)
+	require.True(t, ok)
+	assert.Equal(t, "efa-exporter-service-acct", serviceAccount.Name)
+
+	service, ok := objects[3].(*corev1.Service)
+	require.True(t, ok)
+	assert.Equal(t, "efa-exporter-service", service.Labels["k8s-app"])
+	assert.Equal(t, int32(9809), service.Spec.Ports[0].Port)
+	assert.Equal(t, int32(9809), service.Spec.Ports[0].TargetPort.IntVal)
+}
@@ -1,30 +1,26 @@
 // Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
 // SPDX-License-Identifier: Apache-2.0
 
-package dcgmexporter
+package acceleratorexporter
 
 import (
 	"crypto/sha256"
 	"fmt"
-
-	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 )
 
-// Annotations return the annotations for DcgmExporter pod.
-func Annotations(instance v1alpha1.DcgmExporter) map[string]string {
+// Annotations return the annotations for the exporter pod.
+func (k Kind) Annotations(exporter Exporter) map[string]string {
 	// new map every time, so that we don't touch the instance's annotations
 	annotations := map[string]string{}
 
-	annotations["k8s-app"] = ComponentDcgmExporter
+	annotations["k8s-app"] = k.Component
 
 	// allow override of prometheus annotations
-	if nil != instance.Annotations {
-		for k, v := range instance.Annotations {
-			annotations[k] = v
-		}
+	for key, value := range exporter.Annotations {
+		annotations[key] = value
 	}
 	// make sure sha256 for configMap is always calculated
-	annotations["amazon-cloudwatch-agent-operator-config/sha256"] = getConfigMapSHA(instance.Spec.MetricsConfig)
+	annotations["amazon-cloudwatch-agent-operator-config/sha256"] = getConfigMapSHA(exporter.Spec.Config[k.ConfigFile])
 
 	return annotations
 }
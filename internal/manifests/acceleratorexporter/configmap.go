@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package acceleratorexporter
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+)
+
+// ConfigMap builds the config map holding the configuration files of the exporter.
+func (k Kind) ConfigMap(exporter Exporter) *corev1.ConfigMap {
+	labels := manifestutils.Labels(exporter.ObjectMeta, k.ConfigMapName, exporter.Spec.Image, k.Component, []string{})
+
+	data := make(map[string]string, len(exporter.Spec.Config))
+	for file, content := range exporter.Spec.Config {
+		data[file] = content
+	}
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        k.ConfigMapName,
+			Namespace:   exporter.Namespace,
+			Labels:      labels,
+			Annotations: exporter.Annotations,
+		},
+		Data: data,
+	}
+}
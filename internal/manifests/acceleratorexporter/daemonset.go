@@ -0,0 +1,50 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package acceleratorexporter
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// DaemonSet builds the daemonSet running the exporter on the nodes.
+func (k Kind) DaemonSet(cfg config.Config, exporter Exporter) *appsv1.DaemonSet {
+	name := naming.Collector(exporter.Name)
+	if len(name) == 0 {
+		name = k.Component
+	}
+	labels := manifestutils.Labels(exporter.ObjectMeta, name, exporter.Spec.Image, k.Component, cfg.LabelsFilter())
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        exporter.Name,
+			Namespace:   exporter.Namespace,
+			Labels:      labels,
+			Annotations: k.Annotations(exporter),
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: manifestutils.SelectorLabels(exporter.ObjectMeta, k.Component),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: k.ServiceAccountName(exporter),
+					Containers:         []corev1.Container{k.Container(cfg, exporter)},
+					Volumes:            k.Volumes(exporter),
+					Tolerations:        exporter.Spec.Tolerations,
+					NodeSelector:       exporter.Spec.NodeSelector,
+					Affinity:           exporter.Spec.Affinity,
+				},
+			},
+		},
+	}
+}
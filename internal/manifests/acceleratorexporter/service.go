@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package acceleratorexporter
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// Service builds the service exposing the metrics of the exporter.
+func (k Kind) Service(exporter Exporter) *corev1.Service {
+	name := naming.Service(exporter.Name)
+	if len(name) == 0 {
+		name = k.Component
+	}
+	labels := manifestutils.Labels(exporter.ObjectMeta, name, exporter.Spec.Image, k.Component, []string{})
+	//this label is used by scraper config in the agent.
+	labels["k8s-app"] = k.ServiceAppLabel
+	annotations := k.Annotations(exporter)
+	annotations["prometheus.io/scrape"] = "true"
+	port := corev1.ServicePort{
+		Name:       "metrics",
+		Port:       k.Port,
+		TargetPort: intstr.IntOrString{Type: intstr.Int, IntVal: k.Port},
+		Protocol:   corev1.ProtocolTCP,
+	}
+	if len(exporter.Spec.Ports) > 0 {
+		// update default service values with what's from CR
+		port.Name = exporter.Spec.Ports[0].Name
+		port.Port = exporter.Spec.Ports[0].Port
+		port.TargetPort.IntVal = exporter.Spec.Ports[0].Port
+	}
+	trafficPolicy := corev1.ServiceInternalTrafficPolicyLocal
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-service", name),
+			Namespace:   exporter.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:                  corev1.ServiceTypeClusterIP,
+			InternalTrafficPolicy: &trafficPolicy,
+			Selector:              manifestutils.SelectorLabels(exporter.ObjectMeta, k.Component),
+			Ports:                 []corev1.ServicePort{port},
+		},
+	}
+}
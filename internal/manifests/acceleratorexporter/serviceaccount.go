@@ -0,0 +1,33 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package acceleratorexporter
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
+)
+
+// ServiceAccountName returns the name of the existing or self-provisioned service account to use for the exporter.
+func (k Kind) ServiceAccountName(exporter Exporter) string {
+	if len(exporter.Spec.ServiceAccount) == 0 {
+		return k.DefaultServiceAccountName
+	}
+	return exporter.Spec.ServiceAccount
+}
+
+// ServiceAccount returns the self-provisioned service account of the exporter.
+func (k Kind) ServiceAccount(exporter Exporter) *corev1.ServiceAccount {
+	labels := manifestutils.Labels(exporter.ObjectMeta, k.DefaultServiceAccountName, exporter.Spec.Image, k.Component, []string{})
+
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        k.DefaultServiceAccountName,
+			Namespace:   exporter.Namespace,
+			Labels:      labels,
+			Annotations: k.Annotations(exporter),
+		},
+	}
+}
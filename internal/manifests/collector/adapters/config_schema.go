@@ -0,0 +1,196 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	openapierrors "k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+)
+
+// AgentConfigSchemaVersion is the version of the bundled schema the CloudWatch agent configuration is validated with.
+const AgentConfigSchemaVersion = "v1"
+
+// deprecatedExtension marks the deprecated keys in the schema, its value telling what to use instead.
+const deprecatedExtension = "x-deprecated"
+
+//go:embed schema/agent_config_v1.json
+var agentConfigSchemaJSON []byte
+
+var (
+	agentConfigSchema     *spec.Schema
+	agentConfigSchemaErr  error
+	agentConfigSchemaOnce sync.Once
+)
+
+// getAgentConfigSchema parses the bundled schema once, with its references to definitions expanded since the
+// validator does not resolve them.
+func getAgentConfigSchema() (*spec.Schema, error) {
+	agentConfigSchemaOnce.Do(func() {
+		var raw map[string]interface{}
+		if err := json.Unmarshal(agentConfigSchemaJSON, &raw); err != nil {
+			agentConfigSchemaErr = err
+			return
+		}
+		definitions, _ := raw["definitions"].(map[string]interface{})
+		delete(raw, "definitions")
+		expanded, err := expandSchemaRefs(raw, definitions)
+		if err != nil {
+			agentConfigSchemaErr = err
+			return
+		}
+		expandedJSON, err := json.Marshal(expanded)
+		if err != nil {
+			agentConfigSchemaErr = err
+			return
+		}
+		agentConfigSchema = &spec.Schema{}
+		agentConfigSchemaErr = json.Unmarshal(expandedJSON, agentConfigSchema)
+	})
+	return agentConfigSchema, agentConfigSchemaErr
+}
+
+// expandSchemaRefs replaces the {"$ref": "#/definitions/name"} objects with the definition, merged with the other keys
+// of the object.
+func expandSchemaRefs(node interface{}, definitions map[string]interface{}) (interface{}, error) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		expanded := make(map[string]interface{}, len(n))
+		if ref, ok := n["$ref"].(string); ok {
+			name := strings.TrimPrefix(ref, "#/definitions/")
+			definition, ok := definitions[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown schema reference %s", ref)
+			}
+			resolved, err := expandSchemaRefs(definition, definitions)
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range resolved.(map[string]interface{}) {
+				expanded[key] = value
+			}
+		}
+		for key, value := range n {
+			if key == "$ref" {
+				continue
+			}
+			resolved, err := expandSchemaRefs(value, definitions)
+			if err != nil {
+				return nil, err
+			}
+			expanded[key] = resolved
+		}
+		return expanded, nil
+	case []interface{}:
+		expanded := make([]interface{}, len(n))
+		for i, value := range n {
+			resolved, err := expandSchemaRefs(value, definitions)
+			if err != nil {
+				return nil, err
+			}
+			expanded[i] = resolved
+		}
+		return expanded, nil
+	}
+	return node, nil
+}
+
+// ValidateAgentConfig validates the CloudWatch agent JSON configuration against the bundled schema. The error lists
+// the path of every invalid field, and the warnings report the deprecated keys and the unknown keys of the objects
+// accepting them.
+func ValidateAgentConfig(configStr string) ([]string, error) {
+	schema, err := getAgentConfigSchema()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't load the %s schema of the cloudwatch agent configuration: %w", AgentConfigSchemaVersion, err)
+	}
+
+	var config interface{}
+	if err := json.Unmarshal([]byte(configStr), &config); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+
+	result := validate.NewSchemaValidator(schema, nil, "", strfmt.Default).Validate(config)
+	if !result.IsValid() {
+		return nil, fmt.Errorf("the cloudwatch agent configuration does not match the %s schema: %s", AgentConfigSchemaVersion, strings.Join(validationMessages(result.Errors), ", "))
+	}
+
+	var warnings []string
+	collectConfigWarnings(schema, config, "", &warnings)
+	sort.Strings(warnings)
+	return warnings, nil
+}
+
+// validationMessages returns the sorted messages of the validation errors, in the "path message" form. The errors
+// without a field path duplicate the ones with one and are only kept if there is no other.
+func validationMessages(errs []error) []string {
+	var messages, others []string
+	for _, err := range errs {
+		var validationErr *openapierrors.Validation
+		if errors.As(err, &validationErr) {
+			messages = append(messages, strings.TrimPrefix(strings.Replace(validationErr.Error(), " in body", "", 1), "."))
+		} else {
+			others = append(others, err.Error())
+		}
+	}
+	if len(messages) == 0 {
+		messages = others
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+// collectConfigWarnings walks the configuration along the schema to report the deprecated keys, and the keys unknown
+// to objects that do not restrict their additional properties.
+func collectConfigWarnings(schema *spec.Schema, value interface{}, path string, warnings *[]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			childSchema := propertySchema(schema, key)
+			if childSchema == nil {
+				if len(schema.Properties) > 0 && schema.AdditionalProperties == nil {
+					*warnings = append(*warnings, fmt.Sprintf("%s is not a known key of the cloudwatch agent configuration", childPath))
+				}
+				continue
+			}
+			if deprecation, ok := childSchema.Extensions.GetString(deprecatedExtension); ok {
+				*warnings = append(*warnings, fmt.Sprintf("%s is deprecated, %s", childPath, deprecation))
+			}
+			collectConfigWarnings(childSchema, child, childPath, warnings)
+		}
+	case []interface{}:
+		if schema.Items == nil || schema.Items.Schema == nil {
+			return
+		}
+		for i, child := range v {
+			collectConfigWarnings(schema.Items.Schema, child, fmt.Sprintf("%s[%d]", path, i), warnings)
+		}
+	}
+}
+
+// propertySchema returns the schema of the property of the object, nil if the object does not describe it.
+func propertySchema(schema *spec.Schema, key string) *spec.Schema {
+	if property, ok := schema.Properties[key]; ok {
+		return &property
+	}
+	for pattern, property := range schema.PatternProperties {
+		if matched, _ := regexp.MatchString(pattern, key); matched {
+			return &property
+		}
+	}
+	return nil
+}
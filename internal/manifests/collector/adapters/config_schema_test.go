@@ -0,0 +1,107 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package adapters_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector/adapters"
+)
+
+func TestValidateAgentConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       string
+		wantErr      []string
+		wantWarnings []string
+	}{
+		{
+			name: "valid",
+			config: `{
+				"agent": {"metrics_collection_interval": 60, "region": "us-west-2"},
+				"metrics": {
+					"namespace": "test",
+					"metrics_collected": {
+						"cpu": {"measurement": ["usage_idle", {"name": "usage_user", "unit": "Percent"}], "totalcpu": true},
+						"procstat": [{"exe": "nginx", "measurement": ["cpu_usage"]}],
+						"Processor": {"measurement": ["% Processor Time"], "resources": ["*"]}
+					}
+				},
+				"logs": {"metrics_collected": {"kubernetes": {"cluster_name": "test", "enhanced_container_insights": true}}},
+				"traces": {"traces_collected": {"xray": {"bind_address": "0.0.0.0:2000"}}}
+			}`,
+		},
+		{
+			name:    "invalid json",
+			config:  `{"metrics": `,
+			wantErr: []string{"couldn't parse cloudwatch agent json configuration"},
+		},
+		{
+			name:         "unknown plugin",
+			config:       `{"metrics": {"metrics_collected": {"cpuu": {}}}}`,
+			wantWarnings: []string{"metrics.metrics_collected.cpuu is not a known key of the cloudwatch agent configuration"},
+		},
+		{
+			name:    "unknown section",
+			config:  `{"metric": {}}`,
+			wantErr: []string{"metric is a forbidden property"},
+		},
+		{
+			name:   "invalid types",
+			config: `{"agent": {"metrics_collection_interval": "60s"}, "logs": {"logs_collected": {"files": {"collect_list": [{"log_group_name": "test", "timezone": "PST"}]}}}}`,
+			wantErr: []string{
+				"agent.metrics_collection_interval must be of type integer",
+				"logs.logs_collected.files.collect_list[0].file_path is required",
+				"logs.logs_collected.files.collect_list[0].timezone should be one of [Local UTC]",
+			},
+		},
+		{
+			name:   "deprecated and unknown keys",
+			config: `{"agent": {"debug": true, "flush_interval": 5}, "traces": {"traces_collected": {"app_signals": {}}}}`,
+			wantWarnings: []string{
+				"agent.flush_interval is not a known key of the cloudwatch agent configuration",
+				"traces.traces_collected.app_signals is deprecated, use traces.traces_collected.application_signals instead",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := adapters.ValidateAgentConfig(tt.config)
+			if len(tt.wantErr) > 0 {
+				require.Error(t, err)
+				for _, want := range tt.wantErr {
+					assert.Contains(t, err.Error(), want)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWarnings, warnings)
+		})
+	}
+}
+
+func TestValidateAgentConfigInvalidJSON(t *testing.T) {
+	_, err := adapters.ValidateAgentConfig("🦄")
+	assert.True(t, errors.Is(err, adapters.ErrInvalidJSON))
+}
+
+func TestValidateAgentConfigTestResources(t *testing.T) {
+	// the configurations used by the manifest tests are valid, without warnings
+	files, err := filepath.Glob("../test-resources/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		config, err := os.ReadFile(file)
+		require.NoError(t, err)
+		warnings, err := adapters.ValidateAgentConfig(string(config))
+		assert.NoError(t, err, file)
+		assert.Empty(t, warnings, file)
+	}
+}
@@ -4,32 +4,97 @@
 package dcgmexporter
 
 import (
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"fmt"
+	"sort"
 
-	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/acceleratorexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/version"
 )
 
 const (
 	ComponentDcgmExporter = "dcgm-exporter"
+
+	DcgmConfigMapName       = "dcgm-exporter-config-map"
+	DcgmConfigMapVolumeName = "dcgm-config"
+	DcgmMetricsIncludedCsv  = "dcp-metrics-included.csv"
+	DcgmWebConfigYaml       = "web-config.yaml"
+
+	dcgmServiceAcctName = "dcgm-exporter-service-acct"
+	configmapMountPath  = "/etc/dcgm-exporter"
+	metricsConfigEnvVar = "DCGM_EXPORTER_COLLECTORS"
 )
 
-// Build creates the manifest for the exporter resource.
-func Build(params manifests.Params) ([]client.Object, error) {
-	var resourceManifests []client.Object
-	var manifestFactories []manifests.K8sManifestFactory
-	manifestFactories = append(manifestFactories, []manifests.K8sManifestFactory{
-		manifests.FactoryWithoutError(DaemonSet),
-		manifests.Factory(ConfigMap),
-		manifests.FactoryWithoutError(ServiceAccount),
-		manifests.Factory(Service),
-	}...)
-	for _, factory := range manifestFactories {
-		res, err := factory(params)
-		if err != nil {
-			return nil, err
-		} else if manifests.ObjectIsNotNil(res) {
-			resourceManifests = append(resourceManifests, res)
+// Kind is the DCGM exporter, reporting the telemetry of the NVIDIA GPUs.
+var Kind = acceleratorexporter.Kind{
+	Name:                      "DcgmExporter",
+	Component:                 ComponentDcgmExporter,
+	ServiceAppLabel:           "dcgm-exporter-service",
+	DefaultServiceAccountName: dcgmServiceAcctName,
+	ConfigMapName:             DcgmConfigMapName,
+	ConfigVolumeName:          DcgmConfigMapVolumeName,
+	ConfigMountPath:           configmapMountPath,
+	ConfigFile:                DcgmMetricsIncludedCsv,
+	Port:                      9400,
+	Image: func(cfg config.Config) string {
+		return cfg.DcgmExporterImage()
+	},
+	Version: version.DcgmExporter,
+	Args: func(exporter acceleratorexporter.Exporter) []string {
+		argsMap := make(map[string]string, len(exporter.Spec.Args)+1)
+		for k, v := range exporter.Spec.Args {
+			argsMap[k] = v
+		}
+		if _, ok := exporter.Spec.Config[DcgmWebConfigYaml]; ok {
+			argsMap["web-config-file"] = fmt.Sprintf("%s/%s", configmapMountPath, DcgmWebConfigYaml)
+		}
+
+		// ensure that the v1alpha1.DcgmExporterSpec.Args are ordered when moved to container.Args,
+		// where iterating over a map does not guarantee, so that reconcile will not be fooled by different
+		// ordering in args.
+		var sortedArgs []string
+		for k, v := range argsMap {
+			sortedArgs = append(sortedArgs, fmt.Sprintf("--%s=%s", k, v))
 		}
+		sort.Strings(sortedArgs)
+		return sortedArgs
+	},
+	Env: func(exporter acceleratorexporter.Exporter) []corev1.EnvVar {
+		return []corev1.EnvVar{{
+			Name:  metricsConfigEnvVar,
+			Value: fmt.Sprintf("%s/%s", configmapMountPath, DcgmMetricsIncludedCsv),
+		}}
+	},
+}
+
+// Exporter returns the DcgmExporter as an accelerator exporter, with the metrics config and the TLS config, if any,
+// as configuration files.
+func Exporter(instance v1alpha1.DcgmExporter) acceleratorexporter.Exporter {
+	config := map[string]string{
+		DcgmMetricsIncludedCsv: instance.Spec.MetricsConfig,
+	}
+	if len(instance.Spec.TlsConfig) > 0 {
+		config[DcgmWebConfigYaml] = instance.Spec.TlsConfig
+	}
+	return acceleratorexporter.Exporter{
+		ObjectMeta: instance.ObjectMeta,
+		Spec: acceleratorexporter.Spec{
+			Resources:       instance.Spec.Resources,
+			NodeSelector:    instance.Spec.NodeSelector,
+			SecurityContext: instance.Spec.SecurityContext,
+			Args:            instance.Spec.Args,
+			ServiceAccount:  instance.Spec.ServiceAccount,
+			Image:           instance.Spec.Image,
+			Ports:           instance.Spec.Ports,
+			Env:             instance.Spec.Env,
+			Tolerations:     instance.Spec.Tolerations,
+			Volumes:         instance.Spec.Volumes,
+			VolumeMounts:    instance.Spec.VolumeMounts,
+			Affinity:        instance.Spec.Affinity,
+			Config:          config,
+		},
 	}
-	return resourceManifests, nil
 }
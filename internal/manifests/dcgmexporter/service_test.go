@@ -11,37 +11,28 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
-	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
-	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 )
 
-var logger = logf.Log.WithName("unit-tests")
-
 func TestDesiredDcgmService(t *testing.T) {
 	t.Run("should return the default service", func(t *testing.T) {
-		params := manifests.Params{
-			Config: config.Config{},
-			Log:    logger,
-			DcgmExp: v1alpha1.DcgmExporter{
-				Spec: v1alpha1.DcgmExporterSpec{},
-			},
+		exporter := v1alpha1.DcgmExporter{
+			Spec: v1alpha1.DcgmExporterSpec{},
 		}
 		trafficPolicy := v1.ServiceInternalTrafficPolicyLocal
 		expected := v1.Service{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:        fmt.Sprintf("%s-service", ComponentDcgmExporter),
-				Namespace:   params.DcgmExp.Namespace,
+				Namespace:   exporter.Namespace,
 				Labels:      map[string]string{},
 				Annotations: map[string]string{},
 			},
 			Spec: v1.ServiceSpec{
 				Type:                  v1.ServiceTypeClusterIP,
 				InternalTrafficPolicy: &trafficPolicy,
-				Selector:              manifestutils.SelectorLabels(params.DcgmExp.ObjectMeta, ComponentDcgmExporter),
+				Selector:              manifestutils.SelectorLabels(exporter.ObjectMeta, ComponentDcgmExporter),
 				Ports: []v1.ServicePort{
 					{
 						Name:       "metrics",
@@ -53,8 +44,7 @@ func TestDesiredDcgmService(t *testing.T) {
 			},
 		}
 
-		actual, err := Service(params)
-		assert.Nil(t, err)
+		actual := Kind.Service(Exporter(exporter))
 		assert.Equal(t, expected.Name, actual.Name)
 		assert.Equal(t, expected.Spec.Type, actual.Spec.Type)
 		assert.Equal(t, expected.Spec.InternalTrafficPolicy, actual.Spec.InternalTrafficPolicy)
@@ -62,16 +52,12 @@ func TestDesiredDcgmService(t *testing.T) {
 	})
 
 	t.Run("should return a service object with overriden values", func(t *testing.T) {
-		params := manifests.Params{
-			Config: config.Config{},
-			Log:    logger,
-			DcgmExp: v1alpha1.DcgmExporter{
-				Spec: v1alpha1.DcgmExporterSpec{
-					Ports: []v1.ServicePort{
-						{
-							Name: "test",
-							Port: 9999,
-						},
+		exporter := v1alpha1.DcgmExporter{
+			Spec: v1alpha1.DcgmExporterSpec{
+				Ports: []v1.ServicePort{
+					{
+						Name: "test",
+						Port: 9999,
 					},
 				},
 			},
@@ -80,14 +66,14 @@ func TestDesiredDcgmService(t *testing.T) {
 		expected := v1.Service{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:        fmt.Sprintf("%s-service", ComponentDcgmExporter),
-				Namespace:   params.DcgmExp.Namespace,
+				Namespace:   exporter.Namespace,
 				Labels:      map[string]string{},
 				Annotations: map[string]string{},
 			},
 			Spec: v1.ServiceSpec{
 				Type:                  v1.ServiceTypeClusterIP,
 				InternalTrafficPolicy: &trafficPolicy,
-				Selector:              manifestutils.SelectorLabels(params.DcgmExp.ObjectMeta, ComponentDcgmExporter),
+				Selector:              manifestutils.SelectorLabels(exporter.ObjectMeta, ComponentDcgmExporter),
 				Ports: []v1.ServicePort{
 					{
 						Name:       "test",
@@ -99,8 +85,7 @@ func TestDesiredDcgmService(t *testing.T) {
 			},
 		}
 
-		actual, err := Service(params)
-		assert.Nil(t, err)
+		actual := Kind.Service(Exporter(exporter))
 		assert.Equal(t, expected.Name, actual.Name)
 		assert.Equal(t, expected.Spec.Type, actual.Spec.Type)
 		assert.Equal(t, expected.Spec.InternalTrafficPolicy, actual.Spec.InternalTrafficPolicy)
@@ -18,7 +18,7 @@ func TestServiceAccountNewDefault(t *testing.T) {
 			Name: "my-instance",
 		},
 	}
-	sa := ServiceAccountName(exporter)
+	sa := Kind.ServiceAccountName(Exporter(exporter))
 	assert.Equal(t, "dcgm-exporter-service-acct", sa)
 }
 
@@ -31,6 +31,6 @@ func TestServiceAccountOverride(t *testing.T) {
 			ServiceAccount: "my-special-sa",
 		},
 	}
-	sa := ServiceAccountName(exporter)
+	sa := Kind.ServiceAccountName(Exporter(exporter))
 	assert.Equal(t, "my-special-sa", sa)
 }
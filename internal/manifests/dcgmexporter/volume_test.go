@@ -14,7 +14,7 @@ import (
 
 func TestVolumeNewDefault(t *testing.T) {
 	exporter := v1alpha1.DcgmExporter{}
-	volumes := Volumes(exporter)
+	volumes := Kind.Volumes(Exporter(exporter))
 	assert.Len(t, volumes, 1)
 	assert.Equal(t, DcgmConfigMapVolumeName, volumes[0].Name)
 }
@@ -27,7 +27,7 @@ func TestVolumeAllowsMoreToBeAdded(t *testing.T) {
 			}},
 		},
 	}
-	volumes := Volumes(exporter)
+	volumes := Kind.Volumes(Exporter(exporter))
 	assert.Len(t, volumes, 2)
 	assert.Equal(t, "my-volume", volumes[0].Name)
 	assert.Equal(t, DcgmConfigMapVolumeName, volumes[1].Name)
@@ -22,7 +22,7 @@ func TestDefaultAnnotations(t *testing.T) {
 		Spec: v1alpha1.NeuronMonitorSpec{},
 	}
 	// test
-	annotations := Annotations(exporter)
+	annotations := Kind.Annotations(Exporter(exporter))
 
 	//verify
 	assert.Equal(t, "neuron-monitor", annotations["k8s-app"])
@@ -44,7 +44,7 @@ func TestUserAnnotations(t *testing.T) {
 	}
 
 	// test
-	annotations := Annotations(exporter)
+	annotations := Kind.Annotations(Exporter(exporter))
 
 	//verify
 	assert.Equal(t, "test", annotations["prometheus.io/test"])
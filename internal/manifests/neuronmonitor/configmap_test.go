@@ -9,12 +9,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
-	"k8s.io/client-go/tools/record"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
-	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
-	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 )
 
 func TestDesiredConfigMap(t *testing.T) {
@@ -34,10 +30,8 @@ func TestDesiredConfigMap(t *testing.T) {
 			NeuronMonitorJson: `{"period":"5s","neuron_runtimes":[{"tag_filter":".*","metrics":[{"type":"neuroncore_counters"},{"type":"memory_used"},{"type":"neuron_runtime_vcpu_usage"},{"type":"execution_stats"}]}],"system_metrics":[{"type":"memory_info"},{"period":"5s","type":"neuron_hw_counters"}]}`,
 		}
 
-		param := getParams()
-		actual, err := ConfigMap(param)
+		actual := Kind.ConfigMap(Exporter(getExporter()))
 
-		assert.NoError(t, err)
 		assert.Equal(t, NeuronConfigMapName, actual.Name)
 		assert.Equal(t, expectedLables, actual.Labels)
 		assert.Equal(t, expectedData, actual.Data)
@@ -45,25 +39,20 @@ func TestDesiredConfigMap(t *testing.T) {
 	})
 }
 
-func getParams() manifests.Params {
-	return manifests.Params{
-		Config: config.New(config.WithNeuronMonitorImage("default-exporter")),
-		NeuronExp: v1alpha1.NeuronMonitor{
-			TypeMeta: metav1.TypeMeta{
-				Kind:       "cloudwatch.aws.amazon.com",
-				APIVersion: "v1",
-			},
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "test",
-				Namespace: "default",
-				UID:       uuid.NewUUID(),
-			},
-			Spec: v1alpha1.NeuronMonitorSpec{
-				Image:         "public.ecr.aws/cloudwatch-agent/neuron-monitor:0.1.0",
-				MonitorConfig: `{"period":"5s","neuron_runtimes":[{"tag_filter":".*","metrics":[{"type":"neuroncore_counters"},{"type":"memory_used"},{"type":"neuron_runtime_vcpu_usage"},{"type":"execution_stats"}]}],"system_metrics":[{"type":"memory_info"},{"period":"5s","type":"neuron_hw_counters"}]}`,
-			},
+func getExporter() v1alpha1.NeuronMonitor {
+	return v1alpha1.NeuronMonitor{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "cloudwatch.aws.amazon.com",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+			UID:       uuid.NewUUID(),
+		},
+		Spec: v1alpha1.NeuronMonitorSpec{
+			Image:         "public.ecr.aws/cloudwatch-agent/neuron-monitor:0.1.0",
+			MonitorConfig: `{"period":"5s","neuron_runtimes":[{"tag_filter":".*","metrics":[{"type":"neuroncore_counters"},{"type":"memory_used"},{"type":"neuron_runtime_vcpu_usage"},{"type":"execution_stats"}]}],"system_metrics":[{"type":"memory_info"},{"period":"5s","type":"neuron_hw_counters"}]}`,
 		},
-		Log:      logf.Log.WithName("unit-tests"),
-		Recorder: record.NewFakeRecorder(10),
 	}
 }
@@ -10,14 +10,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 )
 
 func TestNeuronContainer(t *testing.T) {
-	logger := logf.Log.WithName("unit-tests")
 	testCases := []struct {
 		name     string
 		exporter v1alpha1.NeuronMonitor
@@ -87,7 +85,7 @@ func TestNeuronContainer(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		container := Container(config.Config{}, logger, tc.exporter)
+		container := Kind.Container(config.Config{}, Exporter(tc.exporter))
 		assert.Equal(t, tc.expected, container)
 	}
 }
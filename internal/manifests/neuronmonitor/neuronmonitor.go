@@ -4,32 +4,77 @@
 package neuronmonitor
 
 import (
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"fmt"
+	"sort"
 
-	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/acceleratorexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/version"
 )
 
 const (
 	ComponentNeuronExporter = "neuron-monitor"
+
+	NeuronConfigMapName       = "neuron-monitor-config-map"
+	NeuronConfigMapVolumeName = "neuron-monitor-config"
+	NeuronMonitorJson         = "monitor.json"
+
+	neuronSrviceAcctName = "neuron-monitor-service-acct"
+	configmapMountPath   = "/etc/neuron-monitor-config"
 )
 
-// Build creates the manifest for the exporter resource.
-func Build(params manifests.Params) ([]client.Object, error) {
-	var resourceManifests []client.Object
-	var manifestFactories []manifests.K8sManifestFactory
-	manifestFactories = append(manifestFactories, []manifests.K8sManifestFactory{
-		manifests.FactoryWithoutError(DaemonSet),
-		manifests.Factory(ConfigMap),
-		manifests.FactoryWithoutError(ServiceAccount),
-		manifests.Factory(Service),
-	}...)
-	for _, factory := range manifestFactories {
-		res, err := factory(params)
-		if err != nil {
-			return nil, err
-		} else if manifests.ObjectIsNotNil(res) {
-			resourceManifests = append(resourceManifests, res)
+// Kind is the Neuron monitor, reporting the telemetry of the Inferentia and Trainium devices.
+var Kind = acceleratorexporter.Kind{
+	Name:                      "NeuronMonitor",
+	Component:                 ComponentNeuronExporter,
+	ServiceAppLabel:           "neuron-monitor-service",
+	DefaultServiceAccountName: neuronSrviceAcctName,
+	ConfigMapName:             NeuronConfigMapName,
+	ConfigVolumeName:          NeuronConfigMapVolumeName,
+	ConfigMountPath:           configmapMountPath,
+	ConfigFile:                NeuronMonitorJson,
+	Port:                      8000,
+	Image: func(cfg config.Config) string {
+		return cfg.NeuronMonitorImage()
+	},
+	Version: version.NeuronMonitor,
+	Args: func(exporter acceleratorexporter.Exporter) []string {
+		// the args are ordered by name, so that reconcile will not be fooled by the map iteration order
+		names := make([]string, 0, len(exporter.Spec.Args))
+		for k := range exporter.Spec.Args {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		var args []string
+		for _, k := range names {
+			args = append(args, "--"+k, exporter.Spec.Args[k])
 		}
+		return append(args, "--neuron-monitor-config", fmt.Sprintf("%s/%s", configmapMountPath, NeuronMonitorJson))
+	},
+}
+
+// Exporter returns the NeuronMonitor as an accelerator exporter, with the monitor config as configuration file.
+func Exporter(instance v1alpha1.NeuronMonitor) acceleratorexporter.Exporter {
+	return acceleratorexporter.Exporter{
+		ObjectMeta: instance.ObjectMeta,
+		Spec: acceleratorexporter.Spec{
+			Resources:       instance.Spec.Resources,
+			NodeSelector:    instance.Spec.NodeSelector,
+			SecurityContext: instance.Spec.SecurityContext,
+			Command:         instance.Spec.Command,
+			Args:            instance.Spec.Args,
+			ServiceAccount:  instance.Spec.ServiceAccount,
+			Image:           instance.Spec.Image,
+			Ports:           instance.Spec.Ports,
+			Env:             instance.Spec.Env,
+			Tolerations:     instance.Spec.Tolerations,
+			Volumes:         instance.Spec.Volumes,
+			VolumeMounts:    instance.Spec.VolumeMounts,
+			Affinity:        instance.Spec.Affinity,
+			Config: map[string]string{
+				NeuronMonitorJson: instance.Spec.MonitorConfig,
+			},
+		},
 	}
-	return resourceManifests, nil
 }
@@ -0,0 +1,168 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package targetallocator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// HasNamespaceSelector returns whether the TargetAllocator selects the monitors by the labels of their namespace, in
+// which case it watches the namespaces.
+func HasNamespaceSelector(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	return instance.Spec.TargetAllocator.PrometheusCR.ServiceMonitorNamespaceSelector != nil ||
+		instance.Spec.TargetAllocator.PrometheusCR.PodMonitorNamespaceSelector != nil
+}
+
+// ClusterRole returns the cluster role allowing the TargetAllocator to watch the Prometheus Operator custom resources
+// in all the namespaces and to discover the targets they select. The credentials they reference are only readable in
+// the namespaces of CredentialsRoleBindings. It returns nil when the custom resources aren't watched.
+func ClusterRole(params manifests.Params) *rbacv1.ClusterRole {
+	if !params.OtelCol.Spec.TargetAllocator.PrometheusCR.Enabled {
+		return nil
+	}
+	name := naming.TAClusterRole(params.OtelCol.Name, params.OtelCol.Namespace)
+	rules := []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{"monitoring.coreos.com"},
+			Resources: []string{"servicemonitors", "podmonitors", "probes", "scrapeconfigs"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"pods", "services", "endpoints", "nodes"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		{
+			APIGroups: []string{"discovery.k8s.io"},
+			Resources: []string{"endpointslices"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+	}
+	if HasNamespaceSelector(params.OtelCol) {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{""},
+			Resources: []string{"namespaces"},
+			Verbs:     []string{"get", "list", "watch"},
+		})
+	}
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: Labels(params.OtelCol, name),
+		},
+		Rules: rules,
+	}
+}
+
+// ClusterRoleBinding returns the binding of the TargetAllocator ClusterRole to its service account. It returns nil
+// when the Prometheus Operator custom resources aren't watched.
+func ClusterRoleBinding(params manifests.Params) *rbacv1.ClusterRoleBinding {
+	if !params.OtelCol.Spec.TargetAllocator.PrometheusCR.Enabled {
+		return nil
+	}
+	name := naming.TAClusterRole(params.OtelCol.Name, params.OtelCol.Namespace)
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: Labels(params.OtelCol, name),
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     name,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      ServiceAccountName(params.OtelCol),
+			Namespace: params.OtelCol.Namespace,
+		}},
+	}
+}
+
+// CredentialsRoleBindings returns the bindings of the TargetAllocator service account to the credentials cluster role
+// deployed with the operator, in the namespaces of the monitors it reads the credentials of: its own namespace and the
+// namespaces selected by the monitor namespace selectors. The monitors of other namespaces referencing credentials are
+// rejected by the TargetAllocator. It returns no binding when the Prometheus Operator custom resources aren't watched.
+func CredentialsRoleBindings(params manifests.Params) ([]*rbacv1.RoleBinding, error) {
+	if !params.OtelCol.Spec.TargetAllocator.PrometheusCR.Enabled {
+		return nil, nil
+	}
+	namespaces, err := selectedNamespaces(params)
+	if err != nil {
+		return nil, err
+	}
+	name := naming.TACredentialsRoleBinding(params.OtelCol.Name, params.OtelCol.Namespace)
+	var bindings []*rbacv1.RoleBinding
+	for _, namespace := range namespaces {
+		bindings = append(bindings, &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    Labels(params.OtelCol, name),
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     naming.TACredentialsClusterRole(),
+			},
+			Subjects: []rbacv1.Subject{{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      ServiceAccountName(params.OtelCol),
+				Namespace: params.OtelCol.Namespace,
+			}},
+		})
+	}
+	return bindings, nil
+}
+
+// selectedNamespaces returns the namespace of the TargetAllocator followed by the other namespaces selected by its
+// monitor namespace selectors, sorted.
+func selectedNamespaces(params manifests.Params) ([]string, error) {
+	namespaces := []string{params.OtelCol.Namespace}
+	if !HasNamespaceSelector(params.OtelCol) {
+		return namespaces, nil
+	}
+	var selectors []labels.Selector
+	for _, labelSelector := range []*metav1.LabelSelector{
+		params.OtelCol.Spec.TargetAllocator.PrometheusCR.ServiceMonitorNamespaceSelector,
+		params.OtelCol.Spec.TargetAllocator.PrometheusCR.PodMonitorNamespaceSelector,
+	} {
+		if labelSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid monitor namespace selector: %w", err)
+		}
+		selectors = append(selectors, selector)
+	}
+	var namespaceList corev1.NamespaceList
+	if err := params.Client.List(context.Background(), &namespaceList); err != nil {
+		return nil, fmt.Errorf("failed to list the namespaces of the monitors: %w", err)
+	}
+	for _, namespace := range namespaceList.Items {
+		if namespace.Name == params.OtelCol.Namespace {
+			continue
+		}
+		for _, selector := range selectors {
+			if selector.Matches(labels.Set(namespace.Labels)) {
+				namespaces = append(namespaces, namespace.Name)
+				break
+			}
+		}
+	}
+	slices.Sort(namespaces[1:])
+	return namespaces, nil
+}
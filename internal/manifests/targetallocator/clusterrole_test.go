@@ -0,0 +1,126 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package targetallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+func TestClusterRole(t *testing.T) {
+	otelcol := collectorInstance()
+	params := manifests.Params{
+		OtelCol: otelcol,
+		Config:  config.New(),
+		Log:     logger,
+	}
+	namespacesRule := rbacv1.PolicyRule{
+		APIGroups: []string{""},
+		Resources: []string{"namespaces"},
+		Verbs:     []string{"get", "list", "watch"},
+	}
+
+	// the Prometheus Operator custom resources aren't watched
+	assert.Nil(t, ClusterRole(params))
+	assert.Nil(t, ClusterRoleBinding(params))
+
+	params.OtelCol.Spec.TargetAllocator.PrometheusCR.Enabled = true
+	clusterRole := ClusterRole(params)
+	require.NotNil(t, clusterRole)
+	assert.Equal(t, "my-instance-default-target-allocator", clusterRole.Name)
+	assert.Empty(t, clusterRole.Namespace)
+	assert.Contains(t, clusterRole.Rules, rbacv1.PolicyRule{
+		APIGroups: []string{"monitoring.coreos.com"},
+		Resources: []string{"servicemonitors", "podmonitors", "probes", "scrapeconfigs"},
+		Verbs:     []string{"get", "list", "watch"},
+	})
+	// the namespaces are only watched to select the monitors by their labels
+	assert.NotContains(t, clusterRole.Rules, namespacesRule)
+	// the credentials are only readable in the namespaces of the credentials role bindings
+	for _, rule := range clusterRole.Rules {
+		assert.NotContains(t, rule.Resources, "secrets")
+	}
+
+	params.OtelCol.Spec.TargetAllocator.PrometheusCR.PodMonitorNamespaceSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"team": "a"},
+	}
+	assert.Contains(t, ClusterRole(params).Rules, namespacesRule)
+
+	binding := ClusterRoleBinding(params)
+	require.NotNil(t, binding)
+	assert.Equal(t, rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: clusterRole.Name}, binding.RoleRef)
+	assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "target-allocator-service-acct", Namespace: "default"}}, binding.Subjects)
+}
+
+func TestCredentialsRoleBindings(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	newNamespace := func(name, team string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"team": team}}}
+	}
+	params := manifests.Params{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newNamespace("default", "a"),
+			newNamespace("team-b", "b"),
+			newNamespace("team-a2", "a"),
+			newNamespace("team-a1", "a"),
+		).Build(),
+		OtelCol: collectorInstance(),
+		Config:  config.New(),
+		Log:     logger,
+	}
+	namespacesOf := func(bindings []*rbacv1.RoleBinding) []string {
+		var namespaces []string
+		for _, binding := range bindings {
+			namespaces = append(namespaces, binding.Namespace)
+		}
+		return namespaces
+	}
+
+	// the Prometheus Operator custom resources aren't watched
+	bindings, err := CredentialsRoleBindings(params)
+	require.NoError(t, err)
+	assert.Empty(t, bindings)
+
+	// the credentials of the namespace of the TargetAllocator only without namespace selector
+	params.OtelCol.Spec.TargetAllocator.PrometheusCR.Enabled = true
+	bindings, err = CredentialsRoleBindings(params)
+	require.NoError(t, err)
+	require.Len(t, bindings, 1)
+	assert.Equal(t, "my-instance-default-target-allocator-credentials", bindings[0].Name)
+	assert.Equal(t, "default", bindings[0].Namespace)
+	assert.Equal(t, rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: "cloudwatch-target-allocator-credentials-role"}, bindings[0].RoleRef)
+	assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "target-allocator-service-acct", Namespace: "default"}}, bindings[0].Subjects)
+
+	params.OtelCol.Spec.TargetAllocator.PrometheusCR.ServiceMonitorNamespaceSelector = &metav1.LabelSelector{
+		MatchLabels: map[string]string{"team": "a"},
+	}
+	bindings, err = CredentialsRoleBindings(params)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "team-a1", "team-a2"}, namespacesOf(bindings))
+
+	params.OtelCol.Spec.TargetAllocator.PrometheusCR.PodMonitorNamespaceSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: metav1.LabelSelectorOpIn, Values: []string{"b"}}},
+	}
+	bindings, err = CredentialsRoleBindings(params)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "team-a1", "team-a2", "team-b"}, namespacesOf(bindings))
+
+	params.OtelCol.Spec.TargetAllocator.PrometheusCR.PodMonitorNamespaceSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Invalid"}},
+	}
+	_, err = CredentialsRoleBindings(params)
+	assert.ErrorContains(t, err, "invalid monitor namespace selector")
+}
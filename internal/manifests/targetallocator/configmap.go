@@ -51,12 +51,29 @@ func ConfigMap(params manifests.Params) (*corev1.ConfigMap, error) {
 		taConfig["config"] = prometheusConfig
 	}
 
-	taConfig["allocation_strategy"] = v1alpha1.AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing
+	if len(params.OtelCol.Spec.TargetAllocator.AllocationStrategy) > 0 {
+		taConfig["allocation_strategy"] = params.OtelCol.Spec.TargetAllocator.AllocationStrategy
+	} else {
+		taConfig["allocation_strategy"] = v1alpha1.AmazonCloudWatchAgentTargetAllocatorAllocationStrategyConsistentHashing
+	}
+
+	// The replicas elect the leader allocating the targets, the followers serve its assignments. The Role granting
+	// access to the lease and the assignments is built with the other manifests.
+	if leaderElectionEnabled(params.OtelCol) {
+		taConfig["leader_election"] = map[string]interface{}{
+			"enabled":    true,
+			"lease_name": naming.TargetAllocator(params.OtelCol.Name),
+		}
+	}
 
 	if len(params.OtelCol.Spec.TargetAllocator.FilterStrategy) > 0 {
 		taConfig["filter_strategy"] = params.OtelCol.Spec.TargetAllocator.FilterStrategy
 	}
 
+	if filterConfig := filterConfig(params.OtelCol.Spec.TargetAllocator.Filter); len(filterConfig) > 0 {
+		taConfig["filter"] = filterConfig
+	}
+
 	if params.OtelCol.Spec.TargetAllocator.PrometheusCR.ScrapeInterval.Size() > 0 {
 		prometheusCRConfig["scrape_interval"] = params.OtelCol.Spec.TargetAllocator.PrometheusCR.ScrapeInterval.Duration
 	}
@@ -69,6 +86,14 @@ func ConfigMap(params manifests.Params) (*corev1.ConfigMap, error) {
 		taConfig["pod_monitor_selector"] = &params.OtelCol.Spec.TargetAllocator.PrometheusCR.PodMonitorSelector
 	}
 
+	if params.OtelCol.Spec.TargetAllocator.PrometheusCR.ServiceMonitorNamespaceSelector != nil {
+		taConfig["service_monitor_namespace_selector"] = labelSelectorConfig(params.OtelCol.Spec.TargetAllocator.PrometheusCR.ServiceMonitorNamespaceSelector)
+	}
+
+	if params.OtelCol.Spec.TargetAllocator.PrometheusCR.PodMonitorNamespaceSelector != nil {
+		taConfig["pod_monitor_namespace_selector"] = labelSelectorConfig(params.OtelCol.Spec.TargetAllocator.PrometheusCR.PodMonitorNamespaceSelector)
+	}
+
 	if len(prometheusCRConfig) > 0 {
 		taConfig["prometheus_cr"] = prometheusCRConfig
 	}
@@ -90,3 +115,47 @@ func ConfigMap(params manifests.Params) (*corev1.ConfigMap, error) {
 		},
 	}, nil
 }
+
+// labelSelectorConfig converts the label selector to the keys the target allocator reads it with, its configuration
+// being parsed with the field names of metav1.LabelSelector lowercased.
+func labelSelectorConfig(selector *metav1.LabelSelector) map[interface{}]interface{} {
+	selectorConfig := make(map[interface{}]interface{})
+	if len(selector.MatchLabels) > 0 {
+		selectorConfig["matchlabels"] = selector.MatchLabels
+	}
+	if len(selector.MatchExpressions) > 0 {
+		expressions := make([]map[interface{}]interface{}, 0, len(selector.MatchExpressions))
+		for _, expression := range selector.MatchExpressions {
+			expressionConfig := map[interface{}]interface{}{
+				"key":      expression.Key,
+				"operator": string(expression.Operator),
+			}
+			if len(expression.Values) > 0 {
+				expressionConfig["values"] = expression.Values
+			}
+			expressions = append(expressions, expressionConfig)
+		}
+		selectorConfig["matchexpressions"] = expressions
+	}
+	return selectorConfig
+}
+
+// filterConfig converts the filter to the configuration of the namespace and cardinality-guard filter strategies of
+// the target allocator.
+func filterConfig(filter v1alpha1.AmazonCloudWatchAgentTargetAllocatorFilter) map[interface{}]interface{} {
+	filterConfig := make(map[interface{}]interface{})
+	namespacesConfig := make(map[interface{}]interface{})
+	if len(filter.AllowedNamespaces) > 0 {
+		namespacesConfig["allow"] = filter.AllowedNamespaces
+	}
+	if len(filter.DeniedNamespaces) > 0 {
+		namespacesConfig["deny"] = filter.DeniedNamespaces
+	}
+	if len(namespacesConfig) > 0 {
+		filterConfig["namespaces"] = namespacesConfig
+	}
+	if filter.MaxTargetsPerJob > 0 {
+		filterConfig["max_targets_per_job"] = filter.MaxTargetsPerJob
+	}
+	return filterConfig
+}
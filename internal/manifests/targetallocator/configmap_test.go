@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
 )
@@ -104,6 +105,63 @@ service_monitor_selector:
 		assert.Equal(t, expectedLables, actual.Labels)
 		assert.Equal(t, expectedData, actual.Data)
 
+	})
+	t.Run("should return expected target allocator config map with namespace selectors", func(t *testing.T) {
+		expectedLables["app.kubernetes.io/component"] = "amazon-cloudwatch-agent-target-allocator"
+		expectedLables["app.kubernetes.io/name"] = "my-instance-target-allocator"
+
+		expectedData := map[string]string{
+			"targetallocator.yaml": `allocation_strategy: consistent-hashing
+config:
+  scrape_configs:
+  - job_name: otel-collector
+    scrape_interval: 10s
+    static_configs:
+    - targets:
+      - 0.0.0.0:8888
+      - 0.0.0.0:9999
+label_selector:
+  app.kubernetes.io/component: amazon-cloudwatch-agent
+  app.kubernetes.io/instance: default.my-instance
+  app.kubernetes.io/managed-by: amazon-cloudwatch-agent-operator
+  app.kubernetes.io/part-of: amazon-cloudwatch-agent
+pod_monitor_namespace_selector:
+  matchlabels:
+    team: a
+service_monitor_namespace_selector:
+  matchexpressions:
+  - key: team
+    operator: In
+    values:
+    - a
+    - b
+  - key: restricted
+    operator: DoesNotExist
+`,
+		}
+		instance := collectorInstance()
+		instance.Spec.TargetAllocator.PrometheusCR.PodMonitorNamespaceSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{"team": "a"},
+		}
+		instance.Spec.TargetAllocator.PrometheusCR.ServiceMonitorNamespaceSelector = &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "team", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}},
+				{Key: "restricted", Operator: metav1.LabelSelectorOpDoesNotExist},
+			},
+		}
+		cfg := config.New()
+		params := manifests.Params{
+			OtelCol: instance,
+			Config:  cfg,
+			Log:     logr.Discard(),
+		}
+		actual, err := ConfigMap(params)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "my-instance-target-allocator", actual.Name)
+		assert.Equal(t, expectedLables, actual.Labels)
+		assert.Equal(t, expectedData, actual.Data)
+
 	})
 	t.Run("should return expected target allocator config map with scrape interval set", func(t *testing.T) {
 		expectedLables["app.kubernetes.io/component"] = "amazon-cloudwatch-agent-target-allocator"
@@ -145,5 +203,137 @@ prometheus_cr:
 		assert.Equal(t, expectedData, actual.Data)
 
 	})
+	t.Run("should return expected target allocator config map with allocation strategy set", func(t *testing.T) {
+		expectedLables["app.kubernetes.io/component"] = "amazon-cloudwatch-agent-target-allocator"
+		expectedLables["app.kubernetes.io/name"] = "my-instance-target-allocator"
+
+		expectedData := map[string]string{
+			"targetallocator.yaml": `allocation_strategy: least-weighted
+config:
+  scrape_configs:
+  - job_name: otel-collector
+    scrape_interval: 10s
+    static_configs:
+    - targets:
+      - 0.0.0.0:8888
+      - 0.0.0.0:9999
+label_selector:
+  app.kubernetes.io/component: amazon-cloudwatch-agent
+  app.kubernetes.io/instance: default.my-instance
+  app.kubernetes.io/managed-by: amazon-cloudwatch-agent-operator
+  app.kubernetes.io/part-of: amazon-cloudwatch-agent
+`,
+		}
+
+		collector := collectorInstance()
+		collector.Spec.TargetAllocator.AllocationStrategy = v1alpha1.AmazonCloudWatchAgentTargetAllocatorAllocationStrategyLeastWeighted
+		cfg := config.New()
+		params := manifests.Params{
+			OtelCol: collector,
+			Config:  cfg,
+			Log:     logr.Discard(),
+		}
+		actual, err := ConfigMap(params)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "my-instance-target-allocator", actual.Name)
+		assert.Equal(t, expectedLables, actual.Labels)
+		assert.Equal(t, expectedData, actual.Data)
+
+	})
+	t.Run("should return expected target allocator config map with leader election for several replicas", func(t *testing.T) {
+		expectedLables["app.kubernetes.io/component"] = "amazon-cloudwatch-agent-target-allocator"
+		expectedLables["app.kubernetes.io/name"] = "my-instance-target-allocator"
+
+		expectedData := map[string]string{
+			"targetallocator.yaml": `allocation_strategy: consistent-hashing
+config:
+  scrape_configs:
+  - job_name: otel-collector
+    scrape_interval: 10s
+    static_configs:
+    - targets:
+      - 0.0.0.0:8888
+      - 0.0.0.0:9999
+label_selector:
+  app.kubernetes.io/component: amazon-cloudwatch-agent
+  app.kubernetes.io/instance: default.my-instance
+  app.kubernetes.io/managed-by: amazon-cloudwatch-agent-operator
+  app.kubernetes.io/part-of: amazon-cloudwatch-agent
+leader_election:
+  enabled: true
+  lease_name: my-instance-target-allocator
+`,
+		}
+
+		collector := collectorInstance()
+		replicas := int32(2)
+		collector.Spec.TargetAllocator.Replicas = &replicas
+		cfg := config.New()
+		params := manifests.Params{
+			OtelCol: collector,
+			Config:  cfg,
+			Log:     logr.Discard(),
+		}
+		actual, err := ConfigMap(params)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "my-instance-target-allocator", actual.Name)
+		assert.Equal(t, expectedLables, actual.Labels)
+		assert.Equal(t, expectedData, actual.Data)
+
+	})
+	t.Run("should return expected target allocator config map with filter set", func(t *testing.T) {
+		expectedLables["app.kubernetes.io/component"] = "amazon-cloudwatch-agent-target-allocator"
+		expectedLables["app.kubernetes.io/name"] = "my-instance-target-allocator"
+
+		expectedData := map[string]string{
+			"targetallocator.yaml": `allocation_strategy: consistent-hashing
+config:
+  scrape_configs:
+  - job_name: otel-collector
+    scrape_interval: 10s
+    static_configs:
+    - targets:
+      - 0.0.0.0:8888
+      - 0.0.0.0:9999
+filter:
+  max_targets_per_job: 1000
+  namespaces:
+    allow:
+    - default
+    - monitoring
+    deny:
+    - kube-system
+filter_strategy: namespace,cardinality-guard
+label_selector:
+  app.kubernetes.io/component: amazon-cloudwatch-agent
+  app.kubernetes.io/instance: default.my-instance
+  app.kubernetes.io/managed-by: amazon-cloudwatch-agent-operator
+  app.kubernetes.io/part-of: amazon-cloudwatch-agent
+`,
+		}
+
+		collector := collectorInstance()
+		collector.Spec.TargetAllocator.FilterStrategy = "namespace,cardinality-guard"
+		collector.Spec.TargetAllocator.Filter = v1alpha1.AmazonCloudWatchAgentTargetAllocatorFilter{
+			AllowedNamespaces: []string{"default", "monitoring"},
+			DeniedNamespaces:  []string{"kube-system"},
+			MaxTargetsPerJob:  1000,
+		}
+		cfg := config.New()
+		params := manifests.Params{
+			OtelCol: collector,
+			Config:  cfg,
+			Log:     logr.Discard(),
+		}
+		actual, err := ConfigMap(params)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "my-instance-target-allocator", actual.Name)
+		assert.Equal(t, expectedLables, actual.Labels)
+		assert.Equal(t, expectedData, actual.Data)
+
+	})
 
 }
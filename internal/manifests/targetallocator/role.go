@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package targetallocator
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+)
+
+// leaderElectionEnabled returns whether the TargetAllocator replicas elect the leader allocating the targets, which
+// they do when there are several of them.
+func leaderElectionEnabled(instance v1alpha1.AmazonCloudWatchAgent) bool {
+	return instance.Spec.TargetAllocator.Replicas != nil && *instance.Spec.TargetAllocator.Replicas > 1
+}
+
+// Role returns the role allowing the TargetAllocator replicas to elect their leader with a lease, and to replicate the
+// assignments of the leader with a config map. It returns nil when leader election is disabled.
+func Role(params manifests.Params) *rbacv1.Role {
+	if !leaderElectionEnabled(params.OtelCol) {
+		return nil
+	}
+	name := naming.TargetAllocator(params.OtelCol.Name)
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: params.OtelCol.Namespace,
+			Labels:    Labels(params.OtelCol, name),
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"coordination.k8s.io"},
+				Resources: []string{"leases"},
+				Verbs:     []string{"get", "create", "update"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"configmaps"},
+				Verbs:     []string{"get", "list", "watch", "create", "update"},
+			},
+		},
+	}
+}
+
+// RoleBinding returns the binding of the TargetAllocator Role to its service account. It returns nil when leader
+// election is disabled.
+func RoleBinding(params manifests.Params) *rbacv1.RoleBinding {
+	if !leaderElectionEnabled(params.OtelCol) {
+		return nil
+	}
+	name := naming.TargetAllocator(params.OtelCol.Name)
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: params.OtelCol.Namespace,
+			Labels:    Labels(params.OtelCol, name),
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      ServiceAccountName(params.OtelCol),
+			Namespace: params.OtelCol.Namespace,
+		}},
+	}
+}
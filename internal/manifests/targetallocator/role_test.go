@@ -0,0 +1,47 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package targetallocator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/config"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+)
+
+func TestRole(t *testing.T) {
+	otelcol := collectorInstance()
+	params := manifests.Params{
+		OtelCol: otelcol,
+		Config:  config.New(),
+		Log:     logger,
+	}
+
+	// a single replica doesn't elect a leader
+	assert.Nil(t, Role(params))
+	assert.Nil(t, RoleBinding(params))
+
+	replicas := int32(2)
+	params.OtelCol.Spec.TargetAllocator.Replicas = &replicas
+	params.OtelCol.Spec.TargetAllocator.ServiceAccount = "my-ta-sa"
+
+	role := Role(params)
+	require.NotNil(t, role)
+	assert.Equal(t, "my-instance-target-allocator", role.Name)
+	assert.Equal(t, "default", role.Namespace)
+	assert.Contains(t, role.Rules, rbacv1.PolicyRule{
+		APIGroups: []string{"coordination.k8s.io"},
+		Resources: []string{"leases"},
+		Verbs:     []string{"get", "create", "update"},
+	})
+
+	binding := RoleBinding(params)
+	require.NotNil(t, binding)
+	assert.Equal(t, rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: role.Name}, binding.RoleRef)
+	assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "my-ta-sa", Namespace: "default"}}, binding.Subjects)
+}
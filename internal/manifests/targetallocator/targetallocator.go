@@ -20,6 +20,10 @@ func Build(params manifests.Params) ([]client.Object, error) {
 		manifests.Factory(Deployment),
 		manifests.FactoryWithoutError(ServiceAccount),
 		manifests.FactoryWithoutError(Service),
+		manifests.FactoryWithoutError(Role),
+		manifests.FactoryWithoutError(RoleBinding),
+		manifests.FactoryWithoutError(ClusterRole),
+		manifests.FactoryWithoutError(ClusterRoleBinding),
 	}
 	for _, factory := range resourceFactories {
 		res, err := factory(params)
@@ -29,5 +33,12 @@ func Build(params manifests.Params) ([]client.Object, error) {
 			resourceManifests = append(resourceManifests, res)
 		}
 	}
+	bindings, err := CredentialsRoleBindings(params)
+	if err != nil {
+		return nil, err
+	}
+	for _, binding := range bindings {
+		resourceManifests = append(resourceManifests, binding)
+	}
 	return resourceManifests, nil
 }
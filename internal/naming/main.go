@@ -87,6 +87,24 @@ func TargetAllocator(otelcol string) string {
 	return DNSName(Truncate("%s-target-allocator", 63, otelcol))
 }
 
+// TAClusterRole returns the name of the TargetAllocator cluster role and cluster role binding, unique across the
+// namespaces.
+func TAClusterRole(otelcol string, namespace string) string {
+	return DNSName(Truncate("%s-%s-target-allocator", 63, otelcol, namespace))
+}
+
+// TACredentialsClusterRole returns the name of the cluster role deployed with the operator allowing to read the
+// credentials of the monitors, which the TargetAllocators are bound to in the namespaces of their monitors.
+func TACredentialsClusterRole() string {
+	return "cloudwatch-target-allocator-credentials-role"
+}
+
+// TACredentialsRoleBinding returns the name of the role bindings of the TargetAllocator to the credentials cluster
+// role, unique across the TargetAllocators binding it in the same namespace.
+func TACredentialsRoleBinding(otelcol string, namespace string) string {
+	return DNSName(Truncate("%s-%s-target-allocator-credentials", 63, otelcol, namespace))
+}
+
 // HeadlessService builds the name for the headless service based on the instance.
 func HeadlessService(otelcol string) string {
 	return DNSName(Truncate("%s-headless", 63, Service(otelcol)))
@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package acceleratorexporter
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/acceleratorexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/status/conditions"
+)
+
+// UpdateStatus sets the rollout progress and conditions of the accelerator exporter from its daemonSet and the error
+// of the reconcile, a conditions.NotDeployedError if the exporter is not deployed on purpose.
+func UpdateStatus(ctx context.Context, cli client.Client, kind acceleratorexporter.Kind, changed v1alpha1.AcceleratorExporter, reconcileErr error) error {
+	status := changed.GetAcceleratorExporterStatus()
+	if status.Version == "" {
+		status.Version = kind.Version()
+	}
+
+	var rollout *v1alpha1.RolloutStatus
+	var rolloutStale bool
+	daemonSet := &appsv1.DaemonSet{}
+	objKey := client.ObjectKey{Namespace: changed.GetNamespace(), Name: naming.Collector(changed.GetName())}
+	if err := cli.Get(ctx, objKey, daemonSet); err == nil {
+		rollout = conditions.Rollout(daemonSet)
+		rolloutStale = conditions.Stale(daemonSet)
+		status.Image = daemonSet.Spec.Template.Spec.Containers[0].Image
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get daemonSet %s: %w", objKey.Name, err)
+	}
+
+	status.ObservedGeneration = changed.GetGeneration()
+	status.Rollout = rollout
+	status.LastReconcileError = ""
+	if reconcileErr != nil && !conditions.IsNotDeployed(reconcileErr) {
+		status.LastReconcileError = reconcileErr.Error()
+	}
+	conditions.Set(&status.Conditions, conditions.Observed{
+		Generation:   changed.GetGeneration(),
+		ReconcileErr: reconcileErr,
+		Rollout:      rollout,
+		RolloutStale: rolloutStale,
+	})
+	changed.SetAcceleratorExporterStatus(status)
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package acceleratorexporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/acceleratorexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/status/conditions"
+)
+
+const (
+	eventTypeNormal  = "Normal"
+	eventTypeWarning = "Warning"
+
+	reasonError         = "Error"
+	reasonStatusFailure = "StatusFailure"
+	reasonInfo          = "Info"
+)
+
+// HandleReconcileStatus reports the outcome of the reconcile on the status of the accelerator exporter instance, and
+// returns the error of the reconcile. A conditions.NotDeployedError is only reported, not returned.
+func HandleReconcileStatus(ctx context.Context, log logr.Logger, params manifests.Params, kind acceleratorexporter.Kind, instance v1alpha1.AcceleratorExporter, err error) (ctrl.Result, error) {
+	log.V(2).Info("updating accelerator exporter status", "kind", kind.Name)
+	reconcileErr := err
+	if conditions.IsNotDeployed(err) {
+		err = nil
+	}
+	if err != nil {
+		params.Recorder.Event(instance, eventTypeWarning, reasonError, err.Error())
+	}
+	changed := instance.DeepCopyObject().(v1alpha1.AcceleratorExporter)
+	statusErr := UpdateStatus(ctx, params.Client, kind, changed, reconcileErr)
+	if statusErr != nil {
+		params.Recorder.Event(changed, eventTypeWarning, reasonStatusFailure, statusErr.Error())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, statusErr
+	}
+	statusPatch := client.MergeFrom(instance)
+	if patchErr := params.Client.Status().Patch(ctx, changed, statusPatch); patchErr != nil {
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to apply status changes to the %s CR: %w", kind.Name, patchErr)
+	}
+	if err != nil {
+		// the conditions report the error, which is returned to retry the reconcile
+		return ctrl.Result{}, err
+	}
+	params.Recorder.Event(changed, eventTypeNormal, reasonInfo, "applied status changes")
+	return ctrl.Result{}, nil
+}
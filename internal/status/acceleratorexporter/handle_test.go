@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package acceleratorexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/dcgmexporter"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/neuronmonitor"
+)
+
+func TestHandleReconcileStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	exporter := &v1alpha1.DcgmExporter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dcgm-exporter", Namespace: "amazon-cloudwatch", Generation: 3},
+	}
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "dcgm-exporter", Namespace: "amazon-cloudwatch"},
+		Spec: appsv1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: "dcgm-exporter:3.3"}}},
+			},
+		},
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 2, UpdatedNumberScheduled: 2, NumberReady: 2},
+	}
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(exporter, daemonSet).
+		WithStatusSubresource(exporter).
+		Build()
+	params := manifests.Params{
+		Client:   cli,
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	_, err := HandleReconcileStatus(ctx, logr.Discard(), params, dcgmexporter.Kind, exporter, nil)
+	require.NoError(t, err)
+	var got v1alpha1.DcgmExporter
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(exporter), &got))
+	assert.Equal(t, int64(3), got.Status.ObservedGeneration)
+	assert.Equal(t, "dcgm-exporter:3.3", got.Status.Image)
+	assert.NotEmpty(t, got.Status.Version)
+	assert.Equal(t, &v1alpha1.RolloutStatus{Desired: 2, Updated: 2, Ready: 2}, got.Status.Rollout)
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, v1alpha1.ConditionTypeReady))
+
+	// the reconcile error is reported on the status, then returned
+	reconcileErr := errors.New("failed to create objects")
+	_, err = HandleReconcileStatus(ctx, logr.Discard(), params, dcgmexporter.Kind, &got, reconcileErr)
+	assert.Equal(t, reconcileErr, err)
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(exporter), &got))
+	assert.Equal(t, "failed to create objects", got.Status.LastReconcileError)
+	assert.True(t, meta.IsStatusConditionFalse(got.Status.Conditions, v1alpha1.ConditionTypeReconciled))
+}
+
+func TestUpdateStatusDaemonSetNotFound(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+
+	monitor := &v1alpha1.NeuronMonitor{
+		ObjectMeta: metav1.ObjectMeta{Name: "neuron-monitor", Namespace: "amazon-cloudwatch"},
+		Status:     v1alpha1.NeuronMonitorStatus{Version: "1.0"},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	require.NoError(t, UpdateStatus(context.Background(), cli, neuronmonitor.Kind, monitor, nil))
+	assert.Equal(t, "1.0", monitor.Status.Version)
+	assert.Nil(t, monitor.Status.Rollout)
+	assert.True(t, meta.IsStatusConditionFalse(monitor.Status.Conditions, v1alpha1.ConditionTypeReady))
+}
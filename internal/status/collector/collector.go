@@ -9,6 +9,7 @@ import (
 	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -16,18 +17,77 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/collector"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests/manifestutils"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/status/conditions"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/version"
 )
 
-func UpdateCollectorStatus(ctx context.Context, cli client.Client, changed *v1alpha1.AmazonCloudWatchAgent) error {
+// UpdateCollectorStatus sets the scale, rollout progress and conditions of the AmazonCloudWatchAgent from its workload
+// and the error of the reconcile.
+func UpdateCollectorStatus(ctx context.Context, cli client.Client, changed *v1alpha1.AmazonCloudWatchAgent, reconcileErr error) error {
 	if changed.Status.Version == "" {
 		// a version is not set, otherwise let the upgrade mechanism take care of it!
 		changed.Status.Version = version.AmazonCloudWatchAgent()
 	}
+
+	workload, err := getWorkload(ctx, cli, changed)
+	if err != nil {
+		return err
+	}
+	if err := updateScale(changed, workload); err != nil {
+		return err
+	}
+
+	changed.Status.ObservedGeneration = changed.Generation
+	changed.Status.Rollout = conditions.Rollout(workload)
+	changed.Status.LastReconcileError = ""
+	if reconcileErr != nil {
+		changed.Status.LastReconcileError = reconcileErr.Error()
+	}
+	conditions.Set(&changed.Status.Conditions, conditions.Observed{
+		Generation:   changed.Generation,
+		ReconcileErr: reconcileErr,
+		NoWorkload:   changed.Spec.Mode == v1alpha1.ModeSidecar,
+		Rollout:      changed.Status.Rollout,
+		RolloutStale: conditions.Stale(workload),
+	})
+	return nil
+}
+
+// getWorkload returns the deployment, daemonSet or statefulSet of the collector, nil if it is not found or the
+// collector runs as a sidecar.
+func getWorkload(ctx context.Context, cli client.Client, changed *v1alpha1.AmazonCloudWatchAgent) (client.Object, error) {
+	var obj client.Object
+	switch changed.Spec.Mode { // nolint:exhaustive
+	case v1alpha1.ModeDeployment:
+		obj = &appsv1.Deployment{}
+	case v1alpha1.ModeStatefulSet:
+		obj = &appsv1.StatefulSet{}
+	case v1alpha1.ModeDaemonSet:
+		obj = &appsv1.DaemonSet{}
+	default:
+		return nil, nil
+	}
+	objKey := client.ObjectKey{
+		Namespace: changed.GetNamespace(),
+		Name:      naming.Collector(changed.Name),
+	}
+	if err := cli.Get(ctx, objKey, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s %s: %w", changed.Spec.Mode, objKey.Name, err)
+	}
+	return obj, nil
+}
+
+func updateScale(changed *v1alpha1.AmazonCloudWatchAgent, workload client.Object) error {
 	mode := changed.Spec.Mode
 	if mode != v1alpha1.ModeDeployment && mode != v1alpha1.ModeStatefulSet {
 		changed.Status.Scale.Replicas = 0
 		changed.Status.Scale.Selector = ""
+		if daemonSet, ok := workload.(*appsv1.DaemonSet); ok {
+			changed.Status.Image = daemonSet.Spec.Template.Spec.Containers[0].Image
+		}
 		return nil
 	}
 
@@ -42,43 +102,27 @@ func UpdateCollectorStatus(ctx context.Context, cli client.Client, changed *v1al
 	changed.Status.Scale.Selector = selector.String()
 
 	// Set the scale replicas
-	objKey := client.ObjectKey{
-		Namespace: changed.GetNamespace(),
-		Name:      naming.Collector(changed.Name),
-	}
-
 	var replicas int32
 	var readyReplicas int32
 	var statusReplicas string
 	var statusImage string
 
-	switch mode { // nolint:exhaustive
-	case v1alpha1.ModeDeployment:
-		obj := &appsv1.Deployment{}
-		if err := cli.Get(ctx, objKey, obj); err != nil {
-			return fmt.Errorf("failed to get deployment status.replicas: %w", err)
-		}
+	switch obj := workload.(type) {
+	case *appsv1.Deployment:
 		replicas = obj.Status.Replicas
 		readyReplicas = obj.Status.ReadyReplicas
 		statusReplicas = strconv.Itoa(int(readyReplicas)) + "/" + strconv.Itoa(int(replicas))
 		statusImage = obj.Spec.Template.Spec.Containers[0].Image
 
-	case v1alpha1.ModeStatefulSet:
-		obj := &appsv1.StatefulSet{}
-		if err := cli.Get(ctx, objKey, obj); err != nil {
-			return fmt.Errorf("failed to get statefulSet status.replicas: %w", err)
-		}
+	case *appsv1.StatefulSet:
 		replicas = obj.Status.Replicas
 		readyReplicas = obj.Status.ReadyReplicas
 		statusReplicas = strconv.Itoa(int(readyReplicas)) + "/" + strconv.Itoa(int(replicas))
 		statusImage = obj.Spec.Template.Spec.Containers[0].Image
 
-	case v1alpha1.ModeDaemonSet:
-		obj := &appsv1.DaemonSet{}
-		if err := cli.Get(ctx, objKey, obj); err != nil {
-			return fmt.Errorf("failed to get daemonSet status.replicas: %w", err)
-		}
-		statusImage = obj.Spec.Template.Spec.Containers[0].Image
+	default:
+		// the workload is not created yet
+		return nil
 	}
 	changed.Status.Scale.Replicas = replicas
 	changed.Status.Image = statusImage
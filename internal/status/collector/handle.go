@@ -23,23 +23,32 @@ const (
 	reasonInfo          = "Info"
 )
 
-// HandleReconcileStatus handles updating the status of the CRDs managed by the operator.
-// TODO: make the status more useful https://github.com/open-telemetry/opentelemetry-operator/issues/1972
+// HandleReconcileStatus handles updating the status of the CRDs managed by the operator. The conditions of the status
+// report the error of the reconcile, if any, which is returned after the status is patched.
 func HandleReconcileStatus(ctx context.Context, log logr.Logger, params manifests.Params, err error) (ctrl.Result, error) {
 	log.V(2).Info("updating collector status")
 	if err != nil {
 		params.Recorder.Event(&params.OtelCol, eventTypeWarning, reasonError, err.Error())
-		return ctrl.Result{}, err
 	}
 	changed := params.OtelCol.DeepCopy()
-	statusErr := UpdateCollectorStatus(ctx, params.Client, changed)
+	statusErr := UpdateCollectorStatus(ctx, params.Client, changed, err)
 	if statusErr != nil {
 		params.Recorder.Event(changed, eventTypeWarning, reasonStatusFailure, statusErr.Error())
+		if err != nil {
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{}, statusErr
 	}
 	statusPatch := client.MergeFrom(&params.OtelCol)
-	if err := params.Client.Status().Patch(ctx, changed, statusPatch); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to apply status changes to the AmazonCloudWatchAgent CR: %w", err)
+	if patchErr := params.Client.Status().Patch(ctx, changed, statusPatch); patchErr != nil {
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to apply status changes to the AmazonCloudWatchAgent CR: %w", patchErr)
+	}
+	if err != nil {
+		// the conditions report the error, which is returned to retry the reconcile
+		return ctrl.Result{}, err
 	}
 	params.Recorder.Event(changed, eventTypeNormal, reasonInfo, "applied status changes")
 	return ctrl.Result{}, nil
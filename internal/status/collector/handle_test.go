@@ -0,0 +1,99 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/manifests"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/status/conditions"
+)
+
+func TestHandleReconcileStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", Generation: 2},
+		Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Mode: v1alpha1.ModeDeployment},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To(int32(2)),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: "cloudwatch-agent:1.0"}}},
+			},
+		},
+		Status: appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 1},
+	}
+	cli := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(agent, deployment).
+		WithStatusSubresource(agent).
+		Build()
+	params := manifests.Params{
+		Client:   cli,
+		OtelCol:  *agent,
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	_, err := HandleReconcileStatus(ctx, logr.Discard(), params, nil)
+	require.NoError(t, err)
+	var got v1alpha1.AmazonCloudWatchAgent
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(agent), &got))
+	assert.Equal(t, int64(2), got.Status.ObservedGeneration)
+	assert.Equal(t, &v1alpha1.RolloutStatus{Desired: 2, Updated: 2, Ready: 1}, got.Status.Rollout)
+	assert.Equal(t, "1/2", got.Status.Scale.StatusReplicas)
+	assert.Equal(t, "cloudwatch-agent:1.0", got.Status.Image)
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, v1alpha1.ConditionTypeReconciled))
+	assert.True(t, meta.IsStatusConditionFalse(got.Status.Conditions, v1alpha1.ConditionTypeReady))
+	assert.Empty(t, got.Status.LastReconcileError)
+
+	// the reconcile error is reported on the status, then returned
+	params.OtelCol = got
+	reconcileErr := conditions.NewConfigError(errors.New("invalid agent config"))
+	_, err = HandleReconcileStatus(ctx, logr.Discard(), params, reconcileErr)
+	assert.Equal(t, reconcileErr, err)
+	require.NoError(t, cli.Get(ctx, client.ObjectKeyFromObject(agent), &got))
+	assert.Equal(t, "invalid agent config", got.Status.LastReconcileError)
+	assert.True(t, meta.IsStatusConditionFalse(got.Status.Conditions, v1alpha1.ConditionTypeConfigValid))
+	assert.True(t, meta.IsStatusConditionTrue(got.Status.Conditions, v1alpha1.ConditionTypeDegraded))
+}
+
+func TestUpdateCollectorStatusWorkloadNotFound(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+		Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Mode: v1alpha1.ModeDaemonSet},
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	require.NoError(t, UpdateCollectorStatus(context.Background(), cli, agent, nil))
+	assert.Nil(t, agent.Status.Rollout)
+	condition := meta.FindStatusCondition(agent.Status.Conditions, v1alpha1.ConditionTypeReady)
+	require.NotNil(t, condition)
+	assert.Equal(t, conditions.ReasonWorkloadNotFound, condition.Reason)
+}
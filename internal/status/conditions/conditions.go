@@ -0,0 +1,203 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package conditions sets the status conditions shared by the AmazonCloudWatchAgent, DcgmExporter and NeuronMonitor.
+package conditions
+
+import (
+	"errors"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	ReasonReconcileSucceeded = "ReconcileSucceeded"
+	ReasonReconcileFailed    = "ReconcileFailed"
+	ReasonValidConfig        = "ValidConfig"
+	ReasonInvalidConfig      = "InvalidConfig"
+	ReasonRolloutComplete    = "RolloutComplete"
+	ReasonRolloutInProgress  = "RolloutInProgress"
+	ReasonWorkloadNotFound   = "WorkloadNotFound"
+	ReasonNoWorkload         = "NoWorkload"
+	ReasonPodsNotReady       = "PodsNotReady"
+	ReasonAsExpected         = "AsExpected"
+	ReasonDisabled           = "Disabled"
+	ReasonNoAcceleratorNodes = "NoAcceleratorNodes"
+)
+
+// ConfigError is a reconcile error caused by a spec the manifests of the operand cannot be built from.
+type ConfigError struct {
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// NewConfigError wraps the error of building the manifests of the operand, reporting the config as invalid.
+func NewConfigError(err error) error {
+	return &ConfigError{Err: err}
+}
+
+// NotDeployedError is a reconcile outcome where the operand is not deployed on purpose, e.g. because it is disabled.
+// It does not fail the reconcile.
+type NotDeployedError struct {
+	Reason  string
+	Message string
+}
+
+func (e *NotDeployedError) Error() string {
+	return e.Message
+}
+
+// NewNotDeployedError reports the operand as not deployed for the reason.
+func NewNotDeployedError(reason, message string) error {
+	return &NotDeployedError{Reason: reason, Message: message}
+}
+
+// IsNotDeployed tells the reconcile outcome is a NotDeployedError.
+func IsNotDeployed(err error) bool {
+	var notDeployedErr *NotDeployedError
+	return errors.As(err, &notDeployedErr)
+}
+
+// Observed is the state the conditions are set from.
+type Observed struct {
+	// Generation is the generation of the reconciled resource.
+	Generation int64
+	// ReconcileErr is the error of the reconcile, a ConfigError if the spec is invalid or a NotDeployedError if the
+	// operand is not deployed on purpose.
+	ReconcileErr error
+	// NoWorkload tells the operand has no workload of its own to roll out, e.g. in sidecar mode.
+	NoWorkload bool
+	// Rollout is the rollout progress of the workload, nil if it is not found.
+	Rollout *v1alpha1.RolloutStatus
+	// RolloutStale tells the workload controller has not observed the latest spec of the workload yet, so the
+	// rollout progress is the one of the previous spec.
+	RolloutStale bool
+}
+
+// Rollout returns the rollout progress of the Deployment, StatefulSet or DaemonSet, nil for other objects.
+func Rollout(obj client.Object) *v1alpha1.RolloutStatus {
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		desired := int32(1)
+		if workload.Spec.Replicas != nil {
+			desired = *workload.Spec.Replicas
+		}
+		return &v1alpha1.RolloutStatus{
+			Desired: desired,
+			Updated: workload.Status.UpdatedReplicas,
+			Ready:   workload.Status.ReadyReplicas,
+		}
+	case *appsv1.StatefulSet:
+		desired := int32(1)
+		if workload.Spec.Replicas != nil {
+			desired = *workload.Spec.Replicas
+		}
+		return &v1alpha1.RolloutStatus{
+			Desired: desired,
+			Updated: workload.Status.UpdatedReplicas,
+			Ready:   workload.Status.ReadyReplicas,
+		}
+	case *appsv1.DaemonSet:
+		return &v1alpha1.RolloutStatus{
+			Desired: workload.Status.DesiredNumberScheduled,
+			Updated: workload.Status.UpdatedNumberScheduled,
+			Ready:   workload.Status.NumberReady,
+		}
+	}
+	return nil
+}
+
+// Stale tells the observed generation of the Deployment, StatefulSet or DaemonSet is behind its generation, false for
+// other objects.
+func Stale(obj client.Object) bool {
+	var observedGeneration int64
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		observedGeneration = workload.Status.ObservedGeneration
+	case *appsv1.StatefulSet:
+		observedGeneration = workload.Status.ObservedGeneration
+	case *appsv1.DaemonSet:
+		observedGeneration = workload.Status.ObservedGeneration
+	default:
+		return false
+	}
+	return observedGeneration < obj.GetGeneration()
+}
+
+// Set sets the Reconciled, ConfigValid, Ready and Degraded conditions from the observed state.
+func Set(conditions *[]metav1.Condition, observed Observed) {
+	set := func(conditionType string, status bool, reason, message string) {
+		condition := metav1.Condition{
+			Type:               conditionType,
+			Status:             metav1.ConditionFalse,
+			Reason:             reason,
+			Message:            message,
+			ObservedGeneration: observed.Generation,
+		}
+		if status {
+			condition.Status = metav1.ConditionTrue
+		}
+		meta.SetStatusCondition(conditions, condition)
+	}
+
+	var configErr *ConfigError
+	var notDeployedErr *NotDeployedError
+	switch {
+	case errors.As(observed.ReconcileErr, &notDeployedErr):
+		set(v1alpha1.ConditionTypeConfigValid, true, ReasonValidConfig, "the manifests are built from the spec")
+		set(v1alpha1.ConditionTypeReconciled, true, ReasonReconcileSucceeded, "the objects of the operand are deleted")
+		set(v1alpha1.ConditionTypeReady, false, notDeployedErr.Reason, notDeployedErr.Message)
+		set(v1alpha1.ConditionTypeDegraded, false, ReasonAsExpected, "")
+		return
+	case errors.As(observed.ReconcileErr, &configErr):
+		set(v1alpha1.ConditionTypeConfigValid, false, ReasonInvalidConfig, configErr.Error())
+		set(v1alpha1.ConditionTypeReconciled, false, ReasonInvalidConfig, "the manifests cannot be built from the spec")
+		set(v1alpha1.ConditionTypeReady, false, ReasonInvalidConfig, "the manifests cannot be built from the spec")
+		set(v1alpha1.ConditionTypeDegraded, true, ReasonInvalidConfig, configErr.Error())
+		return
+	case observed.ReconcileErr != nil:
+		set(v1alpha1.ConditionTypeConfigValid, true, ReasonValidConfig, "the manifests are built from the spec")
+		set(v1alpha1.ConditionTypeReconciled, false, ReasonReconcileFailed, observed.ReconcileErr.Error())
+		set(v1alpha1.ConditionTypeReady, false, ReasonReconcileFailed, "the last reconcile failed")
+		set(v1alpha1.ConditionTypeDegraded, true, ReasonReconcileFailed, observed.ReconcileErr.Error())
+		return
+	}
+	set(v1alpha1.ConditionTypeConfigValid, true, ReasonValidConfig, "the manifests are built from the spec")
+	set(v1alpha1.ConditionTypeReconciled, true, ReasonReconcileSucceeded, "the manifests are applied")
+
+	rollout := observed.Rollout
+	switch {
+	case observed.NoWorkload:
+		set(v1alpha1.ConditionTypeReady, true, ReasonNoWorkload, "the operand has no workload to roll out")
+		set(v1alpha1.ConditionTypeDegraded, false, ReasonAsExpected, "")
+	case rollout == nil:
+		set(v1alpha1.ConditionTypeReady, false, ReasonWorkloadNotFound, "the workload is not created yet")
+		set(v1alpha1.ConditionTypeDegraded, false, ReasonAsExpected, "")
+	case observed.RolloutStale:
+		set(v1alpha1.ConditionTypeReady, false, ReasonRolloutInProgress, "the latest spec of the workload is not observed yet")
+		set(v1alpha1.ConditionTypeDegraded, false, ReasonAsExpected, "")
+	case rollout.Updated < rollout.Desired:
+		set(v1alpha1.ConditionTypeReady, false, ReasonRolloutInProgress, fmt.Sprintf("%d/%d pods updated", rollout.Updated, rollout.Desired))
+		set(v1alpha1.ConditionTypeDegraded, false, ReasonAsExpected, "")
+	case rollout.Ready < rollout.Desired:
+		message := fmt.Sprintf("%d/%d pods ready", rollout.Ready, rollout.Desired)
+		set(v1alpha1.ConditionTypeReady, false, ReasonPodsNotReady, message)
+		set(v1alpha1.ConditionTypeDegraded, true, ReasonPodsNotReady, message)
+	default:
+		set(v1alpha1.ConditionTypeReady, true, ReasonRolloutComplete, fmt.Sprintf("%d/%d pods ready", rollout.Ready, rollout.Desired))
+		set(v1alpha1.ConditionTypeDegraded, false, ReasonAsExpected, "")
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package conditions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+func TestRollout(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		Spec:   appsv1.DeploymentSpec{Replicas: ptr.To(int32(3))},
+		Status: appsv1.DeploymentStatus{UpdatedReplicas: 2, ReadyReplicas: 1},
+	}
+	assert.Equal(t, &v1alpha1.RolloutStatus{Desired: 3, Updated: 2, Ready: 1}, Rollout(deployment))
+
+	statefulSet := &appsv1.StatefulSet{
+		Status: appsv1.StatefulSetStatus{UpdatedReplicas: 1, ReadyReplicas: 1},
+	}
+	assert.Equal(t, &v1alpha1.RolloutStatus{Desired: 1, Updated: 1, Ready: 1}, Rollout(statefulSet))
+
+	daemonSet := &appsv1.DaemonSet{
+		Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 4, UpdatedNumberScheduled: 4, NumberReady: 3},
+	}
+	assert.Equal(t, &v1alpha1.RolloutStatus{Desired: 4, Updated: 4, Ready: 3}, Rollout(daemonSet))
+
+	assert.Nil(t, Rollout(nil))
+}
+
+func TestStale(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 1},
+	}
+	assert.True(t, Stale(deployment))
+
+	deployment.Status.ObservedGeneration = 2
+	assert.False(t, Stale(deployment))
+
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Generation: 5},
+		Status:     appsv1.DaemonSetStatus{ObservedGeneration: 4},
+	}
+	assert.True(t, Stale(daemonSet))
+
+	assert.False(t, Stale(nil))
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		observed Observed
+		// want maps the condition types to their expected status and reason
+		want map[string][2]string
+	}{
+		{
+			name:     "rollout complete",
+			observed: Observed{Rollout: &v1alpha1.RolloutStatus{Desired: 2, Updated: 2, Ready: 2}},
+			want: map[string][2]string{
+				v1alpha1.ConditionTypeReady:       {"True", ReasonRolloutComplete},
+				v1alpha1.ConditionTypeReconciled:  {"True", ReasonReconcileSucceeded},
+				v1alpha1.ConditionTypeConfigValid: {"True", ReasonValidConfig},
+				v1alpha1.ConditionTypeDegraded:    {"False", ReasonAsExpected},
+			},
+		},
+		{
+			name:     "rollout in progress",
+			observed: Observed{Rollout: &v1alpha1.RolloutStatus{Desired: 2, Updated: 1, Ready: 2}},
+			want: map[string][2]string{
+				v1alpha1.ConditionTypeReady:    {"False", ReasonRolloutInProgress},
+				v1alpha1.ConditionTypeDegraded: {"False", ReasonAsExpected},
+			},
+		},
+		{
+			name:     "rollout not observed",
+			observed: Observed{Rollout: &v1alpha1.RolloutStatus{Desired: 2, Updated: 2, Ready: 2}, RolloutStale: true},
+			want: map[string][2]string{
+				v1alpha1.ConditionTypeReady:    {"False", ReasonRolloutInProgress},
+				v1alpha1.ConditionTypeDegraded: {"False", ReasonAsExpected},
+			},
+		},
+		{
+			name:     "pods not ready",
+			observed: Observed{Rollout: &v1alpha1.RolloutStatus{Desired: 2, Updated: 2, Ready: 1}},
+			want: map[string][2]string{
+				v1alpha1.ConditionTypeReady:    {"False", ReasonPodsNotReady},
+				v1alpha1.ConditionTypeDegraded: {"True", ReasonPodsNotReady},
+			},
+		},
+		{
+			name:     "workload not found",
+			observed: Observed{},
+			want: map[string][2]string{
+				v1alpha1.ConditionTypeReady:      {"False", ReasonWorkloadNotFound},
+				v1alpha1.ConditionTypeReconciled: {"True", ReasonReconcileSucceeded},
+			},
+		},
+		{
+			name:     "no workload",
+			observed: Observed{NoWorkload: true},
+			want: map[string][2]string{
+				v1alpha1.ConditionTypeReady: {"True", ReasonNoWorkload},
+			},
+		},
+		{
+			name:     "reconcile failed",
+			observed: Observed{ReconcileErr: errors.New("failed to apply"), Rollout: &v1alpha1.RolloutStatus{Desired: 1, Updated: 1, Ready: 1}},
+			want: map[string][2]string{
+				v1alpha1.ConditionTypeReady:       {"False", ReasonReconcileFailed},
+				v1alpha1.ConditionTypeReconciled:  {"False", ReasonReconcileFailed},
+				v1alpha1.ConditionTypeConfigValid: {"True", ReasonValidConfig},
+				v1alpha1.ConditionTypeDegraded:    {"True", ReasonReconcileFailed},
+			},
+		},
+		{
+			name:     "not deployed",
+			observed: Observed{ReconcileErr: NewNotDeployedError(ReasonDisabled, "the exporter is disabled")},
+			want: map[string][2]string{
+				v1alpha1.ConditionTypeReady:       {"False", ReasonDisabled},
+				v1alpha1.ConditionTypeReconciled:  {"True", ReasonReconcileSucceeded},
+				v1alpha1.ConditionTypeConfigValid: {"True", ReasonValidConfig},
+				v1alpha1.ConditionTypeDegraded:    {"False", ReasonAsExpected},
+			},
+		},
+		{
+			name:     "invalid config",
+			observed: Observed{ReconcileErr: NewConfigError(errors.New("invalid agent config"))},
+			want: map[string][2]string{
+				v1alpha1.ConditionTypeReady:       {"False", ReasonInvalidConfig},
+				v1alpha1.ConditionTypeReconciled:  {"False", ReasonInvalidConfig},
+				v1alpha1.ConditionTypeConfigValid: {"False", ReasonInvalidConfig},
+				v1alpha1.ConditionTypeDegraded:    {"True", ReasonInvalidConfig},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conditions []metav1.Condition
+			tt.observed.Generation = 3
+			Set(&conditions, tt.observed)
+			assert.Len(t, conditions, 4)
+			for conditionType, want := range tt.want {
+				condition := meta.FindStatusCondition(conditions, conditionType)
+				require.NotNil(t, condition, conditionType)
+				assert.Equal(t, want[0], string(condition.Status), conditionType)
+				assert.Equal(t, want[1], condition.Reason, conditionType)
+				assert.Equal(t, int64(3), condition.ObservedGeneration, conditionType)
+			}
+		})
+	}
+}
+
+func TestSetInvalidConfigMessage(t *testing.T) {
+	var conditions []metav1.Condition
+	Set(&conditions, Observed{ReconcileErr: NewConfigError(errors.New("invalid agent config"))})
+	assert.Equal(t, "invalid agent config", meta.FindStatusCondition(conditions, v1alpha1.ConditionTypeConfigValid).Message)
+
+	// the condition is updated once the config is fixed
+	Set(&conditions, Observed{NoWorkload: true})
+	assert.True(t, meta.IsStatusConditionTrue(conditions, v1alpha1.ConditionTypeConfigValid))
+	assert.True(t, meta.IsStatusConditionTrue(conditions, v1alpha1.ConditionTypeReady))
+}
@@ -0,0 +1,170 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
+
+// maxReportedWorkloads bounds the size of the workload list in the status. The counters always cover all workloads.
+const maxReportedWorkloads = 256
+
+// statePriority orders the workloads in the status so the ones needing attention are reported first.
+var statePriority = map[v1alpha1.InjectionState]int{
+	v1alpha1.InjectionStateFailed:   0,
+	v1alpha1.InjectionStateSkipped:  1,
+	v1alpha1.InjectionStateInjected: 2,
+}
+
+type workloadID struct {
+	kind      string
+	namespace string
+	name      string
+	language  string
+}
+
+type workloadKey struct {
+	kind      string
+	namespace string
+	name      string
+	language  string
+	state     v1alpha1.InjectionState
+}
+
+// UpdateInstrumentationStatus computes the injection coverage of the Instrumentation from the outcomes recorded on
+// the pods. Pods that requested the single Instrumentation of their namespace without recording which one was used
+// are only accounted for when includeUnresolved is set. Returns whether the status changed.
+func UpdateInstrumentationStatus(changed *v1alpha1.Instrumentation, pods []corev1.Pod, includeUnresolved bool) bool {
+	ref := types.NamespacedName{Namespace: changed.Namespace, Name: changed.Name}.String()
+	unresolvedRef := types.NamespacedName{Namespace: changed.Namespace, Name: instrumentation.UnresolvedInstrumentationName}.String()
+
+	workloads := map[workloadKey]*v1alpha1.WorkloadInstrumentationStatus{}
+	for _, pod := range pods {
+		kind, name := workloadOf(pod)
+		for _, outcome := range instrumentation.InjectionOutcomes(pod) {
+			matches := outcome.Instrumentation == ref || (includeUnresolved && outcome.Instrumentation == unresolvedRef)
+			if !matches {
+				continue
+			}
+			key := workloadKey{kind: kind, namespace: pod.Namespace, name: name, language: outcome.Language, state: outcome.State}
+			workload, ok := workloads[key]
+			if !ok {
+				workload = &v1alpha1.WorkloadInstrumentationStatus{
+					Kind:      kind,
+					Namespace: pod.Namespace,
+					Name:      name,
+					Language:  outcome.Language,
+					State:     outcome.State,
+					Reason:    outcome.Reason,
+				}
+				workloads[key] = workload
+			}
+			workload.Pods++
+		}
+	}
+
+	// a workload is counted once, in its worst state, overall and per language
+	overall := map[workloadID]v1alpha1.InjectionState{}
+	perLanguage := map[workloadID]v1alpha1.InjectionState{}
+	status := v1alpha1.InstrumentationStatus{}
+	for key, workload := range workloads {
+		id := workloadID{kind: key.kind, namespace: key.namespace, name: key.name}
+		overall[id] = worstState(overall[id], workload.State)
+		id.language = key.language
+		perLanguage[id] = worstState(perLanguage[id], workload.State)
+		status.Workloads = append(status.Workloads, *workload)
+	}
+	for _, state := range overall {
+		count(state, &status.Injected, &status.Skipped, &status.Failed)
+	}
+	languages := map[string]*v1alpha1.LanguageCoverage{}
+	for id, state := range perLanguage {
+		coverage, ok := languages[id.language]
+		if !ok {
+			coverage = &v1alpha1.LanguageCoverage{Language: id.language}
+			languages[id.language] = coverage
+		}
+		count(state, &coverage.Injected, &coverage.Skipped, &coverage.Failed)
+	}
+	for _, coverage := range languages {
+		status.Languages = append(status.Languages, *coverage)
+	}
+	sort.Slice(status.Languages, func(i, j int) bool {
+		return status.Languages[i].Language < status.Languages[j].Language
+	})
+	sort.Slice(status.Workloads, func(i, j int) bool {
+		a, b := status.Workloads[i], status.Workloads[j]
+		if a.State != b.State {
+			return statePriority[a.State] < statePriority[b.State]
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Language < b.Language
+	})
+	if len(status.Workloads) > maxReportedWorkloads {
+		status.Workloads = status.Workloads[:maxReportedWorkloads]
+	}
+
+	previous := changed.Status.DeepCopy()
+	previous.LastUpdateTime = nil
+	if reflect.DeepEqual(*previous, status) {
+		return false
+	}
+	now := metav1.Now()
+	status.LastUpdateTime = &now
+	changed.Status = status
+	return true
+}
+
+// worstState returns the state needing the most attention, the zero state being the best.
+func worstState(a, b v1alpha1.InjectionState) v1alpha1.InjectionState {
+	if a == "" || statePriority[b] < statePriority[a] {
+		return b
+	}
+	return a
+}
+
+// count increments the counter of the state.
+func count(state v1alpha1.InjectionState, injected, skipped, failed *int32) {
+	switch state {
+	case v1alpha1.InjectionStateInjected:
+		*injected++
+	case v1alpha1.InjectionStateSkipped:
+		*skipped++
+	case v1alpha1.InjectionStateFailed:
+		*failed++
+	}
+}
+
+// workloadOf returns the kind and name of the workload controlling the pod. Pods of a Deployment are attributed to
+// the Deployment rather than to its ReplicaSet.
+func workloadOf(pod corev1.Pod) (string, string) {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil {
+		return "Pod", pod.Name
+	}
+	if owner.Kind == "ReplicaSet" {
+		if hash, ok := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; ok && strings.HasSuffix(owner.Name, "-"+hash) {
+			return "Deployment", strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+	}
+	return owner.Kind, owner.Name
+}
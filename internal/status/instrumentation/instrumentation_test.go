@@ -0,0 +1,165 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
+
+func podWithOutcomes(t *testing.T, name string, owner *metav1.OwnerReference, outcomes ...instrumentation.InjectionOutcome) corev1.Pod {
+	value, err := json.Marshal(outcomes)
+	require.NoError(t, err)
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        name,
+			Annotations: map[string]string{instrumentation.AnnotationInjectionStatus: string(value)},
+		},
+	}
+	if owner != nil {
+		pod.OwnerReferences = []metav1.OwnerReference{*owner}
+		if owner.Kind == "ReplicaSet" {
+			pod.Labels = map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "5d8f7b9c4"}
+		}
+	}
+	return pod
+}
+
+func TestUpdateInstrumentationStatus(t *testing.T) {
+	controller := true
+	replicaSet := &metav1.OwnerReference{Kind: "ReplicaSet", Name: "app-5d8f7b9c4", Controller: &controller}
+	daemonSet := &metav1.OwnerReference{Kind: "DaemonSet", Name: "agent", Controller: &controller}
+
+	inst := &v1alpha1.Instrumentation{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "my-inst"}}
+	pods := []corev1.Pod{
+		podWithOutcomes(t, "app-1", replicaSet,
+			instrumentation.InjectionOutcome{Language: "java", Instrumentation: "ns/my-inst", State: v1alpha1.InjectionStateInjected},
+		),
+		podWithOutcomes(t, "app-2", replicaSet,
+			instrumentation.InjectionOutcome{Language: "java", Instrumentation: "ns/my-inst", State: v1alpha1.InjectionStateInjected},
+		),
+		podWithOutcomes(t, "agent-1", daemonSet,
+			instrumentation.InjectionOutcome{Language: "python", Instrumentation: "ns/*", State: v1alpha1.InjectionStateSkipped, Reason: "disabled"},
+			instrumentation.InjectionOutcome{Language: "java", Instrumentation: "ns/other", State: v1alpha1.InjectionStateInjected},
+		),
+		podWithOutcomes(t, "standalone", nil,
+			instrumentation.InjectionOutcome{Language: "nodejs", Instrumentation: "ns/my-inst", State: v1alpha1.InjectionStateFailed, Reason: "boom"},
+		),
+	}
+
+	changed := UpdateInstrumentationStatus(inst, pods, true)
+
+	assert.True(t, changed)
+	assert.EqualValues(t, 1, inst.Status.Injected)
+	assert.EqualValues(t, 1, inst.Status.Skipped)
+	assert.EqualValues(t, 1, inst.Status.Failed)
+	assert.Equal(t, []v1alpha1.LanguageCoverage{
+		{Language: "java", Injected: 1},
+		{Language: "nodejs", Failed: 1},
+		{Language: "python", Skipped: 1},
+	}, inst.Status.Languages)
+	assert.Equal(t, []v1alpha1.WorkloadInstrumentationStatus{
+		{Kind: "Pod", Namespace: "ns", Name: "standalone", Language: "nodejs", State: v1alpha1.InjectionStateFailed, Reason: "boom", Pods: 1},
+		{Kind: "DaemonSet", Namespace: "ns", Name: "agent", Language: "python", State: v1alpha1.InjectionStateSkipped, Reason: "disabled", Pods: 1},
+		{Kind: "Deployment", Namespace: "ns", Name: "app", Language: "java", State: v1alpha1.InjectionStateInjected, Pods: 2},
+	}, inst.Status.Workloads)
+	assert.NotNil(t, inst.Status.LastUpdateTime)
+
+	// nothing changed, so the status isn't touched
+	assert.False(t, UpdateInstrumentationStatus(inst, pods, true))
+
+	// the unresolved outcomes are dropped once the Instrumentation is no longer the single one of the namespace
+	assert.True(t, UpdateInstrumentationStatus(inst, pods, false))
+	assert.EqualValues(t, 0, inst.Status.Skipped)
+	assert.Len(t, inst.Status.Workloads, 2)
+}
+
+func TestUpdateInstrumentationStatusCountsWorkloadsOnce(t *testing.T) {
+	controller := true
+	replicaSet := &metav1.OwnerReference{Kind: "ReplicaSet", Name: "app-5d8f7b9c4", Controller: &controller}
+
+	inst := &v1alpha1.Instrumentation{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "my-inst"}}
+	pods := []corev1.Pod{
+		podWithOutcomes(t, "app-1", replicaSet,
+			instrumentation.InjectionOutcome{Language: "java", Instrumentation: "ns/my-inst", State: v1alpha1.InjectionStateInjected},
+			instrumentation.InjectionOutcome{Language: "python", Instrumentation: "ns/my-inst", State: v1alpha1.InjectionStateInjected},
+		),
+		// a pod of the same workload, rolled out while the Instrumentation could not be selected
+		podWithOutcomes(t, "app-2", replicaSet,
+			instrumentation.InjectionOutcome{Language: "java", Instrumentation: "ns/my-inst", State: v1alpha1.InjectionStateFailed, Reason: "boom"},
+		),
+	}
+
+	assert.True(t, UpdateInstrumentationStatus(inst, pods, false))
+	assert.EqualValues(t, 0, inst.Status.Injected)
+	assert.EqualValues(t, 1, inst.Status.Failed)
+	assert.Equal(t, []v1alpha1.LanguageCoverage{
+		{Language: "java", Failed: 1},
+		{Language: "python", Injected: 1},
+	}, inst.Status.Languages)
+	assert.Len(t, inst.Status.Workloads, 3)
+}
+
+func TestWorkloadOf(t *testing.T) {
+	controller := true
+	tests := []struct {
+		name         string
+		pod          corev1.Pod
+		expectedKind string
+		expectedName string
+	}{
+		{
+			name:         "no owner",
+			pod:          corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod"}},
+			expectedKind: "Pod",
+			expectedName: "pod",
+		},
+		{
+			name: "deployment",
+			pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            "app-5d8f7b9c4-abcde",
+				Labels:          map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: "5d8f7b9c4"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "app-5d8f7b9c4", Controller: &controller}},
+			}},
+			expectedKind: "Deployment",
+			expectedName: "app",
+		},
+		{
+			name: "bare replicaset",
+			pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            "rs-abcde",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "rs", Controller: &controller}},
+			}},
+			expectedKind: "ReplicaSet",
+			expectedName: "rs",
+		},
+		{
+			name: "statefulset",
+			pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:            "db-0",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "db", Controller: &controller}},
+			}},
+			expectedKind: "StatefulSet",
+			expectedName: "db",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kind, name := workloadOf(test.pod)
+			assert.Equal(t, test.expectedKind, kind)
+			assert.Equal(t, test.expectedName, name)
+		})
+	}
+}
@@ -20,6 +20,8 @@ var (
 	autoInstrumentationApacheHttpd string
 	autoInstrumentationNginx       string
 	autoInstrumentationGo          string
+	autoInstrumentationRuby        string
+	autoInstrumentationPHP         string
 	dcgmExporter                   string
 	neuronMonitor                  string
 	targetAllocator                string
@@ -38,6 +40,8 @@ type Version struct {
 	AutoInstrumentationGo          string `json:"auto-instrumentation-go"`
 	AutoInstrumentationApacheHttpd string `json:"auto-instrumentation-apache-httpd"`
 	AutoInstrumentationNginx       string `json:"auto-instrumentation-nginx"`
+	AutoInstrumentationRuby        string `json:"auto-instrumentation-ruby"`
+	AutoInstrumentationPHP         string `json:"auto-instrumentation-php"`
 	DcgmExporter                   string `json:"dcgm-exporter-version"`
 	NeuronMonitor                  string `json:"neuron-monitor-version"`
 	TargetAllocator                string `json:"target-allocator-version"`
@@ -57,6 +61,8 @@ func Get() Version {
 		AutoInstrumentationGo:          AutoInstrumentationGo(),
 		AutoInstrumentationApacheHttpd: AutoInstrumentationApacheHttpd(),
 		AutoInstrumentationNginx:       AutoInstrumentationNginx(),
+		AutoInstrumentationRuby:        AutoInstrumentationRuby(),
+		AutoInstrumentationPHP:         AutoInstrumentationPHP(),
 		DcgmExporter:                   DcgmExporter(),
 		NeuronMonitor:                  NeuronMonitor(),
 		TargetAllocator:                TargetAllocator(),
@@ -65,7 +71,7 @@ func Get() Version {
 
 func (v Version) String() string {
 	return fmt.Sprintf(
-		"Version(Operator='%v', BuildDate='%v', AmazonCloudWatchAgent='%v', Go='%v', AutoInstrumentationJava='%v', AutoInstrumentationNodeJS='%v', AutoInstrumentationPython='%v', AutoInstrumentationDotNet='%v', AutoInstrumentationGo='%v', AutoInstrumentationApacheHttpd='%v', AutoInstrumentationNginx='%v', DcgmExporter='%v', NeuronMonitor='%v', TargetAllocator='%v')",
+		"Version(Operator='%v', BuildDate='%v', AmazonCloudWatchAgent='%v', Go='%v', AutoInstrumentationJava='%v', AutoInstrumentationNodeJS='%v', AutoInstrumentationPython='%v', AutoInstrumentationDotNet='%v', AutoInstrumentationGo='%v', AutoInstrumentationApacheHttpd='%v', AutoInstrumentationNginx='%v', AutoInstrumentationRuby='%v', AutoInstrumentationPHP='%v', DcgmExporter='%v', NeuronMonitor='%v', TargetAllocator='%v')",
 		v.Operator,
 		v.BuildDate,
 		v.AmazonCloudWatchAgent,
@@ -77,6 +83,8 @@ func (v Version) String() string {
 		v.AutoInstrumentationGo,
 		v.AutoInstrumentationApacheHttpd,
 		v.AutoInstrumentationNginx,
+		v.AutoInstrumentationRuby,
+		v.AutoInstrumentationPHP,
 		v.DcgmExporter,
 		v.NeuronMonitor,
 		v.TargetAllocator,
@@ -143,6 +151,20 @@ func AutoInstrumentationGo() string {
 	return "0.0.0"
 }
 
+func AutoInstrumentationRuby() string {
+	if len(autoInstrumentationRuby) > 0 {
+		return autoInstrumentationRuby
+	}
+	return "0.0.0"
+}
+
+func AutoInstrumentationPHP() string {
+	if len(autoInstrumentationPHP) > 0 {
+		return autoInstrumentationPHP
+	}
+	return "0.0.0"
+}
+
 func DcgmExporter() string {
 	if len(dcgmExporter) > 0 {
 		// this should always be set, as it's specified during the build
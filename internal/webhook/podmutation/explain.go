@@ -0,0 +1,180 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package podmutation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"gomodules.xyz/jsonpatch/v2"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// ExplainPath is the path the explain handler is served on. Callers authenticate with a bearer token and need to be
+// allowed to post to this non-resource URL.
+const ExplainPath = "/explain-v1-pod"
+
+// maxExplainRequestSize bounds the size of the explain request body.
+const maxExplainRequestSize = 3 * 1024 * 1024
+
+// ExplainRequest is the payload accepted by the explain handler.
+type ExplainRequest struct {
+	// Namespace of the pod. When omitted, the namespace named in the pod metadata is read from the cluster.
+	Namespace *corev1.Namespace `json:"namespace,omitempty"`
+	// Pod to run through the pod mutators.
+	Pod corev1.Pod `json:"pod"`
+}
+
+// ExplainResponse is what the pod webhook would do with the pod of an ExplainRequest.
+type ExplainResponse struct {
+	// Patch is the JSON patch the pod webhook would return.
+	Patch []jsonpatch.JsonPatchOperation `json:"patch"`
+	// Trace lists the decisions taken by the pod mutators.
+	Trace []TraceStep `json:"trace"`
+	// Error is the error returned by a pod mutator. The pod webhook then admits the pod unmodified, except for the
+	// failure recorded by the mutator, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// the implementation.
+type explainHandler struct {
+	client      client.Client
+	logger      logr.Logger
+	podMutators []PodMutator
+}
+
+// NewExplainHandler creates a handler that runs the given pod through the pod mutators without creating it, and
+// returns the resulting patch along with the decisions taken by the mutators. The mutators must not have side effects
+// such as recording events, and the callers are authenticated and authorized against the API server.
+func NewExplainHandler(logger logr.Logger, cl client.Client, podMutators []PodMutator) http.Handler {
+	return &explainHandler{
+		client:      cl,
+		logger:      logger,
+		podMutators: podMutators,
+	}
+}
+
+func (h *explainHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if status, err := h.authorize(r); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	req := ExplainRequest{}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxExplainRequestSize)).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode the request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ns := req.Namespace
+	if ns == nil {
+		if len(req.Pod.Namespace) == 0 {
+			http.Error(w, "either the namespace or the pod namespace must be provided", http.StatusBadRequest)
+			return
+		}
+		ns = &corev1.Namespace{}
+		if err := h.client.Get(r.Context(), types.NamespacedName{Name: req.Pod.Namespace}, ns); err != nil {
+			status := http.StatusInternalServerError
+			if apierrors.IsNotFound(err) {
+				status = http.StatusNotFound
+			}
+			http.Error(w, fmt.Sprintf("failed to get the namespace: %v", err), status)
+			return
+		}
+	}
+	if len(req.Pod.Namespace) == 0 {
+		req.Pod.Namespace = ns.Name
+	}
+
+	original, err := json.Marshal(req.Pod)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ctx, trace := WithTrace(r.Context())
+	res := ExplainResponse{Patch: []jsonpatch.JsonPatchOperation{}}
+	pod, mutateErr := mutate(ctx, h.podMutators, *ns, req.Pod)
+	mutated, err := json.Marshal(pod)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if res.Patch, err = jsonpatch.CreatePatch(original, mutated); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if mutateErr != nil {
+		res.Error = mutateErr.Error()
+		if len(res.Patch) == 0 {
+			trace.Recordf("webhook", "a pod mutator failed, the pod would be admitted unmodified: %v", mutateErr)
+		} else {
+			trace.Recordf("webhook", "a pod mutator failed, the pod would be admitted unmodified except for the recorded failure: %v", mutateErr)
+		}
+	}
+	res.Trace = trace.Steps()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		h.logger.Error(err, "failed to write the explain response")
+	}
+}
+
+// authorize authenticates the bearer token of the request with a TokenReview, and checks with a SubjectAccessReview
+// that its user is allowed to post to the explain path. Returns the HTTP status to reply with on failure.
+func (h *explainHandler) authorize(r *http.Request) (int, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || len(token) == 0 {
+		return http.StatusUnauthorized, fmt.Errorf("a bearer token is required")
+	}
+
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := h.client.Create(r.Context(), review); err != nil {
+		h.logger.Error(err, "failed to review the token of the explain request")
+		return http.StatusInternalServerError, fmt.Errorf("authentication failed")
+	}
+	if !review.Status.Authenticated {
+		return http.StatusUnauthorized, fmt.Errorf("unauthorized")
+	}
+
+	user := review.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	access := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User:   user.Username,
+		UID:    user.UID,
+		Groups: user.Groups,
+		Extra:  extra,
+		NonResourceAttributes: &authorizationv1.NonResourceAttributes{
+			Path: ExplainPath,
+			Verb: strings.ToLower(http.MethodPost),
+		},
+	}}
+	if err := h.client.Create(r.Context(), access); err != nil {
+		h.logger.Error(err, "failed to review the access of the explain request", "user", user.Username)
+		return http.StatusInternalServerError, fmt.Errorf("authorization for user %s failed", user.Username)
+	}
+	if !access.Status.Allowed {
+		return http.StatusForbidden, fmt.Errorf("authorization denied for user %s", user.Username)
+	}
+	return http.StatusOK, nil
+}
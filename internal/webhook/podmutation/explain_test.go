@@ -0,0 +1,240 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package podmutation_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gomodules.xyz/jsonpatch/v2"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	. "github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
+)
+
+type labelMutator struct {
+	err error
+	// partial labels the pod before returning the error
+	partial bool
+}
+
+func (m labelMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) (corev1.Pod, error) {
+	if m.err != nil && !m.partial {
+		return pod, m.err
+	}
+	TraceFromContext(ctx).Recordf("label", "labeling pod in namespace %s", ns.Name)
+	pod.Labels = map[string]string{"mutated": "true"}
+	return pod, m.err
+}
+
+// authClient returns a client authenticating the "valid" and "denied" tokens, and allowing the user of the "valid"
+// one.
+func authClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			switch review := obj.(type) {
+			case *authenticationv1.TokenReview:
+				review.Status.Authenticated = review.Spec.Token == "valid" || review.Spec.Token == "denied"
+				review.Status.User = authenticationv1.UserInfo{Username: review.Spec.Token}
+				return nil
+			case *authorizationv1.SubjectAccessReview:
+				review.Status.Allowed = review.Spec.User == "valid" &&
+					review.Spec.NonResourceAttributes.Path == ExplainPath && review.Spec.NonResourceAttributes.Verb == "post"
+				return nil
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+}
+
+func newExplainRequest(t *testing.T, token string, body interface{}) *http.Request {
+	encoded, err := json.Marshal(body)
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, ExplainPath, bytes.NewReader(encoded))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func TestExplain(t *testing.T) {
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-ns"}}
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pod", Namespace: "my-ns"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+	}
+
+	for _, tt := range []struct {
+		name           string
+		req            interface{}
+		mutator        labelMutator
+		expectedStatus int
+		expected       ExplainResponse
+	}{
+		{
+			name:           "namespace looked up in the cluster",
+			req:            ExplainRequest{Pod: pod},
+			expectedStatus: http.StatusOK,
+			expected: ExplainResponse{
+				Patch: []jsonpatch.JsonPatchOperation{{Operation: "add", Path: "/metadata/labels", Value: map[string]interface{}{"mutated": "true"}}},
+				Trace: []TraceStep{{Mutator: "label", Message: "labeling pod in namespace my-ns"}},
+			},
+		},
+		{
+			name: "namespace provided",
+			req: ExplainRequest{
+				Namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other-ns"}},
+				Pod:       corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "my-pod"}},
+			},
+			expectedStatus: http.StatusOK,
+			expected: ExplainResponse{
+				Patch: []jsonpatch.JsonPatchOperation{{Operation: "add", Path: "/metadata/labels", Value: map[string]interface{}{"mutated": "true"}}},
+				Trace: []TraceStep{{Mutator: "label", Message: "labeling pod in namespace other-ns"}},
+			},
+		},
+		{
+			name:           "mutator error",
+			req:            ExplainRequest{Pod: pod},
+			mutator:        labelMutator{err: errors.New("boom")},
+			expectedStatus: http.StatusOK,
+			expected: ExplainResponse{
+				Patch: []jsonpatch.JsonPatchOperation{},
+				Trace: []TraceStep{{Mutator: "webhook", Message: "a pod mutator failed, the pod would be admitted unmodified: boom"}},
+				Error: "boom",
+			},
+		},
+		{
+			name:           "mutator error after changes",
+			req:            ExplainRequest{Pod: pod},
+			mutator:        labelMutator{err: errors.New("boom"), partial: true},
+			expectedStatus: http.StatusOK,
+			expected: ExplainResponse{
+				Patch: []jsonpatch.JsonPatchOperation{},
+				Trace: []TraceStep{
+					{Mutator: "label", Message: "labeling pod in namespace my-ns"},
+					{Mutator: "webhook", Message: "a pod mutator failed, the pod would be admitted unmodified: boom"},
+				},
+				Error: "boom",
+			},
+		},
+		{
+			name: "mutator error recording the failure",
+			req:  ExplainRequest{Pod: pod},
+			mutator: labelMutator{err: &FailedMutationError{
+				Err:         errors.New("boom"),
+				Annotations: map[string]string{"failure": "boom"},
+			}, partial: true},
+			expectedStatus: http.StatusOK,
+			expected: ExplainResponse{
+				Patch: []jsonpatch.JsonPatchOperation{{Operation: "add", Path: "/metadata/annotations", Value: map[string]interface{}{"failure": "boom"}}},
+				Trace: []TraceStep{
+					{Mutator: "label", Message: "labeling pod in namespace my-ns"},
+					{Mutator: "webhook", Message: "a pod mutator failed, the pod would be admitted unmodified except for the recorded failure: boom"},
+				},
+				Error: "boom",
+			},
+		},
+		{
+			name:           "namespace doesn't exist",
+			req:            ExplainRequest{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "my-pod", Namespace: "non-existing"}}},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "no namespace",
+			req:            ExplainRequest{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "my-pod"}}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid payload",
+			req:            "not a request",
+			expectedStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			handler := NewExplainHandler(logger, authClient(ns.DeepCopy()), []PodMutator{tt.mutator})
+			rec := httptest.NewRecorder()
+
+			// test
+			handler.ServeHTTP(rec, newExplainRequest(t, "valid", tt.req))
+
+			// verify
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			res := ExplainResponse{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			assert.Equal(t, tt.expected.Patch, res.Patch)
+			assert.Equal(t, tt.expected.Trace, res.Trace)
+			assert.Equal(t, tt.expected.Error, res.Error)
+		})
+	}
+}
+
+func TestExplainRequiresAuthorization(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{name: "no token", expectedStatus: http.StatusUnauthorized},
+		{name: "invalid token", token: "invalid", expectedStatus: http.StatusUnauthorized},
+		{name: "user not allowed", token: "denied", expectedStatus: http.StatusForbidden},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// prepare
+			mutator := &countingMutator{}
+			handler := NewExplainHandler(logger, authClient(), []PodMutator{mutator})
+			rec := httptest.NewRecorder()
+
+			// test
+			handler.ServeHTTP(rec, newExplainRequest(t, tt.token, ExplainRequest{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns"}}}))
+
+			// verify
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Zero(t, mutator.calls)
+		})
+	}
+}
+
+type countingMutator struct {
+	calls int
+}
+
+func (m *countingMutator) Mutate(_ context.Context, _ corev1.Namespace, pod corev1.Pod) (corev1.Pod, error) {
+	m.calls++
+	return pod, nil
+}
+
+func TestExplainRejectsGet(t *testing.T) {
+	handler := NewExplainHandler(logger, fake.NewClientBuilder().Build(), nil)
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ExplainPath, nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestTraceIsNoopWithoutContext(t *testing.T) {
+	trace := TraceFromContext(context.Background())
+
+	trace.Recordf("label", "ignored")
+
+	assert.Nil(t, trace)
+	assert.Empty(t, trace.Steps())
+}
@@ -0,0 +1,59 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package podmutation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type traceKey struct{}
+
+// Trace records the decisions taken by the pod mutators while processing a pod. A trace is only attached to the
+// context of explain requests, so recording is a no-op during regular admission.
+type Trace struct {
+	mu    sync.Mutex
+	steps []TraceStep
+}
+
+// TraceStep is a single decision taken by a pod mutator.
+type TraceStep struct {
+	// Mutator is the name of the mutator that took the decision, e.g. instrumentation.
+	Mutator string `json:"mutator"`
+	// Message describes the decision.
+	Message string `json:"message"`
+}
+
+// WithTrace returns a copy of the context carrying a new Trace.
+func WithTrace(ctx context.Context) (context.Context, *Trace) {
+	trace := &Trace{}
+	return context.WithValue(ctx, traceKey{}, trace), trace
+}
+
+// TraceFromContext returns the Trace attached to the context, or nil if there is none.
+func TraceFromContext(ctx context.Context) *Trace {
+	trace, _ := ctx.Value(traceKey{}).(*Trace)
+	return trace
+}
+
+// Recordf records a decision taken by the given mutator. It is safe to call on a nil Trace.
+func (t *Trace) Recordf(mutator string, format string, args ...interface{}) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.steps = append(t.steps, TraceStep{Mutator: mutator, Message: fmt.Sprintf(format, args...)})
+}
+
+// Steps returns the decisions recorded so far.
+func (t *Trace) Steps() []TraceStep {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]TraceStep(nil), t.steps...)
+}
@@ -7,6 +7,7 @@ package podmutation
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/go-logr/logr"
@@ -77,13 +78,11 @@ func (p *podMutationWebhook) Handle(ctx context.Context, req admission.Request)
 		return res
 	}
 
-	for _, m := range p.podMutators {
-		pod, err = m.Mutate(ctx, ns, pod)
-		if err != nil {
-			res := admission.Errored(http.StatusInternalServerError, err)
-			res.Allowed = true
-			return res
-		}
+	pod, mutateErr := mutate(ctx, p.podMutators, ns, pod)
+	if mutateErr != nil && !recordsFailure(mutateErr) {
+		res := admission.Errored(http.StatusInternalServerError, mutateErr)
+		res.Allowed = true
+		return res
 	}
 
 	marshaledPod, err := json.Marshal(pod)
@@ -92,5 +91,71 @@ func (p *podMutationWebhook) Handle(ctx context.Context, req admission.Request)
 		res.Allowed = true
 		return res
 	}
-	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
+	res := admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
+	if mutateErr != nil {
+		// the pod is admitted unmodified, except for the failure recorded by the failing mutator
+		res.Warnings = append(res.Warnings, mutateErr.Error())
+	}
+	return res
+}
+
+// FailedMutationError is returned by a pod mutator that records its failure on the pod. The webhook then admits the
+// original pod with only these labels and annotations, dropping the changes made by every mutator.
+type FailedMutationError struct {
+	Err         error
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+func (e *FailedMutationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FailedMutationError) Unwrap() error {
+	return e.Err
+}
+
+// recordsFailure tells the error of a pod mutator records the failure on the pod.
+func recordsFailure(err error) bool {
+	var failedErr *FailedMutationError
+	return errors.As(err, &failedErr) && len(failedErr.Labels)+len(failedErr.Annotations) > 0
+}
+
+// mutate runs the pod through the mutators, in order, stopping at the first error. The pod returned with the error
+// is the original pod, carrying only the failure recorded by the mutator, if any.
+func mutate(ctx context.Context, podMutators []PodMutator, ns corev1.Namespace, pod corev1.Pod) (corev1.Pod, error) {
+	mutated := *pod.DeepCopy()
+	var err error
+	for _, m := range podMutators {
+		mutated, err = m.Mutate(ctx, ns, mutated)
+		if err != nil {
+			return withFailure(pod, err), err
+		}
+	}
+	return mutated, nil
+}
+
+// withFailure returns the pod with the labels and annotations recording the failure of a pod mutator.
+func withFailure(pod corev1.Pod, err error) corev1.Pod {
+	var failedErr *FailedMutationError
+	if !errors.As(err, &failedErr) {
+		return pod
+	}
+	pod.Labels = withEntries(pod.Labels, failedErr.Labels)
+	pod.Annotations = withEntries(pod.Annotations, failedErr.Annotations)
+	return pod
+}
+
+func withEntries(m map[string]string, entries map[string]string) map[string]string {
+	if len(entries) == 0 {
+		return m
+	}
+	merged := make(map[string]string, len(m)+len(entries))
+	for k, v := range m {
+		merged[k] = v
+	}
+	for k, v := range entries {
+		merged[k] = v
+	}
+	return merged
 }
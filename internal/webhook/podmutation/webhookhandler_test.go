@@ -6,15 +6,19 @@ package podmutation_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gomodules.xyz/jsonpatch/v2"
 	admv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/kubectl/pkg/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -76,3 +80,45 @@ func TestFailOnInvalidRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleAdmitsOriginalPodOnFailure(t *testing.T) {
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-ns"}}
+	encoded, err := json.Marshal(corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "my-pod", Namespace: "my-ns"}})
+	require.NoError(t, err)
+	req := admission.Request{
+		AdmissionRequest: admv1.AdmissionRequest{
+			Namespace: "my-ns",
+			Object:    runtime.RawExtension{Raw: encoded},
+		},
+	}
+
+	for _, tt := range []struct {
+		name          string
+		err           error
+		expectedPatch []jsonpatch.JsonPatchOperation
+	}{
+		{
+			name: "failure not recorded",
+			err:  errors.New("boom"),
+		},
+		{
+			name: "failure recorded",
+			err:  &FailedMutationError{Err: errors.New("boom"), Annotations: map[string]string{"failure": "boom"}},
+			expectedPatch: []jsonpatch.JsonPatchOperation{
+				{Operation: "add", Path: "/metadata/annotations", Value: map[string]interface{}{"failure": "boom"}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := admission.NewDecoder(scheme.Scheme)
+			// the first mutator labels the pod, which is dropped when the second one fails
+			injector := NewWebhookHandler(config.New(), logger, decoder, fake.NewClientBuilder().WithObjects(&ns).Build(),
+				[]PodMutator{labelMutator{}, labelMutator{err: tt.err}})
+
+			res := injector.Handle(context.Background(), req)
+
+			assert.True(t, res.Allowed)
+			assert.Equal(t, tt.expectedPatch, res.Patches)
+		})
+	}
+}
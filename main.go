@@ -13,17 +13,22 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	routev1 "github.com/openshift/api/route/v1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/spf13/pflag"
 	colfeaturegate "go.opentelemetry.io/collector/featuregate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/tools/record"
 	k8sapiflag "k8s.io/component-base/cli/flag"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -50,6 +55,9 @@ const (
 	autoInstrumentationPythonImageRepository = "public.ecr.aws/aws-observability/adot-autoinstrumentation-python"
 	autoInstrumentationDotNetImageRepository = "ghcr.io/open-telemetry/opentelemetry-operator/autoinstrumentation-dotnet"
 	autoInstrumentationNodeJSImageRepository = "ghcr.io/open-telemetry/opentelemetry-operator/autoinstrumentation-nodejs"
+	autoInstrumentationRubyImageRepository   = "ghcr.io/open-telemetry/opentelemetry-operator/autoinstrumentation-ruby"
+	autoInstrumentationPHPImageRepository    = "ghcr.io/open-telemetry/opentelemetry-operator/autoinstrumentation-php"
+	autoInstrumentationGoImageRepository     = "ghcr.io/open-telemetry/opentelemetry-go-instrumentation/autoinstrumentation-go"
 	dcgmExporterImageRepository              = "nvcr.io/nvidia/k8s/dcgm-exporter"
 	neuronMonitorImageRepository             = "public.ecr.aws/neuron"
 	targetAllocatorImageRepository           = "public.ecr.aws/cloudwatch-agent/cloudwatch-agent-target-allocator"
@@ -151,6 +159,9 @@ func main() {
 		autoInstrumentationPython    string
 		autoInstrumentationDotNet    string
 		autoInstrumentationNodeJS    string
+		autoInstrumentationRuby      string
+		autoInstrumentationPHP       string
+		autoInstrumentationGo        string
 		autoAnnotationConfigStr      string
 		autoMonitorConfigStr         string
 		autoInstrumentationConfigStr string
@@ -169,6 +180,9 @@ func main() {
 	stringFlagOrEnv(&autoInstrumentationPython, "auto-instrumentation-python-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_PYTHON", fmt.Sprintf("%s:%s", autoInstrumentationPythonImageRepository, v.AutoInstrumentationPython), "The default OpenTelemetry Python instrumentation image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&autoInstrumentationDotNet, "auto-instrumentation-dotnet-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_DOTNET", fmt.Sprintf("%s:%s", autoInstrumentationDotNetImageRepository, v.AutoInstrumentationDotNet), "The default OpenTelemetry Dotnet instrumentation image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&autoInstrumentationNodeJS, "auto-instrumentation-nodejs-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_NODEJS", fmt.Sprintf("%s:%s", autoInstrumentationNodeJSImageRepository, v.AutoInstrumentationNodeJS), "The default OpenTelemetry NodeJS instrumentation image. This image is used when no image is specified in the CustomResource.")
+	stringFlagOrEnv(&autoInstrumentationRuby, "auto-instrumentation-ruby-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_RUBY", fmt.Sprintf("%s:%s", autoInstrumentationRubyImageRepository, v.AutoInstrumentationRuby), "The default OpenTelemetry Ruby instrumentation image. This image is used when no image is specified in the CustomResource.")
+	stringFlagOrEnv(&autoInstrumentationPHP, "auto-instrumentation-php-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_PHP", fmt.Sprintf("%s:%s", autoInstrumentationPHPImageRepository, v.AutoInstrumentationPHP), "The default OpenTelemetry PHP instrumentation image. This image is used when no image is specified in the CustomResource.")
+	stringFlagOrEnv(&autoInstrumentationGo, "auto-instrumentation-go-image", "RELATED_IMAGE_AUTO_INSTRUMENTATION_GO", fmt.Sprintf("%s:%s", autoInstrumentationGoImageRepository, v.AutoInstrumentationGo), "The default OpenTelemetry Go instrumentation image. This image is used when no image is specified in the CustomResource.")
 	stringFlagOrEnv(&autoAnnotationConfigStr, "auto-annotation-config", "AUTO_ANNOTATION_CONFIG", "", "The configuration for auto-annotation.")
 	pflag.StringVar(&autoMonitorConfigStr, "auto-monitor-config", "", "The configuration for auto-monitor.")
 	pflag.StringVar(&autoInstrumentationConfigStr, "auto-instrumentation-config", "", "The configuration for auto-instrumentation.")
@@ -178,7 +192,7 @@ func main() {
 	pflag.Parse()
 
 	// set instrumentation cpu and memory limits in environment variables to be used for default instrumentation; default values received from https://github.com/open-telemetry/opentelemetry-operator/blob/main/apis/v1alpha1/instrumentation_webhook.go
-	autoInstrumentationConfig := map[string]map[string]map[string]string{"java": {"limits": {"cpu": "500m", "memory": "64Mi"}, "requests": {"cpu": "50m", "memory": "64Mi"}, "runtime_metrics": {"enabled": "true"}, "service_events": {}, "dynamic_instrumentation": {}}, "python": {"limits": {"cpu": "500m", "memory": "32Mi"}, "requests": {"cpu": "50m", "memory": "32Mi"}, "runtime_metrics": {"enabled": "true"}, "service_events": {}, "dynamic_instrumentation": {}}, "dotnet": {"limits": {"cpu": "500m", "memory": "128Mi"}, "requests": {"cpu": "50m", "memory": "128Mi"}, "runtime_metrics": {"enabled": "true"}}, "nodejs": {"limits": {"cpu": "500m", "memory": "128Mi"}, "requests": {"cpu": "50m", "memory": "128Mi"}, "service_events": {}, "dynamic_instrumentation": {}}}
+	autoInstrumentationConfig := map[string]map[string]map[string]string{"java": {"limits": {"cpu": "500m", "memory": "64Mi"}, "requests": {"cpu": "50m", "memory": "64Mi"}, "runtime_metrics": {"enabled": "true"}, "service_events": {}, "dynamic_instrumentation": {}}, "python": {"limits": {"cpu": "500m", "memory": "32Mi"}, "requests": {"cpu": "50m", "memory": "32Mi"}, "runtime_metrics": {"enabled": "true"}, "service_events": {}, "dynamic_instrumentation": {}}, "dotnet": {"limits": {"cpu": "500m", "memory": "128Mi"}, "requests": {"cpu": "50m", "memory": "128Mi"}, "runtime_metrics": {"enabled": "true"}}, "nodejs": {"limits": {"cpu": "500m", "memory": "128Mi"}, "requests": {"cpu": "50m", "memory": "128Mi"}, "service_events": {}, "dynamic_instrumentation": {}}, "go": {"limits": {"cpu": "500m", "memory": "32Mi"}, "requests": {"cpu": "50m", "memory": "32Mi"}}}
 	err := json.Unmarshal([]byte(autoInstrumentationConfigStr), &autoInstrumentationConfig)
 	if err != nil {
 		setupLog.Info(fmt.Sprintf("Using default values: %v", autoInstrumentationConfig))
@@ -195,12 +209,18 @@ func main() {
 	if nodeJSVar, ok := autoInstrumentationConfig["nodejs"]; ok {
 		setLangEnvVars("NODEJS", nodeJSVar)
 	}
+	if goVar, ok := autoInstrumentationConfig["go"]; ok {
+		setLangEnvVars("GO", goVar)
+	}
 
 	// set supported language instrumentation images in environment variable to be used for default instrumentation
 	_ = os.Setenv("AUTO_INSTRUMENTATION_JAVA", autoInstrumentationJava)
 	_ = os.Setenv("AUTO_INSTRUMENTATION_PYTHON", autoInstrumentationPython)
 	_ = os.Setenv("AUTO_INSTRUMENTATION_DOTNET", autoInstrumentationDotNet)
 	_ = os.Setenv("AUTO_INSTRUMENTATION_NODEJS", autoInstrumentationNodeJS)
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO", autoInstrumentationGo)
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY", autoInstrumentationRuby)
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP", autoInstrumentationPHP)
 
 	logger := zap.New(zap.UseFlagOptions(&opts))
 	ctrl.SetLogger(logger)
@@ -212,6 +232,9 @@ func main() {
 		"auto-instrumentation-python", autoInstrumentationPython,
 		"auto-instrumentation-dotnet", autoInstrumentationDotNet,
 		"auto-instrumentation-nodejs", autoInstrumentationNodeJS,
+		"auto-instrumentation-ruby", autoInstrumentationRuby,
+		"auto-instrumentation-php", autoInstrumentationPHP,
+		"auto-instrumentation-go", autoInstrumentationGo,
 		"dcgm-exporter", dcgmExporterImage,
 		"neuron-monitor", neuronMonitorImage,
 		"amazon-cloudwatch-agent-target-allocator", targetAllocatorImage,
@@ -229,6 +252,9 @@ func main() {
 		config.WithAutoInstrumentationPythonImage(autoInstrumentationPython),
 		config.WithAutoInstrumentationDotNetImage(autoInstrumentationDotNet),
 		config.WithAutoInstrumentationNodeJSImage(autoInstrumentationNodeJS),
+		config.WithAutoInstrumentationRubyImage(autoInstrumentationRuby),
+		config.WithAutoInstrumentationPHPImage(autoInstrumentationPHP),
+		config.WithAutoInstrumentationGoImage(autoInstrumentationGo),
 		config.WithDcgmExporterImage(dcgmExporterImage),
 		config.WithNeuronMonitorImage(neuronMonitorImage),
 		config.WithTargetAllocatorImage(targetAllocatorImage),
@@ -267,6 +293,12 @@ func main() {
 			DefaultNamespaces: namespaces,
 		},
 	}
+	if featuregate.EnableInstrumentationStatusReporting.IsEnabled() {
+		// only the pods the webhook recorded an injection outcome on are cached, rather than every pod of the cluster
+		mgrOptions.Cache.ByObject = map[client.Object]cache.ByObject{
+			&corev1.Pod{}: {Label: labels.SelectorFromSet(labels.Set{instrumentation.LabelInjectionStatus: "true"})},
+		}
+	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), mgrOptions)
 	if err != nil {
@@ -309,9 +341,37 @@ func main() {
 		os.Exit(1)
 	}
 
+	if featuregate.EnableInstrumentationStatusReporting.IsEnabled() {
+		if err = controllers.NewInstrumentationReconciler(controllers.Params{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("Instrumentation"),
+			Scheme: mgr.GetScheme(),
+			Config: cfg,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Instrumentation")
+			os.Exit(1)
+		}
+	}
+
+	if featuregate.EnableInstrumentationRollback.IsEnabled() {
+		if err = controllers.NewInstrumentationRollbackReconciler(controllers.Params{
+			Client:   mgr.GetClient(),
+			Log:      ctrl.Log.WithName("controllers").WithName("InstrumentationRollback"),
+			Scheme:   mgr.GetScheme(),
+			Config:   cfg,
+			Recorder: mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator"), //nolint:staticcheck // TODO: migrate to events.EventRecorder
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "InstrumentationRollback")
+			os.Exit(1)
+		}
+	}
+
 	decoder := admission.NewDecoder(mgr.GetScheme())
 
 	instrumentationAnnotator := auto.CreateInstrumentationAnnotator(autoMonitorConfigStr, autoAnnotationConfigStr, ctx, mgr.GetClient(), mgr.GetAPIReader(), setupLog)
+	if monitor, ok := instrumentationAnnotator.(*auto.Monitor); ok {
+		monitor.SetEventRecorder(mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator")) //nolint:staticcheck // TODO: migrate to events.EventRecorder
+	}
 
 	if instrumentationAnnotator != nil {
 		mgr.GetWebhookServer().Register("/mutate-v1-workload", &webhook.Admission{
@@ -334,6 +394,24 @@ func main() {
 		setupLog.Info("Auto-annotation / Auto Monitor is disabled")
 	}
 
+	if instrumentationAnnotator != nil && featuregate.EnableAutoMonitorCRD.IsEnabled() {
+		// the monitor stays nil while the deprecated auto-annotation is configured, the AutoMonitor is then reported as
+		// not applied
+		var monitor controllers.AutoMonitorConfigurer
+		if m, ok := instrumentationAnnotator.(*auto.Monitor); ok {
+			monitor = m
+		}
+		if err = controllers.NewAutoMonitorReconciler(controllers.Params{
+			Client: mgr.GetClient(),
+			Log:    ctrl.Log.WithName("controllers").WithName("AutoMonitor"),
+			Scheme: mgr.GetScheme(),
+			Config: cfg,
+		}, monitor).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "AutoMonitor")
+			os.Exit(1)
+		}
+	}
+
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err = otelv1alpha1.SetupCollectorWebhook(mgr, cfg); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "AmazonCloudWatchAgent")
@@ -343,13 +421,20 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Instrumentation")
 			os.Exit(1)
 		}
+		if err = otelv1alpha1.SetupAutoMonitorWebhook(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AutoMonitor")
+			os.Exit(1)
+		}
+		podMutators := newPodMutators(logger, cfg, mgr.GetClient(), mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator")) //nolint:staticcheck // TODO: migrate to events.EventRecorder
 		mgr.GetWebhookServer().Register("/mutate-v1-pod", &webhook.Admission{
-			Handler: podmutation.NewWebhookHandler(cfg, ctrl.Log.WithName("pod-webhook"), decoder, mgr.GetClient(),
-				[]podmutation.PodMutator{
-					sidecar.NewMutator(logger, cfg, mgr.GetClient()),
-					instrumentation.NewMutator(logger, mgr.GetClient(), mgr.GetEventRecorderFor("amazon-cloudwatch-agent-operator")), //nolint:staticcheck // TODO: migrate to events.EventRecorder
-				}),
+			Handler: podmutation.NewWebhookHandler(cfg, ctrl.Log.WithName("pod-webhook"), decoder, mgr.GetClient(), podMutators),
 		})
+		if featuregate.EnablePodMutationExplain.IsEnabled() {
+			// the explain endpoint is a dry run, the mutators don't record events
+			explainMutators := newPodMutators(logger, cfg, mgr.GetClient(), &record.FakeRecorder{})
+			mgr.GetWebhookServer().Register(podmutation.ExplainPath, podmutation.NewExplainHandler(ctrl.Log.WithName("pod-webhook-explain"), mgr.GetClient(), explainMutators))
+			setupLog.Info("Pod mutation explain endpoint is enabled", "path", podmutation.ExplainPath)
+		}
 	} else {
 		ctrl.Log.Info("Webhooks are disabled, operator is running an unsupported mode", "ENABLE_WEBHOOKS", "false")
 	}
@@ -371,6 +456,15 @@ func main() {
 	}
 }
 
+// newPodMutators returns the mutators the pod webhook runs the pods through, in order, recording their events with
+// the recorder.
+func newPodMutators(logger logr.Logger, cfg config.Config, cl client.Client, recorder record.EventRecorder) []podmutation.PodMutator {
+	return []podmutation.PodMutator{
+		sidecar.NewMutator(logger, cfg, cl),
+		instrumentation.NewMutator(logger, cl, recorder),
+	}
+}
+
 func waitForWebhookServerStart(ctx context.Context, checker healthz.Checker, callback func(context.Context)) {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
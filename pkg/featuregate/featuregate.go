@@ -40,7 +40,7 @@ var (
 	)
 	EnableGoAutoInstrumentationSupport = featuregate.GlobalRegistry().MustRegister(
 		"operator.autoinstrumentation.go",
-		featuregate.StageAlpha,
+		featuregate.StageBeta,
 		featuregate.WithRegisterDescription("controls whether the operator supports Golang auto-instrumentation"),
 		featuregate.WithRegisterFromVersion("v0.77.0"),
 	)
@@ -56,6 +56,16 @@ var (
 		featuregate.WithRegisterDescription("controls whether the operator supports Nginx auto-instrumentation"),
 		featuregate.WithRegisterFromVersion("v0.86.0"),
 	)
+	EnableRubyAutoInstrumentationSupport = featuregate.GlobalRegistry().MustRegister(
+		"operator.autoinstrumentation.ruby",
+		featuregate.StageAlpha,
+		featuregate.WithRegisterDescription("controls whether the operator supports Ruby auto-instrumentation"),
+	)
+	EnablePHPAutoInstrumentationSupport = featuregate.GlobalRegistry().MustRegister(
+		"operator.autoinstrumentation.php",
+		featuregate.StageAlpha,
+		featuregate.WithRegisterDescription("controls whether the operator supports PHP auto-instrumentation"),
+	)
 
 	EnableMultiInstrumentationSupport = featuregate.GlobalRegistry().MustRegister(
 		"operator.autoinstrumentation.multiinstrumentation",
@@ -88,6 +98,42 @@ var (
 		"operator.autoinstrumentation.multiinstrumentation.skipcontainervalidation",
 		featuregate.StageBeta,
 		featuregate.WithRegisterDescription("controls whether the operator validates the container annotations when multi-instrumentation is enabled"))
+
+	// EnableInstrumentationStatusReporting is the feature gate that controls whether the pod webhook records the
+	// outcome of the auto-instrumentation injection on the pod, which is aggregated into the Instrumentation status.
+	EnableInstrumentationStatusReporting = featuregate.GlobalRegistry().MustRegister(
+		"operator.autoinstrumentation.statusreporting",
+		featuregate.StageAlpha,
+		featuregate.WithRegisterDescription("controls whether the operator reports the injection outcome of workloads on the Instrumentation status"))
+
+	// EnablePodMutationExplain is the feature gate that controls whether the webhook server exposes an endpoint that
+	// runs a pod through the pod mutators without creating it, and explains the resulting changes. Callers are
+	// authenticated by their bearer token and must be allowed to post to the endpoint's non-resource URL.
+	EnablePodMutationExplain = featuregate.GlobalRegistry().MustRegister(
+		"operator.podmutation.explain",
+		featuregate.StageAlpha,
+		featuregate.WithRegisterDescription("controls whether the operator exposes the dry-run endpoint of the pod mutation webhook"))
+
+	// EnableAutoMonitorCRD is the feature gate that controls whether the auto-monitor is configured by the cluster-scoped
+	// AutoMonitor resource, which takes precedence over the --auto-monitor-config flag and is applied without a restart.
+	EnableAutoMonitorCRD = featuregate.GlobalRegistry().MustRegister(
+		"operator.automonitor.crd",
+		featuregate.StageAlpha,
+		featuregate.WithRegisterDescription("controls whether the auto-monitor is configured by the AutoMonitor resource"))
+
+	// EnableInstrumentationRollback is the feature gate that controls whether the operator rolls back the
+	// auto-instrumentation of workloads whose pods fail shortly after the injection, and quarantines them.
+	EnableInstrumentationRollback = featuregate.GlobalRegistry().MustRegister(
+		"operator.autoinstrumentation.rollback",
+		featuregate.StageAlpha,
+		featuregate.WithRegisterDescription("controls whether the operator rolls back the auto-instrumentation of crash-looping workloads"))
+
+	// EnableLayeredInstrumentation is the feature gate that controls whether the Instrumentation used for a pod is
+	// layered over the default instrumentation and patched by the Instrumentation overrides referenced by the pod.
+	EnableLayeredInstrumentation = featuregate.GlobalRegistry().MustRegister(
+		"operator.autoinstrumentation.layeredinstrumentation",
+		featuregate.StageAlpha,
+		featuregate.WithRegisterDescription("controls whether Instrumentations are merged with the default instrumentation and workload overrides"))
 )
 
 // Flags creates a new FlagSet that represents the available featuregate flags using the supplied featuregate registry.
@@ -31,8 +31,20 @@ const (
 	annotationInjectApacheHttpdContainersName = "instrumentation.opentelemetry.io/apache-httpd-container-names"
 	annotationInjectNginx                     = "instrumentation.opentelemetry.io/inject-nginx"
 	annotationInjectNginxContainersName       = "instrumentation.opentelemetry.io/inject-nginx-container-names"
+	annotationInjectRuby                      = "instrumentation.opentelemetry.io/inject-ruby"
+	annotationInjectRubyContainersName        = "instrumentation.opentelemetry.io/ruby-container-names"
+	annotationInjectPHP                       = "instrumentation.opentelemetry.io/inject-php"
+	annotationInjectPHPContainersName         = "instrumentation.opentelemetry.io/php-container-names"
 )
 
+// AnnotationQuarantined marks a workload whose auto-instrumentation was rolled back because it failed its pods. The
+// value explains why. The pods of quarantined workloads aren't instrumented.
+const AnnotationQuarantined = "cloudwatch.aws.amazon.com/auto-instrumentation-quarantined"
+
+// AnnotationInstrumentedAt records on the pod template of a workload when the auto-monitor last added a language to
+// instrument, so that the failures of its pods are only blamed on the auto-instrumentation shortly after.
+const AnnotationInstrumentedAt = "cloudwatch.aws.amazon.com/auto-instrumented-at"
+
 // annotationValue returns the effective annotationInjectJava value, based on the annotations from the pod and namespace.
 func annotationValue(ns metav1.ObjectMeta, pod metav1.ObjectMeta, annotation string) string {
 	// is the pod annotated with instructions to inject sidecars? is the namespace annotated?
@@ -45,10 +45,15 @@ const (
 	TypePython Type = "python"
 	TypeDotNet Type = "dotnet"
 	TypeGo     Type = "go"
+	TypeRuby   Type = "ruby"
+	TypePHP    Type = "php"
 )
 
 var (
-	SupportedTypes = NewTypeSet(TypeJava, TypeNodeJS, TypePython, TypeDotNet)
+	SupportedTypes = NewTypeSet(TypeJava, TypeNodeJS, TypePython, TypeDotNet, TypeGo)
+	// DefaultTypes are the languages instrumented when none is configured. Go is left out since its instrumentation
+	// runs a privileged eBPF sidecar, it must be requested explicitly.
+	DefaultTypes = NewTypeSet(TypeJava, TypeNodeJS, TypePython, TypeDotNet)
 )
 
 // InjectAnnotationKey maps the instrumentation type to the inject annotation.
@@ -64,6 +69,10 @@ func InjectAnnotationKey(instType Type) string {
 		return annotationInjectDotNet
 	case TypeGo:
 		return annotationInjectGo
+	case TypeRuby:
+		return annotationInjectRuby
+	case TypePHP:
+		return annotationInjectPHP
 	default:
 		return ""
 	}
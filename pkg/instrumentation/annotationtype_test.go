@@ -19,6 +19,8 @@ func TestInjectAnnotationKey(t *testing.T) {
 		{instType: TypePython, want: annotationInjectPython},
 		{instType: TypeDotNet, want: annotationInjectDotNet},
 		{instType: TypeGo, want: annotationInjectGo},
+		{instType: TypeRuby, want: annotationInjectRuby},
+		{instType: TypePHP, want: annotationInjectPHP},
 		{instType: "unsupported", want: ""},
 	}
 	for _, testCase := range testCases {
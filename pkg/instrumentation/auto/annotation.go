@@ -65,6 +65,9 @@ func (m *AnnotationMutators) MutateObject(oldObj client.Object, obj client.Objec
 
 // mutateObject modifies annotations for a single object using the configured mutators.
 func (m *AnnotationMutators) mutateObject(obj client.Object, _ any) (any, bool) {
+	if !isNamespace(obj) && IsQuarantined(obj) {
+		return map[string]string{}, false
+	}
 	switch o := obj.(type) {
 	case *corev1.Namespace:
 		return m.mutate(o.GetName(), m.namespaceMutators, o.GetObjectMeta())
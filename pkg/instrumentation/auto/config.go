@@ -4,10 +4,15 @@
 package auto
 
 import (
-	"slices"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
@@ -20,6 +25,7 @@ type AnnotationConfig struct {
 	Python AnnotationResources `json:"python"`
 	DotNet AnnotationResources `json:"dotnet"`
 	NodeJS AnnotationResources `json:"nodejs"`
+	Go     AnnotationResources `json:"go"`
 }
 
 func (c AnnotationConfig) getResources(instType instrumentation.Type) AnnotationResources {
@@ -32,21 +38,25 @@ func (c AnnotationConfig) getResources(instType instrumentation.Type) Annotation
 		return c.DotNet
 	case instrumentation.TypeNodeJS:
 		return c.NodeJS
+	case instrumentation.TypeGo:
+		return c.Go
 	default:
 		return AnnotationResources{}
 	}
 }
 
-// LanguagesOf get languages to annotate for an object
-func (c AnnotationConfig) LanguagesOf(obj client.Object, checkNamespace bool) instrumentation.TypeSet {
+// LanguagesOf get languages to annotate for an object. When checkNamespace is set, workloads are also selected by
+// their namespace, whose labels are evaluated against the namespace selectors.
+func (c AnnotationConfig) LanguagesOf(obj client.Object, checkNamespace bool, namespaceLabels labels.Set) instrumentation.TypeSet {
 	objName := namespacedName(obj)
 	typesSelected := instrumentation.TypeSet{}
 
 	types := instrumentation.SupportedTypes
 
-	if checkNamespace {
+	if checkNamespace && !isNamespace(obj) {
 		for t := range types {
-			if slices.Contains(c.getResources(t).Namespaces, obj.GetNamespace()) {
+			resources := c.getResources(t)
+			if matchesAny(resources.Namespaces, obj.GetNamespace()) || matchesSelector(resources.NamespaceSelector, namespaceLabels) {
 				typesSelected[t] = nil
 			}
 		}
@@ -55,25 +65,29 @@ func (c AnnotationConfig) LanguagesOf(obj client.Object, checkNamespace bool) in
 	switch obj.(type) {
 	case *appsv1.Deployment:
 		for t := range types {
-			if slices.Contains(c.getResources(t).Deployments, objName) {
+			resources := c.getResources(t)
+			if matchesAny(resources.Deployments, objName) || matchesSelector(resources.WorkloadSelector, obj.GetLabels()) {
 				typesSelected[t] = nil
 			}
 		}
 	case *appsv1.StatefulSet:
 		for t := range types {
-			if slices.Contains(c.getResources(t).StatefulSets, objName) {
+			resources := c.getResources(t)
+			if matchesAny(resources.StatefulSets, objName) || matchesSelector(resources.WorkloadSelector, obj.GetLabels()) {
 				typesSelected[t] = nil
 			}
 		}
 	case *appsv1.DaemonSet:
 		for t := range types {
-			if slices.Contains(c.getResources(t).DaemonSets, objName) {
+			resources := c.getResources(t)
+			if matchesAny(resources.DaemonSets, objName) || matchesSelector(resources.WorkloadSelector, obj.GetLabels()) {
 				typesSelected[t] = nil
 			}
 		}
 	case *corev1.Namespace:
 		for t := range types {
-			if slices.Contains(c.getResources(t).Namespaces, objName) {
+			resources := c.getResources(t)
+			if matchesAny(resources.Namespaces, objName) || matchesSelector(resources.NamespaceSelector, obj.GetLabels()) {
 				typesSelected[t] = nil
 			}
 		}
@@ -82,6 +96,57 @@ func (c AnnotationConfig) LanguagesOf(obj client.Object, checkNamespace bool) in
 	return typesSelected
 }
 
+// HasNamespaceSelector returns whether any of the resources select namespaces by label.
+func (c AnnotationConfig) HasNamespaceSelector() bool {
+	for t := range instrumentation.SupportedTypes {
+		if c.getResources(t).NamespaceSelector != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the name patterns and label selectors of the resources are well-formed.
+func (c AnnotationConfig) Validate() error {
+	var errs []error
+	for t := range instrumentation.SupportedTypes {
+		resources := c.getResources(t)
+		for _, pattern := range resources.names() {
+			if _, err := path.Match(pattern, ""); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid name pattern %q: %w", t, pattern, err))
+			}
+		}
+		if _, err := metav1.LabelSelectorAsSelector(resources.NamespaceSelector); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid namespaceSelector: %w", t, err))
+		}
+		if _, err := metav1.LabelSelectorAsSelector(resources.WorkloadSelector); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid workloadSelector: %w", t, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateForAutoAnnotation checks that the resources only use what the auto-annotation supports. It looks up the
+// resources by their exact name, the name patterns and label selectors are only supported by the auto-monitor.
+func (c AnnotationConfig) ValidateForAutoAnnotation() error {
+	var errs []error
+	for t := range instrumentation.SupportedTypes {
+		resources := c.getResources(t)
+		for _, name := range resources.names() {
+			if isPattern(name) {
+				errs = append(errs, fmt.Errorf("%s: name pattern %q is only supported by the auto-monitor", t, name))
+			}
+		}
+		if resources.NamespaceSelector != nil {
+			errs = append(errs, fmt.Errorf("%s: namespaceSelector is only supported by the auto-monitor", t))
+		}
+		if resources.WorkloadSelector != nil {
+			errs = append(errs, fmt.Errorf("%s: workloadSelector is only supported by the auto-monitor", t))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (c AnnotationConfig) Empty() bool {
 	for t := range instrumentation.SupportedTypes {
 		resources := c.getResources(t)
@@ -101,13 +166,68 @@ func (c AnnotationConfig) Empty() bool {
 	return true
 }
 
+// types returns the languages handled by the auto-annotation: the default languages, and Go only when resources are
+// configured for it.
+func (c AnnotationConfig) types() instrumentation.TypeSet {
+	types := instrumentation.NewTypeSet()
+	for t := range instrumentation.DefaultTypes {
+		types[t] = nil
+	}
+	if len(c.Go.names()) > 0 {
+		types[instrumentation.TypeGo] = nil
+	}
+	return types
+}
+
 // AnnotationResources contains slices of resource names for each
-// of the supported workloads.
+// of the supported workloads. With the auto-monitor, the names may be glob patterns, e.g. "payments-*" or "shop/*-api".
 type AnnotationResources struct {
 	Namespaces   []string `json:"namespaces,omitempty"`
 	Deployments  []string `json:"deployments,omitempty"`
 	DaemonSets   []string `json:"daemonsets,omitempty"`
 	StatefulSets []string `json:"statefulsets,omitempty"`
+	// NamespaceSelector selects namespaces by label. Only supported by the auto-monitor, the auto-annotation rejects it.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// WorkloadSelector selects deployments, daemonsets and statefulsets by label. Only supported by the auto-monitor,
+	// the auto-annotation rejects it.
+	WorkloadSelector *metav1.LabelSelector `json:"workloadSelector,omitempty"`
+}
+
+func (r AnnotationResources) names() []string {
+	var names []string
+	names = append(names, r.Namespaces...)
+	names = append(names, r.Deployments...)
+	names = append(names, r.DaemonSets...)
+	names = append(names, r.StatefulSets...)
+	return names
+}
+
+// isPattern returns whether the name uses glob pattern syntax.
+func isPattern(name string) bool {
+	return strings.ContainsAny(name, `*?[\`)
+}
+
+// matchesAny returns whether the name matches any of the glob patterns.
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesSelector returns whether the labels match the selector. A nil selector matches nothing, invalid selectors are
+// rejected by Validate before the config is used.
+func matchesSelector(selector *metav1.LabelSelector, set labels.Set) bool {
+	if selector == nil {
+		return false
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(set)
 }
 
 func getNamespaces(r AnnotationResources) []string {
@@ -7,6 +7,12 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
 )
@@ -37,6 +43,12 @@ func TestConfig(t *testing.T) {
 			DaemonSets:   []string{"ds3"},
 			StatefulSets: []string{"ss3"},
 		},
+		Go: AnnotationResources{
+			Namespaces:   []string{"n4"},
+			Deployments:  []string{"d4"},
+			DaemonSets:   []string{"ds4"},
+			StatefulSets: []string{"ss4"},
+		},
 	}
 
 	assert.Equal(t, cfg.Java, cfg.getResources(instrumentation.TypeJava))
@@ -52,4 +64,139 @@ func TestConfig(t *testing.T) {
 	assert.Equal(t, cfg.NodeJS, cfg.getResources(instrumentation.TypeNodeJS))
 	assert.Equal(t, []string{"ds3"}, getDaemonSets(cfg.NodeJS))
 	assert.Equal(t, []string{"ss3"}, getStatefulSets(cfg.NodeJS))
+	assert.Equal(t, cfg.Go, cfg.getResources(instrumentation.TypeGo))
+	assert.Equal(t, []string{"d4"}, getDeployments(cfg.Go))
+}
+
+func TestLanguagesOf(t *testing.T) {
+	cfg := AnnotationConfig{
+		Java: AnnotationResources{
+			Namespaces:  []string{"team-*"},
+			Deployments: []string{"prod/api-*"},
+		},
+		Python: AnnotationResources{
+			WorkloadSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/part-of": "shop"}},
+		},
+		NodeJS: AnnotationResources{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: metav1.LabelSelectorOpIn, Values: []string{"dev", "test"}}},
+			},
+		},
+		Go: AnnotationResources{
+			Deployments: []string{"prod/gateway"},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		obj             client.Object
+		checkNamespace  bool
+		namespaceLabels labels.Set
+		expected        instrumentation.TypeSet
+	}{
+		{
+			name:     "namespace glob",
+			obj:      &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+			expected: instrumentation.NewTypeSet(instrumentation.TypeJava),
+		},
+		{
+			name:     "namespace selector",
+			obj:      &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "sandbox", Labels: map[string]string{"env": "dev"}}},
+			expected: instrumentation.NewTypeSet(instrumentation.TypeNodeJS),
+		},
+		{
+			name:     "deployment glob",
+			obj:      &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api-orders", Namespace: "prod"}},
+			expected: instrumentation.NewTypeSet(instrumentation.TypeJava),
+		},
+		{
+			name:     "go deployment",
+			obj:      &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "prod"}},
+			expected: instrumentation.NewTypeSet(instrumentation.TypeGo),
+		},
+		{
+			name:     "deployment glob doesn't cross namespaces",
+			obj:      &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api-orders", Namespace: "staging"}},
+			expected: instrumentation.NewTypeSet(),
+		},
+		{
+			name:     "workload selector",
+			obj:      &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "prod", Labels: map[string]string{"app.kubernetes.io/part-of": "shop"}}},
+			expected: instrumentation.NewTypeSet(instrumentation.TypePython),
+		},
+		{
+			name:     "namespace rules ignored for workloads",
+			obj:      &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "team-a"}},
+			expected: instrumentation.NewTypeSet(),
+		},
+		{
+			name:            "namespace rules checked for workloads",
+			obj:             &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "team-a"}},
+			checkNamespace:  true,
+			namespaceLabels: labels.Set{"env": "test"},
+			expected:        instrumentation.NewTypeSet(instrumentation.TypeJava, instrumentation.TypeNodeJS),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, cfg.LanguagesOf(tt.obj, tt.checkNamespace, tt.namespaceLabels))
+		})
+	}
+}
+
+func TestAnnotationConfigValidate(t *testing.T) {
+	valid := AnnotationConfig{
+		Java: AnnotationResources{
+			Namespaces:       []string{"team-[a-c]"},
+			Deployments:      []string{"prod/*"},
+			WorkloadSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+		},
+	}
+	assert.NoError(t, valid.Validate())
+
+	invalid := AnnotationConfig{
+		Java: AnnotationResources{
+			Deployments: []string{"prod/[api"},
+		},
+		Python: AnnotationResources{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Unknown"}},
+			},
+		},
+	}
+	err := invalid.Validate()
+	assert.ErrorContains(t, err, "prod/[api")
+	assert.ErrorContains(t, err, "namespaceSelector")
+}
+
+func TestAnnotationConfigValidateForAutoAnnotation(t *testing.T) {
+	valid := AnnotationConfig{
+		Java: AnnotationResources{
+			Namespaces:  []string{"team-a"},
+			Deployments: []string{"prod/api"},
+		},
+	}
+	assert.NoError(t, valid.ValidateForAutoAnnotation())
+
+	invalid := AnnotationConfig{
+		Java: AnnotationResources{
+			Deployments: []string{"prod/*"},
+		},
+		Python: AnnotationResources{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			WorkloadSelector:  &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+		},
+	}
+	err := invalid.ValidateForAutoAnnotation()
+	assert.ErrorContains(t, err, `name pattern "prod/*" is only supported by the auto-monitor`)
+	assert.ErrorContains(t, err, "namespaceSelector is only supported by the auto-monitor")
+	assert.ErrorContains(t, err, "workloadSelector is only supported by the auto-monitor")
+}
+
+func TestAnnotationConfigTypes(t *testing.T) {
+	assert.Equal(t, instrumentation.DefaultTypes, AnnotationConfig{Java: AnnotationResources{Namespaces: []string{"team-a"}}}.types())
+
+	withGo := AnnotationConfig{Go: AnnotationResources{Deployments: []string{"prod/api"}}}
+	assert.Equal(t, instrumentation.NewTypeSet(instrumentation.TypeJava, instrumentation.TypeNodeJS, instrumentation.TypePython, instrumentation.TypeDotNet, instrumentation.TypeGo), withGo.types())
 }
@@ -0,0 +1,184 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
+
+const (
+	// AnnotationDetectedLanguages records on the pod template of an auto-monitored workload the languages detected by
+	// the LanguageDetector.
+	AnnotationDetectedLanguages = "cloudwatch.aws.amazon.com/auto-monitor-detected-languages"
+	// undetectedLanguages is recorded when no language was detected, in which case the workload is only instrumented
+	// for all the configured languages if InstrumentUndetected is set.
+	undetectedLanguages = "undetected"
+)
+
+// LanguageDetector detects the languages of the application running in the pods of a workload.
+type LanguageDetector interface {
+	// DetectLanguages returns the languages detected in the pod template. An empty set means no language was detected.
+	DetectLanguages(template *corev1.PodTemplateSpec) instrumentation.TypeSet
+}
+
+// languageLabels are the pod template labels whose value names the language of the application.
+var languageLabels = []string{"app.kubernetes.io/language", "language", "runtime"}
+
+// languageHints are the signs of a language in a pod template.
+type languageHints struct {
+	// names are the values of the language labels naming the language.
+	names []string
+	// images are the path segments of the image repositories, e.g. openjdk in public.ecr.aws/docker/library/openjdk:17.
+	images []string
+	// executables are the programs started by the command or arguments of the containers.
+	executables []string
+	// extensions are the extensions of the files passed to the executables, e.g. .jar in java -jar app.jar.
+	extensions []string
+	// env are the names of the environment variables read by the runtime.
+	env []string
+}
+
+var defaultLanguageHints = map[instrumentation.Type]languageHints{
+	instrumentation.TypeJava: {
+		names:       []string{"java", "kotlin", "scala"},
+		images:      []string{"java", "openjdk", "jdk", "jre", "amazoncorretto", "corretto", "eclipse-temurin", "temurin", "zulu", "tomcat", "jetty", "wildfly"},
+		executables: []string{"java", "catalina.sh", "mvn", "gradle"},
+		extensions:  []string{".jar", ".war"},
+		env:         []string{"JAVA_TOOL_OPTIONS", "JAVA_OPTS", "JDK_JAVA_OPTIONS", "JAVA_HOME"},
+	},
+	instrumentation.TypePython: {
+		names:       []string{"python"},
+		images:      []string{"python", "pypy"},
+		executables: []string{"python", "python3", "python2", "gunicorn", "uvicorn", "celery", "flask", "django-admin"},
+		extensions:  []string{".py"},
+		env:         []string{"PYTHONPATH", "PYTHONHOME", "PYTHONUNBUFFERED", "PYTHONDONTWRITEBYTECODE"},
+	},
+	instrumentation.TypeNodeJS: {
+		names:       []string{"nodejs", "node", "javascript", "typescript"},
+		images:      []string{"node", "nodejs"},
+		executables: []string{"node", "nodejs", "npm", "npx", "yarn", "pnpm"},
+		extensions:  []string{".js", ".mjs", ".cjs"},
+		env:         []string{"NODE_OPTIONS", "NODE_ENV", "NODE_PATH"},
+	},
+	instrumentation.TypeDotNet: {
+		names:       []string{"dotnet", ".net", "csharp"},
+		images:      []string{"dotnet", "aspnet"},
+		executables: []string{"dotnet"},
+		extensions:  []string{".dll"},
+		env:         []string{"ASPNETCORE_URLS", "ASPNETCORE_ENVIRONMENT", "DOTNET_RUNNING_IN_CONTAINER", "DOTNET_ROOT"},
+	},
+	// Go applications are compiled, so they are mostly detected from the variables read by the Go runtime.
+	instrumentation.TypeGo: {
+		names:  []string{"go", "golang"},
+		images: []string{"golang"},
+		env:    []string{"GOMAXPROCS", "GOMEMLIMIT", "GOGC", "GODEBUG", "GOTRACEBACK"},
+	},
+}
+
+// podTemplateDetector detects the languages from the labels of the pod template and the images, commands, arguments
+// and environment variables of its containers.
+type podTemplateDetector struct {
+	hints map[instrumentation.Type]languageHints
+}
+
+// NewPodTemplateDetector creates a LanguageDetector inspecting the pod template of the workloads.
+func NewPodTemplateDetector() LanguageDetector {
+	return &podTemplateDetector{hints: defaultLanguageHints}
+}
+
+func (d *podTemplateDetector) DetectLanguages(template *corev1.PodTemplateSpec) instrumentation.TypeSet {
+	detected := instrumentation.TypeSet{}
+	if template == nil {
+		return detected
+	}
+	for language, hints := range d.hints {
+		if hints.matchLabels(template.Labels) {
+			detected[language] = nil
+			continue
+		}
+		for _, container := range template.Spec.Containers {
+			if hints.matchContainer(container) {
+				detected[language] = nil
+				break
+			}
+		}
+	}
+	return detected
+}
+
+func (h languageHints) matchLabels(labels map[string]string) bool {
+	for _, label := range languageLabels {
+		if value, ok := labels[label]; ok && containsFold(h.names, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h languageHints) matchContainer(container corev1.Container) bool {
+	for _, segment := range imageSegments(container.Image) {
+		if containsFold(h.images, segment) {
+			return true
+		}
+	}
+	for _, arg := range append(append([]string{}, container.Command...), container.Args...) {
+		// commands run through a shell, e.g. sh -c "java -jar app.jar", are split into words
+		for _, word := range strings.Fields(arg) {
+			if containsFold(h.executables, path.Base(word)) {
+				return true
+			}
+			if containsFold(h.extensions, path.Ext(word)) {
+				return true
+			}
+		}
+	}
+	for _, env := range container.Env {
+		if containsFold(h.env, env.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// imageSegments returns the path segments of the image repository without the registry, tag and digest, e.g. library
+// and openjdk for docker.io/library/openjdk:17. The segments are matched whole, so that node-exporter isn't taken for
+// a node image.
+func imageSegments(image string) []string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	if registry, repository, ok := strings.Cut(image, "/"); ok && strings.ContainsAny(registry, ".:") {
+		image = repository
+	}
+	return strings.Split(image, "/")
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectedLanguagesValue returns the value of the AnnotationDetectedLanguages annotation.
+func detectedLanguagesValue(detected instrumentation.TypeSet) string {
+	if len(detected) == 0 {
+		return undetectedLanguages
+	}
+	languages := make([]string, 0, len(detected))
+	for language := range detected {
+		languages = append(languages, string(language))
+	}
+	sort.Strings(languages)
+	return strings.Join(languages, ",")
+}
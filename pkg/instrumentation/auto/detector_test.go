@@ -0,0 +1,127 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
+
+func TestPodTemplateDetector(t *testing.T) {
+	tests := []struct {
+		name      string
+		labels    map[string]string
+		container corev1.Container
+		want      instrumentation.TypeSet
+	}{
+		{
+			name:      "java image",
+			container: corev1.Container{Image: "public.ecr.aws/docker/library/amazoncorretto:17-alpine"},
+			want:      instrumentation.NewTypeSet(instrumentation.TypeJava),
+		},
+		{
+			name:      "python image with digest",
+			container: corev1.Container{Image: "python@sha256:0123456789abcdef"},
+			want:      instrumentation.NewTypeSet(instrumentation.TypePython),
+		},
+		{
+			name:      "registry with port",
+			container: corev1.Container{Image: "localhost:5000/node:20"},
+			want:      instrumentation.NewTypeSet(instrumentation.TypeNodeJS),
+		},
+		{
+			name:      "image name containing a language",
+			container: corev1.Container{Image: "quay.io/prometheus/node-exporter:v1.8.0"},
+			want:      instrumentation.TypeSet{},
+		},
+		{
+			name:      "image name ending with a language",
+			container: corev1.Container{Image: "602401143452.dkr.ecr.us-west-2.amazonaws.com/aws-node:v1.18.0"},
+			want:      instrumentation.TypeSet{},
+		},
+		{
+			name:      "image name with a language prefix",
+			container: corev1.Container{Image: "team/python-tools:1.0"},
+			want:      instrumentation.TypeSet{},
+		},
+		{
+			name:      "hyphenated official image",
+			container: corev1.Container{Image: "eclipse-temurin:21-jre"},
+			want:      instrumentation.NewTypeSet(instrumentation.TypeJava),
+		},
+		{
+			name:      "dotnet image",
+			container: corev1.Container{Image: "mcr.microsoft.com/dotnet/aspnet:8.0"},
+			want:      instrumentation.NewTypeSet(instrumentation.TypeDotNet),
+		},
+		{
+			name:      "java -jar",
+			container: corev1.Container{Image: "shop/api:1.0", Command: []string{"/opt/java/bin/java"}, Args: []string{"-jar", "app.jar"}},
+			want:      instrumentation.NewTypeSet(instrumentation.TypeJava),
+		},
+		{
+			name:      "shell command",
+			container: corev1.Container{Image: "shop/api:1.0", Command: []string{"sh", "-c", "exec gunicorn app:server"}},
+			want:      instrumentation.NewTypeSet(instrumentation.TypePython),
+		},
+		{
+			name:      "script",
+			container: corev1.Container{Image: "shop/api:1.0", Args: []string{"server.mjs"}},
+			want:      instrumentation.NewTypeSet(instrumentation.TypeNodeJS),
+		},
+		{
+			name:      "environment variable",
+			container: corev1.Container{Image: "shop/api:1.0", Env: []corev1.EnvVar{{Name: "JAVA_TOOL_OPTIONS", Value: "-Xmx1g"}}},
+			want:      instrumentation.NewTypeSet(instrumentation.TypeJava),
+		},
+		{
+			name:      "language label",
+			labels:    map[string]string{"app.kubernetes.io/language": "Python"},
+			container: corev1.Container{Image: "shop/api:1.0"},
+			want:      instrumentation.NewTypeSet(instrumentation.TypePython),
+		},
+		{
+			name:      "go runtime environment variable",
+			container: corev1.Container{Image: "shop/api:1.0", Env: []corev1.EnvVar{{Name: "GOMEMLIMIT", Value: "512MiB"}}},
+			want:      instrumentation.NewTypeSet(instrumentation.TypeGo),
+		},
+		{
+			name:      "go language label",
+			labels:    map[string]string{"language": "golang"},
+			container: corev1.Container{Image: "shop/api:1.0"},
+			want:      instrumentation.NewTypeSet(instrumentation.TypeGo),
+		},
+		{
+			name:      "several languages",
+			container: corev1.Container{Image: "node:20", Command: []string{"dotnet", "api.dll"}},
+			want:      instrumentation.NewTypeSet(instrumentation.TypeNodeJS, instrumentation.TypeDotNet),
+		},
+		{
+			name:      "unknown",
+			container: corev1.Container{Image: "nginx:1.25", Args: []string{"-g", "daemon off;"}},
+			want:      instrumentation.TypeSet{},
+		},
+	}
+	detector := NewPodTemplateDetector()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: tt.labels},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{tt.container}},
+			}
+			assert.Equal(t, tt.want, detector.DetectLanguages(template))
+		})
+	}
+	assert.Empty(t, detector.DetectLanguages(nil))
+}
+
+func TestDetectedLanguagesValue(t *testing.T) {
+	assert.Equal(t, "undetected", detectedLanguagesValue(instrumentation.TypeSet{}))
+	assert.Equal(t, "java,python", detectedLanguagesValue(instrumentation.NewTypeSet(instrumentation.TypePython, instrumentation.TypeJava)))
+}
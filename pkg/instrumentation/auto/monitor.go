@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"reflect"
 	"slices"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -20,6 +21,7 @@ import (
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
@@ -84,10 +86,16 @@ type InstrumentationAnnotator interface {
 	MutateAndPatchAll(ctx context.Context)
 }
 
+// namespaceSyncTimeout bounds how long SetConfig waits for the namespace informer it starts to sync.
+const namespaceSyncTimeout = 30 * time.Second
+
 type Monitor struct {
 	serviceInformer     cache.SharedIndexInformer
 	ctx                 context.Context
+	mu                  sync.RWMutex
 	config              MonitorConfig
+	initialConfig       MonitorConfig
+	workloadFactory     informers.SharedInformerFactory
 	k8sInterface        kubernetes.Interface
 	clientReader        client.Reader
 	clientWriter        client.Writer
@@ -95,13 +103,140 @@ type Monitor struct {
 	deploymentInformer  cache.SharedIndexInformer
 	daemonsetInformer   cache.SharedIndexInformer
 	statefulsetInformer cache.SharedIndexInformer
+	// namespaceInformer is only set when namespaces are excluded by label.
+	namespaceInformer cache.SharedIndexInformer
+	// namespaceInformerMu serializes the creation and start of the namespace informer by SetConfig.
+	namespaceInformerMu sync.Mutex
+	// detector narrows down the languages of the auto-monitored workloads when DetectLanguages is enabled.
+	detector LanguageDetector
+	// restarts restarts the workloads following the RestartStrategy.
+	restarts *restartScheduler
 }
 
 func (m *Monitor) MutateAndPatchAll(ctx context.Context) {
-	if m.config.RestartPods {
+	config := m.getConfig()
+	if config.RestartPods && config.RestartStrategy != nil && m.restarts != nil {
+		m.scheduleRestarts(ctx, *config.RestartStrategy)
+		return
+	}
+	// the config changed, so the scheduled restarts are outdated
+	m.restarts.Stop()
+	restartPods := config.RestartPods
+	if restartPods {
 		MutateAndPatchWorkloads(m, ctx)
 	}
-	MutateAndPatchNamespaces(m, ctx, m.config.RestartPods)
+	MutateAndPatchNamespaces(m, ctx, restartPods)
+}
+
+// scheduleRestarts mutates the namespaces right away, and leaves the workloads to restart, either to mutate them or
+// because their namespace was mutated, to the restart scheduler.
+func (m *Monitor) scheduleRestarts(ctx context.Context, strategy RestartStrategy) {
+	var restarts []pendingRestart
+	scheduled := map[string]bool{}
+	schedule := func(obj client.Object, mutate objectCallbackFunc) {
+		restart := pendingRestart{obj: obj, mutate: mutate}
+		if !scheduled[restart.key()] {
+			scheduled[restart.key()] = true
+			restarts = append(restarts, restart)
+		}
+	}
+
+	scheduleMutatedWorkload := func(obj client.Object, _ any) (any, bool) {
+		if mutatedAnnotations := m.MutateObject(nil, obj.DeepCopyObject().(client.Object)).(map[string]string); len(mutatedAnnotations) > 0 {
+			schedule(obj, getMutateObjectFunc(m))
+		}
+		return nil, true
+	}
+	rangeObjectList(m, ctx, &appsv1.DeploymentList{}, &client.ListOptions{}, scheduleMutatedWorkload)
+	rangeObjectList(m, ctx, &appsv1.DaemonSetList{}, &client.ListOptions{}, scheduleMutatedWorkload)
+	rangeObjectList(m, ctx, &appsv1.StatefulSetList{}, &client.ListOptions{}, scheduleMutatedWorkload)
+
+	scheduleNamespaceRestart := func(obj client.Object, previousResult any) (any, bool) {
+		mutatedAnnotations, ok := previousResult.(map[string]string)
+		if !ok {
+			return nil, false
+		}
+		shouldRestart := shouldRestartFunc(m, mutatedAnnotations)
+		scheduleWorkload := func(obj client.Object, _ any) (any, bool) {
+			if _, ok := shouldRestart(obj, nil); ok {
+				schedule(obj, setRestartAnnotation)
+			}
+			return nil, true
+		}
+		rangeObjectList(m, ctx, &appsv1.DeploymentList{}, client.InNamespace(obj.GetName()), scheduleWorkload)
+		rangeObjectList(m, ctx, &appsv1.DaemonSetList{}, client.InNamespace(obj.GetName()), scheduleWorkload)
+		rangeObjectList(m, ctx, &appsv1.StatefulSetList{}, client.InNamespace(obj.GetName()), scheduleWorkload)
+		return nil, true
+	}
+	rangeObjectList(m, ctx, &corev1.NamespaceList{}, &client.ListOptions{}, chainCallbacks(patchFunc(m, ctx, getMutateObjectFunc(m)), scheduleNamespaceRestart))
+
+	// the restarts outlive the caller, e.g. a reconciliation of the AutoMonitor
+	m.restarts.Schedule(m.ctx, strategy, restarts)
+}
+
+// SetEventRecorder sets the recorder of the events about the workloads restarted following the RestartStrategy.
+func (m *Monitor) SetEventRecorder(recorder record.EventRecorder) {
+	if m.restarts != nil {
+		m.restarts.setRecorder(recorder)
+	}
+}
+
+func (m *Monitor) getConfig() MonitorConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config
+}
+
+// InitialConfig returns the config the monitor was created with.
+func (m *Monitor) InitialConfig() MonitorConfig {
+	return m.initialConfig
+}
+
+// SetConfig replaces the config of the monitor and returns whether it changed. The new config applies to the
+// workloads and namespaces mutated from now on, MutateAndPatchAll applies it to the existing ones. An invalid config,
+// or a namespace informer that cannot be started, is returned as an error and leaves the current config in place.
+func (m *Monitor) SetConfig(config MonitorConfig) (bool, error) {
+	if err := config.Validate(); err != nil {
+		return false, err
+	}
+	setConfigDefaults(&config, m.logger)
+	if needsNamespaceInformer(config) {
+		if err := m.startNamespaceInformer(); err != nil {
+			return false, err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if reflect.DeepEqual(m.config, config) {
+		return false, nil
+	}
+	warnNonNamespacedNames(config.Exclude, m.logger)
+	m.config = config
+	return true, nil
+}
+
+// SelectedWorkloads returns the number of deployments, daemonsets and statefulsets currently selected for each
+// language, including the ones selected through their namespace.
+func (m *Monitor) SelectedWorkloads() map[instrumentation.Type]int32 {
+	config := m.getConfig()
+	selected := map[instrumentation.Type]int32{}
+	for _, informer := range []cache.SharedIndexInformer{m.deploymentInformer, m.daemonsetInformer, m.statefulsetInformer} {
+		if informer == nil {
+			continue
+		}
+		for _, obj := range informer.GetStore().List() {
+			workload, ok := obj.(client.Object)
+			if !ok {
+				continue
+			}
+			languages, _ := m.languagesOf(config, workload, true)
+			for l := range languages {
+				selected[l]++
+			}
+		}
+	}
+	return selected
 }
 
 func (m *Monitor) GetLogger() logr.Logger {
@@ -118,11 +253,7 @@ func (m *Monitor) GetWriter() client.Writer {
 
 // NewMonitor is used to create an InstrumentationMutator that supports AutoMonitor.
 func NewMonitor(ctx context.Context, config MonitorConfig, k8sClient kubernetes.Interface, w client.Writer, r client.Reader, logger logr.Logger) *Monitor {
-	// Config default values
-	if len(config.Languages) == 0 {
-		logger.V(1).Info("Setting languages to default", "languages", instrumentation.SupportedTypes)
-		config.Languages = instrumentation.SupportedTypes
-	}
+	setConfigDefaults(&config, logger)
 
 	logger.V(1).Info("AutoMonitor starting...")
 	serviceFactory := informers.NewSharedInformerFactoryWithOptions(k8sClient, informerResyncPeriod)
@@ -166,12 +297,23 @@ func NewMonitor(ctx context.Context, config MonitorConfig, k8sClient kubernetes.
 		logger.Error(err, "Creating statefulset informer failed")
 	}
 
+	// create namespace informer, only needed to evaluate the namespace selectors
+	var namespaceInformer cache.SharedIndexInformer
+	if needsNamespaceInformer(config) {
+		namespaceInformer, err = createNamespaceInformer(workloadFactory)
+		if err != nil {
+			logger.Error(err, "Creating namespace informer failed")
+		}
+	}
+
 	warnNonNamespacedNames(config.Exclude, logger)
 
 	m := &Monitor{
 		serviceInformer:     serviceInformer,
 		ctx:                 ctx,
 		config:              config,
+		initialConfig:       config,
+		workloadFactory:     workloadFactory,
 		k8sInterface:        k8sClient,
 		clientReader:        r,
 		clientWriter:        w,
@@ -179,6 +321,9 @@ func NewMonitor(ctx context.Context, config MonitorConfig, k8sClient kubernetes.
 		deploymentInformer:  deploymentInformer,
 		daemonsetInformer:   daemonsetInformer,
 		statefulsetInformer: statefulSetInformer,
+		namespaceInformer:   namespaceInformer,
+		detector:            NewPodTemplateDetector(),
+		restarts:            newRestartScheduler(r, w, logger),
 	}
 
 	_, err = serviceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -215,6 +360,51 @@ func NewMonitor(ctx context.Context, config MonitorConfig, k8sClient kubernetes.
 	return m
 }
 
+func setConfigDefaults(config *MonitorConfig, logger logr.Logger) {
+	if len(config.Languages) == 0 {
+		logger.V(1).Info("Setting languages to default", "languages", instrumentation.DefaultTypes)
+		config.Languages = instrumentation.DefaultTypes
+	}
+}
+
+// needsNamespaceInformer returns whether namespaces are selected by label, which requires the namespace labels of the
+// workloads.
+func needsNamespaceInformer(config MonitorConfig) bool {
+	return config.Exclude.HasNamespaceSelector() || config.CustomSelector.HasNamespaceSelector()
+}
+
+// startNamespaceInformer starts the namespace informer if the monitor was created without it, and waits at most
+// namespaceSyncTimeout for it to sync. An informer that did not sync in time keeps syncing in the background and is
+// waited for again by the next call.
+func (m *Monitor) startNamespaceInformer() error {
+	m.namespaceInformerMu.Lock()
+	defer m.namespaceInformerMu.Unlock()
+	if m.workloadFactory == nil {
+		return nil
+	}
+	m.mu.RLock()
+	namespaceInformer := m.namespaceInformer
+	m.mu.RUnlock()
+	if namespaceInformer == nil {
+		var err error
+		namespaceInformer, err = createNamespaceInformer(m.workloadFactory)
+		if err != nil {
+			return fmt.Errorf("creating namespace informer failed: %w", err)
+		}
+		m.workloadFactory.Start(m.ctx.Done())
+		m.mu.Lock()
+		m.namespaceInformer = namespaceInformer
+		m.mu.Unlock()
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, namespaceSyncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(ctx.Done(), namespaceInformer.HasSynced) {
+		return fmt.Errorf("namespace informer failed to sync within %s", namespaceSyncTimeout)
+	}
+	return nil
+}
+
 func createDaemonsetInformer(workloadFactory informers.SharedInformerFactory) (cache.SharedIndexInformer, error) {
 	daemonsetInformer := workloadFactory.Apps().V1().DaemonSets().Informer()
 	err := daemonsetInformer.SetTransform(func(obj interface{}) (interface{}, error) {
@@ -224,8 +414,10 @@ func createDaemonsetInformer(workloadFactory informers.SharedInformerFactory) (c
 		}
 		return &appsv1.DaemonSet{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      daemonset.Name,
-				Namespace: daemonset.Namespace,
+				Name:        daemonset.Name,
+				Namespace:   daemonset.Namespace,
+				Labels:      daemonset.Labels,
+				Annotations: quarantineAnnotations(daemonset.Annotations),
 			},
 			Spec: appsv1.DaemonSetSpec{
 				Template: daemonset.Spec.Template,
@@ -253,8 +445,10 @@ func createStatefulsetInformer(workloadFactory informers.SharedInformerFactory)
 		}
 		return &appsv1.StatefulSet{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      statefulSet.Name,
-				Namespace: statefulSet.Namespace,
+				Name:        statefulSet.Name,
+				Namespace:   statefulSet.Namespace,
+				Labels:      statefulSet.Labels,
+				Annotations: quarantineAnnotations(statefulSet.Annotations),
 			},
 			Spec: appsv1.StatefulSetSpec{
 				Template: statefulSet.Spec.Template,
@@ -282,8 +476,10 @@ func createDeploymentInformer(workloadFactory informers.SharedInformerFactory) (
 		}
 		return &appsv1.Deployment{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      deployment.Name,
-				Namespace: deployment.Namespace,
+				Name:        deployment.Name,
+				Namespace:   deployment.Namespace,
+				Labels:      deployment.Labels,
+				Annotations: quarantineAnnotations(deployment.Annotations),
 			},
 			Spec: appsv1.DeploymentSpec{
 				Template: deployment.Spec.Template,
@@ -303,8 +499,40 @@ func createDeploymentInformer(workloadFactory informers.SharedInformerFactory) (
 	return deploymentInformer, err
 }
 
+func createNamespaceInformer(workloadFactory informers.SharedInformerFactory) (cache.SharedIndexInformer, error) {
+	namespaceInformer := workloadFactory.Core().V1().Namespaces().Informer()
+	err := namespaceInformer.SetTransform(func(obj interface{}) (interface{}, error) {
+		namespace, ok := obj.(*corev1.Namespace)
+		if !ok {
+			return obj, fmt.Errorf("error transforming namespace: %s not a namespace", obj)
+		}
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   namespace.Name,
+				Labels: namespace.Labels,
+			},
+		}, nil
+	})
+	return namespaceInformer, err
+}
+
+// namespaceLabels returns the labels of the namespace if they are needed to evaluate the namespace selectors.
+func (m *Monitor) namespaceLabels(name string) labels.Set {
+	m.mu.RLock()
+	namespaceInformer := m.namespaceInformer
+	m.mu.RUnlock()
+	if namespaceInformer == nil || name == "" {
+		return nil
+	}
+	obj, exists, err := namespaceInformer.GetStore().GetByKey(name)
+	if err != nil || !exists {
+		return nil
+	}
+	return obj.(*corev1.Namespace).Labels
+}
+
 func (m *Monitor) onServiceEvent(oldService *corev1.Service, service *corev1.Service) {
-	if !m.config.RestartPods {
+	if !m.getConfig().RestartPods {
 		return
 	}
 	for _, resource := range m.listServiceDeployments(oldService, service) {
@@ -450,34 +678,101 @@ func getTemplateSpecLabels(obj metav1.Object) labels.Set {
 	}
 }
 
-// MutateObject adds all enabled languages in config. Should only be run if selected by auto monitor or custom selector
+// MutateObject adds all enabled languages in config, or only the detected ones when DetectLanguages is enabled, and
+// records the detected languages. Should only be run if selected by auto monitor or custom selector
 func (m *Monitor) MutateObject(oldObj client.Object, obj client.Object) any {
-	if !safeToMutate(oldObj, obj, m.config.RestartPods) {
+	config := m.getConfig()
+	if !safeToMutate(oldObj, obj, config.RestartPods) {
 		return map[string]string{}
 	}
 
-	languagesToAnnotate := m.config.CustomSelector.LanguagesOf(obj, false)
-	if m.isWorkloadAutoMonitored(obj) {
-		for l := range m.config.Languages {
-			languagesToAnnotate[l] = nil
+	languagesToAnnotate, detected := m.languagesOf(config, obj, false)
+
+	m.logger.V(2).Info("languages to annotate", "objName", obj.GetName(), "languages", languagesToAnnotate)
+	mutatedAnnotations := mutate(obj, languagesToAnnotate)
+	for k, v := range recordDetectedLanguages(obj, detected) {
+		mutatedAnnotations[k] = v
+	}
+	return mutatedAnnotations
+}
+
+// languagesOf returns the languages selected for the object by the config. When includeNamespace is set, workloads
+// are also selected by the custom selector of their namespace. Quarantined workloads are never selected. The languages
+// detected in the workload are also returned when the config enables detection and the workload is auto-monitored, nil
+// otherwise.
+func (m *Monitor) languagesOf(config MonitorConfig, obj client.Object, includeNamespace bool) (instrumentation.TypeSet, instrumentation.TypeSet) {
+	if IsQuarantined(obj) {
+		return instrumentation.TypeSet{}, nil
+	}
+	namespaceLabels := m.namespaceLabels(obj.GetNamespace())
+	languages := config.CustomSelector.LanguagesOf(obj, includeNamespace, namespaceLabels)
+	var detected instrumentation.TypeSet
+	if m.isWorkloadAutoMonitored(config, obj) {
+		autoMonitored := config.Languages
+		if config.DetectLanguages && m.detector != nil {
+			detected = m.detector.DetectLanguages(getPodTemplate(obj))
+			autoMonitored = detectedLanguagesOf(config.Languages, detected, config.InstrumentUndetected)
+		}
+		for l := range autoMonitored {
+			languages[l] = nil
 		}
 	}
 
-	for l := range m.config.Exclude.LanguagesOf(obj, true) {
-		delete(languagesToAnnotate, l)
+	for l := range config.Exclude.LanguagesOf(obj, true, namespaceLabels) {
+		delete(languages, l)
 	}
+	return languages, detected
+}
 
-	m.logger.V(2).Info("languages to annotate", "objName", obj.GetName(), "languages", languagesToAnnotate)
-	return mutate(obj, languagesToAnnotate)
+// detectedLanguagesOf returns the configured languages that were detected. Workloads with no detected language are
+// not instrumented, unless instrumentUndetected is set, in which case they are instrumented for all the configured
+// languages.
+func detectedLanguagesOf(configured, detected instrumentation.TypeSet, instrumentUndetected bool) instrumentation.TypeSet {
+	if len(detected) == 0 && instrumentUndetected {
+		return configured
+	}
+	languages := instrumentation.TypeSet{}
+	for l := range detected {
+		if _, ok := configured[l]; ok {
+			languages[l] = nil
+		}
+	}
+	return languages
+}
+
+// recordDetectedLanguages sets the AnnotationDetectedLanguages annotation of the pod template to the detected
+// languages, or removes it when detection didn't run. It returns the annotation if it changed.
+func recordDetectedLanguages(obj client.Object, detected instrumentation.TypeSet) map[string]string {
+	podTemplate := getPodTemplate(obj)
+	if podTemplate == nil {
+		return nil
+	}
+	current, exists := podTemplate.Annotations[AnnotationDetectedLanguages]
+	if detected == nil {
+		if !exists {
+			return nil
+		}
+		delete(podTemplate.Annotations, AnnotationDetectedLanguages)
+		return map[string]string{AnnotationDetectedLanguages: current}
+	}
+	value := detectedLanguagesValue(detected)
+	if exists && current == value {
+		return nil
+	}
+	if podTemplate.Annotations == nil {
+		podTemplate.Annotations = map[string]string{}
+	}
+	podTemplate.Annotations[AnnotationDetectedLanguages] = value
+	return map[string]string{AnnotationDetectedLanguages: value}
 }
 
 // returns if workload is auto monitored (does not include custom selector)
-func (m *Monitor) isWorkloadAutoMonitored(obj client.Object) bool {
+func (m *Monitor) isWorkloadAutoMonitored(config MonitorConfig, obj client.Object) bool {
 	if isNamespace(obj) {
 		return false
 	}
 
-	if !m.config.MonitorAllServices {
+	if !config.MonitorAllServices {
 		return false
 	}
 
@@ -494,7 +789,7 @@ func (m *Monitor) isWorkloadAutoMonitored(obj client.Object) bool {
 		serviceSelector := labels.SelectorFromSet(service.Spec.Selector)
 
 		if serviceSelector.Matches(objectLabels) {
-			m.logger.V(2).Info(fmt.Sprintf("setting %s instrumentation annotations to %s because it is owned by service %s", obj.GetName(), m.config.Languages, service.Name))
+			m.logger.V(2).Info(fmt.Sprintf("setting %s instrumentation annotations to %s because it is owned by service %s", obj.GetName(), config.Languages, service.Name))
 			return true
 		}
 	}
@@ -517,11 +812,13 @@ func mutate(object client.Object, languagesToMonitor instrumentation.TypeSet) ma
 	}
 
 	allMutatedAnnotations := map[string]string{}
+	inserted := false
 	for language := range instrumentation.SupportedTypes {
 		insertMutation, removeMutation := buildMutations(language)
 		var mutatedAnnotations map[string]string
 		if _, ok := languagesToMonitor[language]; ok {
 			mutatedAnnotations = insertMutation.Mutate(annotations)
+			inserted = inserted || len(mutatedAnnotations) > 0
 		} else {
 			mutatedAnnotations = removeMutation.Mutate(annotations)
 		}
@@ -529,6 +826,14 @@ func mutate(object client.Object, languagesToMonitor instrumentation.TypeSet) ma
 			allMutatedAnnotations[k] = v
 		}
 	}
+	// the time the pod template was instrumented changes along with the returned annotations, it isn't returned itself
+	if podTemplate != nil {
+		if inserted {
+			annotations[instrumentation.AnnotationInstrumentedAt] = time.Now().Format(time.RFC3339)
+		} else if len(languagesToMonitor) == 0 {
+			delete(annotations, instrumentation.AnnotationInstrumentedAt)
+		}
+	}
 	obj.SetAnnotations(annotations)
 	return allMutatedAnnotations
 }
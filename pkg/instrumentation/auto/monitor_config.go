@@ -3,14 +3,78 @@
 
 package auto
 
-import "github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
 
 // AnnotationConfig details the resources that have enabled
 // auto-annotation for each instrumentation type.
 type MonitorConfig struct {
-	MonitorAllServices bool                    `json:"monitorAllServices"`
-	Languages          instrumentation.TypeSet `json:"languages,omitempty"`
-	RestartPods        bool                    `json:"restartPods"`
-	Exclude            AnnotationConfig        `json:"exclude,omitempty"`
-	CustomSelector     AnnotationConfig        `json:"customSelector,omitempty"`
+	MonitorAllServices   bool                    `json:"monitorAllServices"`
+	Languages            instrumentation.TypeSet `json:"languages,omitempty"`
+	DetectLanguages      bool                    `json:"detectLanguages,omitempty"`
+	InstrumentUndetected bool                    `json:"instrumentUndetected,omitempty"`
+	RestartPods          bool                    `json:"restartPods"`
+	RestartStrategy      *RestartStrategy        `json:"restartStrategy,omitempty"`
+	Exclude              AnnotationConfig        `json:"exclude,omitempty"`
+	CustomSelector       AnnotationConfig        `json:"customSelector,omitempty"`
+}
+
+// Validate checks that the name patterns and label selectors of the config are well-formed.
+func (c *MonitorConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if err := c.Exclude.Validate(); err != nil {
+		return fmt.Errorf("exclude: %w", err)
+	}
+	if err := c.CustomSelector.Validate(); err != nil {
+		return fmt.Errorf("customSelector: %w", err)
+	}
+	if err := c.RestartStrategy.Validate(); err != nil {
+		return fmt.Errorf("restartStrategy: %w", err)
+	}
+	return nil
+}
+
+// MonitorConfigFromSpec converts the spec of an AutoMonitor to a MonitorConfig.
+func MonitorConfigFromSpec(spec v1alpha1.AutoMonitorSpec) MonitorConfig {
+	config := MonitorConfig{
+		MonitorAllServices:   spec.MonitorAllServices,
+		DetectLanguages:      spec.DetectLanguages,
+		InstrumentUndetected: spec.InstrumentUndetected,
+		RestartPods:          spec.RestartPods,
+		Exclude:              annotationConfigFromSelector(spec.Exclude),
+		CustomSelector:       annotationConfigFromSelector(spec.CustomSelector),
+	}
+	if spec.RestartStrategy != nil {
+		config.RestartStrategy = &RestartStrategy{
+			MaxConcurrent:       spec.RestartStrategy.MaxConcurrent,
+			BatchByNamespace:    spec.RestartStrategy.BatchByNamespace,
+			PauseBetweenBatches: spec.RestartStrategy.PauseBetweenBatches,
+			WaitForRollout:      spec.RestartStrategy.WaitForRollout,
+			RolloutTimeout:      spec.RestartStrategy.RolloutTimeout,
+			MaintenanceWindow:   MaintenanceWindow(spec.RestartStrategy.MaintenanceWindow),
+		}
+	}
+	if len(spec.Languages) > 0 {
+		config.Languages = instrumentation.TypeSet{}
+		for _, language := range spec.Languages {
+			config.Languages[instrumentation.Type(language)] = nil
+		}
+	}
+	return config
+}
+
+func annotationConfigFromSelector(selector v1alpha1.AutoMonitorSelector) AnnotationConfig {
+	return AnnotationConfig{
+		Java:   AnnotationResources(selector.Java),
+		Python: AnnotationResources(selector.Python),
+		DotNet: AnnotationResources(selector.DotNet),
+		NodeJS: AnnotationResources(selector.NodeJS),
+		Go:     AnnotationResources(selector.Go),
+	}
 }
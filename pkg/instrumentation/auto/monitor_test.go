@@ -6,11 +6,13 @@ package auto
 import (
 	"context"
 	"encoding/json"
+	"maps"
 	"testing"
 	"time"
 
 	"github.com/go-logr/logr/testr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -21,6 +23,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fake2 "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
 )
 
@@ -481,6 +484,214 @@ func TestSystemNamespaceExclusion(t *testing.T) {
 	}
 }
 
+func TestExcludeByNamespaceSelector(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	fakeClient := fake2.NewFakeClient()
+	ctx := context.TODO()
+	logger := testr.New(t)
+
+	for name, env := range map[string]string{"sandbox": "dev", "shop": "prod"} {
+		_, err := clientset.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"env": env}}}, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	config := simpleConfig(true, false, AnnotationConfig{}, AnnotationConfig{
+		Java: AnnotationResources{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}}},
+	})
+	monitor := NewMonitor(ctx, config, clientset, fakeClient, fakeClient, logger)
+	assert.NotNil(t, monitor.namespaceInformer)
+
+	labels := map[string]string{"app": "test"}
+	for _, ns := range []string{"sandbox", "shop"} {
+		_, err := clientset.CoreV1().Services(ns).Create(ctx, newTestService("service", ns, labels), metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, waitForInformerUpdate(monitor, func(numKeys int) bool { return numKeys > 1 }))
+
+	assert.Equal(t, map[string]string{}, monitor.MutateObject(nil, newTestDeployment("workload", "sandbox", labels, nil)))
+	assert.Equal(t, buildAnnotations(instrumentation.TypeJava), monitor.MutateObject(nil, newTestDeployment("workload", "shop", labels, nil)))
+}
+
+func TestMonitor_SetConfig(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	fakeClient := fake2.NewFakeClient()
+	ctx := context.TODO()
+	logger := testr.New(t)
+
+	_, err := clientset.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Labels: map[string]string{"env": "prod"}}}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	labels := map[string]string{"app": "test"}
+	_, err = clientset.CoreV1().Services("shop").Create(ctx, newTestService("service", "shop", labels), metav1.CreateOptions{})
+	assert.NoError(t, err)
+	_, err = clientset.AppsV1().Deployments("shop").Create(ctx, newTestDeployment("workload", "shop", labels, nil), metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	monitor := NewMonitor(ctx, simpleConfig(false, false, AnnotationConfig{}, AnnotationConfig{}), clientset, fakeClient, fakeClient, logger)
+	assert.Empty(t, monitor.SelectedWorkloads())
+	assert.Equal(t, map[string]string{}, monitor.MutateObject(nil, newTestDeployment("workload", "shop", labels, map[string]string{})))
+
+	// monitor all services
+	assert.True(t, mustSetConfig(t, monitor, simpleConfig(true, false, AnnotationConfig{}, AnnotationConfig{})))
+	assert.False(t, mustSetConfig(t, monitor, simpleConfig(true, false, AnnotationConfig{}, AnnotationConfig{})))
+	assert.Equal(t, map[instrumentation.Type]int32{instrumentation.TypeJava: 1}, monitor.SelectedWorkloads())
+	assert.Equal(t, buildAnnotations(instrumentation.TypeJava), monitor.MutateObject(nil, newTestDeployment("workload", "shop", labels, map[string]string{})))
+
+	// select the namespace by label, which starts the namespace informer
+	assert.Nil(t, monitor.namespaceInformer)
+	assert.True(t, mustSetConfig(t, monitor, simpleConfig(false, false, AnnotationConfig{
+		Python: AnnotationResources{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}},
+	}, AnnotationConfig{})))
+	assert.NotNil(t, monitor.namespaceInformer)
+	assert.Equal(t, map[instrumentation.Type]int32{instrumentation.TypePython: 1}, monitor.SelectedWorkloads())
+
+	// an invalid selector is rejected and the current config is kept
+	changed, err := monitor.SetConfig(simpleConfig(false, false, AnnotationConfig{
+		Python: AnnotationResources{NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "env", Operator: "Unknown"}}}},
+	}, AnnotationConfig{}))
+	assert.Error(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, map[instrumentation.Type]int32{instrumentation.TypePython: 1}, monitor.SelectedWorkloads())
+
+	// restore the initial config
+	assert.True(t, mustSetConfig(t, monitor, monitor.InitialConfig()))
+	assert.Empty(t, monitor.SelectedWorkloads())
+}
+
+// mustSetConfig sets the config of the monitor and returns whether it changed.
+func mustSetConfig(t *testing.T, monitor *Monitor, config MonitorConfig) bool {
+	t.Helper()
+	changed, err := monitor.SetConfig(config)
+	require.NoError(t, err)
+	return changed
+}
+
+func TestMonitor_DetectLanguages(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	fakeClient := fake2.NewFakeClient()
+	ctx := context.TODO()
+	logger := testr.New(t)
+
+	labels := map[string]string{"app": "test"}
+	_, err := clientset.CoreV1().Services(defaultNs).Create(ctx, newTestService("service", defaultNs, labels), metav1.CreateOptions{})
+	assert.NoError(t, err)
+	config := MonitorConfig{MonitorAllServices: true, DetectLanguages: true}
+	monitor := NewMonitor(ctx, config, clientset, fakeClient, fakeClient, logger)
+	assert.NoError(t, waitForInformerUpdate(monitor, func(numKeys int) bool { return numKeys > 0 }))
+
+	newDeployment := func(image string) *appsv1.Deployment {
+		deployment := newTestDeployment("workload", defaultNs, labels, nil)
+		deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: image}}
+		return deployment
+	}
+
+	// only the detected language is annotated
+	deployment := newDeployment("python:3.12")
+	assert.Equal(t, mergeMaps(buildAnnotations(instrumentation.TypePython), map[string]string{AnnotationDetectedLanguages: "python"}), monitor.MutateObject(nil, deployment))
+	assert.Equal(t, "python", deployment.Spec.Template.Annotations[AnnotationDetectedLanguages])
+	assert.Equal(t, map[string]string{}, monitor.MutateObject(nil, deployment))
+
+	// no language is annotated when none is detected
+	deployment = newDeployment("shop/api:1.0")
+	assert.Equal(t, map[string]string{AnnotationDetectedLanguages: "undetected"}, monitor.MutateObject(nil, deployment))
+	for language := range instrumentation.SupportedTypes {
+		assert.NotContains(t, deployment.Spec.Template.Annotations, instrumentation.InjectAnnotationKey(language))
+	}
+
+	// all the default languages are annotated when none is detected and undetected workloads are instrumented
+	mustSetConfig(t, monitor, MonitorConfig{MonitorAllServices: true, DetectLanguages: true, InstrumentUndetected: true})
+	deployment = newDeployment("shop/api:1.0")
+	monitor.MutateObject(nil, deployment)
+	for language := range instrumentation.DefaultTypes {
+		assert.Contains(t, deployment.Spec.Template.Annotations, instrumentation.InjectAnnotationKey(language))
+	}
+	assert.Equal(t, "undetected", deployment.Spec.Template.Annotations[AnnotationDetectedLanguages])
+
+	// the detected language isn't annotated unless configured
+	mustSetConfig(t, monitor, MonitorConfig{MonitorAllServices: true, DetectLanguages: true, Languages: instrumentation.NewTypeSet(instrumentation.TypeJava, instrumentation.TypeNodeJS)})
+	deployment = newDeployment("node:20")
+	deployment.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{{Name: "JAVA_TOOL_OPTIONS"}, {Name: "PYTHONPATH"}}
+	monitor.MutateObject(nil, deployment)
+	assert.Equal(t, "java,nodejs,python", deployment.Spec.Template.Annotations[AnnotationDetectedLanguages])
+	assert.Contains(t, deployment.Spec.Template.Annotations, instrumentation.InjectAnnotationKey(instrumentation.TypeJava))
+	assert.Contains(t, deployment.Spec.Template.Annotations, instrumentation.InjectAnnotationKey(instrumentation.TypeNodeJS))
+	assert.NotContains(t, deployment.Spec.Template.Annotations, instrumentation.InjectAnnotationKey(instrumentation.TypePython))
+
+	// the detection result is removed once detection is disabled
+	mustSetConfig(t, monitor, MonitorConfig{MonitorAllServices: true, Languages: instrumentation.NewTypeSet(instrumentation.TypeJava)})
+	mutated := monitor.MutateObject(nil, deployment).(map[string]string)
+	assert.Contains(t, mutated, AnnotationDetectedLanguages)
+	assert.NotContains(t, deployment.Spec.Template.Annotations, AnnotationDetectedLanguages)
+}
+
+func TestMonitor_DefaultLanguagesExcludeGo(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	fakeClient := fake2.NewFakeClient()
+	ctx := context.TODO()
+
+	labels := map[string]string{"app": "test"}
+	_, err := clientset.CoreV1().Services(defaultNs).Create(ctx, newTestService("service", defaultNs, labels), metav1.CreateOptions{})
+	assert.NoError(t, err)
+	monitor := NewMonitor(ctx, MonitorConfig{MonitorAllServices: true}, clientset, fakeClient, fakeClient, testr.New(t))
+	assert.NoError(t, waitForInformerUpdate(monitor, func(numKeys int) bool { return numKeys > 0 }))
+
+	// Go is neither annotated by default nor when detected
+	deployment := newTestDeployment("workload", defaultNs, labels, nil)
+	assert.Equal(t, mergeMaps(
+		buildAnnotations(instrumentation.TypeJava),
+		buildAnnotations(instrumentation.TypeNodeJS),
+		buildAnnotations(instrumentation.TypePython),
+		buildAnnotations(instrumentation.TypeDotNet),
+	), monitor.MutateObject(nil, deployment))
+	assert.NotContains(t, deployment.Spec.Template.Annotations, instrumentation.InjectAnnotationKey(instrumentation.TypeGo))
+	mustSetConfig(t, monitor, MonitorConfig{MonitorAllServices: true, DetectLanguages: true})
+	deployment = newTestDeployment("workload", defaultNs, labels, nil)
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: "shop/api:1.0", Env: []corev1.EnvVar{{Name: "GOMEMLIMIT"}}}}
+	monitor.MutateObject(nil, deployment)
+	assert.NotContains(t, deployment.Spec.Template.Annotations, instrumentation.InjectAnnotationKey(instrumentation.TypeGo))
+
+	// Go is annotated once listed explicitly
+	mustSetConfig(t, monitor, MonitorConfig{MonitorAllServices: true, Languages: instrumentation.NewTypeSet(instrumentation.TypeGo)})
+	deployment = newTestDeployment("workload", defaultNs, labels, nil)
+	assert.Equal(t, buildAnnotations(instrumentation.TypeGo), monitor.MutateObject(nil, deployment))
+}
+
+func TestMonitorConfigFromSpec(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}}
+	spec := v1alpha1.AutoMonitorSpec{
+		MonitorAllServices: true,
+		Languages:          []v1alpha1.AutoMonitorLanguage{"java", "python"},
+		DetectLanguages:    true,
+		RestartPods:        true,
+		RestartStrategy: &v1alpha1.AutoMonitorRestartStrategy{
+			MaxConcurrent:     5,
+			MaintenanceWindow: v1alpha1.AutoMonitorMaintenanceWindow{Start: "22:00", End: "06:00"},
+		},
+		Exclude: v1alpha1.AutoMonitorSelector{
+			Java: v1alpha1.AutoMonitorResources{Namespaces: []string{"team-*"}, NamespaceSelector: selector},
+		},
+		CustomSelector: v1alpha1.AutoMonitorSelector{
+			NodeJS: v1alpha1.AutoMonitorResources{Deployments: []string{"shop/api"}, WorkloadSelector: selector},
+		},
+	}
+
+	assert.Equal(t, MonitorConfig{
+		MonitorAllServices: true,
+		Languages:          instrumentation.NewTypeSet(instrumentation.TypeJava, instrumentation.TypePython),
+		DetectLanguages:    true,
+		RestartPods:        true,
+		RestartStrategy: &RestartStrategy{
+			MaxConcurrent:     5,
+			MaintenanceWindow: MaintenanceWindow{Start: "22:00", End: "06:00"},
+		},
+		Exclude: AnnotationConfig{
+			Java: AnnotationResources{Namespaces: []string{"team-*"}, NamespaceSelector: selector},
+		},
+		CustomSelector: AnnotationConfig{
+			NodeJS: AnnotationResources{Deployments: []string{"shop/api"}, WorkloadSelector: selector},
+		},
+	}, MonitorConfigFromSpec(spec))
+	assert.Nil(t, MonitorConfigFromSpec(v1alpha1.AutoMonitorSpec{}).Languages)
+}
+
 func TestMonitor_MutateObject_Namespace(t *testing.T) {
 	tests := []struct {
 		name                         string
@@ -866,7 +1077,7 @@ func Test_mutate(t *testing.T) {
 					obj := workload.create("workload", "default", nil, tt.podAnnotations).DeepCopyObject().(client.Object)
 					// TODO test different isWorkloadAutoMonitored values
 					gotMutated := mutate(obj, tt.languagesToMonitor)
-					assert.Equal(t, tt.wantObjAnnotations, getPodTemplate(obj).GetAnnotations())
+					assert.Equal(t, tt.wantObjAnnotations, withoutInstrumentedAt(getPodTemplate(obj).GetAnnotations()))
 					assert.Equal(t, tt.wantMutated, gotMutated)
 				})
 			}
@@ -885,7 +1096,7 @@ func Test_StartupRestartPods(t *testing.T) {
 		RestartPods:        true,
 		CustomSelector: AnnotationConfig{
 			AnnotationResources{}, AnnotationResources{Deployments: []string{namespacedName(customSelectedDeployment)}},
-			AnnotationResources{}, AnnotationResources{},
+			AnnotationResources{}, AnnotationResources{}, AnnotationResources{},
 		},
 	}
 	objs := []runtime.Object{service, matchingDeployment, nonMatchingDeployment, customSelectedDeployment}
@@ -897,13 +1108,30 @@ func Test_StartupRestartPods(t *testing.T) {
 	m.MutateAndPatchAll(context.TODO())
 	updatedMatchingDeployment, err := m.k8sInterface.AppsV1().Deployments(defaultNs).Get(context.TODO(), matchingDeployment.Name, metav1.GetOptions{})
 	assert.NoError(t, err)
-	assert.Equal(t, buildAnnotations(instrumentation.TypeJava), updatedMatchingDeployment.Spec.Template.GetAnnotations())
+	assert.Equal(t, buildAnnotations(instrumentation.TypeJava), withoutInstrumentedAt(updatedMatchingDeployment.Spec.Template.GetAnnotations()))
 	updatedNonMatchingDeployment, err := m.k8sInterface.AppsV1().Deployments(defaultNs).Get(context.TODO(), nonMatchingDeployment.Name, metav1.GetOptions{})
 	assert.NoError(t, err)
 	assert.Empty(t, updatedNonMatchingDeployment.Spec.Template.GetAnnotations())
 	err = fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(customSelectedDeployment), customSelectedDeployment)
 	assert.NoError(t, err)
-	assert.Equal(t, buildAnnotations(instrumentation.TypePython), customSelectedDeployment.Spec.Template.GetAnnotations())
+	assert.Equal(t, buildAnnotations(instrumentation.TypePython), withoutInstrumentedAt(customSelectedDeployment.Spec.Template.GetAnnotations()))
+}
+
+func Test_mutateRecordsInstrumentedAt(t *testing.T) {
+	deployment := newTestDeployment("workload", defaultNs, nil, nil)
+
+	// the time is recorded when a language is added
+	mutate(deployment, instrumentation.NewTypeSet(instrumentation.TypeJava))
+	instrumentedAt, err := time.Parse(time.RFC3339, deployment.Spec.Template.Annotations[instrumentation.AnnotationInstrumentedAt])
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), instrumentedAt, time.Minute)
+
+	// it is kept when no language is added, and removed along with the last language
+	deployment.Spec.Template.Annotations[instrumentation.AnnotationInstrumentedAt] = "2024-01-01T00:00:00Z"
+	mutate(deployment, instrumentation.NewTypeSet(instrumentation.TypeJava))
+	assert.Equal(t, "2024-01-01T00:00:00Z", deployment.Spec.Template.Annotations[instrumentation.AnnotationInstrumentedAt])
+	mutate(deployment, instrumentation.TypeSet{})
+	assert.Empty(t, deployment.Spec.Template.Annotations)
 }
 
 // Helper functions
@@ -991,6 +1219,16 @@ func newTestDaemonSet(name string, namespace string, labels map[string]string, a
 	return daemonSet.DeepCopy()
 }
 
+// withoutInstrumentedAt returns the annotations without the AnnotationInstrumentedAt timestamp.
+func withoutInstrumentedAt(annotations map[string]string) map[string]string {
+	if _, ok := annotations[instrumentation.AnnotationInstrumentedAt]; !ok {
+		return annotations
+	}
+	result := maps.Clone(annotations)
+	delete(result, instrumentation.AnnotationInstrumentedAt)
+	return result
+}
+
 func mergeMaps(maps ...map[string]string) map[string]string {
 	result := make(map[string]string)
 	for _, m := range maps {
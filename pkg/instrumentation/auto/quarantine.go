@@ -0,0 +1,44 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
+
+// AnnotationQuarantined marks a workload whose auto-instrumentation was rolled back because it failed its pods. The
+// value explains why. The operator doesn't annotate quarantined workloads for instrumentation until the annotation is
+// removed, and the pod webhook doesn't instrument the pods of the workload through the annotations of their namespace.
+const AnnotationQuarantined = instrumentation.AnnotationQuarantined
+
+// IsQuarantined returns whether the workload is quarantined.
+func IsQuarantined(obj metav1.Object) bool {
+	_, ok := obj.GetAnnotations()[AnnotationQuarantined]
+	return ok
+}
+
+// Quarantine marks the workload as quarantined, and removes the instrumentation annotations added by the operator from
+// its pod template, which rolls back the auto-instrumentation of its pods. It returns the removed annotations, the
+// annotations added by users are kept.
+func Quarantine(obj client.Object, reason string) map[string]string {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationQuarantined] = reason
+	obj.SetAnnotations(annotations)
+	return mutate(obj, instrumentation.TypeSet{})
+}
+
+// quarantineAnnotations returns the quarantine annotation of the workload, the only one the auto-monitor needs.
+func quarantineAnnotations(annotations map[string]string) map[string]string {
+	reason, ok := annotations[AnnotationQuarantined]
+	if !ok {
+		return nil
+	}
+	return map[string]string{AnnotationQuarantined: reason}
+}
@@ -0,0 +1,53 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	fake2 "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
+
+func TestQuarantine(t *testing.T) {
+	userAnnotations := map[string]string{instrumentation.InjectAnnotationKey(instrumentation.TypePython): "true"}
+	deployment := newTestDeployment("workload", defaultNs, nil, mergeMaps(buildAnnotations(instrumentation.TypeJava), userAnnotations))
+	assert.False(t, IsQuarantined(deployment))
+
+	assert.Equal(t, buildAnnotations(instrumentation.TypeJava), Quarantine(deployment, "crash-looping"))
+	assert.True(t, IsQuarantined(deployment))
+	assert.Equal(t, "crash-looping", deployment.Annotations[AnnotationQuarantined])
+	assert.Equal(t, userAnnotations, deployment.Spec.Template.Annotations)
+}
+
+func TestMonitor_Quarantined(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	fakeClient := fake2.NewFakeClient()
+	ctx := context.TODO()
+
+	labels := map[string]string{"app": "test"}
+	_, err := clientset.CoreV1().Services(defaultNs).Create(ctx, newTestService("service", defaultNs, labels), metav1.CreateOptions{})
+	assert.NoError(t, err)
+	config := simpleConfig(true, false, AnnotationConfig{
+		Java: AnnotationResources{Deployments: []string{defaultNs + "/workload"}},
+	}, AnnotationConfig{})
+	monitor := NewMonitor(ctx, config, clientset, fakeClient, fakeClient, testr.New(t))
+	assert.NoError(t, waitForInformerUpdate(monitor, func(numKeys int) bool { return numKeys > 0 }))
+
+	// the auto-annotations of quarantined workloads are removed, even when selected by the custom selector
+	deployment := newTestDeployment("workload", defaultNs, labels, buildAnnotations(instrumentation.TypeJava))
+	deployment.Annotations = map[string]string{AnnotationQuarantined: "crash-looping"}
+	assert.Equal(t, buildAnnotations(instrumentation.TypeJava), monitor.MutateObject(nil, deployment))
+	assert.Empty(t, deployment.Spec.Template.Annotations)
+
+	// once cleared, the workload is annotated again
+	deployment.Annotations = nil
+	assert.Equal(t, buildAnnotations(instrumentation.TypeJava), monitor.MutateObject(nil, deployment))
+}
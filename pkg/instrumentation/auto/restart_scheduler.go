@@ -0,0 +1,441 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	defaultRolloutTimeout = 10 * time.Minute
+	rolloutPollInterval   = 5 * time.Second
+	// timeOfDayLayout is the layout of the start and end of the MaintenanceWindow.
+	timeOfDayLayout = "15:04"
+
+	eventReasonRestarted      = "AutoMonitorRestarted"
+	eventReasonRestartFailed  = "AutoMonitorRestartFailed"
+	eventReasonRolloutTimeout = "AutoMonitorRolloutTimeout"
+)
+
+var (
+	restartsPending = promauto.With(metrics.Registry).NewGauge(prometheus.GaugeOpts{
+		Name: "cloudwatch_agent_operator_auto_monitor_restarts_pending",
+		Help: "Number of workloads waiting to be restarted by the auto-monitor.",
+	})
+	restartsTotal = promauto.With(metrics.Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "cloudwatch_agent_operator_auto_monitor_restarts_total",
+		Help: "Number of workloads restarted by the auto-monitor, by result.",
+	}, []string{"result"})
+	restartBatchesTotal = promauto.With(metrics.Registry).NewCounter(prometheus.CounterOpts{
+		Name: "cloudwatch_agent_operator_auto_monitor_restart_batches_total",
+		Help: "Number of batches of workloads restarted by the auto-monitor.",
+	})
+	restartsHaltedTotal = promauto.With(metrics.Registry).NewCounter(prometheus.CounterOpts{
+		Name: "cloudwatch_agent_operator_auto_monitor_restarts_halted_total",
+		Help: "Number of times the auto-monitor stopped restarting workloads because a batch wasn't rolled out in time.",
+	})
+)
+
+// RestartStrategy controls how the workloads are restarted to apply the auto-monitor config when RestartPods is
+// enabled. Without a strategy, all the workloads are restarted at once.
+type RestartStrategy struct {
+	// MaxConcurrent is the maximum number of workloads restarted in a batch. Zero means no limit.
+	MaxConcurrent int `json:"maxConcurrent,omitempty"`
+	// BatchByNamespace restricts the batches to the workloads of a single namespace.
+	BatchByNamespace bool `json:"batchByNamespace,omitempty"`
+	// PauseBetweenBatches is how long to wait after a batch before restarting the next one.
+	PauseBetweenBatches metav1.Duration `json:"pauseBetweenBatches,omitempty"`
+	// WaitForRollout waits for the workloads of a batch to be rolled out before restarting the next one. The restarts
+	// stop if they aren't rolled out within RolloutTimeout.
+	WaitForRollout bool `json:"waitForRollout,omitempty"`
+	// RolloutTimeout defaults to 10 minutes.
+	RolloutTimeout metav1.Duration `json:"rolloutTimeout,omitempty"`
+	// MaintenanceWindow restricts the restarts to a daily time window.
+	MaintenanceWindow MaintenanceWindow `json:"maintenanceWindow,omitempty"`
+}
+
+// MaintenanceWindow is a daily time window in UTC, e.g. from 22:00 to 06:00. An empty window is always open.
+type MaintenanceWindow struct {
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+}
+
+// Validate checks the durations and the maintenance window of the strategy.
+func (s *RestartStrategy) Validate() error {
+	if s == nil {
+		return nil
+	}
+	var errs []error
+	if s.MaxConcurrent < 0 {
+		errs = append(errs, fmt.Errorf("maxConcurrent must not be negative"))
+	}
+	if s.PauseBetweenBatches.Duration < 0 {
+		errs = append(errs, fmt.Errorf("pauseBetweenBatches must not be negative"))
+	}
+	if s.RolloutTimeout.Duration < 0 {
+		errs = append(errs, fmt.Errorf("rolloutTimeout must not be negative"))
+	}
+	if err := s.MaintenanceWindow.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("maintenanceWindow: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (s RestartStrategy) rolloutTimeout() time.Duration {
+	if s.RolloutTimeout.Duration == 0 {
+		return defaultRolloutTimeout
+	}
+	return s.RolloutTimeout.Duration
+}
+
+func (w MaintenanceWindow) validate() error {
+	if w.Start == "" && w.End == "" {
+		return nil
+	}
+	start, err := parseTimeOfDay(w.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start %q: %w", w.Start, err)
+	}
+	end, err := parseTimeOfDay(w.End)
+	if err != nil {
+		return fmt.Errorf("invalid end %q: %w", w.End, err)
+	}
+	if start == end {
+		return fmt.Errorf("start and end must differ")
+	}
+	return nil
+}
+
+// waitTime returns how long to wait at now for the window to open, zero if it's open.
+func (w MaintenanceWindow) waitTime(now time.Time) time.Duration {
+	start, err := parseTimeOfDay(w.Start)
+	if err != nil {
+		return 0
+	}
+	end, err := parseTimeOfDay(w.End)
+	if err != nil {
+		return 0
+	}
+	now = now.UTC()
+	t := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+	switch {
+	case start < end && t >= start && t < end:
+		return 0
+	case start > end && (t >= start || t < end):
+		// the window spans midnight
+		return 0
+	case t < start:
+		return start - t
+	default:
+		return 24*time.Hour - t + start
+	}
+}
+
+// parseTimeOfDay returns the time elapsed since midnight.
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse(timeOfDayLayout, s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// pendingRestart is a workload to restart, and the change restarting it.
+type pendingRestart struct {
+	obj    client.Object
+	mutate objectCallbackFunc
+}
+
+func (r pendingRestart) key() string {
+	return fmt.Sprintf("%T/%s/%s", r.obj, r.obj.GetNamespace(), r.obj.GetName())
+}
+
+// restartBatches sorts the restarts by namespace and splits them into batches following the strategy.
+func restartBatches(restarts []pendingRestart, strategy RestartStrategy) [][]pendingRestart {
+	sort.SliceStable(restarts, func(i, j int) bool {
+		if ni, nj := restarts[i].obj.GetNamespace(), restarts[j].obj.GetNamespace(); ni != nj {
+			return ni < nj
+		}
+		return restarts[i].key() < restarts[j].key()
+	})
+	var batches [][]pendingRestart
+	var batch []pendingRestart
+	for _, restart := range restarts {
+		full := strategy.MaxConcurrent > 0 && len(batch) == strategy.MaxConcurrent
+		otherNamespace := strategy.BatchByNamespace && len(batch) > 0 && batch[0].obj.GetNamespace() != restart.obj.GetNamespace()
+		if full || otherNamespace {
+			batches = append(batches, batch)
+			batch = nil
+		}
+		batch = append(batch, restart)
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// restartScheduler restarts the workloads in the background, batch after batch. Scheduling new restarts stops the
+// ones in progress.
+type restartScheduler struct {
+	reader       client.Reader
+	writer       client.Writer
+	logger       logr.Logger
+	pollInterval time.Duration
+	now          func() time.Time
+
+	mu       sync.Mutex
+	recorder record.EventRecorder
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func newRestartScheduler(reader client.Reader, writer client.Writer, logger logr.Logger) *restartScheduler {
+	return &restartScheduler{
+		reader:       reader,
+		writer:       writer,
+		logger:       logger,
+		pollInterval: rolloutPollInterval,
+		now:          time.Now,
+	}
+}
+
+func (s *restartScheduler) setRecorder(recorder record.EventRecorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recorder = recorder
+}
+
+// Schedule stops the restarts in progress, and starts restarting the workloads following the strategy.
+func (s *restartScheduler) Schedule(ctx context.Context, strategy RestartStrategy, restarts []pendingRestart) {
+	s.Stop()
+	batches := restartBatches(restarts, strategy)
+	restartsPending.Set(float64(len(restarts)))
+	if len(batches) == 0 {
+		return
+	}
+	s.logger.Info("scheduling workload restarts", "workloads", len(restarts), "batches", len(batches))
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	s.mu.Lock()
+	s.cancel, s.done = cancel, done
+	s.mu.Unlock()
+	go func() {
+		defer close(done)
+		defer cancel()
+		// the restarts left when stopped or halted are no longer pending, they are scheduled again with the next config
+		defer restartsPending.Set(0)
+		s.run(ctx, strategy, batches)
+	}()
+}
+
+// Stop stops the restarts in progress and waits for the current restart to complete.
+func (s *restartScheduler) Stop() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel, s.done = nil, nil
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// wait waits for the scheduled restarts to complete.
+func (s *restartScheduler) wait() {
+	s.mu.Lock()
+	done := s.done
+	s.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+func (s *restartScheduler) run(ctx context.Context, strategy RestartStrategy, batches [][]pendingRestart) {
+	for i, batch := range batches {
+		if i > 0 && !sleep(ctx, strategy.PauseBetweenBatches.Duration) {
+			return
+		}
+		s.logger.V(1).Info("restarting workloads", "batch", i+1, "batches", len(batches), "workloads", len(batch))
+		restarted := s.restartBatch(ctx, batch, strategy.MaintenanceWindow, fmt.Sprintf("batch %d/%d", i+1, len(batches)))
+		restartBatchesTotal.Inc()
+		if strategy.WaitForRollout && !s.waitForRollout(ctx, restarted, strategy.rolloutTimeout()) {
+			if ctx.Err() == nil {
+				restartsHaltedTotal.Inc()
+				s.logger.Info("W! stopping the workload restarts, the previous batch wasn't rolled out in time", "batch", i+1, "batches", len(batches))
+			}
+			return
+		}
+	}
+}
+
+// restartBatch restarts the workloads of the batch within the maintenance window, and returns the restarted ones.
+func (s *restartScheduler) restartBatch(ctx context.Context, batch []pendingRestart, window MaintenanceWindow, progress string) []client.Object {
+	var restarted []client.Object
+	for _, restart := range batch {
+		// the window is checked before every restart, since it may close while a batch is restarted
+		if !s.waitForWindow(ctx, window) {
+			return restarted
+		}
+		restartsPending.Dec()
+		obj, err := s.restart(ctx, restart)
+		if err != nil {
+			obj = restart.obj
+			s.logger.Error(err, "Unable to send patch", "kind", fmt.Sprintf("%T", obj), "name", obj.GetName(), "namespace", obj.GetNamespace())
+			restartsTotal.WithLabelValues("failed").Inc()
+			s.event(obj, corev1.EventTypeWarning, eventReasonRestartFailed, fmt.Sprintf("unable to restart the workload to apply the auto-monitor config: %v", err))
+			continue
+		}
+		if obj == nil {
+			continue
+		}
+		restartsTotal.WithLabelValues("restarted").Inc()
+		s.event(obj, corev1.EventTypeNormal, eventReasonRestarted, fmt.Sprintf("restarted to apply the auto-monitor config (%s)", progress))
+		restarted = append(restarted, obj)
+	}
+	return restarted
+}
+
+// restart re-reads the workload, which may have changed since the restart was scheduled, and patches the change
+// restarting it with a resourceVersion precondition, retrying on conflicts. It returns the restarted workload, or nil
+// if the workload was deleted or no longer needs the change.
+func (s *restartScheduler) restart(ctx context.Context, restart pendingRestart) (client.Object, error) {
+	var restarted client.Object
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		restarted = nil
+		current := restart.obj.DeepCopyObject().(client.Object)
+		if err := s.reader.Get(ctx, client.ObjectKeyFromObject(restart.obj), current); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		patch := client.MergeFromWithOptions(current.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+		result, ok := restart.mutate(current, nil)
+		if !ok {
+			return nil
+		}
+		if mutatedAnnotations, isAnnotations := result.(map[string]string); isAnnotations && len(mutatedAnnotations) == 0 {
+			return nil
+		}
+		if err := s.writer.Patch(ctx, current, patch); err != nil {
+			return err
+		}
+		restarted = current
+		return nil
+	})
+	return restarted, err
+}
+
+// waitForWindow waits for the maintenance window to open, and returns false if the context is done first.
+func (s *restartScheduler) waitForWindow(ctx context.Context, window MaintenanceWindow) bool {
+	if waitTime := window.waitTime(s.now()); waitTime > 0 {
+		s.logger.Info("waiting for the maintenance window to restart workloads", "wait", waitTime.String())
+		return sleep(ctx, waitTime)
+	}
+	return ctx.Err() == nil
+}
+
+// waitForRollout returns whether the workloads were rolled out within the timeout.
+func (s *restartScheduler) waitForRollout(ctx context.Context, workloads []client.Object, timeout time.Duration) bool {
+	var pending []client.Object
+	err := wait.PollUntilContextTimeout(ctx, s.pollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		pending = pending[:0]
+		for _, workload := range workloads {
+			current := workload.DeepCopyObject().(client.Object)
+			if err := s.reader.Get(ctx, client.ObjectKeyFromObject(workload), current); err != nil {
+				// the workload may have been deleted, which doesn't block the rollout
+				continue
+			}
+			if !rolledOut(current) {
+				pending = append(pending, current)
+			}
+		}
+		return len(pending) == 0, nil
+	})
+	if err == nil {
+		return true
+	}
+	if ctx.Err() == nil {
+		for _, workload := range pending {
+			s.event(workload, corev1.EventTypeWarning, eventReasonRolloutTimeout, fmt.Sprintf("not rolled out within %s, the auto-monitor stopped restarting workloads", timeout))
+		}
+	}
+	return false
+}
+
+func (s *restartScheduler) event(obj client.Object, eventType, reason, message string) {
+	s.mu.Lock()
+	recorder := s.recorder
+	s.mu.Unlock()
+	if recorder != nil {
+		recorder.Event(obj, eventType, reason, message)
+	}
+}
+
+// rolledOut returns whether the pods of the workload are all updated and available.
+func rolledOut(obj client.Object) bool {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		if o.Spec.Paused {
+			return true
+		}
+		replicas := ptr.Deref(o.Spec.Replicas, 1)
+		return o.Status.ObservedGeneration >= o.Generation &&
+			o.Status.UpdatedReplicas >= replicas &&
+			o.Status.Replicas == o.Status.UpdatedReplicas &&
+			o.Status.AvailableReplicas >= replicas
+	case *appsv1.StatefulSet:
+		if o.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+			return true
+		}
+		replicas := ptr.Deref(o.Spec.Replicas, 1)
+		return o.Status.ObservedGeneration >= o.Generation &&
+			o.Status.UpdatedReplicas >= replicas &&
+			o.Status.ReadyReplicas >= replicas
+	case *appsv1.DaemonSet:
+		if o.Spec.UpdateStrategy.Type == appsv1.OnDeleteDaemonSetStrategyType {
+			return true
+		}
+		return o.Status.ObservedGeneration >= o.Generation &&
+			o.Status.UpdatedNumberScheduled >= o.Status.DesiredNumberScheduled &&
+			o.Status.NumberAvailable >= o.Status.DesiredNumberScheduled
+	default:
+		return true
+	}
+}
+
+// sleep waits for the duration and returns false if the context is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fake2 "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+)
+
+func TestRestartBatches(t *testing.T) {
+	restarts := []pendingRestart{
+		{obj: newTestDeployment("b", "shop", nil, nil)},
+		{obj: newTestDeployment("a", "shop", nil, nil)},
+		{obj: newTestStatefulSet("db", "billing", nil, nil)},
+		{obj: newTestDeployment("c", "shop", nil, nil)},
+	}
+	names := func(batches [][]pendingRestart) [][]string {
+		var result [][]string
+		for _, batch := range batches {
+			var batchNames []string
+			for _, restart := range batch {
+				batchNames = append(batchNames, restart.obj.GetNamespace()+"/"+restart.obj.GetName())
+			}
+			result = append(result, batchNames)
+		}
+		return result
+	}
+
+	assert.Equal(t, [][]string{{"billing/db", "shop/a", "shop/b", "shop/c"}}, names(restartBatches(restarts, RestartStrategy{})))
+	assert.Equal(t, [][]string{{"billing/db", "shop/a"}, {"shop/b", "shop/c"}}, names(restartBatches(restarts, RestartStrategy{MaxConcurrent: 2})))
+	assert.Equal(t, [][]string{{"billing/db"}, {"shop/a", "shop/b", "shop/c"}}, names(restartBatches(restarts, RestartStrategy{BatchByNamespace: true})))
+	assert.Equal(t, [][]string{{"billing/db"}, {"shop/a", "shop/b"}, {"shop/c"}}, names(restartBatches(restarts, RestartStrategy{MaxConcurrent: 2, BatchByNamespace: true})))
+	assert.Empty(t, restartBatches(nil, RestartStrategy{MaxConcurrent: 2}))
+}
+
+func TestMaintenanceWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		now    time.Time
+		want   time.Duration
+	}{
+		{name: "no window", now: at(12, 0)},
+		{name: "open", window: MaintenanceWindow{Start: "09:00", End: "17:00"}, now: at(12, 0)},
+		{name: "before", window: MaintenanceWindow{Start: "09:00", End: "17:00"}, now: at(8, 30), want: 30 * time.Minute},
+		{name: "after", window: MaintenanceWindow{Start: "09:00", End: "17:00"}, now: at(17, 0), want: 16 * time.Hour},
+		{name: "open before midnight", window: MaintenanceWindow{Start: "22:00", End: "06:00"}, now: at(23, 0)},
+		{name: "open after midnight", window: MaintenanceWindow{Start: "22:00", End: "06:00"}, now: at(5, 59)},
+		{name: "closed across midnight", window: MaintenanceWindow{Start: "22:00", End: "06:00"}, now: at(12, 0), want: 10 * time.Hour},
+		{name: "other time zone", window: MaintenanceWindow{Start: "09:00", End: "17:00"}, now: at(12, 0).In(time.FixedZone("UTC-8", -8*3600))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.window.waitTime(tt.now))
+		})
+	}
+}
+
+func TestRestartStrategyValidate(t *testing.T) {
+	var nilStrategy *RestartStrategy
+	assert.NoError(t, nilStrategy.Validate())
+	assert.NoError(t, (&RestartStrategy{MaxConcurrent: 5, MaintenanceWindow: MaintenanceWindow{Start: "22:00", End: "06:00"}}).Validate())
+	assert.ErrorContains(t, (&RestartStrategy{MaxConcurrent: -1}).Validate(), "maxConcurrent")
+	assert.ErrorContains(t, (&RestartStrategy{PauseBetweenBatches: metav1.Duration{Duration: -time.Second}}).Validate(), "pauseBetweenBatches")
+	assert.ErrorContains(t, (&RestartStrategy{MaintenanceWindow: MaintenanceWindow{Start: "25:00", End: "06:00"}}).Validate(), "invalid start")
+	assert.ErrorContains(t, (&RestartStrategy{MaintenanceWindow: MaintenanceWindow{Start: "22:00"}}).Validate(), "invalid end")
+	assert.ErrorContains(t, (&RestartStrategy{MaintenanceWindow: MaintenanceWindow{Start: "22:00", End: "22:00"}}).Validate(), "must differ")
+}
+
+func TestRolledOut(t *testing.T) {
+	deployment := newTestDeployment("deployment", defaultNs, nil, nil)
+	deployment.Generation = 2
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	assert.False(t, rolledOut(deployment))
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 1, AvailableReplicas: 1}
+	assert.False(t, rolledOut(deployment))
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	assert.True(t, rolledOut(deployment))
+
+	statefulSet := newTestStatefulSet("statefulset", defaultNs, nil, nil)
+	assert.False(t, rolledOut(statefulSet))
+	statefulSet.Spec.UpdateStrategy.Type = appsv1.OnDeleteStatefulSetStrategyType
+	assert.True(t, rolledOut(statefulSet))
+
+	daemonSet := newTestDaemonSet("daemonset", defaultNs, nil, nil)
+	daemonSet.Status = appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2}
+	assert.False(t, rolledOut(daemonSet))
+	daemonSet.Status.NumberAvailable = 3
+	assert.True(t, rolledOut(daemonSet))
+}
+
+func TestRestartScheduler(t *testing.T) {
+	rolledOutStatus := appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	newDeployment := func(name string, status appsv1.DeploymentStatus) *appsv1.Deployment {
+		deployment := newTestDeployment(name, defaultNs, nil, nil)
+		deployment.Status = status
+		return deployment
+	}
+
+	tests := []struct {
+		name      string
+		strategy  RestartStrategy
+		objs      []client.Object
+		restarted []string
+		events    int
+	}{
+		{
+			name:      "all batches",
+			strategy:  RestartStrategy{MaxConcurrent: 1, PauseBetweenBatches: metav1.Duration{Duration: time.Millisecond}, WaitForRollout: true},
+			objs:      []client.Object{newDeployment("a", rolledOutStatus), newDeployment("b", rolledOutStatus), newDeployment("c", rolledOutStatus)},
+			restarted: []string{"a", "b", "c"},
+			events:    3,
+		},
+		{
+			name:      "stops when a batch isn't rolled out",
+			strategy:  RestartStrategy{MaxConcurrent: 2, WaitForRollout: true, RolloutTimeout: metav1.Duration{Duration: 50 * time.Millisecond}},
+			objs:      []client.Object{newDeployment("a", rolledOutStatus), newDeployment("b", appsv1.DeploymentStatus{}), newDeployment("c", rolledOutStatus)},
+			restarted: []string{"a", "b"},
+			// the 2 restarts and the rollout timeout of b
+			events: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake2.NewClientBuilder().WithObjects(tt.objs...).Build()
+			recorder := record.NewFakeRecorder(10)
+			scheduler := newRestartScheduler(fakeClient, fakeClient, testr.New(t))
+			scheduler.pollInterval = time.Millisecond
+			scheduler.setRecorder(recorder)
+
+			var restarts []pendingRestart
+			for _, obj := range tt.objs {
+				restarts = append(restarts, pendingRestart{obj: obj, mutate: setRestartAnnotation})
+			}
+			scheduler.Schedule(context.TODO(), tt.strategy, restarts)
+			scheduler.wait()
+
+			var restarted []string
+			for _, obj := range tt.objs {
+				var deployment appsv1.Deployment
+				require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(obj), &deployment))
+				if _, ok := deployment.Spec.Template.Annotations[restartedAtAnnotation]; ok {
+					restarted = append(restarted, deployment.Name)
+				}
+			}
+			assert.Equal(t, tt.restarted, restarted)
+			assert.Len(t, recorder.Events, tt.events)
+		})
+	}
+}
+
+func TestRestartSchedulerRestartsCurrentWorkload(t *testing.T) {
+	scheduled := newTestDeployment("scheduled", defaultNs, nil, nil)
+	paused := newTestDeployment("paused", defaultNs, nil, nil)
+	deleted := newTestDeployment("deleted", defaultNs, nil, nil)
+	fakeClient := fake2.NewClientBuilder().WithObjects(scheduled, paused).Build()
+	recorder := record.NewFakeRecorder(10)
+	scheduler := newRestartScheduler(fakeClient, fakeClient, testr.New(t))
+	scheduler.setRecorder(recorder)
+
+	// the workloads change after the restarts are scheduled
+	var current appsv1.Deployment
+	require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(scheduled), &current))
+	current.Spec.Template.Labels = map[string]string{"version": "2"}
+	require.NoError(t, fakeClient.Update(context.TODO(), &current))
+	require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(paused), &current))
+	current.Spec.Paused = true
+	require.NoError(t, fakeClient.Update(context.TODO(), &current))
+
+	scheduler.Schedule(context.TODO(), RestartStrategy{}, []pendingRestart{
+		{obj: scheduled.DeepCopy(), mutate: setRestartAnnotation},
+		{obj: paused.DeepCopy(), mutate: setRestartAnnotation},
+		{obj: deleted.DeepCopy(), mutate: setRestartAnnotation},
+	})
+	scheduler.wait()
+
+	// the restart keeps the changes made since it was scheduled
+	require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(scheduled), &current))
+	assert.Contains(t, current.Spec.Template.Annotations, restartedAtAnnotation)
+	assert.Equal(t, map[string]string{"version": "2"}, current.Spec.Template.Labels)
+	// the paused deployment isn't restarted, and the deleted one isn't reported as failed
+	require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(paused), &current))
+	assert.NotContains(t, current.Spec.Template.Annotations, restartedAtAnnotation)
+	assert.Len(t, recorder.Events, 1)
+}
+
+func TestRestartSchedulerStop(t *testing.T) {
+	deployment := newTestDeployment("deployment", defaultNs, nil, nil)
+	fakeClient := fake2.NewClientBuilder().WithObjects(deployment).Build()
+	scheduler := newRestartScheduler(fakeClient, fakeClient, testr.New(t))
+
+	// the window opens in at least an hour, so the restart is still pending when stopped
+	now := time.Now().UTC()
+	window := MaintenanceWindow{Start: now.Add(time.Hour).Format(timeOfDayLayout), End: now.Add(2 * time.Hour).Format(timeOfDayLayout)}
+	scheduler.Schedule(context.TODO(), RestartStrategy{MaintenanceWindow: window}, []pendingRestart{{obj: deployment, mutate: setRestartAnnotation}})
+	scheduler.Stop()
+
+	var actual appsv1.Deployment
+	require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(deployment), &actual))
+	assert.NotContains(t, actual.Spec.Template.Annotations, restartedAtAnnotation)
+}
+
+func TestRestartSchedulerMaintenanceWindowClosesDuringBatch(t *testing.T) {
+	a := newTestDeployment("a", defaultNs, nil, nil)
+	b := newTestDeployment("b", defaultNs, nil, nil)
+	// the keys are taken before scheduling, since the scheduler patches the objects
+	keyA, keyB := client.ObjectKeyFromObject(a), client.ObjectKeyFromObject(b)
+	fakeClient := fake2.NewClientBuilder().WithObjects(a, b).Build()
+	scheduler := newRestartScheduler(fakeClient, fakeClient, testr.New(t))
+	// the window closes right after the first restart of the batch
+	window := MaintenanceWindow{Start: "09:00", End: "17:00"}
+	calls := 0
+	scheduler.now = func() time.Time {
+		calls++
+		if calls == 1 {
+			return time.Date(2024, 1, 1, 16, 59, 0, 0, time.UTC)
+		}
+		return time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC)
+	}
+
+	scheduler.Schedule(context.TODO(), RestartStrategy{MaintenanceWindow: window}, []pendingRestart{
+		{obj: a, mutate: setRestartAnnotation},
+		{obj: b, mutate: setRestartAnnotation},
+	})
+	require.Eventually(t, func() bool {
+		var actual appsv1.Deployment
+		require.NoError(t, fakeClient.Get(context.TODO(), keyA, &actual))
+		_, ok := actual.Spec.Template.Annotations[restartedAtAnnotation]
+		return ok
+	}, time.Second, time.Millisecond)
+	scheduler.Stop()
+
+	var actual appsv1.Deployment
+	require.NoError(t, fakeClient.Get(context.TODO(), keyB, &actual))
+	assert.NotContains(t, actual.Spec.Template.Annotations, restartedAtAnnotation)
+	// the restart left isn't pending anymore
+	assert.Zero(t, testutil.ToFloat64(restartsPending))
+}
+
+func TestRestartSchedulerResetsPendingRestarts(t *testing.T) {
+	rolledOut := appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	a := newTestDeployment("a", defaultNs, nil, nil)
+	a.Status = rolledOut
+	b := newTestDeployment("b", defaultNs, nil, nil)
+	c := newTestDeployment("c", defaultNs, nil, nil)
+	fakeClient := fake2.NewClientBuilder().WithObjects(a, b, c).Build()
+	scheduler := newRestartScheduler(fakeClient, fakeClient, testr.New(t))
+	scheduler.pollInterval = time.Millisecond
+
+	// b isn't rolled out, so c is never restarted
+	strategy := RestartStrategy{MaxConcurrent: 2, WaitForRollout: true, RolloutTimeout: metav1.Duration{Duration: 20 * time.Millisecond}}
+	scheduler.Schedule(context.TODO(), strategy, []pendingRestart{
+		{obj: a, mutate: setRestartAnnotation},
+		{obj: b, mutate: setRestartAnnotation},
+		{obj: c, mutate: setRestartAnnotation},
+	})
+	scheduler.wait()
+
+	assert.Zero(t, testutil.ToFloat64(restartsPending))
+}
+
+func TestMonitor_MutateAndPatchAllWithRestartStrategy(t *testing.T) {
+	labels := map[string]string{"app": "test"}
+	service := newTestService("service", "shop", labels)
+	objs := []runtime.Object{
+		service,
+		newTestDeployment("a", "shop", labels, nil),
+		newTestDeployment("b", "shop", labels, nil),
+		newTestDeployment("c", "other", labels, nil),
+	}
+	clientset := fake.NewSimpleClientset(objs...)
+	fakeClient := fake2.NewFakeClient(objs...)
+	config := MonitorConfig{
+		MonitorAllServices: true,
+		Languages:          instrumentation.NewTypeSet(instrumentation.TypeJava),
+		RestartPods:        true,
+		RestartStrategy:    &RestartStrategy{MaxConcurrent: 1},
+	}
+	m := NewMonitor(context.TODO(), config, clientset, fakeClient, fakeClient, testr.New(t))
+	m.MutateAndPatchAll(context.TODO())
+	m.restarts.wait()
+
+	for name, want := range map[string]map[string]string{"a": buildAnnotations(instrumentation.TypeJava), "b": buildAnnotations(instrumentation.TypeJava)} {
+		var deployment appsv1.Deployment
+		require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKey{Namespace: "shop", Name: name}, &deployment))
+		assert.Equal(t, want, withoutInstrumentedAt(deployment.Spec.Template.Annotations))
+	}
+	var other appsv1.Deployment
+	require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKey{Namespace: "other", Name: "c"}, &other))
+	assert.Empty(t, other.Spec.Template.Annotations)
+}
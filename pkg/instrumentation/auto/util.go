@@ -16,7 +16,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
-	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/instrumentation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
 )
 
 // configureAutoAnnotation handles the auto annotation configuration logic
@@ -36,6 +36,10 @@ func configureAutoAnnotation(autoAnnotationConfigStr string, client client.Clien
 		return nil, fmt.Errorf("unable to unmarshal auto-annotation config, disabling AutoAnnotation: %w", err)
 	}
 
+	if err := autoAnnotationConfig.ValidateForAutoAnnotation(); err != nil {
+		return nil, fmt.Errorf("invalid auto-annotation config, disabling AutoAnnotation: %w", err)
+	}
+
 	if autoAnnotationConfig.Empty() {
 		return nil, fmt.Errorf("AutoAnnotation configuration is empty, disabling AutoAnnotation")
 	}
@@ -46,7 +50,7 @@ func configureAutoAnnotation(autoAnnotationConfigStr string, client client.Clien
 		reader,
 		setupLog,
 		autoAnnotationConfig,
-		instrumentation.SupportedTypes,
+		autoAnnotationConfig.types(),
 	), nil
 }
 
@@ -59,9 +63,15 @@ func configureAutoMonitor(ctx context.Context, autoMonitorConfigStr string, clie
 	}
 
 	var autoMonitorConfig *MonitorConfig
-	if err := json.Unmarshal([]byte(autoMonitorConfigStr), &autoMonitorConfig); err != nil {
+	if autoMonitorConfigStr == "" && featuregate.EnableAutoMonitorCRD.IsEnabled() {
+		// the config is provided by the AutoMonitor resource
+		autoMonitorConfig = &MonitorConfig{}
+	} else if err := json.Unmarshal([]byte(autoMonitorConfigStr), &autoMonitorConfig); err != nil {
 		return nil, fmt.Errorf("unable to unmarshal auto-monitor config: %w", err)
 	}
+	if err := autoMonitorConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid auto-monitor config: %w", err)
+	}
 
 	resources, err := clientSet.Discovery().ServerResourcesForGroupVersion("opentelemetry.io/v1alpha1")
 	if err == nil {
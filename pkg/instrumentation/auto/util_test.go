@@ -11,10 +11,14 @@ import (
 
 	"github.com/go-logr/logr/testr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colfeaturegate "go.opentelemetry.io/collector/featuregate"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	discoveryfake "k8s.io/client-go/discovery/fake"
 	"k8s.io/client-go/kubernetes/fake"
 	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
 )
 
 func TestCreateInstrumentationAnnotator(t *testing.T) {
@@ -30,6 +34,7 @@ func TestCreateInstrumentationAnnotator(t *testing.T) {
 		autoAnnotationConfig string
 		autoMonitorConfig    string
 		otelSetupExists      bool
+		autoMonitorCRD       bool
 		expectNilAnnotator   bool
 		expectedType         string
 	}{
@@ -87,6 +92,15 @@ func TestCreateInstrumentationAnnotator(t *testing.T) {
 			expectNilAnnotator:   false,
 			expectedType:         "*auto.Monitor",
 		},
+		{
+			name:                 "Selector annotation config, valid monitor config",
+			envDisableAnnotation: false,
+			envDisableMonitor:    false,
+			autoAnnotationConfig: `{"java":{"workloadSelector":{"matchLabels":{"app":"api"}}}}`,
+			autoMonitorConfig:    `{"monitorAllServices":true}`,
+			expectNilAnnotator:   false,
+			expectedType:         "*auto.Monitor",
+		},
 		{
 			name:                 "Valid annotation config, invalid monitor config",
 			envDisableAnnotation: false,
@@ -106,6 +120,21 @@ func TestCreateInstrumentationAnnotator(t *testing.T) {
 			expectNilAnnotator:   true,
 			expectedType:         "",
 		},
+		{
+			name:                 "Empty monitor config",
+			envDisableAnnotation: true,
+			autoMonitorConfig:    ``,
+			expectNilAnnotator:   true,
+			expectedType:         "",
+		},
+		{
+			name:                 "Empty monitor config, configured by the AutoMonitor resource",
+			envDisableAnnotation: true,
+			autoMonitorConfig:    ``,
+			autoMonitorCRD:       true,
+			expectNilAnnotator:   false,
+			expectedType:         "*auto.Monitor",
+		},
 	}
 
 	for _, tt := range tests {
@@ -123,6 +152,12 @@ func TestCreateInstrumentationAnnotator(t *testing.T) {
 				_ = os.Unsetenv("DISABLE_AUTO_MONITOR")
 			}
 
+			originalVal := featuregate.EnableAutoMonitorCRD.IsEnabled()
+			require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableAutoMonitorCRD.ID(), tt.autoMonitorCRD))
+			t.Cleanup(func() {
+				require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableAutoMonitorCRD.ID(), originalVal))
+			})
+
 			fakeClientset := fake.NewSimpleClientset()
 			if tt.otelSetupExists {
 				fakeDiscovery, ok := fakeClientset.Discovery().(*discoveryfake.FakeDiscovery)
@@ -30,6 +30,9 @@ const (
 	python  = "PYTHON"
 	dotNet  = "DOTNET"
 	nodeJS  = "NODEJS"
+	golang  = "GO"
+	ruby    = "RUBY"
+	php     = "PHP"
 	limit   = "LIMIT"
 	request = "REQUEST"
 )
@@ -67,6 +70,18 @@ func getDefaultInstrumentation(agentConfig *adapters.CwaConfig, additionalEnvs m
 	if !ok {
 		return nil, errors.New("unable to determine nodejs instrumentation image")
 	}
+	goInstrumentationImage, ok := os.LookupEnv("AUTO_INSTRUMENTATION_GO")
+	if !ok {
+		return nil, errors.New("unable to determine go instrumentation image")
+	}
+	rubyInstrumentationImage, ok := os.LookupEnv("AUTO_INSTRUMENTATION_RUBY")
+	if !ok {
+		return nil, errors.New("unable to determine ruby instrumentation image")
+	}
+	phpInstrumentationImage, ok := os.LookupEnv("AUTO_INSTRUMENTATION_PHP")
+	if !ok {
+		return nil, errors.New("unable to determine php instrumentation image")
+	}
 
 	cloudwatchAgentServiceEndpoint := "cloudwatch-agent.amazon-cloudwatch"
 	if isWindowsPod {
@@ -133,6 +148,30 @@ func getDefaultInstrumentation(agentConfig *adapters.CwaConfig, additionalEnvs m
 					Requests: getInstrumentationConfigForResource(nodeJS, request),
 				},
 			},
+			Go: v1alpha1.Go{
+				Image: goInstrumentationImage,
+				Env:   getGoEnvs(isApplicationSignalsEnabled, cloudwatchAgentServiceEndpoint, exporterPrefix),
+				Resources: corev1.ResourceRequirements{
+					Limits:   getInstrumentationConfigForResource(golang, limit),
+					Requests: getInstrumentationConfigForResource(golang, request),
+				},
+			},
+			Ruby: v1alpha1.Ruby{
+				Image: rubyInstrumentationImage,
+				Env:   getTracesOnlyEnvs(isApplicationSignalsEnabled, cloudwatchAgentServiceEndpoint, exporterPrefix),
+				Resources: corev1.ResourceRequirements{
+					Limits:   getInstrumentationConfigForResource(ruby, limit),
+					Requests: getInstrumentationConfigForResource(ruby, request),
+				},
+			},
+			PHP: v1alpha1.PHP{
+				Image: phpInstrumentationImage,
+				Env:   getTracesOnlyEnvs(isApplicationSignalsEnabled, cloudwatchAgentServiceEndpoint, exporterPrefix),
+				Resources: corev1.ResourceRequirements{
+					Limits:   getInstrumentationConfigForResource(php, limit),
+					Requests: getInstrumentationConfigForResource(php, request),
+				},
+			},
 		},
 	}, nil
 }
@@ -289,3 +328,23 @@ func getNodeJSEnvs(isAppSignalsEnabled bool, cloudwatchAgentServiceEndpoint, exp
 	}
 	return envs
 }
+
+// getGoEnvs returns the env vars of the Go eBPF agent, which only exports traces.
+func getGoEnvs(isAppSignalsEnabled bool, cloudwatchAgentServiceEndpoint, exporterPrefix string) []corev1.EnvVar {
+	return getTracesOnlyEnvs(isAppSignalsEnabled, cloudwatchAgentServiceEndpoint, exporterPrefix)
+}
+
+// getTracesOnlyEnvs returns the env vars of the SDKs that only export traces to the CloudWatch Agent,
+// which are the Go, Ruby and PHP ones.
+func getTracesOnlyEnvs(isAppSignalsEnabled bool, cloudwatchAgentServiceEndpoint, exporterPrefix string) []corev1.EnvVar {
+	var envs []corev1.EnvVar
+	if isAppSignalsEnabled {
+		envs = []corev1.EnvVar{
+			{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+			{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: fmt.Sprintf("%s://%s:4316/v1/traces", exporterPrefix, cloudwatchAgentServiceEndpoint)},
+			{Name: "OTEL_METRICS_EXPORTER", Value: "none"},
+			{Name: "OTEL_LOGS_EXPORTER", Value: "none"},
+		}
+	}
+	return envs
+}
@@ -22,6 +22,9 @@ func Test_getDefaultInstrumentationLinux(t *testing.T) {
 	_ = os.Setenv("AUTO_INSTRUMENTATION_PYTHON", defaultPythonInstrumentationImage)
 	_ = os.Setenv("AUTO_INSTRUMENTATION_DOTNET", defaultDotNetInstrumentationImage)
 	_ = os.Setenv("AUTO_INSTRUMENTATION_NODEJS", defaultNodeJSInstrumentationImage)
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO", defaultGoInstrumentationImage)
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY", defaultRubyInstrumentationImage)
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP", defaultPHPInstrumentationImage)
 	_ = os.Setenv("AUTO_INSTRUMENTATION_JAVA_CPU_LIMIT", "500m")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_JAVA_MEM_LIMIT", "64Mi")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_JAVA_CPU_REQUEST", "50m")
@@ -38,6 +41,18 @@ func Test_getDefaultInstrumentationLinux(t *testing.T) {
 	_ = os.Setenv("AUTO_INSTRUMENTATION_NODEJS_MEM_LIMIT", "128Mi")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_NODEJS_CPU_REQUEST", "50m")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_NODEJS_MEM_REQUEST", "128Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO_CPU_LIMIT", "500m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO_MEM_LIMIT", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO_CPU_REQUEST", "50m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO_MEM_REQUEST", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY_CPU_LIMIT", "500m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY_MEM_LIMIT", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY_CPU_REQUEST", "50m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY_MEM_REQUEST", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP_CPU_LIMIT", "500m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP_MEM_LIMIT", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP_CPU_REQUEST", "50m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP_MEM_REQUEST", "32Mi")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_JAVA_RUNTIME_ENABLED", "true")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_PYTHON_RUNTIME_ENABLED", "true")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_DOTNET_RUNTIME_ENABLED", "true")
@@ -172,6 +187,63 @@ func Test_getDefaultInstrumentationLinux(t *testing.T) {
 					},
 				},
 			},
+			Go: v1alpha1.Go{
+				Image: defaultGoInstrumentationImage,
+				Env: []corev1.EnvVar{
+					{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+					{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://cloudwatch-agent.amazon-cloudwatch:4316/v1/traces"},
+					{Name: "OTEL_METRICS_EXPORTER", Value: "none"},
+					{Name: "OTEL_LOGS_EXPORTER", Value: "none"},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
+			Ruby: v1alpha1.Ruby{
+				Image: defaultRubyInstrumentationImage,
+				Env: []corev1.EnvVar{
+					{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+					{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://cloudwatch-agent.amazon-cloudwatch:4316/v1/traces"},
+					{Name: "OTEL_METRICS_EXPORTER", Value: "none"},
+					{Name: "OTEL_LOGS_EXPORTER", Value: "none"},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
+			PHP: v1alpha1.PHP{
+				Image: defaultPHPInstrumentationImage,
+				Env: []corev1.EnvVar{
+					{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+					{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://cloudwatch-agent.amazon-cloudwatch:4316/v1/traces"},
+					{Name: "OTEL_METRICS_EXPORTER", Value: "none"},
+					{Name: "OTEL_LOGS_EXPORTER", Value: "none"},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
 		},
 	}
 	httpsInst := &v1alpha1.Instrumentation{
@@ -304,6 +376,63 @@ func Test_getDefaultInstrumentationLinux(t *testing.T) {
 					},
 				},
 			},
+			Go: v1alpha1.Go{
+				Image: defaultGoInstrumentationImage,
+				Env: []corev1.EnvVar{
+					{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+					{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "https://cloudwatch-agent.amazon-cloudwatch:4316/v1/traces"},
+					{Name: "OTEL_METRICS_EXPORTER", Value: "none"},
+					{Name: "OTEL_LOGS_EXPORTER", Value: "none"},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
+			Ruby: v1alpha1.Ruby{
+				Image: defaultRubyInstrumentationImage,
+				Env: []corev1.EnvVar{
+					{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+					{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "https://cloudwatch-agent.amazon-cloudwatch:4316/v1/traces"},
+					{Name: "OTEL_METRICS_EXPORTER", Value: "none"},
+					{Name: "OTEL_LOGS_EXPORTER", Value: "none"},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
+			PHP: v1alpha1.PHP{
+				Image: defaultPHPInstrumentationImage,
+				Env: []corev1.EnvVar{
+					{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+					{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "https://cloudwatch-agent.amazon-cloudwatch:4316/v1/traces"},
+					{Name: "OTEL_METRICS_EXPORTER", Value: "none"},
+					{Name: "OTEL_LOGS_EXPORTER", Value: "none"},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
 		},
 	}
 
@@ -370,6 +499,9 @@ func Test_getDefaultInstrumentationWindows(t *testing.T) {
 	_ = os.Setenv("AUTO_INSTRUMENTATION_PYTHON", defaultPythonInstrumentationImage)
 	_ = os.Setenv("AUTO_INSTRUMENTATION_DOTNET", defaultDotNetInstrumentationImage)
 	_ = os.Setenv("AUTO_INSTRUMENTATION_NODEJS", defaultNodeJSInstrumentationImage)
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO", defaultGoInstrumentationImage)
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY", defaultRubyInstrumentationImage)
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP", defaultPHPInstrumentationImage)
 	_ = os.Setenv("AUTO_INSTRUMENTATION_JAVA_CPU_LIMIT", "500m")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_JAVA_MEM_LIMIT", "64Mi")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_JAVA_CPU_REQUEST", "50m")
@@ -386,6 +518,18 @@ func Test_getDefaultInstrumentationWindows(t *testing.T) {
 	_ = os.Setenv("AUTO_INSTRUMENTATION_NODEJS_MEM_LIMIT", "128Mi")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_NODEJS_CPU_REQUEST", "50m")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_NODEJS_MEM_REQUEST", "128Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO_CPU_LIMIT", "500m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO_MEM_LIMIT", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO_CPU_REQUEST", "50m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO_MEM_REQUEST", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY_CPU_LIMIT", "500m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY_MEM_LIMIT", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY_CPU_REQUEST", "50m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY_MEM_REQUEST", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP_CPU_LIMIT", "500m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP_MEM_LIMIT", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP_CPU_REQUEST", "50m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP_MEM_REQUEST", "32Mi")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_JAVA_RUNTIME_METRICS", "true")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_PYTHON_RUNTIME_METRICS", "true")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_DOTNET_RUNTIME_METRICS", "true")
@@ -520,6 +664,63 @@ func Test_getDefaultInstrumentationWindows(t *testing.T) {
 					},
 				},
 			},
+			Go: v1alpha1.Go{
+				Image: defaultGoInstrumentationImage,
+				Env: []corev1.EnvVar{
+					{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+					{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://cloudwatch-agent-windows-headless.amazon-cloudwatch.svc.cluster.local:4316/v1/traces"},
+					{Name: "OTEL_METRICS_EXPORTER", Value: "none"},
+					{Name: "OTEL_LOGS_EXPORTER", Value: "none"},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
+			Ruby: v1alpha1.Ruby{
+				Image: defaultRubyInstrumentationImage,
+				Env: []corev1.EnvVar{
+					{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+					{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://cloudwatch-agent-windows-headless.amazon-cloudwatch.svc.cluster.local:4316/v1/traces"},
+					{Name: "OTEL_METRICS_EXPORTER", Value: "none"},
+					{Name: "OTEL_LOGS_EXPORTER", Value: "none"},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
+			PHP: v1alpha1.PHP{
+				Image: defaultPHPInstrumentationImage,
+				Env: []corev1.EnvVar{
+					{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+					{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "http://cloudwatch-agent-windows-headless.amazon-cloudwatch.svc.cluster.local:4316/v1/traces"},
+					{Name: "OTEL_METRICS_EXPORTER", Value: "none"},
+					{Name: "OTEL_LOGS_EXPORTER", Value: "none"},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
 		},
 	}
 	httpsInst := &v1alpha1.Instrumentation{
@@ -652,6 +853,63 @@ func Test_getDefaultInstrumentationWindows(t *testing.T) {
 					},
 				},
 			},
+			Go: v1alpha1.Go{
+				Image: defaultGoInstrumentationImage,
+				Env: []corev1.EnvVar{
+					{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+					{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "https://cloudwatch-agent-windows-headless.amazon-cloudwatch.svc.cluster.local:4316/v1/traces"},
+					{Name: "OTEL_METRICS_EXPORTER", Value: "none"},
+					{Name: "OTEL_LOGS_EXPORTER", Value: "none"},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
+			Ruby: v1alpha1.Ruby{
+				Image: defaultRubyInstrumentationImage,
+				Env: []corev1.EnvVar{
+					{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+					{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "https://cloudwatch-agent-windows-headless.amazon-cloudwatch.svc.cluster.local:4316/v1/traces"},
+					{Name: "OTEL_METRICS_EXPORTER", Value: "none"},
+					{Name: "OTEL_LOGS_EXPORTER", Value: "none"},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
+			PHP: v1alpha1.PHP{
+				Image: defaultPHPInstrumentationImage,
+				Env: []corev1.EnvVar{
+					{Name: "OTEL_EXPORTER_OTLP_PROTOCOL", Value: "http/protobuf"},
+					{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Value: "https://cloudwatch-agent-windows-headless.amazon-cloudwatch.svc.cluster.local:4316/v1/traces"},
+					{Name: "OTEL_METRICS_EXPORTER", Value: "none"},
+					{Name: "OTEL_LOGS_EXPORTER", Value: "none"},
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
 		},
 	}
 
@@ -717,6 +975,9 @@ func Test_getDefaultInstrumentationLinuxWithApplicationSignalsDisabled(t *testin
 	_ = os.Setenv("AUTO_INSTRUMENTATION_PYTHON", defaultPythonInstrumentationImage)
 	_ = os.Setenv("AUTO_INSTRUMENTATION_DOTNET", defaultDotNetInstrumentationImage)
 	_ = os.Setenv("AUTO_INSTRUMENTATION_NODEJS", defaultNodeJSInstrumentationImage)
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO", defaultGoInstrumentationImage)
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY", defaultRubyInstrumentationImage)
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP", defaultPHPInstrumentationImage)
 	_ = os.Setenv("AUTO_INSTRUMENTATION_JAVA_CPU_LIMIT", "500m")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_JAVA_MEM_LIMIT", "64Mi")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_JAVA_CPU_REQUEST", "50m")
@@ -733,6 +994,18 @@ func Test_getDefaultInstrumentationLinuxWithApplicationSignalsDisabled(t *testin
 	_ = os.Setenv("AUTO_INSTRUMENTATION_NODEJS_MEM_LIMIT", "128Mi")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_NODEJS_CPU_REQUEST", "50m")
 	_ = os.Setenv("AUTO_INSTRUMENTATION_NODEJS_MEM_REQUEST", "128Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO_CPU_LIMIT", "500m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO_MEM_LIMIT", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO_CPU_REQUEST", "50m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_GO_MEM_REQUEST", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY_CPU_LIMIT", "500m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY_MEM_LIMIT", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY_CPU_REQUEST", "50m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_RUBY_MEM_REQUEST", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP_CPU_LIMIT", "500m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP_MEM_LIMIT", "32Mi")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP_CPU_REQUEST", "50m")
+	_ = os.Setenv("AUTO_INSTRUMENTATION_PHP_MEM_REQUEST", "32Mi")
 
 	httpInst := &v1alpha1.Instrumentation{
 		Status: v1alpha1.InstrumentationStatus{},
@@ -811,6 +1084,45 @@ func Test_getDefaultInstrumentationLinuxWithApplicationSignalsDisabled(t *testin
 					},
 				},
 			},
+			Go: v1alpha1.Go{
+				Image: defaultGoInstrumentationImage,
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
+			Ruby: v1alpha1.Ruby{
+				Image: defaultRubyInstrumentationImage,
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
+			PHP: v1alpha1.PHP{
+				Image: defaultPHPInstrumentationImage,
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
 		},
 	}
 
@@ -893,6 +1205,45 @@ func Test_getDefaultInstrumentationLinuxWithApplicationSignalsDisabled(t *testin
 					},
 				},
 			},
+			Go: v1alpha1.Go{
+				Image: defaultGoInstrumentationImage,
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
+			Ruby: v1alpha1.Ruby{
+				Image: defaultRubyInstrumentationImage,
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
+			PHP: v1alpha1.PHP{
+				Image: defaultPHPInstrumentationImage,
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("50m"),
+						corev1.ResourceMemory: resource.MustParse("32Mi"),
+					},
+				},
+			},
 		},
 	}
 
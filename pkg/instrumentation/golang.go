@@ -18,8 +18,29 @@ const (
 
 	kernelDebugVolumeName = "kernel-debug"
 	kernelDebugVolumePath = "/sys/kernel/debug"
+
+	// podSecurityEnforceLabel is the namespace label of the Pod Security Admission enforced pod security standard.
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
 )
 
+// validateGoSidecar checks that the pod and its namespace allow the Go instrumentation sidecar, a privileged eBPF
+// agent running as root and sharing the process namespace of the application, to work.
+func validateGoSidecar(ns corev1.Namespace, pod corev1.Pod) error {
+	if isWindowsPod(pod) {
+		return fmt.Errorf("go instrumentation isn't supported on Windows pods")
+	}
+	if pod.Spec.ShareProcessNamespace != nil && !*pod.Spec.ShareProcessNamespace {
+		return fmt.Errorf("shared process namespace has been explicitly disabled, the go instrumentation sidecar can't see the application process")
+	}
+	if sc := pod.Spec.SecurityContext; sc != nil && sc.RunAsNonRoot != nil && *sc.RunAsNonRoot {
+		return fmt.Errorf("the pod security context requires containers to run as non-root, the go instrumentation sidecar runs as root")
+	}
+	if level := ns.Labels[podSecurityEnforceLabel]; level == "baseline" || level == "restricted" {
+		return fmt.Errorf("the %s pod security standard enforced in namespace %s forbids the privileged go instrumentation sidecar", level, ns.Name)
+	}
+	return nil
+}
+
 func injectGoSDK(goSpec v1alpha1.Go, pod corev1.Pod) (corev1.Pod, error) {
 	// skip instrumentation if share process namespaces is explicitly disabled
 	if pod.Spec.ShareProcessNamespace != nil && !*pod.Spec.ShareProcessNamespace {
@@ -286,3 +286,55 @@ func TestInjectGoSDK(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateGoSidecar(t *testing.T) {
+	falsee := false
+	true := true
+
+	tests := []struct {
+		name string
+		ns   corev1.Namespace
+		pod  corev1.Pod
+		err  string
+	}{
+		{
+			name: "no restriction",
+			ns:   corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "go", Labels: map[string]string{podSecurityEnforceLabel: "privileged"}}},
+			pod:  corev1.Pod{Spec: corev1.PodSpec{ShareProcessNamespace: &true, SecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: &falsee}}},
+		},
+		{
+			name: "windows pod",
+			ns:   corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "go"}},
+			pod:  corev1.Pod{Spec: corev1.PodSpec{NodeSelector: map[string]string{"kubernetes.io/os": "windows"}}},
+			err:  "go instrumentation isn't supported on Windows pods",
+		},
+		{
+			name: "shared process namespace disabled",
+			ns:   corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "go"}},
+			pod:  corev1.Pod{Spec: corev1.PodSpec{ShareProcessNamespace: &falsee}},
+			err:  "shared process namespace has been explicitly disabled, the go instrumentation sidecar can't see the application process",
+		},
+		{
+			name: "pod runs as non-root",
+			ns:   corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "go"}},
+			pod:  corev1.Pod{Spec: corev1.PodSpec{SecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: &true}}},
+			err:  "the pod security context requires containers to run as non-root, the go instrumentation sidecar runs as root",
+		},
+		{
+			name: "restricted pod security standard",
+			ns:   corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "go", Labels: map[string]string{podSecurityEnforceLabel: "restricted"}}},
+			err:  "the restricted pod security standard enforced in namespace go forbids the privileged go instrumentation sidecar",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateGoSidecar(test.ns, test.pod)
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"encoding/json"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
+)
+
+const (
+	// AnnotationInjectionStatus holds the outcome of the auto-instrumentation injection recorded by the pod webhook.
+	// The value is a JSON list of InjectionOutcome.
+	AnnotationInjectionStatus = "cloudwatch.aws.amazon.com/instrumentation-status"
+	// LabelInjectionStatus marks the pods carrying the AnnotationInjectionStatus, so that only those are watched.
+	LabelInjectionStatus = "cloudwatch.aws.amazon.com/instrumentation-status"
+
+	// UnresolvedInstrumentationName is used as the Instrumentation name in InjectionOutcome.Instrumentation when the
+	// pod requested the single Instrumentation of its namespace but the webhook did not record which one it used.
+	// It is not a valid object name, so it never matches an Instrumentation.
+	UnresolvedInstrumentationName = "*"
+
+	languageApacheHttpd = "apache-httpd"
+	languageNginx       = "nginx"
+	languageSdk         = "sdk"
+
+	reasonNoOutcomeRecorded = "the pod webhook did not record an injection outcome for this pod"
+	reasonNotInjected       = "no container was instrumented, check the operator logs for details"
+)
+
+// injectAnnotationsByLanguage lists the languages reported in InjectionOutcome with their inject annotation.
+var injectAnnotationsByLanguage = []struct {
+	language   string
+	annotation string
+}{
+	{string(TypeJava), annotationInjectJava},
+	{string(TypeNodeJS), annotationInjectNodeJS},
+	{string(TypePython), annotationInjectPython},
+	{string(TypeDotNet), annotationInjectDotNet},
+	{string(TypeGo), annotationInjectGo},
+	{string(TypeRuby), annotationInjectRuby},
+	{string(TypePHP), annotationInjectPHP},
+	{languageApacheHttpd, annotationInjectApacheHttpd},
+	{languageNginx, annotationInjectNginx},
+	{languageSdk, annotationInjectSdk},
+}
+
+// InjectionOutcome is the decision taken by the pod webhook for a single instrumentation language.
+type InjectionOutcome struct {
+	// Language is the instrumentation language, e.g. java.
+	Language string `json:"language"`
+	// Instrumentation is the namespace/name of the Instrumentation used. Empty when the default instrumentation
+	// was used.
+	Instrumentation string `json:"instrumentation,omitempty"`
+	// State is the outcome of the injection.
+	State v1alpha1.InjectionState `json:"state"`
+	// Reason explains skipped and failed outcomes.
+	Reason string `json:"reason,omitempty"`
+}
+
+type injectionOutcomes []InjectionOutcome
+
+func (o *injectionOutcomes) add(language string, inst *v1alpha1.Instrumentation, state v1alpha1.InjectionState, reason string) {
+	*o = append(*o, InjectionOutcome{
+		Language:        language,
+		Instrumentation: instrumentationRef(inst),
+		State:           state,
+		Reason:          reason,
+	})
+}
+
+// addFailed records a failed outcome for the language, attributed to the Instrumentation requested by the inject
+// annotation value since none could be selected.
+func (o *injectionOutcomes) addFailed(language string, namespace string, value string, reason string) {
+	*o = append(*o, InjectionOutcome{
+		Language:        language,
+		Instrumentation: requestedInstrumentationRef(namespace, value),
+		State:           v1alpha1.InjectionStateFailed,
+		Reason:          reason,
+	})
+}
+
+// addInjected records the outcome of every instrumentation selected for the pod, based on whether the injection
+// actually modified the pod.
+func (o *injectionOutcomes) addInjected(insts languageInstrumentations, pod corev1.Pod) {
+	injected := map[string]bool{
+		string(TypeJava):    !isInitContainerMissing(pod, javaInitContainerName),
+		string(TypeNodeJS):  !isInitContainerMissing(pod, nodejsInitContainerName),
+		string(TypePython):  !isInitContainerMissing(pod, pythonInitContainerName),
+		string(TypeDotNet):  !isInitContainerMissing(pod, dotnetInitContainerName),
+		string(TypeGo):      isContainerPresent(pod, sideCarName),
+		string(TypeRuby):    !isInitContainerMissing(pod, rubyInitContainerName),
+		string(TypePHP):     !isInitContainerMissing(pod, phpInitContainerName),
+		languageApacheHttpd: !isInitContainerMissing(pod, apacheAgentInitContainerName),
+		languageNginx:       !isInitContainerMissing(pod, nginxAgentInitContainerName),
+		// the SDK instrumentation only sets env vars and is always applied
+		languageSdk: true,
+	}
+	for _, s := range insts.selected() {
+		if injected[s.language] {
+			o.add(s.language, s.inst, v1alpha1.InjectionStateInjected, "")
+		} else {
+			o.add(s.language, s.inst, v1alpha1.InjectionStateSkipped, reasonNotInjected)
+		}
+	}
+}
+
+// addSkipped records a skipped outcome for every instrumentation selected for the pod.
+func (o *injectionOutcomes) addSkipped(insts languageInstrumentations, reason string) {
+	for _, s := range insts.selected() {
+		o.add(s.language, s.inst, v1alpha1.InjectionStateSkipped, reason)
+	}
+}
+
+// apply records the outcomes on the pod.
+func (o injectionOutcomes) apply(pod corev1.Pod) corev1.Pod {
+	labels, annotations := o.record()
+	if len(annotations) == 0 {
+		return pod
+	}
+	pod.Labels = withEntries(pod.Labels, labels)
+	pod.Annotations = withEntries(pod.Annotations, annotations)
+	return pod
+}
+
+// failed returns the error of the mutator with the outcomes to record on the pod. The pod webhook records them on
+// the original pod and drops every other change.
+func (o injectionOutcomes) failed(err error) error {
+	labels, annotations := o.record()
+	if len(annotations) == 0 {
+		return err
+	}
+	return &podmutation.FailedMutationError{Err: err, Labels: labels, Annotations: annotations}
+}
+
+// record returns the labels and annotations recording the outcomes, none if the status reporting is disabled.
+func (o injectionOutcomes) record() (map[string]string, map[string]string) {
+	if len(o) == 0 || !featuregate.EnableInstrumentationStatusReporting.IsEnabled() {
+		return nil, nil
+	}
+	value, err := json.Marshal(o)
+	if err != nil {
+		return nil, nil
+	}
+	return map[string]string{LabelInjectionStatus: "true"}, map[string]string{AnnotationInjectionStatus: string(value)}
+}
+
+func withEntries(m map[string]string, entries map[string]string) map[string]string {
+	merged := make(map[string]string, len(m)+len(entries))
+	for k, v := range m {
+		merged[k] = v
+	}
+	for k, v := range entries {
+		merged[k] = v
+	}
+	return merged
+}
+
+// instrumentationRef returns the namespace/name of an Instrumentation stored in the cluster. The default
+// instrumentation is built in memory and has no UID, so it isn't referenced.
+func instrumentationRef(inst *v1alpha1.Instrumentation) string {
+	if inst == nil || inst.UID == "" {
+		return ""
+	}
+	return types.NamespacedName{Namespace: inst.Namespace, Name: inst.Name}.String()
+}
+
+// InjectionOutcomes returns the injection outcomes of the pod. When the pod webhook did not record them, the
+// outcomes are derived from the inject annotations of the pod: the languages are reported as injected if the pod
+// carries auto-instrumentation, and as failed otherwise.
+func InjectionOutcomes(pod corev1.Pod) []InjectionOutcome {
+	if value, ok := pod.Annotations[AnnotationInjectionStatus]; ok {
+		var outcomes []InjectionOutcome
+		if err := json.Unmarshal([]byte(value), &outcomes); err == nil {
+			return outcomes
+		}
+	}
+
+	var outcomes []InjectionOutcome
+	injected := isAutoInstrumentationInjected(pod)
+	for _, l := range injectAnnotationsByLanguage {
+		value := pod.Annotations[l.annotation]
+		if len(value) == 0 || strings.EqualFold(value, "false") {
+			continue
+		}
+		outcome := InjectionOutcome{
+			Language:        l.language,
+			Instrumentation: requestedInstrumentationRef(pod.Namespace, value),
+			State:           v1alpha1.InjectionStateInjected,
+		}
+		if !injected {
+			outcome.State = v1alpha1.InjectionStateFailed
+			outcome.Reason = reasonNoOutcomeRecorded
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes
+}
+
+// requestedInstrumentationRef returns the namespace/name of the Instrumentation requested by an inject annotation
+// value. A "true" value selects the single Instrumentation of the namespace, which is returned with the
+// UnresolvedInstrumentationName.
+func requestedInstrumentationRef(namespace string, value string) string {
+	if strings.EqualFold(value, "true") {
+		return types.NamespacedName{Namespace: namespace, Name: UnresolvedInstrumentationName}.String()
+	}
+	if instNamespace, instName, namespaced := strings.Cut(value, "/"); namespaced {
+		return types.NamespacedName{Namespace: instNamespace, Name: instName}.String()
+	}
+	return types.NamespacedName{Namespace: namespace, Name: value}.String()
+}
+
+// RequestsInstrumentation returns whether the pod carries an inject annotation or a recorded injection outcome.
+func RequestsInstrumentation(pod corev1.Pod) bool {
+	if _, ok := pod.Annotations[AnnotationInjectionStatus]; ok {
+		return true
+	}
+	for _, l := range injectAnnotationsByLanguage {
+		if value := pod.Annotations[l.annotation]; len(value) != 0 && !strings.EqualFold(value, "false") {
+			return true
+		}
+	}
+	return false
+}
+
+type selectedInstrumentation struct {
+	language string
+	inst     *v1alpha1.Instrumentation
+}
+
+// selected returns the instrumentations selected for the pod with the language reported in InjectionOutcome.
+func (langInsts languageInstrumentations) selected() []selectedInstrumentation {
+	all := []selectedInstrumentation{
+		{string(TypeJava), langInsts.Java.Instrumentation},
+		{string(TypeNodeJS), langInsts.NodeJS.Instrumentation},
+		{string(TypePython), langInsts.Python.Instrumentation},
+		{string(TypeDotNet), langInsts.DotNet.Instrumentation},
+		{string(TypeGo), langInsts.Go.Instrumentation},
+		{string(TypeRuby), langInsts.Ruby.Instrumentation},
+		{string(TypePHP), langInsts.PHP.Instrumentation},
+		{languageApacheHttpd, langInsts.ApacheHttpd.Instrumentation},
+		{languageNginx, langInsts.Nginx.Instrumentation},
+		{languageSdk, langInsts.Sdk.Instrumentation},
+	}
+	var selected []selectedInstrumentation
+	for _, s := range all {
+		if s.inst != nil {
+			selected = append(selected, s)
+		}
+	}
+	return selected
+}
+
+func isContainerPresent(pod corev1.Pod, containerName string) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == containerName {
+			return true
+		}
+	}
+	return false
+}
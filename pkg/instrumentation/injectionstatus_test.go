@@ -0,0 +1,138 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colfeaturegate "go.opentelemetry.io/collector/featuregate"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
+)
+
+func TestInjectionOutcomesRoundTrip(t *testing.T) {
+	setInstrumentationStatusReporting(t, true)
+
+	inst := &v1alpha1.Instrumentation{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "my-inst", UID: "uid"}}
+	outcomes := injectionOutcomes{}
+	outcomes.add(string(TypeJava), inst, v1alpha1.InjectionStateInjected, "")
+	outcomes.add(string(TypePython), nil, v1alpha1.InjectionStateSkipped, "support for Python auto instrumentation is not enabled")
+
+	pod := outcomes.apply(corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"foo": "bar"}}})
+
+	assert.Equal(t, "bar", pod.Annotations["foo"])
+	assert.Equal(t, []InjectionOutcome{
+		{Language: "java", Instrumentation: "ns/my-inst", State: v1alpha1.InjectionStateInjected},
+		{Language: "python", State: v1alpha1.InjectionStateSkipped, Reason: "support for Python auto instrumentation is not enabled"},
+	}, InjectionOutcomes(pod))
+	assert.True(t, RequestsInstrumentation(pod))
+}
+
+func TestInjectionOutcomesApplyDisabled(t *testing.T) {
+	setInstrumentationStatusReporting(t, false)
+
+	outcomes := injectionOutcomes{}
+	outcomes.add(string(TypeJava), nil, v1alpha1.InjectionStateInjected, "")
+	pod := outcomes.apply(corev1.Pod{})
+
+	assert.NotContains(t, pod.Annotations, AnnotationInjectionStatus)
+}
+
+func TestInjectionOutcomesFromAnnotations(t *testing.T) {
+	tests := []struct {
+		name     string
+		pod      corev1.Pod
+		expected []InjectionOutcome
+	}{
+		{
+			name: "not requested",
+			pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ns",
+				Annotations: map[string]string{annotationInjectJava: "false"},
+			}},
+		},
+		{
+			name: "requested and injected",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "ns",
+					Annotations: map[string]string{
+						annotationInjectJava:   "true",
+						annotationInjectPython: "other/my-inst",
+						annotationInjectNodeJS: "my-inst",
+					},
+				},
+				Spec: corev1.PodSpec{InitContainers: []corev1.Container{{Name: javaInitContainerName}}},
+			},
+			expected: []InjectionOutcome{
+				{Language: "java", Instrumentation: "ns/*", State: v1alpha1.InjectionStateInjected},
+				{Language: "nodejs", Instrumentation: "ns/my-inst", State: v1alpha1.InjectionStateInjected},
+				{Language: "python", Instrumentation: "other/my-inst", State: v1alpha1.InjectionStateInjected},
+			},
+		},
+		{
+			name: "requested and not injected",
+			pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ns",
+				Annotations: map[string]string{annotationInjectDotNet: "my-inst"},
+			}},
+			expected: []InjectionOutcome{
+				{Language: "dotnet", Instrumentation: "ns/my-inst", State: v1alpha1.InjectionStateFailed, Reason: reasonNoOutcomeRecorded},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, InjectionOutcomes(test.pod))
+			assert.Equal(t, len(test.expected) > 0, RequestsInstrumentation(test.pod))
+		})
+	}
+}
+
+func TestMutateRecordsFailedOutcome(t *testing.T) {
+	setInstrumentationStatusReporting(t, true)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	mutator := NewMutator(logr.Discard(), cl, record.NewFakeRecorder(10))
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "ns",
+		Annotations: map[string]string{annotationInjectJava: "missing-inst"},
+	}}
+
+	mutated, err := mutator.Mutate(context.Background(), ns, pod)
+
+	// the failure is returned to the pod webhook, which records it on the original pod
+	var failedErr *podmutation.FailedMutationError
+	require.ErrorAs(t, err, &failedErr)
+	assert.Equal(t, pod, mutated)
+	recorded := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: failedErr.Labels, Annotations: failedErr.Annotations}}
+	assert.Equal(t, []InjectionOutcome{
+		{Language: "java", Instrumentation: "ns/missing-inst", State: v1alpha1.InjectionStateFailed, Reason: err.Error()},
+	}, InjectionOutcomes(recorded))
+	assert.Equal(t, "true", recorded.Labels[LabelInjectionStatus])
+}
+
+// setInstrumentationStatusReporting sets the status reporting feature gate for the duration of the test.
+func setInstrumentationStatusReporting(t *testing.T, enabled bool) {
+	originalVal := featuregate.EnableInstrumentationStatusReporting.IsEnabled()
+	require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableInstrumentationStatusReporting.ID(), enabled))
+	t.Cleanup(func() {
+		require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableInstrumentationStatusReporting.ID(), originalVal))
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+const reasonCrashLoopBackOff = "CrashLoopBackOff"
+
+// InstrumentationFailure returns why the auto-instrumentation injected in the pod is failing it: either an
+// auto-instrumentation init container or the Go instrumentation sidecar failed, or a container the auto-instrumentation
+// was injected into is crash-looping. The other containers are not blamed on the auto-instrumentation. It returns false
+// if the pod wasn't auto-instrumented.
+func InstrumentationFailure(pod corev1.Pod) (string, bool) {
+	if !isAutoInstrumentationInjected(pod) {
+		return "", false
+	}
+	for _, status := range pod.Status.InitContainerStatuses {
+		if !isAutoInstrumentationInitContainer(status.Name) {
+			continue
+		}
+		if reason, failed := containerFailure(status); failed {
+			return fmt.Sprintf("auto-instrumentation init container %s %s", status.Name, reason), true
+		}
+	}
+	injected := injectedContainers(pod)
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == sideCarName {
+			if reason, failed := containerFailure(status); failed {
+				return fmt.Sprintf("go auto-instrumentation sidecar %s %s", status.Name, reason), true
+			}
+			continue
+		}
+		if !injected[status.Name] {
+			continue
+		}
+		if waiting := status.State.Waiting; waiting != nil && waiting.Reason == reasonCrashLoopBackOff {
+			return fmt.Sprintf("container %s is crash-looping after the auto-instrumentation was injected", status.Name), true
+		}
+	}
+	return "", false
+}
+
+// InjectedAt returns when the auto-instrumentation of the pod was injected: when the auto-monitor instrumented its
+// workload if recorded in AnnotationInstrumentedAt, or else when the pod was created, since the webhook injects the
+// auto-instrumentation at creation.
+func InjectedAt(pod corev1.Pod) time.Time {
+	if value, ok := pod.Annotations[AnnotationInstrumentedAt]; ok {
+		if instrumentedAt, err := time.Parse(time.RFC3339, value); err == nil {
+			return instrumentedAt
+		}
+	}
+	return pod.CreationTimestamp.Time
+}
+
+// injectedContainers returns the names of the containers the auto-instrumentation was injected into.
+func injectedContainers(pod corev1.Pod) map[string]bool {
+	injected := map[string]bool{}
+	for _, container := range pod.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name == constants.EnvNodeName {
+				injected[container.Name] = true
+				break
+			}
+		}
+	}
+	return injected
+}
+
+func isAutoInstrumentationInitContainer(name string) bool {
+	return strings.HasPrefix(name, initContainerName) ||
+		name == apacheAgentInitContainerName ||
+		name == apacheAgentCloneContainerName ||
+		name == nginxAgentInitContainerName
+}
+
+// containerFailure returns whether the init container failed, unless it succeeded on a retry.
+func containerFailure(status corev1.ContainerStatus) (string, bool) {
+	if terminated := status.State.Terminated; terminated != nil {
+		if terminated.ExitCode == 0 {
+			return "", false
+		}
+		return fmt.Sprintf("failed with exit code %d (%s)", terminated.ExitCode, terminated.Reason), true
+	}
+	if waiting := status.State.Waiting; waiting != nil && waiting.Reason == reasonCrashLoopBackOff {
+		return "is crash-looping", true
+	}
+	if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.ExitCode != 0 && status.State.Running == nil {
+		return fmt.Sprintf("failed with exit code %d (%s)", terminated.ExitCode, terminated.Reason), true
+	}
+	return "", false
+}
@@ -0,0 +1,127 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+func TestInstrumentationFailure(t *testing.T) {
+	instrumented := corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: javaInitContainerName}},
+		Containers:     []corev1.Container{{Name: "app", Env: []corev1.EnvVar{{Name: constants.EnvNodeName}}}, {Name: "proxy"}},
+	}
+	crashLoopBackOff := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reasonCrashLoopBackOff}}
+	failed := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}}
+	completed := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0, Reason: "Completed"}}
+
+	tests := []struct {
+		name   string
+		spec   corev1.PodSpec
+		status corev1.PodStatus
+		reason string
+	}{
+		{
+			name: "healthy",
+			spec: instrumented,
+			status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: javaInitContainerName, State: completed}},
+				ContainerStatuses:     []corev1.ContainerStatus{{Name: "app", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}},
+			},
+		},
+		{
+			name: "init container failed",
+			spec: instrumented,
+			status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: javaInitContainerName, State: failed}},
+			},
+			reason: "auto-instrumentation init container opentelemetry-auto-instrumentation-java failed with exit code 1 (Error)",
+		},
+		{
+			name: "init container crash-looping",
+			spec: instrumented,
+			status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: javaInitContainerName, State: crashLoopBackOff, LastTerminationState: failed}},
+			},
+			reason: "auto-instrumentation init container opentelemetry-auto-instrumentation-java is crash-looping",
+		},
+		{
+			name: "init container succeeded on retry",
+			spec: instrumented,
+			status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: javaInitContainerName, State: completed, LastTerminationState: failed}},
+			},
+		},
+		{
+			name: "other init container failed",
+			spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "migrations"}, {Name: javaInitContainerName}},
+			},
+			status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: "migrations", State: failed}},
+			},
+		},
+		{
+			name: "application crash-looping",
+			spec: instrumented,
+			status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: javaInitContainerName, State: completed}},
+				ContainerStatuses:     []corev1.ContainerStatus{{Name: "app", State: crashLoopBackOff}},
+			},
+			reason: "container app is crash-looping after the auto-instrumentation was injected",
+		},
+		{
+			name: "container not injected crash-looping",
+			spec: instrumented,
+			status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: javaInitContainerName, State: completed}},
+				ContainerStatuses:     []corev1.ContainerStatus{{Name: "proxy", State: crashLoopBackOff}},
+			},
+		},
+		{
+			name: "go sidecar failed",
+			spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}, {Name: sideCarName}}},
+			status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "app", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+					{Name: sideCarName, State: failed},
+				},
+			},
+			reason: "go auto-instrumentation sidecar opentelemetry-auto-instrumentation failed with exit code 1 (Error)",
+		},
+		{
+			name: "not instrumented",
+			spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "app", State: crashLoopBackOff}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, failed := InstrumentationFailure(corev1.Pod{Spec: tt.spec, Status: tt.status})
+			assert.Equal(t, tt.reason != "", failed)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestInjectedAt(t *testing.T) {
+	created := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+	assert.Equal(t, created, InjectedAt(pod))
+
+	pod.Annotations = map[string]string{AnnotationInstrumentedAt: "2024-01-01T00:00:00Z"}
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), InjectedAt(pod))
+
+	pod.Annotations[AnnotationInstrumentedAt] = "yesterday"
+	assert.Equal(t, created, InjectedAt(pod))
+}
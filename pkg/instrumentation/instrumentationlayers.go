@@ -0,0 +1,270 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
+)
+
+const (
+	// annotationInstrumentationOverrides references the ConfigMap, in the namespace of the pod, holding the
+	// workload-level patch of the Instrumentation used for the pod.
+	annotationInstrumentationOverrides = "cloudwatch.aws.amazon.com/instrumentation-overrides"
+	// instrumentationOverridesKey is the key of the ConfigMap holding the patch, a partial InstrumentationSpec.
+	instrumentationOverridesKey = "spec"
+)
+
+// layerInstrumentation resolves the Instrumentation used for the pod from its layers, from the lowest to the highest
+// precedence: the cluster default instrumentation, the Instrumentation selected in the namespace of the pod and the
+// patch referenced by the workload. The selected Instrumentation keeps its identity and only takes the images, volume
+// size limits and resources it leaves unset from the cluster default instrumentation, not its Application Signals
+// configuration.
+func (pm *instPodMutator) layerInstrumentation(ctx context.Context, ns corev1.Namespace, pod corev1.Pod, inst *v1alpha1.Instrumentation, additionalEnvs map[Type]map[string]string) (*v1alpha1.Instrumentation, error) {
+	trace := podmutation.TraceFromContext(ctx)
+	layered := inst.DeepCopy()
+
+	// the default instrumentation is already the cluster default layer
+	if inst.UID != "" {
+		defaultInst, err := pm.defaultInstrumentation(ctx, additionalEnvs, isWindowsPod(pod))
+		if err != nil {
+			pm.Logger.V(1).Info("unable to layer the Instrumentation over the default instrumentation", "reason", err.Error())
+			trace.Recordf(mutatorName, "Instrumentation %s/%s is not layered over the default instrumentation: %v", inst.Namespace, inst.Name, err)
+		} else {
+			layered.Spec = mergeInstrumentationSpec(clusterDefaultLayer(defaultInst.Spec), inst.Spec)
+			trace.Recordf(mutatorName, "Instrumentation %s/%s is layered over the default instrumentation", inst.Namespace, inst.Name)
+		}
+	}
+
+	name, ok := pod.Annotations[annotationInstrumentationOverrides]
+	if !ok || name == "" {
+		return layered, nil
+	}
+	patch, err := pm.instrumentationOverrides(ctx, types.NamespacedName{Namespace: ns.Name, Name: name})
+	if err != nil {
+		return nil, err
+	}
+	layered.Spec = mergeInstrumentationSpec(layered.Spec, *patch)
+	trace.Recordf(mutatorName, "annotation %s patches the Instrumentation with ConfigMap %s/%s", annotationInstrumentationOverrides, ns.Name, name)
+	return layered, nil
+}
+
+// clusterDefaultLayer returns the part of the default instrumentation layered under the Instrumentations of the
+// namespaces: the images, volume size limits and resources of the languages. The env vars, sampler, exporter and
+// propagators of the default instrumentation configure Application Signals and are left to the Instrumentation.
+func clusterDefaultLayer(spec v1alpha1.InstrumentationSpec) v1alpha1.InstrumentationSpec {
+	spec = *spec.DeepCopy()
+	return v1alpha1.InstrumentationSpec{
+		Java:        v1alpha1.Java{Image: spec.Java.Image, VolumeSizeLimit: spec.Java.VolumeSizeLimit, Resources: spec.Java.Resources},
+		NodeJS:      v1alpha1.NodeJS{Image: spec.NodeJS.Image, VolumeSizeLimit: spec.NodeJS.VolumeSizeLimit, Resources: spec.NodeJS.Resources},
+		Python:      v1alpha1.Python{Image: spec.Python.Image, VolumeSizeLimit: spec.Python.VolumeSizeLimit, Resources: spec.Python.Resources},
+		DotNet:      v1alpha1.DotNet{Image: spec.DotNet.Image, VolumeSizeLimit: spec.DotNet.VolumeSizeLimit, Resources: spec.DotNet.Resources},
+		Go:          v1alpha1.Go{Image: spec.Go.Image, VolumeSizeLimit: spec.Go.VolumeSizeLimit, Resources: spec.Go.Resources},
+		ApacheHttpd: v1alpha1.ApacheHttpd{Image: spec.ApacheHttpd.Image, VolumeSizeLimit: spec.ApacheHttpd.VolumeSizeLimit, Resources: spec.ApacheHttpd.Resources},
+		Nginx:       v1alpha1.Nginx{Image: spec.Nginx.Image, VolumeSizeLimit: spec.Nginx.VolumeSizeLimit, Resources: spec.Nginx.Resources},
+		Ruby:        v1alpha1.Ruby{Image: spec.Ruby.Image, VolumeSizeLimit: spec.Ruby.VolumeSizeLimit, Resources: spec.Ruby.Resources},
+		PHP:         v1alpha1.PHP{Image: spec.PHP.Image, VolumeSizeLimit: spec.PHP.VolumeSizeLimit, Resources: spec.PHP.Resources},
+	}
+}
+
+// instrumentationOverrides returns the partial InstrumentationSpec held by the ConfigMap.
+func (pm *instPodMutator) instrumentationOverrides(ctx context.Context, name types.NamespacedName) (*v1alpha1.InstrumentationSpec, error) {
+	var configMap corev1.ConfigMap
+	if err := pm.Client.Get(ctx, name, &configMap); err != nil {
+		return nil, fmt.Errorf("failed to get the Instrumentation overrides %s: %w", name, err)
+	}
+	data, ok := configMap.Data[instrumentationOverridesKey]
+	if !ok {
+		return nil, fmt.Errorf("the Instrumentation overrides %s have no %q key", name, instrumentationOverridesKey)
+	}
+	var spec v1alpha1.InstrumentationSpec
+	if err := yaml.UnmarshalStrict([]byte(data), &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse the Instrumentation overrides %s: %w", name, err)
+	}
+	return &spec, nil
+}
+
+// mergeInstrumentationSpec merges the override into the base field by field, the override taking precedence:
+//   - env vars, common and language specific, are merged by name.
+//   - resource limits and requests are merged by resource name.
+//   - resource attributes are merged by key, K8s UID attributes are added if either spec adds them.
+//   - the sampler type replaces the base sampler, a sampler argument alone replaces the base argument.
+//   - the other fields, e.g. the exporter endpoint, propagators or images, replace the base fields when set.
+//
+// The base env vars configuring the sampler or the exporter endpoint are dropped when the override sets the sampler or
+// the endpoint, since the SDK injection doesn't override env vars already set.
+func mergeInstrumentationSpec(base, override v1alpha1.InstrumentationSpec) v1alpha1.InstrumentationSpec {
+	merged := *base.DeepCopy()
+	override = *override.DeepCopy()
+	superseded := supersededEnvVars(override)
+
+	if override.Endpoint != "" {
+		merged.Endpoint = override.Endpoint
+	}
+	for key, value := range override.Resource.Attributes {
+		if merged.Resource.Attributes == nil {
+			merged.Resource.Attributes = map[string]string{}
+		}
+		merged.Resource.Attributes[key] = value
+	}
+	merged.Resource.AddK8sUIDAttributes = merged.Resource.AddK8sUIDAttributes || override.Resource.AddK8sUIDAttributes
+	if len(override.Propagators) > 0 {
+		merged.Propagators = override.Propagators
+	}
+	if override.Sampler.Type != "" {
+		merged.Sampler = override.Sampler
+	} else if override.Sampler.Argument != "" {
+		merged.Sampler.Argument = override.Sampler.Argument
+	}
+	merged.Env = mergeEnvVars(dropEnvVars(merged.Env, superseded), override.Env)
+
+	merged.Java = v1alpha1.Java{
+		Image:           mergeString(merged.Java.Image, override.Java.Image),
+		VolumeSizeLimit: mergeQuantity(merged.Java.VolumeSizeLimit, override.Java.VolumeSizeLimit),
+		Env:             mergeEnvVars(dropEnvVars(merged.Java.Env, superseded), override.Java.Env),
+		Resources:       mergeResources(merged.Java.Resources, override.Java.Resources),
+	}
+	merged.NodeJS = v1alpha1.NodeJS{
+		Image:           mergeString(merged.NodeJS.Image, override.NodeJS.Image),
+		VolumeSizeLimit: mergeQuantity(merged.NodeJS.VolumeSizeLimit, override.NodeJS.VolumeSizeLimit),
+		Env:             mergeEnvVars(dropEnvVars(merged.NodeJS.Env, superseded), override.NodeJS.Env),
+		Resources:       mergeResources(merged.NodeJS.Resources, override.NodeJS.Resources),
+	}
+	merged.Python = v1alpha1.Python{
+		Image:           mergeString(merged.Python.Image, override.Python.Image),
+		VolumeSizeLimit: mergeQuantity(merged.Python.VolumeSizeLimit, override.Python.VolumeSizeLimit),
+		Env:             mergeEnvVars(dropEnvVars(merged.Python.Env, superseded), override.Python.Env),
+		Resources:       mergeResources(merged.Python.Resources, override.Python.Resources),
+	}
+	merged.DotNet = v1alpha1.DotNet{
+		Image:           mergeString(merged.DotNet.Image, override.DotNet.Image),
+		VolumeSizeLimit: mergeQuantity(merged.DotNet.VolumeSizeLimit, override.DotNet.VolumeSizeLimit),
+		Env:             mergeEnvVars(dropEnvVars(merged.DotNet.Env, superseded), override.DotNet.Env),
+		Resources:       mergeResources(merged.DotNet.Resources, override.DotNet.Resources),
+	}
+	merged.Go = v1alpha1.Go{
+		Image:           mergeString(merged.Go.Image, override.Go.Image),
+		VolumeSizeLimit: mergeQuantity(merged.Go.VolumeSizeLimit, override.Go.VolumeSizeLimit),
+		Env:             mergeEnvVars(dropEnvVars(merged.Go.Env, superseded), override.Go.Env),
+		Resources:       mergeResources(merged.Go.Resources, override.Go.Resources),
+	}
+	merged.ApacheHttpd = v1alpha1.ApacheHttpd{
+		Image:           mergeString(merged.ApacheHttpd.Image, override.ApacheHttpd.Image),
+		VolumeSizeLimit: mergeQuantity(merged.ApacheHttpd.VolumeSizeLimit, override.ApacheHttpd.VolumeSizeLimit),
+		Env:             mergeEnvVars(dropEnvVars(merged.ApacheHttpd.Env, superseded), override.ApacheHttpd.Env),
+		Attrs:           mergeEnvVars(merged.ApacheHttpd.Attrs, override.ApacheHttpd.Attrs),
+		Version:         mergeString(merged.ApacheHttpd.Version, override.ApacheHttpd.Version),
+		ConfigPath:      mergeString(merged.ApacheHttpd.ConfigPath, override.ApacheHttpd.ConfigPath),
+		Resources:       mergeResources(merged.ApacheHttpd.Resources, override.ApacheHttpd.Resources),
+	}
+	merged.Nginx = v1alpha1.Nginx{
+		Image:           mergeString(merged.Nginx.Image, override.Nginx.Image),
+		VolumeSizeLimit: mergeQuantity(merged.Nginx.VolumeSizeLimit, override.Nginx.VolumeSizeLimit),
+		Env:             mergeEnvVars(dropEnvVars(merged.Nginx.Env, superseded), override.Nginx.Env),
+		Attrs:           mergeEnvVars(merged.Nginx.Attrs, override.Nginx.Attrs),
+		ConfigFile:      mergeString(merged.Nginx.ConfigFile, override.Nginx.ConfigFile),
+		Resources:       mergeResources(merged.Nginx.Resources, override.Nginx.Resources),
+	}
+	merged.Ruby = v1alpha1.Ruby{
+		Image:           mergeString(merged.Ruby.Image, override.Ruby.Image),
+		VolumeSizeLimit: mergeQuantity(merged.Ruby.VolumeSizeLimit, override.Ruby.VolumeSizeLimit),
+		Env:             mergeEnvVars(dropEnvVars(merged.Ruby.Env, superseded), override.Ruby.Env),
+		Resources:       mergeResources(merged.Ruby.Resources, override.Ruby.Resources),
+	}
+	merged.PHP = v1alpha1.PHP{
+		Image:           mergeString(merged.PHP.Image, override.PHP.Image),
+		VolumeSizeLimit: mergeQuantity(merged.PHP.VolumeSizeLimit, override.PHP.VolumeSizeLimit),
+		Env:             mergeEnvVars(dropEnvVars(merged.PHP.Env, superseded), override.PHP.Env),
+		Resources:       mergeResources(merged.PHP.Resources, override.PHP.Resources),
+	}
+	return merged
+}
+
+// supersededEnvVars returns the names of the env vars set for the sampler and the exporter endpoint of the override.
+func supersededEnvVars(override v1alpha1.InstrumentationSpec) []string {
+	var names []string
+	if override.Sampler.Type != "" || override.Sampler.Argument != "" {
+		names = append(names, constants.EnvOTELTracesSampler, constants.EnvOTELTracesSamplerArg)
+	}
+	if override.Endpoint != "" {
+		names = append(names, constants.EnvOTELExporterOTLPEndpoint, constants.EnvOTELExporterOTLPTracesEndpoint)
+	}
+	return names
+}
+
+// dropEnvVars returns the env vars without the named ones.
+func dropEnvVars(envs []corev1.EnvVar, names []string) []corev1.EnvVar {
+	if len(names) == 0 {
+		return envs
+	}
+	var kept []corev1.EnvVar
+	for _, env := range envs {
+		if !slices.Contains(names, env.Name) {
+			kept = append(kept, env)
+		}
+	}
+	return kept
+}
+
+func mergeString(base, override string) string {
+	if override != "" {
+		return override
+	}
+	return base
+}
+
+func mergeQuantity(base, override *resource.Quantity) *resource.Quantity {
+	if override != nil {
+		return override
+	}
+	return base
+}
+
+// mergeEnvVars replaces the base env vars by the override env vars of the same name, in place, and appends the other
+// override env vars.
+func mergeEnvVars(base, override []corev1.EnvVar) []corev1.EnvVar {
+	merged := base
+	for _, env := range override {
+		if idx := getIndexOfEnv(merged, env.Name); idx > -1 {
+			merged[idx] = env
+		} else {
+			merged = append(merged, env)
+		}
+	}
+	return merged
+}
+
+func mergeResources(base, override corev1.ResourceRequirements) corev1.ResourceRequirements {
+	merged := base
+	merged.Limits = mergeResourceList(base.Limits, override.Limits)
+	merged.Requests = mergeResourceList(base.Requests, override.Requests)
+	if len(override.Claims) > 0 {
+		merged.Claims = override.Claims
+	}
+	return merged
+}
+
+func mergeResourceList(base, override corev1.ResourceList) corev1.ResourceList {
+	if len(override) == 0 {
+		return base
+	}
+	merged := corev1.ResourceList{}
+	for name, quantity := range base {
+		merged[name] = quantity
+	}
+	for name, quantity := range override {
+		merged[name] = quantity
+	}
+	return merged
+}
@@ -0,0 +1,267 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colfeaturegate "go.opentelemetry.io/collector/featuregate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/featuregate"
+)
+
+func TestMergeInstrumentationSpec(t *testing.T) {
+	base := v1alpha1.InstrumentationSpec{
+		Exporter:    v1alpha1.Exporter{Endpoint: "http://base:4316"},
+		Resource:    v1alpha1.Resource{Attributes: map[string]string{"team": "platform", "env": "prod"}, AddK8sUIDAttributes: true},
+		Propagators: []v1alpha1.Propagator{v1alpha1.TraceContext, v1alpha1.XRay},
+		Sampler:     v1alpha1.Sampler{Type: v1alpha1.ParentBasedTraceIDRatio, Argument: "0.5"},
+		Env:         []corev1.EnvVar{{Name: "OTEL_A", Value: "base"}, {Name: "OTEL_B", Value: "base"}},
+		Java: v1alpha1.Java{
+			Image: "java:1",
+			Env:   []corev1.EnvVar{{Name: "OTEL_JAVA", Value: "base"}},
+			Resources: corev1.ResourceRequirements{
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("64Mi")},
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+			},
+		},
+		Python: v1alpha1.Python{Image: "python:1"},
+	}
+
+	tests := []struct {
+		name     string
+		override v1alpha1.InstrumentationSpec
+		check    func(t *testing.T, merged v1alpha1.InstrumentationSpec)
+	}{
+		{
+			name: "empty override",
+			check: func(t *testing.T, merged v1alpha1.InstrumentationSpec) {
+				assert.Equal(t, base, merged)
+			},
+		},
+		{
+			name: "sampler argument only",
+			override: v1alpha1.InstrumentationSpec{
+				Sampler: v1alpha1.Sampler{Argument: "0.1"},
+			},
+			check: func(t *testing.T, merged v1alpha1.InstrumentationSpec) {
+				assert.Equal(t, v1alpha1.Sampler{Type: v1alpha1.ParentBasedTraceIDRatio, Argument: "0.1"}, merged.Sampler)
+			},
+		},
+		{
+			name: "sampler type replaces the argument",
+			override: v1alpha1.InstrumentationSpec{
+				Sampler: v1alpha1.Sampler{Type: v1alpha1.AlwaysOn},
+			},
+			check: func(t *testing.T, merged v1alpha1.InstrumentationSpec) {
+				assert.Equal(t, v1alpha1.Sampler{Type: v1alpha1.AlwaysOn}, merged.Sampler)
+			},
+		},
+		{
+			name: "field by field",
+			override: v1alpha1.InstrumentationSpec{
+				Exporter: v1alpha1.Exporter{Endpoint: "http://override:4316"},
+				Resource: v1alpha1.Resource{Attributes: map[string]string{"env": "staging"}},
+				Env:      []corev1.EnvVar{{Name: "OTEL_B", Value: "override"}, {Name: "OTEL_C", Value: "override"}},
+				Java: v1alpha1.Java{
+					Env: []corev1.EnvVar{{Name: "OTEL_JAVA", Value: "override"}},
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+					},
+				},
+			},
+			check: func(t *testing.T, merged v1alpha1.InstrumentationSpec) {
+				assert.Equal(t, "http://override:4316", merged.Endpoint)
+				assert.Equal(t, v1alpha1.Resource{Attributes: map[string]string{"team": "platform", "env": "staging"}, AddK8sUIDAttributes: true}, merged.Resource)
+				assert.Equal(t, base.Propagators, merged.Propagators)
+				assert.Equal(t, base.Sampler, merged.Sampler)
+				assert.Equal(t, []corev1.EnvVar{{Name: "OTEL_A", Value: "base"}, {Name: "OTEL_B", Value: "override"}, {Name: "OTEL_C", Value: "override"}}, merged.Env)
+				assert.Equal(t, "java:1", merged.Java.Image)
+				assert.Equal(t, []corev1.EnvVar{{Name: "OTEL_JAVA", Value: "override"}}, merged.Java.Env)
+				assert.Equal(t, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("128Mi")}, merged.Java.Resources.Limits)
+				assert.Equal(t, base.Java.Resources.Requests, merged.Java.Resources.Requests)
+				assert.Equal(t, "python:1", merged.Python.Image)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := *base.DeepCopy()
+			tt.check(t, mergeInstrumentationSpec(base, tt.override))
+			assert.Equal(t, original, base, "the base must not be modified")
+		})
+	}
+}
+
+func TestLayerInstrumentation(t *testing.T) {
+	t.Setenv("AUTO_INSTRUMENTATION_JAVA", defaultJavaInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_PYTHON", defaultPythonInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_DOTNET", defaultDotNetInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_NODEJS", defaultNodeJSInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_GO", defaultGoInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_RUBY", defaultRubyInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_PHP", defaultPHPInstrumentationImage)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}
+	inst := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop-instrumentation", Namespace: "shop", UID: "uid"},
+		Spec: v1alpha1.InstrumentationSpec{
+			Sampler: v1alpha1.Sampler{Type: v1alpha1.ParentBasedTraceIDRatio, Argument: "1"},
+			Java:    v1alpha1.Java{Env: []corev1.EnvVar{{Name: "OTEL_METRICS_EXPORTER", Value: "none"}}},
+		},
+	}
+	overrides := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout-overrides", Namespace: "shop"},
+		Data: map[string]string{
+			instrumentationOverridesKey: "sampler:\n  argument: \"0.05\"\nresource:\n  resourceAttributes:\n    team: checkout\n",
+		},
+	}
+	invalid := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "invalid-overrides", Namespace: "shop"},
+		Data:       map[string]string{instrumentationOverridesKey: "samplr: {}\n"},
+	}
+	mutator := instPodMutator{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(overrides, invalid).Build(),
+		Logger: logr.Discard(),
+	}
+	podWithOverrides := func(name string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{annotationInstrumentationOverrides: name}}}
+	}
+
+	t.Run("namespace Instrumentation over the default instrumentation", func(t *testing.T) {
+		layered, err := mutator.layerInstrumentation(context.Background(), ns, corev1.Pod{}, inst, nil)
+		require.NoError(t, err)
+		assert.Equal(t, client.ObjectKeyFromObject(inst), client.ObjectKeyFromObject(layered))
+		assert.Equal(t, inst.UID, layered.UID)
+		assert.Equal(t, defaultJavaInstrumentationImage, layered.Spec.Java.Image)
+		assert.Equal(t, inst.Spec.Sampler, layered.Spec.Sampler)
+		assert.Equal(t, "none", getEnvValue(layered.Spec.Java.Env, "OTEL_METRICS_EXPORTER"))
+		assert.Equal(t, "OTEL_METRICS_EXPORTER", inst.Spec.Java.Env[0].Name, "the selected Instrumentation must not be modified")
+		assert.Len(t, inst.Spec.Java.Env, 1)
+	})
+
+	t.Run("workload overrides", func(t *testing.T) {
+		layered, err := mutator.layerInstrumentation(context.Background(), ns, podWithOverrides("checkout-overrides"), inst, nil)
+		require.NoError(t, err)
+		assert.Equal(t, v1alpha1.Sampler{Type: v1alpha1.ParentBasedTraceIDRatio, Argument: "0.05"}, layered.Spec.Sampler)
+		assert.Equal(t, "checkout", layered.Spec.Resource.Attributes["team"])
+	})
+
+	t.Run("workload overrides of the default instrumentation", func(t *testing.T) {
+		defaultInst, err := mutator.defaultInstrumentation(context.Background(), nil, false)
+		require.NoError(t, err)
+		layered, err := mutator.layerInstrumentation(context.Background(), ns, podWithOverrides("checkout-overrides"), defaultInst, nil)
+		require.NoError(t, err)
+		assert.Empty(t, layered.UID)
+		assert.Equal(t, "0.05", layered.Spec.Sampler.Argument)
+		assert.Equal(t, defaultInst.Spec.Java, layered.Spec.Java)
+	})
+
+	t.Run("missing overrides", func(t *testing.T) {
+		_, err := mutator.layerInstrumentation(context.Background(), ns, podWithOverrides("missing"), inst, nil)
+		assert.ErrorContains(t, err, "failed to get the Instrumentation overrides shop/missing")
+	})
+
+	t.Run("invalid overrides", func(t *testing.T) {
+		_, err := mutator.layerInstrumentation(context.Background(), ns, podWithOverrides("invalid-overrides"), inst, nil)
+		assert.ErrorContains(t, err, "failed to parse the Instrumentation overrides shop/invalid-overrides")
+	})
+}
+
+func TestLayeredInstrumentationPodEnv(t *testing.T) {
+	originalVal := featuregate.EnableLayeredInstrumentation.IsEnabled()
+	require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableLayeredInstrumentation.ID(), true))
+	t.Cleanup(func() {
+		require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableLayeredInstrumentation.ID(), originalVal))
+	})
+	t.Setenv("AUTO_INSTRUMENTATION_JAVA", defaultJavaInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_PYTHON", defaultPythonInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_DOTNET", defaultDotNetInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_NODEJS", defaultNodeJSInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_GO", defaultGoInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_RUBY", defaultRubyInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_PHP", defaultPHPInstrumentationImage)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}
+	inst := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop-instrumentation", Namespace: "shop", UID: "uid"},
+		Spec: v1alpha1.InstrumentationSpec{
+			Exporter: v1alpha1.Exporter{Endpoint: "http://collector.shop:4317"},
+			Sampler:  v1alpha1.Sampler{Type: v1alpha1.ParentBasedTraceIDRatio, Argument: "0.25"},
+		},
+	}
+	overrides := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout-overrides", Namespace: "shop"},
+		Data:       map[string]string{instrumentationOverridesKey: "sampler:\n  argument: \"0.05\"\n"},
+	}
+	// Application Signals sets the sampler and the traces endpoint in the env vars of the default instrumentation
+	agent := &v1alpha1.AmazonCloudWatchAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: amazonCloudWatchAgentName, Namespace: amazonCloudWatchNamespace},
+		Spec:       v1alpha1.AmazonCloudWatchAgentSpec{Config: `{"logs":{"metrics_collected":{"application_signals":{}}}}`},
+	}
+	mutator := NewMutator(logr.Discard(), fake.NewClientBuilder().WithScheme(scheme).WithObjects(inst, overrides, agent).Build(), record.NewFakeRecorder(10))
+	newPod := func(annotations map[string]string) corev1.Pod {
+		annotations[annotationInjectJava] = "shop-instrumentation"
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "checkout", Annotations: annotations},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		pod        corev1.Pod
+		samplerArg string
+	}{
+		{
+			name:       "Instrumentation sampler and endpoint",
+			pod:        newPod(map[string]string{}),
+			samplerArg: "0.25",
+		},
+		{
+			name:       "workload sampler",
+			pod:        newPod(map[string]string{annotationInstrumentationOverrides: "checkout-overrides"}),
+			samplerArg: "0.05",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutated, err := mutator.Mutate(context.Background(), ns, tt.pod)
+			require.NoError(t, err)
+			require.True(t, isAutoInstrumentationInjected(mutated))
+
+			// the sampler and endpoint of the default instrumentation don't take precedence over the upper layers
+			env := mutated.Spec.Containers[0].Env
+			assert.Equal(t, string(v1alpha1.ParentBasedTraceIDRatio), getEnvValue(env, "OTEL_TRACES_SAMPLER"))
+			assert.Equal(t, tt.samplerArg, getEnvValue(env, "OTEL_TRACES_SAMPLER_ARG"))
+			assert.Equal(t, "http://collector.shop:4317", getEnvValue(env, "OTEL_EXPORTER_OTLP_ENDPOINT"))
+			assert.Equal(t, -1, getIndexOfEnv(env, "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"))
+			// the Application Signals configuration of the default instrumentation isn't layered under Instrumentations
+			assert.Equal(t, -1, getIndexOfEnv(env, "OTEL_AWS_APPLICATION_SIGNALS_ENABLED"))
+			assert.Equal(t, -1, getIndexOfEnv(env, "OTEL_AWS_APPLICATION_SIGNALS_EXPORTER_ENDPOINT"))
+			assert.Equal(t, defaultJavaInstrumentationImage, mutated.Spec.InitContainers[0].Image)
+		})
+	}
+}
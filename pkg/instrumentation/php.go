@@ -0,0 +1,92 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	envPHPIniScanDir       = "PHP_INI_SCAN_DIR"
+	envOtelPHPAutoload     = "OTEL_PHP_AUTOLOAD_ENABLED"
+	phpIniScanDir          = "/otel-auto-instrumentation-php/conf.d"
+	phpInitContainerName   = initContainerName + "-php"
+	phpVolumeName          = volumeName + "-php"
+	phpInstrMountPath      = "/otel-auto-instrumentation-php"
+	phpIniScanDirSeparator = ":"
+)
+
+// injectPHPSDK adds the ini directory of the auto-instrumentation image to PHP_INI_SCAN_DIR. Its ini file loads the
+// opentelemetry extension and prepends the autoloader of the SDK and instrumentation packages to every script.
+func injectPHPSDK(phpSpec v1alpha1.PHP, pod corev1.Pod, index int, allEnvs []corev1.EnvVar) (corev1.Pod, error) {
+	container := &pod.Spec.Containers[index]
+
+	err := validateContainerEnv(container.Env, envPHPIniScanDir)
+	if err != nil {
+		return pod, err
+	}
+
+	// Check if ADOT SDK should be injected based on all environment variables and security context
+	if !shouldInjectADOTSDK(allEnvs, pod, container) {
+		return pod, fmt.Errorf("ADOT PHP SDK injection skipped due to incompatible OTel configuration")
+	}
+
+	// inject PHP instrumentation spec env vars with validation
+	for _, env := range phpSpec.Env {
+		if shouldInjectEnvVar(allEnvs, env.Name) {
+			container.Env = append(container.Env, env)
+		}
+	}
+
+	// A leading separator keeps the ini files of the default scan directory of the image.
+	idx := getIndexOfEnv(container.Env, envPHPIniScanDir)
+	if idx == -1 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  envPHPIniScanDir,
+			Value: phpIniScanDirSeparator + phpIniScanDir,
+		})
+	} else if idx > -1 {
+		container.Env[idx].Value = container.Env[idx].Value + phpIniScanDirSeparator + phpIniScanDir
+	}
+
+	// Set OTEL_PHP_AUTOLOAD_ENABLED to true if not set by user and validation allows
+	if shouldInjectEnvVar(container.Env, envOtelPHPAutoload) {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  envOtelPHPAutoload,
+			Value: "true",
+		})
+	}
+
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      phpVolumeName,
+		MountPath: phpInstrMountPath,
+	})
+
+	// We just inject Volumes and init containers for the first processed container
+	if isInitContainerMissing(pod, phpInitContainerName) {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: phpVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					SizeLimit: volumeSize(phpSpec.VolumeSizeLimit),
+				},
+			}})
+
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
+			Name:      phpInitContainerName,
+			Image:     phpSpec.Image,
+			Command:   []string{"cp", "-r", "/autoinstrumentation/.", phpInstrMountPath},
+			Resources: phpSpec.Resources,
+			VolumeMounts: []corev1.VolumeMount{{
+				Name:      phpVolumeName,
+				MountPath: phpInstrMountPath,
+			}},
+		})
+	}
+	return pod, nil
+}
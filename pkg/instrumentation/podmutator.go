@@ -25,6 +25,9 @@ import (
 const (
 	amazonCloudWatchNamespace = "amazon-cloudwatch"
 	amazonCloudWatchAgentName = "cloudwatch-agent"
+
+	// mutatorName identifies the decisions of this mutator in the explain trace.
+	mutatorName = "instrumentation"
 )
 
 var (
@@ -52,6 +55,8 @@ type languageInstrumentations struct {
 	ApacheHttpd instrumentationWithContainers
 	Nginx       instrumentationWithContainers
 	Go          instrumentationWithContainers
+	Ruby        instrumentationWithContainers
+	PHP         instrumentationWithContainers
 	Sdk         instrumentationWithContainers
 }
 
@@ -80,6 +85,12 @@ func (langInsts languageInstrumentations) isSingleInstrumentationEnabled() bool
 	if langInsts.Go.Instrumentation != nil {
 		count++
 	}
+	if langInsts.Ruby.Instrumentation != nil {
+		count++
+	}
+	if langInsts.PHP.Instrumentation != nil {
+		count++
+	}
 	if langInsts.Sdk.Instrumentation != nil {
 		count++
 	}
@@ -133,6 +144,16 @@ func (langInsts languageInstrumentations) areContainerNamesConfiguredForMultiple
 		instrWithoutContainers += isInstrWithoutContainers(langInsts.Go)
 		allContainers = append(allContainers, langInsts.Go.Containers)
 	}
+	if langInsts.Ruby.Instrumentation != nil {
+		instrWithContainers += isInstrWithContainers(langInsts.Ruby)
+		instrWithoutContainers += isInstrWithoutContainers(langInsts.Ruby)
+		allContainers = append(allContainers, langInsts.Ruby.Containers)
+	}
+	if langInsts.PHP.Instrumentation != nil {
+		instrWithContainers += isInstrWithContainers(langInsts.PHP)
+		instrWithoutContainers += isInstrWithoutContainers(langInsts.PHP)
+		allContainers = append(allContainers, langInsts.PHP.Containers)
+	}
 	if langInsts.Sdk.Instrumentation != nil {
 		instrWithContainers += isInstrWithContainers(langInsts.Sdk)
 		instrWithoutContainers += isInstrWithoutContainers(langInsts.Sdk)
@@ -185,6 +206,12 @@ func (langInsts *languageInstrumentations) setInstrumentationLanguageContainers(
 	if langInsts.Go.Instrumentation != nil {
 		langInsts.Go.Containers = containers
 	}
+	if langInsts.Ruby.Instrumentation != nil {
+		langInsts.Ruby.Containers = containers
+	}
+	if langInsts.PHP.Instrumentation != nil {
+		langInsts.PHP.Containers = containers
+	}
 	if langInsts.Sdk.Instrumentation != nil {
 		langInsts.Sdk.Containers = containers
 	}
@@ -204,12 +231,38 @@ func NewMutator(logger logr.Logger, client client.Client, recorder record.EventR
 	}
 }
 
+// quarantineReason returns why the workload controlling the pod is quarantined, if it is. A workload that can't be read
+// isn't considered quarantined.
+func (pm *instPodMutator) quarantineReason(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) (string, bool) {
+	// the namespace of the pod isn't set yet when it is created
+	pod.Namespace = ns.Name
+	workload, err := OwningWorkload(ctx, pm.Client, pod)
+	if err != nil {
+		pm.Logger.Error(err, "failed to get the workload of the pod, assuming it isn't quarantined", "namespace", ns.Name, "name", pod.Name)
+		return "", false
+	}
+	if workload == nil {
+		return "", false
+	}
+	reason, ok := workload.GetAnnotations()[AnnotationQuarantined]
+	return reason, ok
+}
+
 func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) (corev1.Pod, error) {
 	logger := pm.Logger.WithValues("namespace", pod.Namespace, "name", pod.Name)
+	trace := podmutation.TraceFromContext(ctx)
 
 	// We check if Pod is already instrumented.
 	if isAutoInstrumentationInjected(pod) {
 		logger.Info("Skipping pod instrumentation - already instrumented")
+		trace.Recordf(mutatorName, "pod is already instrumented, skipping injection")
+		return pod, nil
+	}
+
+	// We don't instrument the pods of quarantined workloads, even when requested by their namespace.
+	if reason, quarantined := pm.quarantineReason(ctx, ns, pod); quarantined {
+		logger.Info("Skipping pod instrumentation - the workload is quarantined", "reason", reason)
+		trace.Recordf(mutatorName, "the workload of the pod is quarantined (%s), skipping injection", reason)
 		return pod, nil
 	}
 
@@ -217,108 +270,182 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	var err error
 
 	insts := languageInstrumentations{}
+	outcomes := injectionOutcomes{}
 
 	// We bail out if any annotation fails to process.
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectJava); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
-		return pod, err
+		trace.Recordf(mutatorName, "failed to select an Instrumentation instance for this pod: %v", err)
+		outcomes.addFailed(string(TypeJava), ns.Name, annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectJava), err.Error())
+		return pod, outcomes.failed(err)
 	}
 	if featuregate.EnableJavaAutoInstrumentationSupport.IsEnabled() || inst == nil {
 		insts.Java.Instrumentation = inst
 	} else {
 		logger.Error(nil, "support for Java auto instrumentation is not enabled")
+		trace.Recordf(mutatorName, "support for Java auto instrumentation is not enabled, skipping it")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Java auto instrumentation is not enabled")
+		outcomes.add(string(TypeJava), inst, v1alpha1.InjectionStateSkipped, "support for Java auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectNodeJS); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
-		return pod, err
+		trace.Recordf(mutatorName, "failed to select an Instrumentation instance for this pod: %v", err)
+		outcomes.addFailed(string(TypeNodeJS), ns.Name, annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectNodeJS), err.Error())
+		return pod, outcomes.failed(err)
 	}
 	if featuregate.EnableNodeJSAutoInstrumentationSupport.IsEnabled() || inst == nil {
 		insts.NodeJS.Instrumentation = inst
 	} else {
 		logger.Error(nil, "support for NodeJS auto instrumentation is not enabled")
+		trace.Recordf(mutatorName, "support for NodeJS auto instrumentation is not enabled, skipping it")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for NodeJS auto instrumentation is not enabled")
+		outcomes.add(string(TypeNodeJS), inst, v1alpha1.InjectionStateSkipped, "support for NodeJS auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectPython); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
-		return pod, err
+		trace.Recordf(mutatorName, "failed to select an Instrumentation instance for this pod: %v", err)
+		outcomes.addFailed(string(TypePython), ns.Name, annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectPython), err.Error())
+		return pod, outcomes.failed(err)
 	}
 	if featuregate.EnablePythonAutoInstrumentationSupport.IsEnabled() || inst == nil {
 		insts.Python.Instrumentation = inst
 	} else {
 		logger.Error(nil, "support for Python auto instrumentation is not enabled")
+		trace.Recordf(mutatorName, "support for Python auto instrumentation is not enabled, skipping it")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Python auto instrumentation is not enabled")
+		outcomes.add(string(TypePython), inst, v1alpha1.InjectionStateSkipped, "support for Python auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectDotNet); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
-		return pod, err
+		trace.Recordf(mutatorName, "failed to select an Instrumentation instance for this pod: %v", err)
+		outcomes.addFailed(string(TypeDotNet), ns.Name, annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectDotNet), err.Error())
+		return pod, outcomes.failed(err)
 	}
 	if featuregate.EnableDotnetAutoInstrumentationSupport.IsEnabled() || inst == nil {
 		insts.DotNet.Instrumentation = inst
 		insts.DotNet.AdditionalAnnotations = map[string]string{annotationDotNetRuntime: annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationDotNetRuntime)}
 	} else {
 		logger.Error(nil, "support for .NET auto instrumentation is not enabled")
+		trace.Recordf(mutatorName, "support for .NET auto instrumentation is not enabled, skipping it")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for .NET auto instrumentation is not enabled")
+		outcomes.add(string(TypeDotNet), inst, v1alpha1.InjectionStateSkipped, "support for .NET auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectGo); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
-		return pod, err
+		trace.Recordf(mutatorName, "failed to select an Instrumentation instance for this pod: %v", err)
+		outcomes.addFailed(string(TypeGo), ns.Name, annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectGo), err.Error())
+		return pod, outcomes.failed(err)
 	}
 	if featuregate.EnableGoAutoInstrumentationSupport.IsEnabled() || inst == nil {
 		insts.Go.Instrumentation = inst
+		if inst != nil {
+			if goErr := validateGoSidecar(ns, pod); goErr != nil {
+				message := fmt.Sprintf("go auto instrumentation can't work in this pod: %v", goErr)
+				logger.Info("skipping Go auto instrumentation", "reason", goErr.Error())
+				trace.Recordf(mutatorName, "%s, skipping it", message)
+				pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", message)
+				outcomes.add(string(TypeGo), inst, v1alpha1.InjectionStateSkipped, message)
+				insts.Go.Instrumentation = nil
+			}
+		}
 	} else {
 		logger.Error(err, "support for Go auto instrumentation is not enabled")
+		trace.Recordf(mutatorName, "support for Go auto instrumentation is not enabled, skipping it")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Go auto instrumentation is not enabled")
+		outcomes.add(string(TypeGo), inst, v1alpha1.InjectionStateSkipped, "support for Go auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectApacheHttpd); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
-		return pod, err
+		trace.Recordf(mutatorName, "failed to select an Instrumentation instance for this pod: %v", err)
+		outcomes.addFailed(languageApacheHttpd, ns.Name, annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectApacheHttpd), err.Error())
+		return pod, outcomes.failed(err)
 	}
 	if featuregate.EnableApacheHTTPAutoInstrumentationSupport.IsEnabled() || inst == nil {
 		insts.ApacheHttpd.Instrumentation = inst
 	} else {
 		logger.Error(nil, "support for Apache HTTPD auto instrumentation is not enabled")
+		trace.Recordf(mutatorName, "support for Apache HTTPD auto instrumentation is not enabled, skipping it")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Apache HTTPD auto instrumentation is not enabled")
+		outcomes.add(languageApacheHttpd, inst, v1alpha1.InjectionStateSkipped, "support for Apache HTTPD auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectNginx); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
-		return pod, err
+		trace.Recordf(mutatorName, "failed to select an Instrumentation instance for this pod: %v", err)
+		outcomes.addFailed(languageNginx, ns.Name, annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectNginx), err.Error())
+		return pod, outcomes.failed(err)
 	}
 	if featuregate.EnableNginxAutoInstrumentationSupport.IsEnabled() || inst == nil {
 		insts.Nginx.Instrumentation = inst
 	} else {
 		logger.Error(nil, "support for Nginx auto instrumentation is not enabled")
+		trace.Recordf(mutatorName, "support for Nginx auto instrumentation is not enabled, skipping it")
 		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Nginx auto instrumentation is not enabled")
+		outcomes.add(languageNginx, inst, v1alpha1.InjectionStateSkipped, "support for Nginx auto instrumentation is not enabled")
+	}
+
+	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectRuby); err != nil {
+		// we still allow the pod to be created, but we log a message to the operator's logs
+		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
+		trace.Recordf(mutatorName, "failed to select an Instrumentation instance for this pod: %v", err)
+		outcomes.addFailed(string(TypeRuby), ns.Name, annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectRuby), err.Error())
+		return pod, outcomes.failed(err)
+	}
+	if featuregate.EnableRubyAutoInstrumentationSupport.IsEnabled() || inst == nil {
+		insts.Ruby.Instrumentation = inst
+	} else {
+		logger.Error(nil, "support for Ruby auto instrumentation is not enabled")
+		trace.Recordf(mutatorName, "support for Ruby auto instrumentation is not enabled, skipping it")
+		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for Ruby auto instrumentation is not enabled")
+		outcomes.add(string(TypeRuby), inst, v1alpha1.InjectionStateSkipped, "support for Ruby auto instrumentation is not enabled")
+	}
+
+	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectPHP); err != nil {
+		// we still allow the pod to be created, but we log a message to the operator's logs
+		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
+		trace.Recordf(mutatorName, "failed to select an Instrumentation instance for this pod: %v", err)
+		outcomes.addFailed(string(TypePHP), ns.Name, annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectPHP), err.Error())
+		return pod, outcomes.failed(err)
+	}
+	if featuregate.EnablePHPAutoInstrumentationSupport.IsEnabled() || inst == nil {
+		insts.PHP.Instrumentation = inst
+	} else {
+		logger.Error(nil, "support for PHP auto instrumentation is not enabled")
+		trace.Recordf(mutatorName, "support for PHP auto instrumentation is not enabled, skipping it")
+		pm.Recorder.Event(pod.DeepCopy(), "Warning", "InstrumentationRequestRejected", "support for PHP auto instrumentation is not enabled")
+		outcomes.add(string(TypePHP), inst, v1alpha1.InjectionStateSkipped, "support for PHP auto instrumentation is not enabled")
 	}
 
 	if inst, err = pm.getInstrumentationInstance(ctx, ns, pod, annotationInjectSdk); err != nil {
 		// we still allow the pod to be created, but we log a message to the operator's logs
 		logger.Error(err, "failed to select an OpenTelemetry Instrumentation instance for this pod")
-		return pod, err
+		trace.Recordf(mutatorName, "failed to select an Instrumentation instance for this pod: %v", err)
+		outcomes.addFailed(languageSdk, ns.Name, annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectSdk), err.Error())
+		return pod, outcomes.failed(err)
 	}
 	insts.Sdk.Instrumentation = inst
 
 	if insts.Java.Instrumentation == nil && insts.NodeJS.Instrumentation == nil && insts.Python.Instrumentation == nil &&
 		insts.DotNet.Instrumentation == nil && insts.Go.Instrumentation == nil && insts.ApacheHttpd.Instrumentation == nil &&
-		insts.Nginx.Instrumentation == nil &&
+		insts.Nginx.Instrumentation == nil && insts.Ruby.Instrumentation == nil && insts.PHP.Instrumentation == nil &&
 		insts.Sdk.Instrumentation == nil {
 
 		logger.V(1).Info("annotation not present in deployment, skipping instrumentation injection")
-		return pod, nil
+		trace.Recordf(mutatorName, "no instrumentation annotation on the pod or namespace, skipping injection")
+		return outcomes.apply(pod), nil
 	}
 
 	// We retrieve the annotation for podname
@@ -331,13 +458,17 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 		insts.Go.Containers = annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectGoContainersName)
 		insts.ApacheHttpd.Containers = annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectApacheHttpdContainersName)
 		insts.Nginx.Containers = annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectNginxContainersName)
+		insts.Ruby.Containers = annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectRubyContainersName)
+		insts.PHP.Containers = annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectPHPContainersName)
 		insts.Sdk.Containers = annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectSdkContainersName)
 
 		// We check if provided annotations and instrumentations are valid
 		ok, msg := insts.areContainerNamesConfiguredForMultipleInstrumentations()
 		if !ok {
 			logger.V(1).Error(msg, "skipping instrumentation injection")
-			return pod, nil
+			trace.Recordf(mutatorName, "skipping injection: %v", msg)
+			outcomes.addSkipped(insts, msg.Error())
+			return outcomes.apply(pod), nil
 		}
 	} else {
 		// We use general annotation for container names
@@ -347,8 +478,11 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 			generalContainerNames := annotationValue(ns.ObjectMeta, pod.ObjectMeta, annotationInjectContainerName)
 			insts.setInstrumentationLanguageContainers(generalContainerNames)
 		} else {
-			logger.V(1).Error(fmt.Errorf("multiple injection annotations present"), "skipping instrumentation injection")
-			return pod, nil
+			msg := fmt.Errorf("multiple injection annotations present")
+			logger.V(1).Error(msg, "skipping instrumentation injection")
+			trace.Recordf(mutatorName, "skipping injection: %v", msg)
+			outcomes.addSkipped(insts, msg.Error())
+			return outcomes.apply(pod), nil
 		}
 
 	}
@@ -357,8 +491,9 @@ func (pm *instPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod c
 	// we should inject the instrumentation.
 	modifiedPod := pod
 	modifiedPod = pm.sdkInjector.inject(ctx, insts, ns, modifiedPod)
+	outcomes.addInjected(insts, modifiedPod)
 
-	return modifiedPod, nil
+	return outcomes.apply(modifiedPod), nil
 }
 
 func (pm *instPodMutator) getInstrumentationInstance(ctx context.Context, ns corev1.Namespace, pod corev1.Pod, instAnnotation string) (*v1alpha1.Instrumentation, error) {
@@ -380,7 +515,19 @@ func (pm *instPodMutator) getInstrumentationInstance(ctx context.Context, ns cor
 	}
 
 	if strings.EqualFold(instValue, "true") {
-		return pm.selectInstrumentationInstanceFromNamespace(ctx, ns, additionalEnvs, isWindowsPod(pod))
+		inst, err := pm.selectInstrumentationInstanceFromNamespace(ctx, ns, additionalEnvs, isWindowsPod(pod))
+		if err != nil {
+			return inst, err
+		}
+		if inst.UID == "" {
+			podmutation.TraceFromContext(ctx).Recordf(mutatorName, "annotation %s is true and namespace %s has no Instrumentation, using the default instrumentation", instAnnotation, ns.Name)
+		} else {
+			podmutation.TraceFromContext(ctx).Recordf(mutatorName, "annotation %s is true, using Instrumentation %s/%s, the only one in namespace %s", instAnnotation, inst.Namespace, inst.Name, ns.Name)
+		}
+		if featuregate.EnableLayeredInstrumentation.IsEnabled() {
+			return pm.layerInstrumentation(ctx, ns, pod, inst, additionalEnvs)
+		}
+		return inst, nil
 	}
 
 	var instNamespacedName types.NamespacedName
@@ -395,7 +542,11 @@ func (pm *instPodMutator) getInstrumentationInstance(ctx context.Context, ns cor
 	if err != nil {
 		return nil, err
 	}
+	podmutation.TraceFromContext(ctx).Recordf(mutatorName, "annotation %s selects Instrumentation %s", instAnnotation, instNamespacedName)
 
+	if featuregate.EnableLayeredInstrumentation.IsEnabled() {
+		return pm.layerInstrumentation(ctx, ns, pod, otelInst, additionalEnvs)
+	}
 	return otelInst, nil
 }
 
@@ -408,13 +559,7 @@ func (pm *instPodMutator) selectInstrumentationInstanceFromNamespace(ctx context
 	switch s := len(otelInsts.Items); {
 	case s == 0:
 		pm.Logger.Info("no OpenTelemetry Instrumentation instances available. Using default Instrumentation instance")
-		cr := GetAmazonCloudWatchAgentResource(ctx, pm.Client, amazonCloudWatchAgentName)
-		config, err := adapters.ConfigStructFromJSONString(cr.Spec.Config)
-		if err != nil {
-			pm.Logger.Error(err, "unable to retrieve cloudwatch agent config for instrumentation")
-		}
-
-		return getDefaultInstrumentation(config, additionalEnvs, isWindowsPod)
+		return pm.defaultInstrumentation(ctx, additionalEnvs, isWindowsPod)
 	case s > 1:
 		return nil, errMultipleInstancesPossible
 	default:
@@ -422,6 +567,17 @@ func (pm *instPodMutator) selectInstrumentationInstanceFromNamespace(ctx context
 	}
 }
 
+// defaultInstrumentation returns the default instrumentation, configured for the CloudWatch agent.
+func (pm *instPodMutator) defaultInstrumentation(ctx context.Context, additionalEnvs map[Type]map[string]string, isWindowsPod bool) (*v1alpha1.Instrumentation, error) {
+	cr := GetAmazonCloudWatchAgentResource(ctx, pm.Client, amazonCloudWatchAgentName)
+	config, err := adapters.ConfigStructFromJSONString(cr.Spec.Config)
+	if err != nil {
+		pm.Logger.Error(err, "unable to retrieve cloudwatch agent config for instrumentation")
+	}
+
+	return getDefaultInstrumentation(config, additionalEnvs, isWindowsPod)
+}
+
 func GetAmazonCloudWatchAgentResource(ctx context.Context, c client.Client, name string) v1alpha1.AmazonCloudWatchAgent {
 	cr := &v1alpha1.AmazonCloudWatchAgent{}
 
@@ -13,9 +13,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	colfeaturegate "go.opentelemetry.io/collector/featuregate"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
@@ -29,6 +32,9 @@ const (
 	defaultPythonInstrumentationImage = "test.registry/adot-autoinstrumentation-python:test-tag"
 	defaultDotNetInstrumentationImage = "test.registry/adot-autoinstrumentation-dotnet:test-tag"
 	defaultNodeJSInstrumentationImage = "test.registry/adot-autoinstrumentation-nodejs:test-tag"
+	defaultGoInstrumentationImage     = "test.registry/autoinstrumentation-go:test-tag"
+	defaultRubyInstrumentationImage   = "test.registry/autoinstrumentation-ruby:test-tag"
+	defaultPHPInstrumentationImage    = "test.registry/autoinstrumentation-php:test-tag"
 )
 
 func TestGetInstrumentationInstanceFromNameSpaceDefault(t *testing.T) {
@@ -104,6 +110,100 @@ func TestGetInstrumentationInstanceJMX(t *testing.T) {
 	}
 }
 
+func TestMutatePodQuarantined(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	inst := &v1alpha1.Instrumentation{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "my-inst"},
+		Spec:       v1alpha1.InstrumentationSpec{Java: v1alpha1.Java{Image: defaultJavaInstrumentationImage}},
+	}
+	quarantinedSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+		Namespace:   "ns",
+		Name:        "quarantined",
+		Annotations: map[string]string{AnnotationQuarantined: "crash-looping"},
+	}}
+	healthySet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "healthy"}}
+	mutator := NewMutator(logr.Discard(), fake.NewClientBuilder().WithScheme(scheme).WithObjects(inst, quarantinedSet, healthySet).Build(), record.NewFakeRecorder(10))
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "ns",
+		Annotations: map[string]string{annotationInjectJava: "true"},
+	}}
+	// the namespace of the pod isn't set yet when the webhook mutates it
+	newPod := func(owner string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "StatefulSet", Name: owner, Controller: ptr.To(true)}}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		}
+	}
+
+	mutated, err := mutator.Mutate(context.Background(), ns, newPod(healthySet.Name))
+	require.NoError(t, err)
+	assert.True(t, isAutoInstrumentationInjected(mutated))
+
+	// the pods of quarantined workloads aren't instrumented through the annotations of their namespace
+	quarantined := newPod(quarantinedSet.Name)
+	mutated, err = mutator.Mutate(context.Background(), ns, quarantined)
+	require.NoError(t, err)
+	assert.Equal(t, quarantined, mutated)
+}
+
+func TestMutatePodDefaultRubyAndPHPInstrumentation(t *testing.T) {
+	for _, gate := range []*colfeaturegate.Gate{featuregate.EnableRubyAutoInstrumentationSupport, featuregate.EnablePHPAutoInstrumentationSupport} {
+		originalVal := gate.IsEnabled()
+		require.NoError(t, colfeaturegate.GlobalRegistry().Set(gate.ID(), true))
+		t.Cleanup(func() {
+			require.NoError(t, colfeaturegate.GlobalRegistry().Set(gate.ID(), originalVal))
+		})
+	}
+	t.Setenv("AUTO_INSTRUMENTATION_JAVA", defaultJavaInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_PYTHON", defaultPythonInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_DOTNET", defaultDotNetInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_NODEJS", defaultNodeJSInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_GO", defaultGoInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_RUBY", defaultRubyInstrumentationImage)
+	t.Setenv("AUTO_INSTRUMENTATION_PHP", defaultPHPInstrumentationImage)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	// no Instrumentation in the namespace, the pods get the default instrumentation
+	mutator := NewMutator(logr.Discard(), fake.NewClientBuilder().WithScheme(scheme).Build(), record.NewFakeRecorder(10))
+	ns := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns"}}
+
+	tests := []struct {
+		name          string
+		annotation    string
+		initContainer string
+		image         string
+	}{
+		{
+			name:          "ruby",
+			annotation:    annotationInjectRuby,
+			initContainer: rubyInitContainerName,
+			image:         defaultRubyInstrumentationImage,
+		},
+		{
+			name:          "php",
+			annotation:    annotationInjectPHP,
+			initContainer: phpInitContainerName,
+			image:         defaultPHPInstrumentationImage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{tt.annotation: "true"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			}
+			mutated, err := mutator.Mutate(context.Background(), ns, pod)
+			require.NoError(t, err)
+			require.NotEmpty(t, mutated.Spec.InitContainers)
+			assert.Equal(t, tt.initContainer, mutated.Spec.InitContainers[0].Name)
+			assert.Equal(t, tt.image, mutated.Spec.InitContainers[0].Image)
+		})
+	}
+}
+
 func TestMutatePod(t *testing.T) {
 	mutator := NewMutator(logr.Discard(), k8sClient, record.NewFakeRecorder(100))
 	require.NotNil(t, mutator)
@@ -2675,6 +2775,13 @@ func TestMutatePod(t *testing.T) {
 					},
 				},
 			},
+			setFeatureGates: func(t *testing.T) {
+				originalVal := featuregate.EnableGoAutoInstrumentationSupport.IsEnabled()
+				require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableGoAutoInstrumentationSupport.ID(), false))
+				t.Cleanup(func() {
+					require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableGoAutoInstrumentationSupport.ID(), originalVal))
+				})
+			},
 		},
 		{
 			name: "apache httpd injection, true",
@@ -0,0 +1,79 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
+)
+
+const (
+	envRubyOpt            = "RUBYOPT"
+	rubyRequireArgument   = " -r/otel-auto-instrumentation-ruby/autoinstrumentation.rb"
+	rubyInitContainerName = initContainerName + "-ruby"
+	rubyVolumeName        = volumeName + "-ruby"
+	rubyInstrMountPath    = "/otel-auto-instrumentation-ruby"
+)
+
+func injectRubySDK(rubySpec v1alpha1.Ruby, pod corev1.Pod, index int, allEnvs []corev1.EnvVar) (corev1.Pod, error) {
+	container := &pod.Spec.Containers[index]
+
+	err := validateContainerEnv(container.Env, envRubyOpt)
+	if err != nil {
+		return pod, err
+	}
+
+	// Check if ADOT SDK should be injected based on all environment variables and security context
+	if !shouldInjectADOTSDK(allEnvs, pod, container) {
+		return pod, fmt.Errorf("ADOT Ruby SDK injection skipped due to incompatible OTel configuration")
+	}
+
+	// inject Ruby instrumentation spec env vars with validation
+	for _, env := range rubySpec.Env {
+		if shouldInjectEnvVar(allEnvs, env.Name) {
+			container.Env = append(container.Env, env)
+		}
+	}
+
+	idx := getIndexOfEnv(container.Env, envRubyOpt)
+	if idx == -1 {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name:  envRubyOpt,
+			Value: rubyRequireArgument,
+		})
+	} else if idx > -1 {
+		container.Env[idx].Value = container.Env[idx].Value + rubyRequireArgument
+	}
+
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      rubyVolumeName,
+		MountPath: rubyInstrMountPath,
+	})
+
+	// We just inject Volumes and init containers for the first processed container
+	if isInitContainerMissing(pod, rubyInitContainerName) {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: rubyVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{
+					SizeLimit: volumeSize(rubySpec.VolumeSizeLimit),
+				},
+			}})
+
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, corev1.Container{
+			Name:      rubyInitContainerName,
+			Image:     rubySpec.Image,
+			Command:   []string{"cp", "-r", "/autoinstrumentation/.", rubyInstrMountPath},
+			Resources: rubySpec.Resources,
+			VolumeMounts: []corev1.VolumeMount{{
+				Name:      rubyVolumeName,
+				MountPath: rubyInstrMountPath,
+			}},
+		})
+	}
+	return pod, nil
+}
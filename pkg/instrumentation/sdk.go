@@ -25,6 +25,7 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent-operator/apis/v1alpha1"
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/naming"
+	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
 	"github.com/aws/amazon-cloudwatch-agent-operator/pkg/constants"
 )
 
@@ -58,6 +59,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 	// as a sidecar is not a officially supported configuration pattern within the operator.
 	if otcContainerExistsIn(pod) {
 		i.logger.V(3).Info("An otel collector container already exists, skipping injection")
+		podmutation.TraceFromContext(ctx).Recordf(mutatorName, "an OpenTelemetry collector container already exists, skipping injection")
 		return pod
 	}
 
@@ -96,7 +98,10 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			pod, err = injectJavaagent(otelinst.Spec.Java, pod, index, envs)
 			if err != nil {
 				i.logger.Info("Skipping javaagent injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				traceContainerSkipped(ctx, string(TypeJava), pod.Spec.Containers[index].Name, err)
 			} else {
+				traceContainerInjected(ctx, string(TypeJava), otelinst, pod.Spec.Containers[index].Name)
+				traceSkippedEnvVars(ctx, string(TypeJava), pod.Spec.Containers[index].Name, otelinst.Spec.Java.Env, envs)
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 				//disable setting security context in init container due to issue with runAsNonRoot conflict
@@ -123,7 +128,10 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			pod, err = injectNodeJSSDK(otelinst.Spec.NodeJS, pod, index, envs)
 			if err != nil {
 				i.logger.Info("Skipping NodeJS SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				traceContainerSkipped(ctx, string(TypeNodeJS), pod.Spec.Containers[index].Name, err)
 			} else {
+				traceContainerInjected(ctx, string(TypeNodeJS), otelinst, pod.Spec.Containers[index].Name)
+				traceSkippedEnvVars(ctx, string(TypeNodeJS), pod.Spec.Containers[index].Name, otelinst.Spec.NodeJS.Env, envs)
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 				pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, nodejsInitContainerName)
@@ -148,7 +156,10 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			pod, err = injectPythonSDK(otelinst.Spec.Python, pod, index, envs)
 			if err != nil {
 				i.logger.Info("Skipping Python SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				traceContainerSkipped(ctx, string(TypePython), pod.Spec.Containers[index].Name, err)
 			} else {
+				traceContainerInjected(ctx, string(TypePython), otelinst, pod.Spec.Containers[index].Name)
+				traceSkippedEnvVars(ctx, string(TypePython), pod.Spec.Containers[index].Name, otelinst.Spec.Python.Env, envs)
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 				pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, pythonInitContainerName)
@@ -173,7 +184,10 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			pod, err = injectDotNetSDK(otelinst.Spec.DotNet, pod, index, insts.DotNet.AdditionalAnnotations[annotationDotNetRuntime], envs)
 			if err != nil {
 				i.logger.Info("Skipping DotNet SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				traceContainerSkipped(ctx, string(TypeDotNet), pod.Spec.Containers[index].Name, err)
 			} else {
+				traceContainerInjected(ctx, string(TypeDotNet), otelinst, pod.Spec.Containers[index].Name)
+				traceSkippedEnvVars(ctx, string(TypeDotNet), pod.Spec.Containers[index].Name, otelinst.Spec.DotNet.Env, envs)
 				pod = i.injectCommonEnvVar(otelinst, pod, index)
 				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 				pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, dotnetInitContainerName)
@@ -193,6 +207,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 		pod, err = injectGoSDK(otelinst.Spec.Go, pod)
 		if err != nil {
 			i.logger.Info("Skipping Go SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+			traceContainerSkipped(ctx, string(TypeGo), pod.Spec.Containers[index].Name, err)
 		} else {
 			// Common env vars and config need to be applied to the agent contain.
 			pod = i.injectCommonEnvVar(otelinst, pod, len(pod.Spec.Containers)-1)
@@ -202,7 +217,10 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			idx := getIndexOfEnv(pod.Spec.Containers[len(pod.Spec.Containers)-1].Env, envOtelTargetExe)
 			if idx == -1 {
 				i.logger.Info("Skipping Go SDK injection", "reason", "OTEL_GO_AUTO_TARGET_EXE not set", "container", pod.Spec.Containers[index].Name)
+				traceContainerSkipped(ctx, string(TypeGo), pod.Spec.Containers[index].Name, fmt.Errorf("%s not set", envOtelTargetExe))
 				pod = origPod
+			} else {
+				traceContainerInjected(ctx, string(TypeGo), otelinst, pod.Spec.Containers[index].Name)
 			}
 		}
 	}
@@ -218,6 +236,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			// Therefore, service name, otlp endpoint and other attributes are passed to the agent injection method
 			resMap, _ := i.createResourceMap(ctx, otelinst, ns, pod, index)
 			pod = injectApacheHttpdagent(i.logger, otelinst.Spec.ApacheHttpd, pod, index, otelinst.Spec.Endpoint, resMap)
+			traceContainerInjected(ctx, languageApacheHttpd, otelinst, pod.Spec.Containers[index].Name)
 			pod = i.injectCommonEnvVar(otelinst, pod, index)
 			pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 			pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, apacheAgentInitContainerName)
@@ -237,11 +256,69 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 			// Therefore, service name, otlp endpoint and other attributes are passed to the agent injection method
 			resMap, _ := i.createResourceMap(ctx, otelinst, ns, pod, index)
 			pod = injectNginxSDK(i.logger, otelinst.Spec.Nginx, pod, index, otelinst.Spec.Endpoint, resMap)
+			traceContainerInjected(ctx, languageNginx, otelinst, pod.Spec.Containers[index].Name)
 			pod = i.injectCommonEnvVar(otelinst, pod, index)
 			pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 		}
 	}
 
+	if insts.Ruby.Instrumentation != nil {
+		otelinst := *insts.Ruby.Instrumentation
+		var err error
+		i.logger.V(1).Info("injecting Ruby instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
+
+		rubyContainers := insts.Ruby.Containers
+
+		for _, container := range strings.Split(rubyContainers, ",") {
+			index := getContainerIndex(container, pod)
+			// Pass cached environment variables to avoid re-fetching ConfigMap
+			envs, exists := containerEnvCache[index]
+			if !exists {
+				i.logger.Error(fmt.Errorf("container index %d not found in cache", index), "missing container in cache")
+				continue
+			}
+			pod, err = injectRubySDK(otelinst.Spec.Ruby, pod, index, envs)
+			if err != nil {
+				i.logger.Info("Skipping Ruby SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				traceContainerSkipped(ctx, string(TypeRuby), pod.Spec.Containers[index].Name, err)
+			} else {
+				traceContainerInjected(ctx, string(TypeRuby), otelinst, pod.Spec.Containers[index].Name)
+				traceSkippedEnvVars(ctx, string(TypeRuby), pod.Spec.Containers[index].Name, otelinst.Spec.Ruby.Env, envs)
+				pod = i.injectCommonEnvVar(otelinst, pod, index)
+				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
+				pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, rubyInitContainerName)
+			}
+		}
+	}
+	if insts.PHP.Instrumentation != nil {
+		otelinst := *insts.PHP.Instrumentation
+		var err error
+		i.logger.V(1).Info("injecting PHP instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
+
+		phpContainers := insts.PHP.Containers
+
+		for _, container := range strings.Split(phpContainers, ",") {
+			index := getContainerIndex(container, pod)
+			// Pass cached environment variables to avoid re-fetching ConfigMap
+			envs, exists := containerEnvCache[index]
+			if !exists {
+				i.logger.Error(fmt.Errorf("container index %d not found in cache", index), "missing container in cache")
+				continue
+			}
+			pod, err = injectPHPSDK(otelinst.Spec.PHP, pod, index, envs)
+			if err != nil {
+				i.logger.Info("Skipping PHP SDK injection", "reason", err.Error(), "container", pod.Spec.Containers[index].Name)
+				traceContainerSkipped(ctx, string(TypePHP), pod.Spec.Containers[index].Name, err)
+			} else {
+				traceContainerInjected(ctx, string(TypePHP), otelinst, pod.Spec.Containers[index].Name)
+				traceSkippedEnvVars(ctx, string(TypePHP), pod.Spec.Containers[index].Name, otelinst.Spec.PHP.Env, envs)
+				pod = i.injectCommonEnvVar(otelinst, pod, index)
+				pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
+				pod = i.setInitContainerSecurityContext(pod, pod.Spec.Containers[index].SecurityContext, phpInitContainerName)
+			}
+		}
+	}
+
 	if insts.Sdk.Instrumentation != nil {
 		otelinst := *insts.Sdk.Instrumentation
 		i.logger.V(1).Info("injecting sdk-only instrumentation into pod", "otelinst-namespace", otelinst.Namespace, "otelinst-name", otelinst.Name)
@@ -250,6 +327,7 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 
 		for _, container := range strings.Split(sdkContainers, ",") {
 			index := getContainerIndex(container, pod)
+			traceContainerInjected(ctx, languageSdk, otelinst, pod.Spec.Containers[index].Name)
 			pod = i.injectCommonEnvVar(otelinst, pod, index)
 			pod = i.injectCommonSDKConfig(ctx, otelinst, ns, pod, index, index)
 		}
@@ -258,6 +336,43 @@ func (i *sdkInjector) inject(ctx context.Context, insts languageInstrumentations
 	return pod
 }
 
+// traceContainerInjected records in the explain trace that a container was instrumented.
+func traceContainerInjected(ctx context.Context, language string, otelinst v1alpha1.Instrumentation, container string) {
+	podmutation.TraceFromContext(ctx).Recordf(mutatorName, "injecting %s instrumentation of %s into container %s", language, instrumentationDisplayName(otelinst), container)
+}
+
+// traceContainerSkipped records in the explain trace that a container was not instrumented.
+func traceContainerSkipped(ctx context.Context, language string, container string, err error) {
+	podmutation.TraceFromContext(ctx).Recordf(mutatorName, "skipping %s instrumentation of container %s: %v", language, container, err)
+}
+
+// traceSkippedEnvVars records in the explain trace the instrumentation env vars that aren't injected into a container
+// because the user already set them or disabled Application Signals.
+func traceSkippedEnvVars(ctx context.Context, language string, container string, envs []corev1.EnvVar, allEnvs []corev1.EnvVar) {
+	trace := podmutation.TraceFromContext(ctx)
+	if trace == nil {
+		return
+	}
+	for _, env := range envs {
+		if shouldInjectEnvVar(allEnvs, env.Name) {
+			continue
+		}
+		if isApplicationSignalsExplicitlyDisabled(allEnvs) {
+			trace.Recordf(mutatorName, "not injecting %s env var %s into container %s: Application Signals is disabled", language, env.Name, container)
+		} else {
+			trace.Recordf(mutatorName, "not injecting %s env var %s into container %s: already set to %q", language, env.Name, container, getEnvValue(allEnvs, env.Name))
+		}
+	}
+}
+
+// instrumentationDisplayName names an Instrumentation in the explain trace.
+func instrumentationDisplayName(otelinst v1alpha1.Instrumentation) string {
+	if otelinst.UID == "" {
+		return "the default instrumentation"
+	}
+	return fmt.Sprintf("Instrumentation %s/%s", otelinst.Namespace, otelinst.Name)
+}
+
 func otcContainerExistsIn(pod corev1.Pod) bool {
 	if len(pod.Spec.Containers)+len(pod.Spec.InitContainers) == 1 {
 		return false
@@ -328,7 +443,7 @@ func (i *sdkInjector) injectCommonEnvVar(otelinst v1alpha1.Instrumentation, pod
 // agentIndex represents the index of the pod the needs the env vars to instrument the application.
 // appIndex represents the index of the pod the will produce the telemetry.
 // When the pod handling the instrumentation is the same as the pod producing the telemetry agentIndex
-// and appIndex should be the same value.  This is true for dotnet, java, nodejs, and python instrumentations.
+// and appIndex should be the same value.  This is true for dotnet, java, nodejs, python, ruby and php instrumentations.
 // Go requires the agent to be a different container in the pod, so the agentIndex should represent this new sidecar
 // and appIndex should represent the application being instrumented.
 func (i *sdkInjector) injectCommonSDKConfig(ctx context.Context, otelinst v1alpha1.Instrumentation, ns corev1.Namespace, pod corev1.Pod, agentIndex int, appIndex int) corev1.Pod {
@@ -41,6 +41,18 @@ func TestUpgrade(t *testing.T) {
 		require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableNginxAutoInstrumentationSupport.ID(), originalVal))
 	})
 
+	originalVal = featuregate.EnableRubyAutoInstrumentationSupport.IsEnabled()
+	require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableRubyAutoInstrumentationSupport.ID(), true))
+	t.Cleanup(func() {
+		require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnableRubyAutoInstrumentationSupport.ID(), originalVal))
+	})
+
+	originalVal = featuregate.EnablePHPAutoInstrumentationSupport.IsEnabled()
+	require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnablePHPAutoInstrumentationSupport.ID(), true))
+	t.Cleanup(func() {
+		require.NoError(t, colfeaturegate.GlobalRegistry().Set(featuregate.EnablePHPAutoInstrumentationSupport.ID(), originalVal))
+	})
+
 	nsName := strings.ToLower(t.Name())
 	err := k8sClient.Create(context.Background(), &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -71,6 +83,8 @@ func TestUpgrade(t *testing.T) {
 			config.WithAutoInstrumentationGoImage("go:1"),
 			config.WithAutoInstrumentationApacheHttpdImage("apache-httpd:1"),
 			config.WithAutoInstrumentationNginxImage("nginx:1"),
+			config.WithAutoInstrumentationRubyImage("ruby:1"),
+			config.WithAutoInstrumentationPHPImage("php:1"),
 		),
 	).Default(context.Background(), inst)
 	assert.Nil(t, err)
@@ -81,6 +95,8 @@ func TestUpgrade(t *testing.T) {
 	assert.Equal(t, "go:1", inst.Spec.Go.Image)
 	assert.Equal(t, "apache-httpd:1", inst.Spec.ApacheHttpd.Image)
 	assert.Equal(t, "nginx:1", inst.Spec.Nginx.Image)
+	assert.Equal(t, "ruby:1", inst.Spec.Ruby.Image)
+	assert.Equal(t, "php:1", inst.Spec.PHP.Image)
 	err = k8sClient.Create(context.Background(), inst)
 	require.NoError(t, err)
 
@@ -93,6 +109,8 @@ func TestUpgrade(t *testing.T) {
 		DefaultAutoInstGo:          "go:2",
 		DefaultAutoInstApacheHttpd: "apache-httpd:2",
 		DefaultAutoInstNginx:       "nginx:2",
+		DefaultAutoInstRuby:        "ruby:2",
+		DefaultAutoInstPHP:         "php:2",
 		Client:                     k8sClient,
 	}
 	err = up.ManagedInstances(context.Background())
@@ -118,4 +136,8 @@ func TestUpgrade(t *testing.T) {
 	assert.Equal(t, "apache-httpd:2", updated.Spec.ApacheHttpd.Image)
 	assert.Equal(t, "nginx:2", updated.Annotations[constants.AnnotationDefaultAutoInstrumentationNginx])
 	assert.Equal(t, "nginx:2", updated.Spec.Nginx.Image)
+	assert.Equal(t, "ruby:2", updated.Annotations[constants.AnnotationDefaultAutoInstrumentationRuby])
+	assert.Equal(t, "ruby:2", updated.Spec.Ruby.Image)
+	assert.Equal(t, "php:2", updated.Annotations[constants.AnnotationDefaultAutoInstrumentationPHP])
+	assert.Equal(t, "php:2", updated.Spec.PHP.Image)
 }
@@ -0,0 +1,47 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package instrumentation
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OwningWorkload returns the deployment, daemonset or statefulset controlling the pod, or nil if there is none. Given
+// the manager client, the lookups are served by the informers its cache already runs for the replicasets read by the
+// SDK injection and for the workloads owned by the CloudWatchAgent controller.
+func OwningWorkload(ctx context.Context, reader client.Reader, pod corev1.Pod) (client.Object, error) {
+	owner := metav1.GetControllerOf(&pod)
+	if owner == nil {
+		return nil, nil
+	}
+	var workload client.Object
+	switch owner.Kind {
+	case "ReplicaSet":
+		var replicaSet appsv1.ReplicaSet
+		if err := reader.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}, &replicaSet); err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		owner = metav1.GetControllerOf(&replicaSet)
+		if owner == nil || owner.Kind != "Deployment" {
+			return nil, nil
+		}
+		workload = &appsv1.Deployment{}
+	case "DaemonSet":
+		workload = &appsv1.DaemonSet{}
+	case "StatefulSet":
+		workload = &appsv1.StatefulSet{}
+	default:
+		return nil, nil
+	}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}, workload); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return workload, nil
+}
@@ -20,6 +20,9 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent-operator/internal/webhook/podmutation"
 )
 
+// mutatorName identifies the decisions of this mutator in the explain trace.
+const mutatorName = "sidecar"
+
 var (
 	errMultipleInstancesPossible = errors.New("multiple OpenTelemetry Collector instances available, cannot determine which one to select")
 	errNoInstancesAvailable      = errors.New("no OpenTelemetry Collector instances available")
@@ -44,17 +47,20 @@ func NewMutator(logger logr.Logger, config config.Config, client client.Client)
 
 func (p *sidecarPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod corev1.Pod) (corev1.Pod, error) {
 	logger := p.logger.WithValues("namespace", pod.Namespace, "name", pod.Name)
+	trace := podmutation.TraceFromContext(ctx)
 
 	// if no annotations are found at all, just return the same pod
 	annValue := annotationValue(ns, pod)
 	if len(annValue) == 0 {
 		logger.V(1).Info("annotation not present in deployment, skipping sidecar injection")
+		trace.Recordf(mutatorName, "annotation %s not present on the pod or namespace, skipping sidecar injection", Annotation)
 		return pod, nil
 	}
 
 	// is the annotation value 'false'? if so, we need a pod without the sidecar (ie, remove if exists)
 	if strings.EqualFold(annValue, "false") {
 		logger.V(1).Info("pod explicitly refuses sidecar injection, attempting to remove sidecar if it exists")
+		trace.Recordf(mutatorName, "pod explicitly refuses sidecar injection, removing the sidecar if it exists")
 		return remove(pod)
 	}
 
@@ -62,6 +68,7 @@ func (p *sidecarPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod
 	// check whether there's a sidecar already -- return the same pod if that's the case.
 	if existsIn(pod) {
 		logger.V(1).Info("pod already has sidecar in it, skipping injection")
+		trace.Recordf(mutatorName, "pod already has a sidecar, skipping injection")
 		return pod, nil
 	}
 
@@ -71,6 +78,7 @@ func (p *sidecarPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod
 		if errors.Is(err, errMultipleInstancesPossible) || errors.Is(err, errNoInstancesAvailable) || errors.Is(err, errInstanceNotSidecar) {
 			// we still allow the pod to be created, but we log a message to the operator's logs
 			logger.Error(err, "failed to select an OpenTelemetry Collector instance for this pod's sidecar")
+			trace.Recordf(mutatorName, "failed to select an AmazonCloudWatchAgent instance for annotation value %q, skipping injection: %v", annValue, err)
 			return pod, nil
 		}
 
@@ -85,6 +93,7 @@ func (p *sidecarPodMutator) Mutate(ctx context.Context, ns corev1.Namespace, pod
 	// once it's been determined that a sidecar is desired, none exists yet, and we know which instance it should talk to,
 	// we should add the sidecar.
 	logger.V(1).Info("injecting sidecar into pod", "otelcol-namespace", otelcol.Namespace, "otelcol-name", otelcol.Name)
+	trace.Recordf(mutatorName, "injecting the sidecar of AmazonCloudWatchAgent %s/%s", otelcol.Namespace, otelcol.Name)
 
 	return add(p.config, p.logger, otelcol, pod, attributes)
 }